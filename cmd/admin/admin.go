@@ -0,0 +1,291 @@
+// Package admin implements the command-line administration of the books.
+package admin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/hoodnoah/ghoam/cmd/migrate"
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/backup"
+	"github.com/hoodnoah/ghoam/internal/config"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	schema "github.com/hoodnoah/ghoam/internal/persistence/sqlite/migrate"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+// CreateAdmin adds an admin who may sign in and manage the other users, per the arguments following
+// `create-admin`: -username along with the usual settings flags, chiefly
+// -db. The password is prompted for twice on a terminal, and otherwise read
+// as the first line of stdin, so that the command may be scripted.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func CreateAdmin(ctx context.Context, args []string, getenv func(string) string, stdin *os.File, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam create-admin", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	username := fs.String("username", "", "the new user's username")
+	resolve := config.Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if strings.TrimSpace(*username) == "" {
+		fs.Usage()
+		return errors.New("a -username is required")
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	repos, err := migrate.Execute(cfg.DBPath, cfg.BackupDirectory())
+	if err != nil {
+		return fmt.Errorf("failed to initialize the database: %w", err)
+	}
+	defer repos.Close()
+
+	auth := services.AuthService{UserRepo: repos.Users, SessionRepo: repos.Sessions}
+	// whoever can run the command administers the server, and so the books
+	user, err := auth.CreateUser(accounting.AsSystem(ctx), *username, password, accounting.AdminRole)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stderr, "created admin %s in %s\n", user.Username, cfg.DBPath)
+	return nil
+}
+
+// Verify walks the hash chain over the posted entries of the database named
+// by the settings flags following `verify`, reporting to stdout how many
+// entries matched, or the first which did not, in which case it returns the
+// ErrChainBroken. The database is opened read-only and audited as it stands;
+// one with migrations pending is refused rather than migrated.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func Verify(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	resolve := config.Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to find the database: %w", err)
+	}
+
+	db, err := sqlite.OpenReadOnly(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	status, err := schema.GetStatus(db)
+	if err != nil {
+		return err
+	}
+	if status.Dirty || len(status.Pending) > 0 {
+		return fmt.Errorf("the database is at version %d of %d; migrate it up before verifying it", status.Version, status.Latest)
+	}
+
+	entries := services.JournalEntryService{JournalEntryRepo: sqlite.NewJournalEntryRepo(db)}
+	report, err := entries.VerifyChain(accounting.AsSystem(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !report.Intact() {
+		fmt.Fprintf(stdout, "%s: broken after checking %d entries\n", cfg.DBPath, report.Entries)
+		return report.Break
+	}
+
+	fmt.Fprintf(stdout, "%s: all %d entries match their hashes\n", cfg.DBPath, report.Entries)
+	return nil
+}
+
+// Check examines the whole of the books in the database named by the
+// settings flags following `check`, which is read as it stands, without
+// migrating it. Each problem found is written to stdout with the record it
+// concerns and a suggested fix, in which case an error is returned.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func Check(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	resolve := config.Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to find the database: %w", err)
+	}
+
+	db, err := sqlite.Open(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	integrity := services.IntegrityService{IntegrityRepo: sqlite.NewIntegrityRepo(db)}
+	report, err := integrity.Check(accounting.AsSystem(ctx))
+	if err != nil {
+		return err
+	}
+
+	services.WriteIntegrityReport(stdout, cfg.DBPath, report)
+	if !report.OK() {
+		return fmt.Errorf("found %d problems in the books", len(report.Problems))
+	}
+	return nil
+}
+
+// Backup takes a backup of the database named by the settings flags
+// following `backup`, which may be in use by the server meanwhile, verifies
+// it, and prunes the backups the retention settings no longer keep.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func Backup(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam backup", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	resolve := config.Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+	retention, _ := cfg.Retention() // validated by resolve
+
+	repos, err := migrate.Execute(cfg.DBPath, cfg.BackupDirectory())
+	if err != nil {
+		return fmt.Errorf("failed to initialize the database: %w", err)
+	}
+	defer repos.Close()
+
+	backups := services.BackupService{
+		Snapshotter:   repos,
+		IntegrityRepo: repos.Integrity,
+		Dir:           cfg.BackupDirectory(),
+		Retention:     retention,
+	}
+	result, err := backups.CreateBackup(accounting.AsSystem(ctx))
+	if err != nil {
+		return err
+	}
+
+	services.WriteIntegrityReport(stdout, cfg.DBPath, result.Check)
+	fmt.Fprintf(stdout, "backed up %s to %s (%d bytes)\n", cfg.DBPath, result.Backup.Path, result.Backup.Size)
+	for _, b := range result.Pruned {
+		fmt.Fprintf(stdout, "pruned %s\n", b.Path)
+	}
+	return nil
+}
+
+// Restore replaces the database named by the settings flags following
+// `restore` with the backup named by its argument, once the backup passes
+// verification. A database recording changes the backup lacks is
+// overwritten only with -force. The server must be stopped meanwhile.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func Restore(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam restore", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ghoam restore [flags] <backup file>")
+		fs.PrintDefaults()
+	}
+	force := fs.Bool("force", false, "overwrite the database even if it records changes the backup lacks")
+	resolve := config.Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("a single backup file to restore is required")
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+
+	if err := backup.Restore(ctx, fs.Arg(0), cfg.DBPath, *force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "restored %s from %s\n", cfg.DBPath, fs.Arg(0))
+	return nil
+}
+
+func readPassword(stdin *os.File, stderr io.Writer) (string, error) {
+	fd := int(stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(stderr)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprint(stderr, "Confirm password: ")
+	confirmation, err := term.ReadPassword(fd)
+	fmt.Fprintln(stderr)
+	if err != nil {
+		return "", err
+	}
+
+	if string(password) != string(confirmation) {
+		return "", errors.New("the passwords do not match")
+	}
+
+	return string(password), nil
+}
@@ -0,0 +1,189 @@
+package migrate
+
+import (
+	// std
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/config"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	schema "github.com/hoodnoah/ghoam/internal/persistence/sqlite/migrate"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+const usage = `usage: ghoam migrate <command> [flags] [version]
+
+commands:
+  status      report the schema version, whether it is dirty, and the pending migrations
+  up          apply the pending migrations, or those up to -to
+  down -to N  undo the migrations applied after version N; 0 undoes them all
+  force N     record the schema as at version N and clean, once repaired by hand
+
+flags:
+`
+
+// Command manages the schema of the database named by the settings flags,
+// per the arguments following `migrate`. Migrating up or down checks the
+// books for integrity and backs up the database first, and the books are
+// checked again after any change to the schema. The server should be stopped meanwhile.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to stderr.
+func Command(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ghoam migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	to := fs.Int("to", -1, "the version to migrate up or down to")
+	destroyJournal := fs.Bool("destroy-journal", false, "confirm migrating down past migrations which destroy posted journal entries or the records linked to them")
+	resolve := config.Flags(fs, getenv)
+
+	if len(args) == 0 {
+		fs.Usage()
+		return errors.New("a command is required")
+	}
+	command := args[0]
+	if command == "-h" || command == "-help" || command == "--help" {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	// flags may follow the version given to force
+	var positional []string
+	for rest := args[1:]; ; rest = rest[1:] {
+		if err := fs.Parse(rest); err != nil {
+			return err
+		}
+		if rest = fs.Args(); len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+	}
+
+	cfg, err := resolve()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cfg.DBPath); err != nil {
+		return fmt.Errorf("failed to find the database: %w", err)
+	}
+
+	db, err := sqlite.Open(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch command {
+	case "status":
+		if len(positional) > 0 {
+			return fmt.Errorf("unexpected arguments: %s", strings.Join(positional, " "))
+		}
+
+	case "up":
+		if len(positional) > 0 {
+			return fmt.Errorf("unexpected arguments: %s", strings.Join(positional, " "))
+		}
+		taken, err := BackupBeforeMigrating(ctx, db, cfg.BackupDirectory())
+		if err != nil {
+			return err
+		}
+		if taken != nil {
+			services.WriteIntegrityReport(stdout, cfg.DBPath, taken.Check)
+			fmt.Fprintf(stdout, "backed up %s to %s\n", cfg.DBPath, taken.Backup.Path)
+		}
+
+		if *to < 0 {
+			err = schema.Up(db)
+		} else {
+			err = schema.UpTo(db, uint(*to))
+		}
+		if err != nil {
+			return err
+		}
+
+	case "down":
+		if len(positional) > 0 {
+			return fmt.Errorf("unexpected arguments: %s", strings.Join(positional, " "))
+		}
+		if *to < 0 {
+			return errors.New("a version to migrate down to is required, e.g. -to 20")
+		}
+
+		// checked first, so as not to back up for a migration refused
+		if err := schema.CheckDown(db, uint(*to), *destroyJournal); err != nil {
+			return err
+		}
+		taken, err := checkAndBackUp(ctx, db, cfg.BackupDirectory())
+		if err != nil {
+			return err
+		}
+		services.WriteIntegrityReport(stdout, cfg.DBPath, taken.Check)
+		fmt.Fprintf(stdout, "backed up %s to %s\n", cfg.DBPath, taken.Backup.Path)
+
+		if err := schema.DownTo(db, uint(*to), *destroyJournal); err != nil {
+			return err
+		}
+
+	case "force":
+		if len(positional) != 1 {
+			return errors.New("a single version to force is required, e.g. force 20")
+		}
+		version, err := strconv.Atoi(positional[0])
+		if err != nil || version < -1 {
+			return fmt.Errorf("invalid version %q", positional[0])
+		}
+
+		if err := schema.Force(db, version); err != nil {
+			return err
+		}
+
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	status, err := schema.GetStatus(db)
+	if err != nil {
+		return err
+	}
+	writeStatus(stdout, cfg.DBPath, status)
+
+	// the migration stands whatever the check finds; it is reported for repair
+	if command != "status" {
+		data, err := sqlite.NewIntegrityRepo(db).Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check the books after migrating them: %w", err)
+		}
+		services.WriteIntegrityReport(stdout, cfg.DBPath, accounting.CheckIntegrity(data))
+	}
+
+	return nil
+}
+
+func writeStatus(w io.Writer, dbPath string, status schema.Status) {
+	dirty := ""
+	if status.Dirty {
+		dirty = " (dirty: a migration failed part way; repair it, then force a version)"
+	}
+	fmt.Fprintf(w, "%s: version %d of %d%s\n", dbPath, status.Version, status.Latest, dirty)
+
+	if len(status.Pending) == 0 {
+		fmt.Fprintln(w, "no migrations pending")
+		return
+	}
+	fmt.Fprintf(w, "%d pending:", len(status.Pending))
+	for _, v := range status.Pending {
+		fmt.Fprintf(w, " %d", v)
+	}
+	fmt.Fprintln(w)
+}
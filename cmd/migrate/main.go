@@ -1,18 +1,128 @@
 package migrate
 
 import (
+	// std
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
 	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/backup"
 	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	schema "github.com/hoodnoah/ghoam/internal/persistence/sqlite/migrate"
 )
 
 // Execute bootstraps the database, runs migrations, and inserts the basic account groups
 // Returns repositories which abstract data retrieval, saving, etc.
-func Execute(dbPath string) (*sqlite.Repositories, error) {
+// Books already begun are checked for integrity and backed up into backupDir
+// before any pending migration is applied to them, and checked again after.
+func Execute(dbPath, backupDir string) (*sqlite.Repositories, error) {
+	// the database's directory is created on first run
+	if dir := filepath.Dir(dbPath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create the database directory: %w", err)
+		}
+	}
+
+	var migrated bool
+	if _, err := os.Stat(dbPath); err == nil {
+		db, err := sqlite.Open(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		taken, err := BackupBeforeMigrating(context.Background(), db, backupDir)
+		db.Close()
+		if err != nil {
+			return nil, err
+		}
+		if taken != nil {
+			logIntegrity("before migrating", taken.Check)
+			slog.Info("database backed up before migrating", "path", taken.Backup.Path)
+			migrated = true
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
 	// Open the SQLite database
 	repos, err := sqlite.New(dbPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if migrated {
+		data, err := repos.Integrity.Load(context.Background())
+		if err != nil {
+			repos.Close()
+			return nil, fmt.Errorf("failed to check the books after migrating them: %w", err)
+		}
+		logIntegrity("after migrating", accounting.CheckIntegrity(data))
+	}
+
 	return repos, nil
 }
+
+// logs what the integrity check found when, before or after migrating
+func logIntegrity(when string, report accounting.IntegrityReport) {
+	for _, p := range report.Problems {
+		slog.Warn("integrity problem "+when, "entity_type", p.EntityType, "entity_id", p.EntityID, "problem", p.Problem, "fix", p.Fix)
+	}
+	if report.OK() {
+		slog.Info("books checked "+when, "entries", report.Entries, "accounts", report.Accounts, "closed_periods", report.ClosedPeriods)
+	}
+}
+
+// a backup taken before migrating
+type MigrationBackup struct {
+	Backup backup.Backup
+	// the integrity check run just before; a backup is taken whatever it
+	// finds, so that problems the migration did not cause are known as such
+	Check accounting.IntegrityReport
+}
+
+// BackupBeforeMigrating checks the books and backs up db into backupDir if
+// migrations are pending for it and it has been migrated before, reporting
+// the backup taken, if any
+func BackupBeforeMigrating(ctx context.Context, db *sql.DB, backupDir string) (*MigrationBackup, error) {
+	status, err := schema.GetStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	// a new database holds nothing to lose, and a dirty one will not migrate
+	if len(status.Pending) == 0 || status.Version == 0 || status.Dirty {
+		return nil, nil
+	}
+
+	return checkAndBackUp(ctx, db, backupDir)
+}
+
+// checks the books held by db, then backs it up into backupDir
+func checkAndBackUp(ctx context.Context, db *sql.DB, backupDir string) (*MigrationBackup, error) {
+	data, err := sqlite.NewIntegrityRepo(db).Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the books before migrating them: %w", err)
+	}
+	taken := MigrationBackup{Check: accounting.CheckIntegrity(data)}
+
+	if taken.Backup, err = backup.Create(ctx, dbSnapshotter{db}, backupDir, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to back up the database before migrating it: %w", err)
+	}
+
+	return &taken, nil
+}
+
+// snapshots a database opened without the repositories
+type dbSnapshotter struct {
+	db *sql.DB
+}
+
+func (s dbSnapshotter) Snapshot(ctx context.Context, path string) error {
+	return sqlite.SnapshotDB(ctx, s.db, path)
+}
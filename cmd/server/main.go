@@ -2,50 +2,496 @@ package server
 
 import (
 	// std
-	"html/template"
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"path/filepath"
+	texttemplate "text/template"
+	"time"
 
 	// internal
+	"github.com/hoodnoah/ghoam/internal/config"
 	"github.com/hoodnoah/ghoam/internal/http/handlers"
+	"github.com/hoodnoah/ghoam/internal/http/middleware"
+	"github.com/hoodnoah/ghoam/internal/http/view"
 	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
 	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/static"
+	"github.com/hoodnoah/ghoam/templates"
 )
 
-func Execute(repos *sqlite.Repositories) {
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+	writeTimeout      = 60 * time.Second // generous enough for PDF generation
+	idleTimeout       = 2 * time.Minute
+	// how long in-flight requests are given to finish once shutdown begins
+	shutdownTimeout = 30 * time.Second
+)
+
+// Execute serves the application per cfg until ctx is cancelled, then stops
+// accepting connections and waits for in-flight requests to finish, so that
+// no posting is cut off midway. The caller closes the database afterward.
+func Execute(ctx context.Context, cfg config.Config, repos *sqlite.Repositories, logger *slog.Logger) error {
+	handler, err := NewHandler(cfg, repos, logger)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("server starting", "addr", cfg.ListenAddr, "url", cfg.URL(), "tls", cfg.TLS())
+		if cfg.TLS() {
+			serveErr <- srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			serveErr <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server error: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down; draining in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to drain in-flight requests: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	logger.Info("server stopped")
+	return nil
+}
+
+// NewHandler wires the services, handlers and routes onto a dedicated router,
+// wrapped in request IDs, access logging, panic recovery, compression,
+// sessions and CSRF checks; everything but the login page and static assets
+// requires signing in
+func NewHandler(cfg config.Config, repos *sqlite.Repositories, logger *slog.Logger) (http.Handler, error) {
 	// Instantiate the ChartOfAccountsService using the SQLite repositories
 	chartService := services.ChartOfAccountsService{
 		AccountRepo:      repos.Accounts,
 		AccountGroupRepo: repos.AccountGroups,
 	}
 
-	// Parse templates from the templates/ folder
-	tmpl, err := template.ParseGlob(filepath.Join("templates", "*.gohtml"))
+	// Instantiate the AgingService for receivables and payables aging reports
+	agingService := services.AgingService{
+		CustomerRepo:          repos.Customers,
+		InvoiceRepo:           repos.Invoices,
+		VendorRepo:            repos.Vendors,
+		BillRepo:              repos.Bills,
+		AccountRepo:           repos.Accounts,
+		JournalEntryRepo:      repos.JournalEntries,
+		ReceivableAccountName: "Accounts Receivable",
+		PayableAccountName:    "Accounts Payable",
+	}
+
+	// Instantiate the PayablesService for vendors, bills and bill payments
+	payablesService := services.PayablesService{
+		VendorRepo:          repos.Vendors,
+		BillRepo:            repos.Bills,
+		AccountRepo:         repos.Accounts,
+		DiscountAccountName: "Purchase Discounts",
+	}
+
+	// Instantiate the ReceivablesService for customers, invoices and receipts
+	receivablesService := services.ReceivablesService{
+		CustomerRepo: repos.Customers,
+		InvoiceRepo:  repos.Invoices,
+		AccountRepo:  repos.Accounts,
+		SalesTaxRepo: repos.SalesTax,
+	}
+
+	// Instantiate the FixedAssetService for the asset register and depreciation schedules
+	fixedAssetService := services.FixedAssetService{
+		FixedAssetRepo:      repos.FixedAssets,
+		AccountRepo:         repos.Accounts,
+		GainLossAccountName: "Gain or Loss on Disposal of Assets",
+	}
+
+	// Instantiate the InventoryService for individually tracked units and fungible stock
+	inventoryService := services.InventoryService{
+		UnitRepo:             repos.InventoryUnits,
+		StockRepo:            repos.Stock,
+		CountRepo:            repos.Counts,
+		ProjectRepo:          repos.Projects,
+		CustomerRepo:         repos.Customers,
+		AccountRepo:          repos.Accounts,
+		JournalEntryRepo:     repos.JournalEntries,
+		COGSAccountName:      "Cost of Goods Sold",
+		ShrinkageAccountName: "Inventory Shrinkage",
+	}
+
+	// Instantiate the ProjectService for projects and the labor hours worked on them
+	projectService := services.ProjectService{
+		ProjectRepo:        repos.Projects,
+		CustomerRepo:       repos.Customers,
+		AccountRepo:        repos.Accounts,
+		JournalEntryRepo:   repos.JournalEntries,
+		LaborAccountName:   "Direct Labor",
+		PayrollAccountName: "Accrued Payroll",
+	}
+
+	// Instantiate the YearEndService for closing and reopening fiscal years
+	yearEndService := services.YearEndService{
+		YearEndRepo:                 repos.YearEnds,
+		AccountRepo:                 repos.Accounts,
+		JournalEntryRepo:            repos.JournalEntries,
+		RetainedEarningsAccountName: "Retained Earnings",
+		IncomeSummaryAccountName:    "Income Summary",
+	}
+
+	// Instantiate the PeriodService for the fiscal calendar and period locks
+	periodService := services.PeriodService{
+		PeriodRepo: repos.Periods,
+	}
+
+	// Instantiate the SETaxService for self-employment tax estimates and reserves
+	seTaxService := services.SETaxService{
+		SETaxRepo:          repos.SETax,
+		AccountRepo:        repos.Accounts,
+		JournalEntryRepo:   repos.JournalEntries,
+		YearEndRepo:        repos.YearEnds,
+		ReserveAccountName: "Tax Reserve",
+		DrawAccountName:    "Owner Draws",
+	}
+
+	// Instantiate the TaxReportService for tax line mappings and the tax report
+	taxReportService := services.TaxReportService{
+		TaxLineRepo:      repos.TaxLines,
+		AccountRepo:      repos.Accounts,
+		JournalEntryRepo: repos.JournalEntries,
+		YearEndRepo:      repos.YearEnds,
+	}
+
+	// Instantiate the SalesTaxService for sales tax rates, returns and remittances
+	salesTaxService := services.SalesTaxService{
+		SalesTaxRepo:     repos.SalesTax,
+		InvoiceRepo:      repos.Invoices,
+		AccountRepo:      repos.Accounts,
+		JournalEntryRepo: repos.JournalEntries,
+	}
+
+	// Instantiate the AuthService for users and their sessions
+	authService := services.AuthService{
+		UserRepo:    repos.Users,
+		SessionRepo: repos.Sessions,
+	}
+
+	// Instantiate the AuditService for the audit log and the history of each record
+	auditService := services.AuditService{
+		AuditRepo: repos.Audit,
+	}
+
+	// Instantiate the JournalEntryService for viewing posted entries
+	journalEntryService := services.JournalEntryService{
+		JournalEntryRepo: repos.JournalEntries,
+	}
+
+	// Instantiate the BackupService for snapshots of the live books
+	retention, err := cfg.Retention()
+	if err != nil {
+		return nil, err
+	}
+	backupService := services.BackupService{
+		Snapshotter:   repos,
+		IntegrityRepo: repos.Integrity,
+		Dir:           cfg.BackupDirectory(),
+		Retention:     retention,
+	}
+
+	// Instantiate the IntegrityService for checking the books whole
+	integrityService := services.IntegrityService{
+		IntegrityRepo: repos.Integrity,
+	}
+
+	// Parse the embedded page templates, one set per page
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
 	if err != nil {
-		log.Fatalf("failed to parse templates with error %v", err)
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+
+	// Parse the embedded document (PDF layout) templates
+	documentTemplates, err := texttemplate.New("documents").
+		Funcs(services.DocumentTemplateFuncs).
+		ParseFS(templates.FS, "*.gotmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse document templates: %w", err)
+	}
+
+	// Instantiate the DocumentService for invoice and statement PDFs
+	documentService := services.DocumentService{
+		BookSettingsRepo: repos.BookSettings,
+		CustomerRepo:     repos.Customers,
+		InvoiceRepo:      repos.Invoices,
+		AttachmentRepo:   repos.Attachments,
+		Templates:        documentTemplates,
 	}
 
 	// Create the handler for the Chart of Accounts endpoint
 	chartHandler := &handlers.ChartOfAccountsHandler{
-		ChartOfAccountsService:  &chartService,
-		ChartOfAccountsTemplate: tmpl,
+		ChartOfAccountsService: &chartService,
+		AuditService:           &auditService,
+		Views:                  views,
 	}
 
-	// Set up routes: the index page and the chart endpoint for HTMX
+	// Create the handler for journal entries
+	journalEntriesHandler := &handlers.JournalEntriesHandler{
+		JournalEntryService: &journalEntryService,
+		AuditService:        &auditService,
+		Views:               views,
+	}
+
+	// Create the handler for the audit log
+	auditHandler := &handlers.AuditHandler{
+		AuditService: &auditService,
+		Views:        views,
+	}
+
+	// Create the handler for the aging reports
+	agingHandler := &handlers.AgingHandler{
+		AgingService: &agingService,
+		Views:        views,
+	}
+
+	// Create the handler for vendors, bills and bill payments
+	payablesHandler := &handlers.PayablesHandler{
+		PayablesService: &payablesService,
+		Views:           views,
+	}
+
+	// Create the handler for customers, invoices and receipts
+	receivablesHandler := &handlers.ReceivablesHandler{
+		ReceivablesService: &receivablesService,
+		Views:              views,
+	}
+
+	// Create the handler for generated documents and attachments
+	documentsHandler := &handlers.DocumentsHandler{
+		DocumentService: &documentService,
+	}
+
+	// Create the handler for the fixed asset register
+	fixedAssetsHandler := &handlers.FixedAssetsHandler{
+		FixedAssetService: &fixedAssetService,
+		Views:             views,
+	}
+
+	// Create the handler for inventory
+	inventoryHandler := &handlers.InventoryHandler{
+		InventoryService: &inventoryService,
+		Views:            views,
+	}
+
+	// Create the handler for projects
+	projectsHandler := &handlers.ProjectsHandler{
+		ProjectService: &projectService,
+		Views:          views,
+	}
+
+	// Create the handler for year-end closes
+	yearEndHandler := &handlers.YearEndHandler{
+		YearEndService: &yearEndService,
+		Views:          views,
+	}
+
+	// Create the handler for accounting periods
+	periodsHandler := &handlers.PeriodsHandler{
+		PeriodService: &periodService,
+		Views:         views,
+	}
+
+	// Create the handler for self-employment tax
+	seTaxHandler := &handlers.SETaxHandler{
+		SETaxService: &seTaxService,
+		Views:        views,
+	}
+
+	// Create the handler for the tax report
+	taxReportHandler := &handlers.TaxReportHandler{
+		TaxReportService: &taxReportService,
+		Views:            views,
+	}
+
+	// Create the handler for sales tax
+	salesTaxHandler := &handlers.SalesTaxHandler{
+		SalesTaxService: &salesTaxService,
+		Views:           views,
+	}
+
+	// Create the handler for signing in and out
+	authHandler := &handlers.AuthHandler{
+		AuthService:   &authService,
+		Views:         views,
+		SecureCookies: cfg.SecureCookies(),
+	}
+
+	// Create the handler for managing users
+	usersHandler := &handlers.UsersHandler{
+		AuthService: &authService,
+		Views:       views,
+	}
+
+	// Create the handler for backups
+	backupsHandler := &handlers.BackupsHandler{
+		BackupService: &backupService,
+		Views:         views,
+	}
+
+	// Create the handler for the integrity check
+	integrityHandler := &handlers.IntegrityHandler{
+		IntegrityService: &integrityService,
+		Views:            views,
+	}
+
+	// Set up routes on a dedicated router: the index page and the chart endpoint for HTMX
+	mux := http.NewServeMux()
+
 	// index handler
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		data := map[string]any{"Title": "Home "}
-		if err := tmpl.ExecuteTemplate(w, "layout", data); err != nil {
-			http.Error(w, "rendering index failed:"+err.Error(), http.StatusInternalServerError)
-		}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		views.Render(w, r, "index", nil)
 	})
 
+	// login handlers
+	mux.HandleFunc("GET /login", authHandler.GetLogin)
+	mux.HandleFunc("POST /login", authHandler.PostLogin)
+	mux.HandleFunc("POST /logout", authHandler.PostLogout)
+
+	// user management handlers
+	mux.HandleFunc("GET /users", usersHandler.GetUsers)
+	mux.HandleFunc("POST /users", usersHandler.PostUser)
+	mux.HandleFunc("POST /users/{id}/role", usersHandler.PostRole)
+
+	// backup handlers
+	mux.HandleFunc("GET /backups", backupsHandler.GetBackups)
+	mux.HandleFunc("POST /backups", backupsHandler.PostBackup)
+	mux.HandleFunc("GET /integrity", integrityHandler.GetCheck)
+
+	// embedded static assets
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static.FS)))
+
 	// chart of accounts handler
-	http.HandleFunc("/chart", chartHandler.GetChart)
+	mux.HandleFunc("/chart", chartHandler.GetChart)
+	mux.HandleFunc("GET /accounts/{name}", chartHandler.GetAccount)
+	mux.HandleFunc("POST /accounts", chartHandler.PostAccount)
+	mux.HandleFunc("POST /account-groups", chartHandler.PostGroup)
+	mux.HandleFunc("POST /account-groups/{name}/move", chartHandler.PostGroupMove)
 
-	log.Println("Server starting on :8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("server error: %v", err)
-	}
+	// journal entry handlers
+	mux.HandleFunc("GET /journal-entries/chain", journalEntriesHandler.GetChain)
+	mux.HandleFunc("GET /journal-entries/{id}", journalEntriesHandler.GetEntry)
+
+	// audit log handlers
+	mux.HandleFunc("GET /audit", auditHandler.GetLog)
+
+	// aging report handlers
+	mux.HandleFunc("/aging/receivables", agingHandler.GetReceivables)
+	mux.HandleFunc("/aging/payables", agingHandler.GetPayables)
+
+	// payables handlers
+	mux.HandleFunc("GET /payables", payablesHandler.GetPayables)
+	mux.HandleFunc("POST /vendors", payablesHandler.PostVendor)
+	mux.HandleFunc("POST /bills", payablesHandler.PostBill)
+	mux.HandleFunc("GET /bills/{id}", payablesHandler.GetBill)
+	mux.HandleFunc("POST /bills/{id}/payments", payablesHandler.PostPayment)
+
+	// receivables handlers
+	mux.HandleFunc("GET /receivables", receivablesHandler.GetReceivables)
+	mux.HandleFunc("POST /customers", receivablesHandler.PostCustomer)
+	mux.HandleFunc("POST /invoices", receivablesHandler.PostInvoice)
+	mux.HandleFunc("GET /invoices/{id}", receivablesHandler.GetInvoice)
+	mux.HandleFunc("POST /invoices/{id}/payments", receivablesHandler.PostPayment)
+
+	// fixed asset handlers
+	mux.HandleFunc("GET /fixed-assets", fixedAssetsHandler.GetRegister)
+	mux.HandleFunc("GET /fixed-assets/{id}/schedule", fixedAssetsHandler.GetSchedule)
+	mux.HandleFunc("POST /fixed-assets/depreciation", fixedAssetsHandler.PostDepreciation)
+	mux.HandleFunc("POST /fixed-assets/{id}/dispose", fixedAssetsHandler.PostDisposal)
+
+	// inventory handlers
+	mux.HandleFunc("GET /inventory/units", inventoryHandler.GetOnHand)
+	mux.HandleFunc("POST /inventory/units", inventoryHandler.PostUnit)
+	mux.HandleFunc("POST /inventory/units/{id}/move", inventoryHandler.PostMove)
+	mux.HandleFunc("POST /inventory/units/{id}/sell", inventoryHandler.PostSale)
+	mux.HandleFunc("POST /inventory/items", inventoryHandler.PostStockItem)
+	mux.HandleFunc("GET /inventory/items/{id}", inventoryHandler.GetStockItem)
+	mux.HandleFunc("POST /inventory/items/{id}/receipts", inventoryHandler.PostReceipt)
+	mux.HandleFunc("POST /inventory/items/{id}/issues", inventoryHandler.PostIssue)
+	mux.HandleFunc("GET /inventory/counts", inventoryHandler.GetCounts)
+	mux.HandleFunc("POST /inventory/counts", inventoryHandler.PostCount)
+	mux.HandleFunc("GET /inventory/counts/{id}", inventoryHandler.GetCount)
+	mux.HandleFunc("POST /inventory/counts/{id}/lines", inventoryHandler.PostCountedQuantities)
+	mux.HandleFunc("POST /inventory/counts/{id}/post", inventoryHandler.PostCountPosting)
+
+	// project handlers
+	mux.HandleFunc("GET /projects", projectsHandler.GetProjects)
+	mux.HandleFunc("POST /projects", projectsHandler.PostProject)
+	mux.HandleFunc("GET /projects/margins", projectsHandler.GetMargins)
+	mux.HandleFunc("GET /projects/{id}", projectsHandler.GetProject)
+	mux.HandleFunc("POST /projects/{id}/time", projectsHandler.PostTime)
+	mux.HandleFunc("POST /projects/{id}/close", projectsHandler.PostClose)
+	mux.HandleFunc("GET /projects/{id}/margin", projectsHandler.GetMargin)
+
+	// year-end handlers
+	mux.HandleFunc("GET /year-end", yearEndHandler.GetCloses)
+	mux.HandleFunc("POST /year-end", yearEndHandler.PostClose)
+	mux.HandleFunc("POST /year-end/{id}/reopen", yearEndHandler.PostReopen)
+
+	// period handlers
+	mux.HandleFunc("GET /periods", periodsHandler.GetPeriods)
+	mux.HandleFunc("POST /periods/calendar", periodsHandler.PostCalendar)
+	mux.HandleFunc("POST /periods/open", periodsHandler.PostOpenYear)
+	mux.HandleFunc("POST /periods/{id}/status", periodsHandler.PostStatus)
+
+	// self-employment tax handlers
+	mux.HandleFunc("GET /se-tax", seTaxHandler.GetEstimate)
+	mux.HandleFunc("POST /se-tax/rates", seTaxHandler.PostRates)
+	mux.HandleFunc("POST /se-tax/reserve", seTaxHandler.PostReserve)
+	mux.HandleFunc("POST /se-tax/payments", seTaxHandler.PostPayment)
+
+	// tax report handlers
+	mux.HandleFunc("GET /tax", taxReportHandler.GetReport)
+	mux.HandleFunc("POST /tax/mappings", taxReportHandler.PostMapping)
+	mux.HandleFunc("POST /tax/lines", taxReportHandler.PostLine)
+	mux.HandleFunc("POST /tax/lines/delete", taxReportHandler.PostDeleteLine)
+	mux.HandleFunc("POST /tax/copy", taxReportHandler.PostCopy)
+
+	// sales tax handlers
+	mux.HandleFunc("GET /sales-tax", salesTaxHandler.GetReturn)
+	mux.HandleFunc("POST /sales-tax/jurisdictions", salesTaxHandler.PostJurisdiction)
+	mux.HandleFunc("POST /sales-tax/rates", salesTaxHandler.PostRate)
+	mux.HandleFunc("POST /sales-tax/codes", salesTaxHandler.PostCode)
+	mux.HandleFunc("POST /sales-tax/remittances", salesTaxHandler.PostRemittance)
+
+	// document handlers
+	mux.HandleFunc("POST /invoices/{id}/pdf", documentsHandler.PostInvoicePDF)
+	mux.HandleFunc("POST /customers/{id}/statements", documentsHandler.PostStatementPDF)
+	mux.HandleFunc("GET /attachments/{id}", documentsHandler.GetAttachment)
+
+	return middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.AccessLog(logger),
+		middleware.Recover(logger),
+		middleware.Gzip,
+		middleware.Sessions(&authService, cfg.SecureCookies()),
+		middleware.CSRF(cfg.SecureCookies()),
+		middleware.RequireLogin("/login", "/static/"),
+	), nil
 }
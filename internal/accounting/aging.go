@@ -0,0 +1,161 @@
+package accounting
+
+import (
+	"sort"
+	"time"
+)
+
+// the side of the ledger an aging report covers
+type AgingKind string
+
+const (
+	ReceivablesAging AgingKind = "Receivables"
+	PayablesAging    AgingKind = "Payables"
+)
+
+// enumeration of aging buckets, by days past due
+type AgingBucket int
+
+const (
+	AgingCurrent AgingBucket = iota
+	Aging1To30
+	Aging31To60
+	Aging61To90
+	AgingOver90
+)
+
+// the number of aging buckets
+const AgingBucketCount = 5
+
+// labels for the aging buckets, indexed by AgingBucket
+var AgingBucketLabels = [AgingBucketCount]string{"Current", "1–30", "31–60", "61–90", "90+"}
+
+func (b AgingBucket) String() string {
+	return AgingBucketLabels[b]
+}
+
+// an open invoice or bill, as of the report date
+type AgingDocument struct {
+	ID          string      `json:"id"`
+	PartyID     string      `json:"party_id"`
+	PartyName   string      `json:"party_name"`
+	Number      string      `json:"number"`
+	Date        time.Time   `json:"date"`
+	DueDate     time.Time   `json:"due_date"`
+	Total       float64     `json:"total"`
+	OpenBalance float64     `json:"open_balance"`
+	DaysPastDue int         `json:"days_past_due"`
+	Bucket      AgingBucket `json:"bucket"`
+}
+
+// the open balances of a single customer or vendor
+type AgingRow struct {
+	PartyID   string                    `json:"party_id"`
+	PartyName string                    `json:"party_name"`
+	Buckets   [AgingBucketCount]float64 `json:"buckets"`
+	Total     float64                   `json:"total"`
+	Documents []AgingDocument           `json:"documents"`
+}
+
+// representation of an aging report, tied out to its control accounts
+type AgingReport struct {
+	Kind            AgingKind                 `json:"kind"`
+	AsOf            time.Time                 `json:"as_of"`
+	Rows            []AgingRow                `json:"rows"`
+	Buckets         [AgingBucketCount]float64 `json:"buckets"`
+	Total           float64                   `json:"total"`
+	ControlAccounts []string                  `json:"control_accounts"`
+	ControlBalance  float64                   `json:"control_balance"`
+	Difference      float64                   `json:"difference"` // Total - ControlBalance
+}
+
+// the bucket labels, for presentation
+func (r *AgingReport) BucketLabels() [AgingBucketCount]string {
+	return AgingBucketLabels
+}
+
+// Ties reports whether the report total agrees with the control accounts
+func (r *AgingReport) Ties() bool {
+	return r.Difference == 0
+}
+
+// AgeDocument places an open balance due on dueDate into a bucket as of asOf.
+// Documents not yet past due are current.
+func AgeDocument(dueDate, asOf time.Time) (AgingBucket, int) {
+	days := int(truncateToDay(asOf).Sub(truncateToDay(dueDate)).Hours() / 24)
+
+	switch {
+	case days <= 0:
+		return AgingCurrent, 0
+	case days <= 30:
+		return Aging1To30, days
+	case days <= 60:
+		return Aging31To60, days
+	case days <= 90:
+		return Aging61To90, days
+	default:
+		return AgingOver90, days
+	}
+}
+
+// BuildAgingReport groups open documents by party and buckets them as of asOf.
+//
+// Documents without an open balance are omitted. The control balance is the
+// combined balance of the control accounts, as of the same date.
+func BuildAgingReport(
+	kind AgingKind,
+	asOf time.Time,
+	documents []AgingDocument,
+	controlAccounts []string,
+	controlBalance float64,
+) AgingReport {
+	report := AgingReport{
+		Kind:            kind,
+		AsOf:            asOf,
+		Rows:            []AgingRow{},
+		ControlAccounts: controlAccounts,
+		ControlBalance:  RoundCents(controlBalance),
+	}
+
+	rowIndex := make(map[string]int)
+	for _, doc := range documents {
+		if doc.OpenBalance == 0 {
+			continue
+		}
+
+		doc.Bucket, doc.DaysPastDue = AgeDocument(doc.DueDate, asOf)
+
+		i, ok := rowIndex[doc.PartyID]
+		if !ok {
+			i = len(report.Rows)
+			rowIndex[doc.PartyID] = i
+			report.Rows = append(report.Rows, AgingRow{PartyID: doc.PartyID, PartyName: doc.PartyName})
+		}
+
+		row := &report.Rows[i]
+		row.Documents = append(row.Documents, doc)
+		row.Buckets[doc.Bucket] = RoundCents(row.Buckets[doc.Bucket] + doc.OpenBalance)
+		row.Total = RoundCents(row.Total + doc.OpenBalance)
+
+		report.Buckets[doc.Bucket] = RoundCents(report.Buckets[doc.Bucket] + doc.OpenBalance)
+		report.Total = RoundCents(report.Total + doc.OpenBalance)
+	}
+
+	// rows by party name, documents oldest due first
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		return report.Rows[i].PartyName < report.Rows[j].PartyName
+	})
+	for _, row := range report.Rows {
+		sort.SliceStable(row.Documents, func(i, j int) bool {
+			return row.Documents[i].DueDate.Before(row.Documents[j].DueDate)
+		})
+	}
+
+	report.Difference = RoundCents(report.Total - report.ControlBalance)
+
+	return report
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
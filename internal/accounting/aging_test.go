@@ -0,0 +1,76 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgeDocument(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		dueDate    time.Time
+		wantBucket AgingBucket
+		wantDays   int
+	}{
+		{"not yet due", asOf.AddDate(0, 0, 5), AgingCurrent, 0},
+		{"due today", asOf, AgingCurrent, 0},
+		{"one day past due", asOf.AddDate(0, 0, -1), Aging1To30, 1},
+		{"thirty days past due", asOf.AddDate(0, 0, -30), Aging1To30, 30},
+		{"thirty-one days past due", asOf.AddDate(0, 0, -31), Aging31To60, 31},
+		{"ninety days past due", asOf.AddDate(0, 0, -90), Aging61To90, 90},
+		{"ninety-one days past due", asOf.AddDate(0, 0, -91), AgingOver90, 91},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, days := AgeDocument(tt.dueDate, asOf)
+			if bucket != tt.wantBucket || days != tt.wantDays {
+				t.Fatalf("expected %s (%d days), got %s (%d days)", tt.wantBucket, tt.wantDays, bucket, days)
+			}
+		})
+	}
+}
+
+func TestBuildAgingReport(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	documents := []AgingDocument{
+		{ID: "1", PartyID: "b", PartyName: "Beta", DueDate: asOf.AddDate(0, 0, -45), OpenBalance: 100},
+		{ID: "2", PartyID: "a", PartyName: "Alpha", DueDate: asOf.AddDate(0, 0, 10), OpenBalance: 250},
+		{ID: "3", PartyID: "b", PartyName: "Beta", DueDate: asOf.AddDate(0, 0, -100), OpenBalance: 50.5},
+		{ID: "4", PartyID: "a", PartyName: "Alpha", DueDate: asOf.AddDate(0, 0, -100), OpenBalance: 0},
+	}
+
+	t.Run("groups open documents by party and bucket", func(t *testing.T) {
+		report := BuildAgingReport(ReceivablesAging, asOf, documents, []string{"Accounts Receivable"}, 400.5)
+
+		if len(report.Rows) != 2 || report.Rows[0].PartyName != "Alpha" {
+			t.Fatalf("expected rows for Alpha then Beta, got %+v", report.Rows)
+		}
+
+		beta := report.Rows[1]
+		if len(beta.Documents) != 2 || beta.Documents[0].ID != "3" {
+			t.Fatalf("expected Beta's documents oldest due first, got %+v", beta.Documents)
+		}
+		if beta.Buckets[Aging31To60] != 100 || beta.Buckets[AgingOver90] != 50.5 || beta.Total != 150.5 {
+			t.Fatalf("unexpected buckets for Beta: %+v", beta.Buckets)
+		}
+
+		if report.Buckets[AgingCurrent] != 250 || report.Total != 400.5 {
+			t.Fatalf("unexpected report totals: %+v (%v)", report.Buckets, report.Total)
+		}
+
+		if !report.Ties() {
+			t.Fatalf("expected the report to tie to its control balance, difference %v", report.Difference)
+		}
+	})
+
+	t.Run("reports a difference from the control balance", func(t *testing.T) {
+		report := BuildAgingReport(ReceivablesAging, asOf, documents, []string{"Accounts Receivable"}, 500)
+
+		if report.Ties() || report.Difference != -99.5 {
+			t.Fatalf("expected a difference of -99.5, got %v", report.Difference)
+		}
+	})
+}
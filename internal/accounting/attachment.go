@@ -0,0 +1,61 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// the kinds of records to which files may be attached
+type EntityType string
+
+const (
+	InvoiceEntity  EntityType = "invoice"
+	CustomerEntity EntityType = "customer"
+)
+
+// representation of a file attached to a record
+type Attachment struct {
+	ID          string     `json:"id"`
+	EntityType  EntityType `json:"entity_type"`
+	EntityID    string     `json:"entity_id"`
+	FileName    string     `json:"file_name"`
+	ContentType string     `json:"content_type"`
+	Data        []byte     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// constructor for a new Attachment
+func NewAttachment(entityType EntityType, entityID, fileName, contentType string, data []byte) (*Attachment, error) {
+	if entityID == "" {
+		return nil, errors.New("Attachment requires the ID of the record it is attached to")
+	}
+
+	if fileName == "" {
+		return nil, errors.New("Attachment requires a file name")
+	}
+
+	return &Attachment{
+		ID:          NewID(),
+		EntityType:  entityType,
+		EntityID:    entityID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Data:        data,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+type ErrAttachmentNotFound struct {
+	ID string
+}
+
+func (e *ErrAttachmentNotFound) Error() string {
+	return fmt.Sprintf("attachment \"%s\" not found", e.ID)
+}
+
+// helper utility
+func IsAttachmentNotFound(err error) bool {
+	_, ok := err.(*ErrAttachmentNotFound)
+	return ok
+}
@@ -0,0 +1,94 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// the kinds of change recorded in the audit trail
+type AuditOperation string
+
+const (
+	AuditCreate AuditOperation = "create"
+	AuditUpdate AuditOperation = "update"
+	// an update which only moves the record within its siblings' display order
+	AuditReorder AuditOperation = "reorder"
+	AuditPost    AuditOperation = "post"
+)
+
+// the kinds of records whose changes are audited
+const (
+	AccountGroupEntity EntityType = "account_group"
+	AccountEntity      EntityType = "account"
+	JournalEntryEntity EntityType = "journal_entry"
+)
+
+// the actor recorded for changes made by the system itself, or outside any
+// signed-in user's request
+const SystemActor = "system"
+
+// an append-only record of a change to the books: who made it, when, while
+// serving which request, and the record as it was before and after
+type AuditRecord struct {
+	ID         string          `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	Operation  AuditOperation  `json:"operation"`
+	EntityType EntityType      `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"` // null for a create or post
+	After      json.RawMessage `json:"after"`
+}
+
+// constructor for a new AuditRecord of a change made by actor, capturing the
+// record as JSON before (nil if it did not exist) and after the change
+func NewAuditRecord(actor, requestID string, op AuditOperation, entityType EntityType, entityID string, before, after any) (AuditRecord, error) {
+	if entityID == "" {
+		return AuditRecord{}, errors.New("AuditRecord requires the ID of the record changed")
+	}
+
+	record := AuditRecord{
+		ID:         NewID(),
+		OccurredAt: time.Now().UTC(),
+		Actor:      actor,
+		RequestID:  requestID,
+		Operation:  op,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	var err error
+	if before != nil {
+		if record.Before, err = json.Marshal(before); err != nil {
+			return AuditRecord{}, err
+		}
+	}
+	if record.After, err = json.Marshal(after); err != nil {
+		return AuditRecord{}, err
+	}
+
+	return record, nil
+}
+
+// Actor names who the context acts for in the audit trail: the signed-in
+// user's username, or SystemActor
+func Actor(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok {
+		return user.Username
+	}
+	return SystemActor
+}
+
+// criteria for selecting audit records; zero fields match every record
+type AuditFilter struct {
+	Actor      string
+	EntityType EntityType
+	EntityID   string
+	// records which occurred within [From, To)
+	From, To time.Time
+	// the most records to return, newest first; zero for no limit
+	Limit int
+}
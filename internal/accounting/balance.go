@@ -0,0 +1,37 @@
+package accounting
+
+// the sum of debits and credits posted to a single account
+// over some span of time
+type AccountTotal struct {
+	AccountName string  `json:"account_name"`
+	Debits      float64 `json:"debits"`
+	Credits     float64 `json:"credits"`
+}
+
+// Balance reports the net of the totals from the perspective of the
+// account's normal balance; a positive result is a normal balance.
+func (t AccountTotal) Balance(normal NormalBalance) float64 {
+	if normal == CreditNormal {
+		return RoundCents(t.Credits - t.Debits)
+	}
+	return RoundCents(t.Debits - t.Credits)
+}
+
+// NetIncome reports revenues less expenses over the totals, given the
+// accounts which classify them; totals of other accounts are ignored.
+func NetIncome(accounts []*Account, totals []AccountTotal) float64 {
+	types := make(map[string]AccountType, len(accounts))
+	for _, account := range accounts {
+		types[account.Name] = account.AccountType
+	}
+
+	var netIncome float64
+	for _, total := range totals {
+		switch types[total.AccountName] {
+		case Revenue, Expense:
+			netIncome += total.Credits - total.Debits
+		}
+	}
+
+	return RoundCents(netIncome)
+}
@@ -0,0 +1,215 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// representation of a single line on a vendor bill; each line
+// is charged to an expense, asset or inventory account
+type BillLine struct {
+	AccountName string  `json:"account_name"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	ProjectID   string  `json:"project_id"` // empty if not charged to a project
+}
+
+// representation of a bill received from a vendor
+type Bill struct {
+	ID                 string       `json:"id"`
+	VendorID           string       `json:"vendor_id"`
+	Number             string       `json:"number"` // the vendor's document number
+	BillDate           time.Time    `json:"bill_date"`
+	DueDate            time.Time    `json:"due_date"`
+	Terms              PaymentTerms `json:"terms"`
+	PayableAccountName string       `json:"payable_account_name"` // the A/P control account
+	Memo               string       `json:"memo"`
+	Lines              []BillLine   `json:"lines"`
+	EntryID            string       `json:"entry_id"` // the journal entry which posted the bill
+}
+
+// representation of a payment made against a bill
+type BillPayment struct {
+	ID                  string    `json:"id"`
+	BillID              string    `json:"bill_id"`
+	PaymentDate         time.Time `json:"payment_date"`
+	CashAccountName     string    `json:"cash_account_name"`
+	Amount              float64   `json:"amount"`                // cash paid
+	DiscountTaken       float64   `json:"discount_taken"`        // early-payment discount earned
+	DiscountAccountName string    `json:"discount_account_name"` // empty when no discount is taken
+	EntryID             string    `json:"entry_id"`
+}
+
+// constructor for a new Bill
+//
+// The due date is derived from the bill date and the payment terms.
+func NewBill(
+	vendorID string,
+	number string,
+	billDate time.Time,
+	terms PaymentTerms,
+	payableAccountName string,
+	lines []BillLine,
+) (*Bill, error) {
+	if vendorID == "" {
+		return nil, errors.New("Bill requires a vendor")
+	}
+
+	if strings.TrimSpace(payableAccountName) == "" {
+		return nil, errors.New("Bill requires a payable account")
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("Bill requires at least one line")
+	}
+
+	for i, line := range lines {
+		if line.AccountName == "" {
+			return nil, fmt.Errorf("Bill line %d requires an account", i+1)
+		}
+		if line.Amount <= 0 {
+			return nil, fmt.Errorf("Bill line %d requires a positive amount", i+1)
+		}
+	}
+
+	return &Bill{
+		ID:                 NewID(),
+		VendorID:           vendorID,
+		Number:             strings.TrimSpace(number),
+		BillDate:           billDate,
+		DueDate:            terms.DueDate(billDate),
+		Terms:              terms,
+		PayableAccountName: payableAccountName,
+		Lines:              lines,
+	}, nil
+}
+
+// the sum of the bill's lines
+func (b *Bill) Total() float64 {
+	var total float64
+	for _, line := range b.Lines {
+		total += line.Amount
+	}
+	return RoundCents(total)
+}
+
+// the balance left on the bill after the given payments
+func (b *Bill) OpenBalance(payments []BillPayment) float64 {
+	open := b.Total()
+	for _, p := range payments {
+		open -= p.Amount + p.DiscountTaken
+	}
+	return RoundCents(open)
+}
+
+// JournalEntry produces the entry which posts the bill:
+// each line is debited, and the total is credited to the payable account.
+func (b *Bill) JournalEntry() JournalEntry {
+	lines := make([]JournalEntryLine, 0, len(b.Lines)+1)
+	for _, line := range b.Lines {
+		lines = append(lines, JournalEntryLine{
+			AccountName: line.AccountName,
+			Amount:      line.Amount,
+			Side:        Debit,
+			ProjectID:   line.ProjectID,
+		})
+	}
+	lines = append(lines, JournalEntryLine{
+		AccountName: b.PayableAccountName,
+		Amount:      b.Total(),
+		Side:        Credit,
+	})
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   b.BillDate,
+		Description: b.description(),
+		Lines:       lines,
+	}
+}
+
+// NewBillPayment settles `amount` of a bill from a cash account.
+//
+// If the payment settles the open balance within the terms' discount period,
+// the discount is taken automatically and posted to discountAccountName.
+func NewBillPayment(
+	bill *Bill,
+	openBalance float64,
+	paymentDate time.Time,
+	cashAccountName string,
+	amount float64,
+	discountAccountName string,
+) (*BillPayment, error) {
+	if cashAccountName == "" {
+		return nil, errors.New("BillPayment requires a cash account")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("BillPayment requires a positive amount")
+	}
+
+	if paymentDate.Before(bill.BillDate) {
+		return nil, errors.New("BillPayment cannot precede the bill date")
+	}
+
+	payment := BillPayment{
+		ID:              NewID(),
+		BillID:          bill.ID,
+		PaymentDate:     paymentDate,
+		CashAccountName: cashAccountName,
+		Amount:          RoundCents(amount),
+	}
+
+	// the discount is only earned when the discounted balance is paid in full
+	discount := bill.Terms.DiscountFor(bill.BillDate, paymentDate, openBalance)
+	if discount > 0 && payment.Amount >= RoundCents(openBalance-discount) {
+		payment.DiscountTaken = RoundCents(openBalance - payment.Amount)
+		if payment.DiscountTaken > 0 {
+			payment.DiscountAccountName = discountAccountName
+		}
+	}
+
+	if payment.DiscountTaken > 0 && discountAccountName == "" {
+		return nil, errors.New("BillPayment requires a discount account to take a discount")
+	}
+
+	if payment.DiscountTaken < 0 || RoundCents(payment.Amount+payment.DiscountTaken) > openBalance {
+		return nil, &ErrOverpayment{DocumentID: bill.ID, OpenBalance: openBalance, Amount: payment.Amount}
+	}
+
+	return &payment, nil
+}
+
+// JournalEntry produces the entry which posts the payment: the payable account
+// is debited for the amount settled, cash is credited for the amount paid, and
+// any discount taken is credited to the discount account.
+func (p *BillPayment) JournalEntry(bill *Bill) JournalEntry {
+	lines := []JournalEntryLine{
+		{AccountName: bill.PayableAccountName, Amount: RoundCents(p.Amount + p.DiscountTaken), Side: Debit},
+		{AccountName: p.CashAccountName, Amount: p.Amount, Side: Credit},
+	}
+
+	if p.DiscountTaken > 0 {
+		lines = append(lines, JournalEntryLine{
+			AccountName: p.DiscountAccountName,
+			Amount:      p.DiscountTaken,
+			Side:        Credit,
+		})
+	}
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   p.PaymentDate,
+		Description: "Payment of " + bill.description(),
+		Lines:       lines,
+	}
+}
+
+func (b *Bill) description() string {
+	if b.Number != "" {
+		return "Bill " + b.Number
+	}
+	return "Bill " + b.ID
+}
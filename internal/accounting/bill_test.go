@@ -0,0 +1,142 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePaymentTerms(t *testing.T) {
+	t.Run("parses net terms", func(t *testing.T) {
+		terms, err := ParsePaymentTerms("Net 30")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if terms != (PaymentTerms{NetDays: 30}) {
+			t.Fatalf("expected Net 30, got %+v", terms)
+		}
+	})
+
+	t.Run("parses discount terms", func(t *testing.T) {
+		terms, err := ParsePaymentTerms("2/10 Net 30")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if terms != (PaymentTerms{DiscountPercent: 2, DiscountDays: 10, NetDays: 30}) {
+			t.Fatalf("expected 2/10 Net 30, got %+v", terms)
+		}
+		if terms.String() != "2/10 Net 30" {
+			t.Fatalf("expected terms to format as \"2/10 Net 30\", got %q", terms.String())
+		}
+	})
+
+	t.Run("fails on malformed terms", func(t *testing.T) {
+		for _, s := range []string{"Net", "Net thirty", "2-10 Net 30", "20/40 Net 30", "Net 30 days"} {
+			if _, err := ParsePaymentTerms(s); err == nil {
+				t.Fatalf("expected an error parsing %q, didn't receive one", s)
+			}
+		}
+	})
+}
+
+func TestNewBill(t *testing.T) {
+	billDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	terms := PaymentTerms{DiscountPercent: 2, DiscountDays: 10, NetDays: 30}
+
+	t.Run("derives the due date from the terms", func(t *testing.T) {
+		bill, err := NewBill("vendor", "INV-1", billDate, terms, "Accounts Payable", []BillLine{
+			{AccountName: "Supplies", Amount: 100},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+		if !bill.DueDate.Equal(want) {
+			t.Fatalf("expected due date %v, got %v", want, bill.DueDate)
+		}
+	})
+
+	t.Run("fails without lines", func(t *testing.T) {
+		if _, err := NewBill("vendor", "INV-1", billDate, terms, "Accounts Payable", nil); err == nil {
+			t.Fatalf("expected an error, didn't receive one")
+		}
+	})
+
+	t.Run("fails with a non-positive line", func(t *testing.T) {
+		_, err := NewBill("vendor", "INV-1", billDate, terms, "Accounts Payable", []BillLine{
+			{AccountName: "Supplies", Amount: 0},
+		})
+		if err == nil {
+			t.Fatalf("expected an error, didn't receive one")
+		}
+	})
+
+	t.Run("posts a balanced entry crediting the payable account", func(t *testing.T) {
+		bill, _ := NewBill("vendor", "INV-1", billDate, terms, "Accounts Payable", []BillLine{
+			{AccountName: "Supplies", Amount: 60.10},
+			{AccountName: "Inventory", Amount: 39.95},
+		})
+
+		je := bill.JournalEntry()
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+
+		last := je.Lines[len(je.Lines)-1]
+		if last.AccountName != "Accounts Payable" || last.Side != Credit || last.Amount != 100.05 {
+			t.Fatalf("expected a 100.05 credit to Accounts Payable, got %+v", last)
+		}
+	})
+}
+
+func TestNewBillPayment(t *testing.T) {
+	billDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	bill, _ := NewBill("vendor", "INV-1", billDate, PaymentTerms{DiscountPercent: 2, DiscountDays: 10, NetDays: 30}, "Accounts Payable", []BillLine{
+		{AccountName: "Supplies", Amount: 500},
+	})
+
+	t.Run("takes the discount when paid in full within the discount period", func(t *testing.T) {
+		payment, err := NewBillPayment(bill, 500, billDate.AddDate(0, 0, 10), "Cash", 490, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if payment.DiscountTaken != 10 {
+			t.Fatalf("expected a discount of 10, got %v", payment.DiscountTaken)
+		}
+
+		je := payment.JournalEntry(bill)
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if len(je.Lines) != 3 {
+			t.Fatalf("expected 3 lines, got %d", len(je.Lines))
+		}
+	})
+
+	t.Run("takes no discount after the discount period", func(t *testing.T) {
+		payment, err := NewBillPayment(bill, 500, billDate.AddDate(0, 0, 11), "Cash", 490, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if payment.DiscountTaken != 0 {
+			t.Fatalf("expected no discount, got %v", payment.DiscountTaken)
+		}
+	})
+
+	t.Run("takes no discount on a partial payment", func(t *testing.T) {
+		payment, err := NewBillPayment(bill, 500, billDate.AddDate(0, 0, 5), "Cash", 200, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if payment.DiscountTaken != 0 {
+			t.Fatalf("expected no discount, got %v", payment.DiscountTaken)
+		}
+	})
+
+	t.Run("fails on overpayment", func(t *testing.T) {
+		_, err := NewBillPayment(bill, 500, billDate.AddDate(0, 0, 20), "Cash", 500.01, "Purchase Discounts")
+		if !IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error, received %v", err)
+		}
+	})
+}
@@ -0,0 +1,11 @@
+package accounting
+
+// representation of the settings of the book; the business identity
+// printed on invoices and statements
+type BookSettings struct {
+	BusinessName string `json:"business_name"`
+	Address      string `json:"address"` // may span multiple lines
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Logo         []byte `json:"-"` // JPEG; empty -> no logo
+}
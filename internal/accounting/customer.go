@@ -0,0 +1,29 @@
+package accounting
+
+import (
+	"errors"
+	"strings"
+)
+
+// representation of a customer; a party to whom invoices are issued
+type Customer struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	Email        string       `json:"email"`
+	Phone        string       `json:"phone"`
+	Address      string       `json:"address"`
+	DefaultTerms PaymentTerms `json:"default_terms"` // applied to new invoices unless overridden
+}
+
+// constructor for a new Customer
+func NewCustomer(name string, defaultTerms PaymentTerms) (*Customer, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("Customer requires a non-empty string for its Name attribute")
+	}
+
+	return &Customer{
+		ID:           NewID(),
+		Name:         strings.TrimSpace(name),
+		DefaultTerms: defaultTerms,
+	}, nil
+}
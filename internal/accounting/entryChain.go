@@ -0,0 +1,108 @@
+package accounting
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// a posted entry as stored, with the hashes chaining it to the entry before
+// it in ID (ULID) order
+type ChainLink struct {
+	Entry    JournalEntry `json:"entry"`
+	PrevHash string       `json:"prev_hash"` // empty for the first entry
+	Hash     string       `json:"hash"`
+}
+
+// the result of walking the hash chain over every posted entry
+type ChainReport struct {
+	// the entries walked, up to and including any broken link
+	Entries int `json:"entries"`
+	// the first entry whose content or link no longer matches; nil if intact
+	Break *ErrChainBroken `json:"break,omitempty"`
+}
+
+// Intact reports whether every entry matched its hash and link
+func (r ChainReport) Intact() bool {
+	return r.Break == nil
+}
+
+// the content of an entry which its hash covers, in a fixed form
+type canonicalEntry struct {
+	ID             string          `json:"id"`
+	Timestamp      string          `json:"timestamp"`
+	Description    string          `json:"description"`
+	CrossReference string          `json:"cross_reference"`
+	Lines          []canonicalLine `json:"lines"`
+}
+
+type canonicalLine struct {
+	AccountName string    `json:"account_name"`
+	Amount      string    `json:"amount"`
+	Side        EntrySide `json:"side"`
+	ProjectID   string    `json:"project_id"`
+}
+
+// EntryHash is the hex SHA-256 of the previous entry's hash followed by the
+// entry's canonical content: its ID, timestamp to the second, description,
+// cross reference and lines in order, with amounts to the cent
+func EntryHash(je JournalEntry, prevHash string) string {
+	canonical := canonicalEntry{
+		ID:             je.ID,
+		Timestamp:      je.Timestamp.UTC().Truncate(time.Second).Format(time.RFC3339),
+		Description:    je.Description,
+		CrossReference: je.CrossReference.String,
+		Lines:          make([]canonicalLine, len(je.Lines)),
+	}
+	for i, line := range je.Lines {
+		canonical.Lines[i] = canonicalLine{
+			AccountName: line.AccountName,
+			Amount:      strconv.FormatFloat(RoundCents(line.Amount), 'f', 2, 64),
+			Side:        line.Side,
+			ProjectID:   line.ProjectID,
+		}
+	}
+
+	// marshalling fixed structs of strings cannot fail
+	content, _ := json.Marshal(canonical)
+
+	sum := sha256.New()
+	sum.Write([]byte(prevHash))
+	sum.Write([]byte{'\n'})
+	sum.Write(content)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// Verify checks the link against the hash of the entry before it, and the
+// entry's content against its own hash, returning the first mismatch. An
+// entry without a hash is a break, as when its hash was cleared.
+func (l ChainLink) Verify(prevHash string) *ErrChainBroken {
+	if l.Hash == "" {
+		return &ErrChainBroken{EntryID: l.Entry.ID, Reason: "it has no hash"}
+	}
+	if l.PrevHash != prevHash {
+		return &ErrChainBroken{EntryID: l.Entry.ID, Reason: "its link does not match the previous entry's hash"}
+	}
+	if EntryHash(l.Entry, l.PrevHash) != l.Hash {
+		return &ErrChainBroken{EntryID: l.Entry.ID, Reason: "its content does not match its hash"}
+	}
+	return nil
+}
+
+// VerifyChain walks the links in ID order, reporting the first whose link
+// to the previous entry's hash, or whose hash of its own content, no longer
+// matches, as when the entry was edited, inserted or deleted behind the
+// application's back
+func VerifyChain(links []ChainLink) ChainReport {
+	prevHash := ""
+	for i, link := range links {
+		if broken := link.Verify(prevHash); broken != nil {
+			return ChainReport{Entries: i + 1, Break: broken}
+		}
+		prevHash = link.Hash
+	}
+
+	return ChainReport{Entries: len(links)}
+}
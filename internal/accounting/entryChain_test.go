@@ -0,0 +1,86 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryChain(t *testing.T) {
+	entry := func(description string, amount float64) JournalEntry {
+		return JournalEntry{
+			ID:          NewID(),
+			Timestamp:   time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC),
+			Description: description,
+			Lines: []JournalEntryLine{
+				{AccountName: "Cash", Amount: amount, Side: Debit},
+				{AccountName: "Sales", Amount: amount, Side: Credit},
+			},
+		}
+	}
+	chain := func(entries ...JournalEntry) []ChainLink {
+		links := make([]ChainLink, len(entries))
+		prevHash := ""
+		for i, je := range entries {
+			links[i] = ChainLink{Entry: je, PrevHash: prevHash, Hash: EntryHash(je, prevHash)}
+			prevHash = links[i].Hash
+		}
+		return links
+	}
+
+	t.Run("hashes the stored form of the content and its link", func(t *testing.T) {
+		je := entry("Sale", 100)
+		stored := je
+		stored.Timestamp = je.Timestamp.In(time.FixedZone("EST", -5*3600))
+		stored.Lines = []JournalEntryLine{
+			{AccountName: "Cash", Amount: 100.001, Side: Debit},
+			{AccountName: "Sales", Amount: 100, Side: Credit},
+		}
+
+		if EntryHash(je, "") != EntryHash(stored, "") {
+			t.Fatal("expected the same hash regardless of time zone or sub-cent amounts")
+		}
+		if EntryHash(je, "") == EntryHash(je, "abc") {
+			t.Fatal("expected the hash to cover the link")
+		}
+		if EntryHash(je, "") == EntryHash(entry("Sale", 101), "") {
+			t.Fatal("expected the hash to cover the amounts")
+		}
+	})
+
+	t.Run("passes an intact chain", func(t *testing.T) {
+		report := VerifyChain(chain(entry("Sale", 100), entry("Sale", 50), entry("Refund", 20)))
+		if !report.Intact() || report.Entries != 3 {
+			t.Fatalf("expected 3 intact entries, got %+v", report)
+		}
+	})
+
+	t.Run("reports the first entry edited", func(t *testing.T) {
+		links := chain(entry("Sale", 100), entry("Sale", 50), entry("Refund", 20))
+		links[1].Entry.Lines[0].Amount = 5
+
+		report := VerifyChain(links)
+		if report.Intact() || report.Break.EntryID != links[1].Entry.ID || report.Entries != 2 {
+			t.Fatalf("expected the second entry to break the chain, got %+v", report)
+		}
+	})
+
+	t.Run("reports the entry after one deleted", func(t *testing.T) {
+		links := chain(entry("Sale", 100), entry("Sale", 50), entry("Refund", 20))
+		links = append(links[:1], links[2:]...)
+
+		report := VerifyChain(links)
+		if report.Intact() || report.Break.EntryID != links[1].Entry.ID {
+			t.Fatalf("expected the entry after the deleted one to break the chain, got %+v", report)
+		}
+	})
+
+	t.Run("reports an entry without a hash", func(t *testing.T) {
+		links := chain(entry("Sale", 100))
+		links[0].Hash = ""
+
+		report := VerifyChain(links)
+		if report.Intact() || report.Break.EntryID != links[0].Entry.ID {
+			t.Fatalf("expected the unhashed entry to break the chain, got %+v", report)
+		}
+	})
+}
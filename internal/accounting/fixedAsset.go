@@ -0,0 +1,460 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// enumeration of depreciation methods
+type DepreciationMethod string
+
+const (
+	StraightLine     DepreciationMethod = "Straight Line"
+	DecliningBalance DepreciationMethod = "Declining Balance"
+	MACRS            DepreciationMethod = "MACRS"
+)
+
+// MACRS GDS percentages under the half-year convention, by recovery period (IRS Pub. 946, Table A-1)
+var macrsHalfYearTables = map[int][]float64{
+	3:  {33.33, 44.45, 14.81, 7.41},
+	5:  {20.00, 32.00, 19.20, 11.52, 11.52, 5.76},
+	7:  {14.29, 24.49, 17.49, 12.49, 8.93, 8.92, 8.93, 4.46},
+	10: {10.00, 18.00, 14.40, 11.52, 9.22, 7.37, 6.55, 6.55, 6.56, 6.55, 3.28},
+	15: {5.00, 9.50, 8.55, 7.70, 6.93, 6.23, 5.90, 5.90, 5.91, 5.90, 5.91, 5.90, 5.91, 5.90, 5.91, 2.95},
+	20: {3.750, 7.219, 6.677, 6.177, 5.713, 5.285, 4.888, 4.522, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 2.231},
+}
+
+// representation of a fixed asset
+type FixedAsset struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Cost          float64            `json:"cost"`
+	SalvageValue  float64            `json:"salvage_value"` // ignored by MACRS
+	InServiceDate time.Time          `json:"in_service_date"`
+	UsefulLife    int                `json:"useful_life"` // in years; for MACRS, the recovery period
+	Method        DepreciationMethod `json:"method"`
+	// the multiple of the straight-line rate used by declining balance, e.g. 2 for double-declining
+	DecliningBalanceFactor float64 `json:"declining_balance_factor"`
+
+	AssetAccountName                   string `json:"asset_account_name"`
+	AccumulatedDepreciationAccountName string `json:"accumulated_depreciation_account_name"`
+	DepreciationExpenseAccountName     string `json:"depreciation_expense_account_name"` // required to post depreciation
+
+	Disposal *FixedAssetDisposal `json:"disposal"` // nil while the asset is held
+}
+
+// a record of one period's depreciation having been posted for an asset
+type DepreciationPosting struct {
+	AssetID   string    `json:"asset_id"`
+	PeriodEnd time.Time `json:"period_end"`
+	Amount    float64   `json:"amount"`
+	EntryID   string    `json:"entry_id"`
+}
+
+// representation of the sale or retirement of a fixed asset
+type FixedAssetDisposal struct {
+	AssetID                 string    `json:"asset_id"`
+	DisposalDate            time.Time `json:"disposal_date"`
+	Proceeds                float64   `json:"proceeds"`
+	ProceedsAccountName     string    `json:"proceeds_account_name"` // empty when there are no proceeds
+	GainLossAccountName     string    `json:"gain_loss_account_name"`
+	AccumulatedDepreciation float64   `json:"accumulated_depreciation"` // removed from the books
+	GainOrLoss              float64   `json:"gain_or_loss"`             // positive for a gain
+	EntryID                 string    `json:"entry_id"`
+}
+
+// one month of an asset's depreciation schedule
+type DepreciationPeriod struct {
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"` // inclusive; the last day of the month
+	Depreciation float64   `json:"depreciation"`
+	Accumulated  float64   `json:"accumulated"`
+	BookValue    float64   `json:"book_value"`
+}
+
+// constructor for a new FixedAsset; the asset is validated before it is returned
+func NewFixedAsset(
+	name string,
+	cost float64,
+	salvageValue float64,
+	inServiceDate time.Time,
+	usefulLife int,
+	method DepreciationMethod,
+	assetAccountName string,
+	accumulatedDepreciationAccountName string,
+) (*FixedAsset, error) {
+	asset := FixedAsset{
+		ID:                                 NewID(),
+		Name:                               strings.TrimSpace(name),
+		Cost:                               RoundCents(cost),
+		SalvageValue:                       RoundCents(salvageValue),
+		InServiceDate:                      inServiceDate,
+		UsefulLife:                         usefulLife,
+		Method:                             method,
+		AssetAccountName:                   assetAccountName,
+		AccumulatedDepreciationAccountName: accumulatedDepreciationAccountName,
+	}
+
+	if method == DecliningBalance {
+		asset.DecliningBalanceFactor = 2
+	}
+
+	if err := asset.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &asset, nil
+}
+
+// Validate checks that the asset can be depreciated
+func (a *FixedAsset) Validate() error {
+	if a.Name == "" {
+		return errors.New("FixedAsset requires a non-empty string for its Name attribute")
+	}
+
+	if a.Cost <= 0 {
+		return errors.New("FixedAsset requires a positive cost")
+	}
+
+	if a.SalvageValue < 0 || a.SalvageValue >= a.Cost {
+		return errors.New("FixedAsset requires a salvage value of at least zero and less than its cost")
+	}
+
+	if a.AssetAccountName == "" || a.AccumulatedDepreciationAccountName == "" {
+		return errors.New("FixedAsset requires an asset account and an accumulated depreciation account")
+	}
+
+	switch a.Method {
+	case StraightLine:
+	case DecliningBalance:
+		if a.DecliningBalanceFactor <= 0 {
+			return errors.New("FixedAsset requires a positive declining balance factor")
+		}
+	case MACRS:
+		if _, ok := macrsHalfYearTables[a.UsefulLife]; !ok {
+			return fmt.Errorf("no MACRS table for a recovery period of %d years", a.UsefulLife)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown depreciation method %q", a.Method)
+	}
+
+	if a.UsefulLife <= 0 {
+		return errors.New("FixedAsset requires a useful life of at least one year")
+	}
+
+	return nil
+}
+
+// the amount to be depreciated over the asset's life
+func (a *FixedAsset) DepreciableBase() float64 {
+	if a.Method == MACRS {
+		return a.Cost
+	}
+	return RoundCents(a.Cost - a.SalvageValue)
+}
+
+// Schedule produces the asset's full monthly depreciation schedule, beginning
+// with the month it was placed in service (a full-month convention).
+//
+// MACRS assets take the table percentage for each tax year, spread over the
+// months of that calendar year the asset was in service.
+func (a *FixedAsset) Schedule() ([]DepreciationPeriod, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	var amounts []float64
+	switch a.Method {
+	case StraightLine:
+		amounts = a.straightLineAmounts()
+	case DecliningBalance:
+		amounts = a.decliningBalanceAmounts()
+	case MACRS:
+		amounts = a.macrsAmounts()
+	}
+
+	// the final period absorbs rounding, so the schedule depreciates exactly the base
+	var sum float64
+	for _, amount := range amounts[:len(amounts)-1] {
+		sum += amount
+	}
+	amounts[len(amounts)-1] = RoundCents(a.DepreciableBase() - sum)
+
+	schedule := make([]DepreciationPeriod, len(amounts))
+	start := firstOfMonth(a.InServiceDate)
+	var accumulated float64
+	for i, amount := range amounts {
+		periodStart := start.AddDate(0, i, 0)
+		accumulated = RoundCents(accumulated + amount)
+		schedule[i] = DepreciationPeriod{
+			PeriodStart:  periodStart,
+			PeriodEnd:    periodStart.AddDate(0, 1, -1),
+			Depreciation: amount,
+			Accumulated:  accumulated,
+			BookValue:    RoundCents(a.Cost - accumulated),
+		}
+	}
+
+	return schedule, nil
+}
+
+// the depreciation accumulated through the end of asOf, per the schedule
+func (a *FixedAsset) AccumulatedDepreciationAsOf(asOf time.Time) (float64, error) {
+	schedule, err := a.Schedule()
+	if err != nil {
+		return 0, err
+	}
+
+	if a.Disposal != nil && !a.Disposal.DisposalDate.After(asOf) {
+		return a.Disposal.AccumulatedDepreciation, nil
+	}
+
+	var accumulated float64
+	for _, period := range schedule {
+		if period.PeriodEnd.After(asOf) {
+			break
+		}
+		accumulated = period.Accumulated
+	}
+
+	return accumulated, nil
+}
+
+// DepreciationEntry produces the entry posting a period's depreciation for the given assets:
+// each asset's expense account is debited, and its accumulated depreciation account credited.
+// Assets with nothing to depreciate in the period are omitted.
+func DepreciationEntry(periodEnd time.Time, assets []*FixedAsset, amounts []float64) JournalEntry {
+	je := JournalEntry{
+		ID:          NewID(),
+		Timestamp:   periodEnd,
+		Description: "Depreciation for " + periodEnd.Format("2006-01"),
+	}
+
+	for i, asset := range assets {
+		if amounts[i] <= 0 {
+			continue
+		}
+		je.Lines = append(je.Lines,
+			JournalEntryLine{AccountName: asset.DepreciationExpenseAccountName, Amount: amounts[i], Side: Debit},
+			JournalEntryLine{AccountName: asset.AccumulatedDepreciationAccountName, Amount: amounts[i], Side: Credit},
+		)
+	}
+
+	return je
+}
+
+// NewFixedAssetDisposal records the disposal of an asset on which `accumulated`
+// depreciation has been posted, for the given sale proceeds.
+func NewFixedAssetDisposal(
+	asset *FixedAsset,
+	disposalDate time.Time,
+	proceeds float64,
+	proceedsAccountName string,
+	gainLossAccountName string,
+	accumulated float64,
+) (*FixedAssetDisposal, error) {
+	if asset.Disposal != nil {
+		return nil, fmt.Errorf("fixed asset \"%s\" was already disposed of on %s", asset.Name, asset.Disposal.DisposalDate.Format(time.DateOnly))
+	}
+
+	if disposalDate.Before(asset.InServiceDate) {
+		return nil, errors.New("FixedAssetDisposal cannot precede the in-service date")
+	}
+
+	if proceeds < 0 {
+		return nil, errors.New("FixedAssetDisposal requires proceeds of at least zero")
+	}
+
+	if proceeds > 0 && proceedsAccountName == "" {
+		return nil, errors.New("FixedAssetDisposal requires an account to receive the proceeds")
+	}
+
+	if gainLossAccountName == "" {
+		return nil, errors.New("FixedAssetDisposal requires a gain or loss account")
+	}
+
+	return &FixedAssetDisposal{
+		AssetID:                 asset.ID,
+		DisposalDate:            disposalDate,
+		Proceeds:                RoundCents(proceeds),
+		ProceedsAccountName:     proceedsAccountName,
+		GainLossAccountName:     gainLossAccountName,
+		AccumulatedDepreciation: RoundCents(accumulated),
+		GainOrLoss:              RoundCents(proceeds - (asset.Cost - accumulated)),
+	}, nil
+}
+
+// JournalEntry produces the entry which removes the asset from the books: the
+// proceeds and accumulated depreciation are debited, the cost is credited,
+// and the difference is posted as a gain (credit) or loss (debit).
+func (d *FixedAssetDisposal) JournalEntry(asset *FixedAsset) JournalEntry {
+	var lines []JournalEntryLine
+
+	if d.Proceeds > 0 {
+		lines = append(lines, JournalEntryLine{AccountName: d.ProceedsAccountName, Amount: d.Proceeds, Side: Debit})
+	}
+	if d.AccumulatedDepreciation > 0 {
+		lines = append(lines, JournalEntryLine{AccountName: asset.AccumulatedDepreciationAccountName, Amount: d.AccumulatedDepreciation, Side: Debit})
+	}
+	switch {
+	case d.GainOrLoss < 0:
+		lines = append(lines, JournalEntryLine{AccountName: d.GainLossAccountName, Amount: -d.GainOrLoss, Side: Debit})
+	case d.GainOrLoss > 0:
+		lines = append(lines, JournalEntryLine{AccountName: d.GainLossAccountName, Amount: d.GainOrLoss, Side: Credit})
+	}
+	lines = append(lines, JournalEntryLine{AccountName: asset.AssetAccountName, Amount: asset.Cost, Side: Credit})
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   d.DisposalDate,
+		Description: "Disposal of " + asset.Name,
+		Lines:       lines,
+	}
+}
+
+func (a *FixedAsset) straightLineAmounts() []float64 {
+	months := a.UsefulLife * 12
+	monthly := RoundCents(a.DepreciableBase() / float64(months))
+
+	amounts := make([]float64, months)
+	for i := range amounts {
+		amounts[i] = monthly
+	}
+	return amounts
+}
+
+// declining balance on the book value, switching to straight-line over
+// the remaining life once that yields more, and never below salvage
+func (a *FixedAsset) decliningBalanceAmounts() []float64 {
+	months := a.UsefulLife * 12
+	monthlyRate := a.DecliningBalanceFactor / float64(a.UsefulLife) / 12
+
+	amounts := make([]float64, months)
+	bookValue := a.Cost
+	for i := range amounts {
+		declining := bookValue * monthlyRate
+		straight := (bookValue - a.SalvageValue) / float64(months-i)
+
+		amount := RoundCents(max(declining, straight))
+		amount = min(amount, RoundCents(bookValue-a.SalvageValue))
+
+		amounts[i] = amount
+		bookValue -= amount
+	}
+	return amounts
+}
+
+func (a *FixedAsset) macrsAmounts() []float64 {
+	table := macrsHalfYearTables[a.UsefulLife]
+
+	var amounts []float64
+	for year, percent := range table {
+		yearAmount := RoundCents(a.Cost * percent / 100)
+
+		// the first tax year runs from the in-service month through December
+		months := 12
+		if year == 0 {
+			months = 13 - int(a.InServiceDate.Month())
+		}
+
+		monthly := RoundCents(yearAmount / float64(months))
+		for m := range months {
+			if m == months-1 {
+				amounts = append(amounts, RoundCents(yearAmount-monthly*float64(months-1)))
+			} else {
+				amounts = append(amounts, monthly)
+			}
+		}
+	}
+	return amounts
+}
+
+// a line of the fixed asset register
+type FixedAssetRegisterLine struct {
+	Asset                   FixedAsset `json:"asset"`
+	Cost                    float64    `json:"cost"`
+	AccumulatedDepreciation float64    `json:"accumulated_depreciation"`
+	NetBookValue            float64    `json:"net_book_value"`
+}
+
+// representation of the fixed asset register as of a date
+type FixedAssetRegister struct {
+	AsOf                    time.Time                `json:"as_of"`
+	Lines                   []FixedAssetRegisterLine `json:"lines"`
+	Cost                    float64                  `json:"cost"`
+	AccumulatedDepreciation float64                  `json:"accumulated_depreciation"`
+	NetBookValue            float64                  `json:"net_book_value"`
+}
+
+// BuildFixedAssetRegister reports the cost, accumulated depreciation and net
+// book value of every asset in service, and not yet disposed of, as of the end of asOf
+func BuildFixedAssetRegister(assets []*FixedAsset, asOf time.Time) (FixedAssetRegister, error) {
+	register := FixedAssetRegister{AsOf: asOf, Lines: []FixedAssetRegisterLine{}}
+
+	for _, asset := range assets {
+		if asset.InServiceDate.After(asOf) {
+			continue
+		}
+		if asset.Disposal != nil && !asset.Disposal.DisposalDate.After(asOf) {
+			continue
+		}
+
+		accumulated, err := asset.AccumulatedDepreciationAsOf(asOf)
+		if err != nil {
+			return FixedAssetRegister{}, err
+		}
+
+		line := FixedAssetRegisterLine{
+			Asset:                   *asset,
+			Cost:                    asset.Cost,
+			AccumulatedDepreciation: accumulated,
+			NetBookValue:            RoundCents(asset.Cost - accumulated),
+		}
+		register.Lines = append(register.Lines, line)
+
+		register.Cost = RoundCents(register.Cost + line.Cost)
+		register.AccumulatedDepreciation = RoundCents(register.AccumulatedDepreciation + line.AccumulatedDepreciation)
+		register.NetBookValue = RoundCents(register.NetBookValue + line.NetBookValue)
+	}
+
+	return register, nil
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+type ErrFixedAssetNotFound struct {
+	ID string
+}
+
+func (e *ErrFixedAssetNotFound) Error() string {
+	return fmt.Sprintf("fixed asset \"%s\" not found", e.ID)
+}
+
+// helper utility
+func IsFixedAssetNotFound(err error) bool {
+	_, ok := err.(*ErrFixedAssetNotFound)
+	return ok
+}
+
+// returned on disposing of an asset as of a date earlier than the end of
+// a period it has already been depreciated for
+type ErrDisposalBeforeDepreciation struct {
+	Name               string
+	DisposalDate       time.Time
+	DepreciatedThrough time.Time
+}
+
+func (e *ErrDisposalBeforeDepreciation) Error() string {
+	return fmt.Sprintf("fixed asset \"%s\" is depreciated through %s; it cannot be disposed of as of %s", e.Name, e.DepreciatedThrough.Format(time.DateOnly), e.DisposalDate.Format(time.DateOnly))
+}
+
+// helper utility
+func IsDisposalBeforeDepreciation(err error) bool {
+	_, ok := err.(*ErrDisposalBeforeDepreciation)
+	return ok
+}
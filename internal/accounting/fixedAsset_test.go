@@ -0,0 +1,170 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedAssetSchedule(t *testing.T) {
+	inService := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("straight line depreciates the base evenly by month", func(t *testing.T) {
+		asset, err := NewFixedAsset("Truck", 36000, 6000, inService, 5, StraightLine, "Vehicles", "Accumulated Depreciation")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		schedule, err := asset.Schedule()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(schedule) != 60 {
+			t.Fatalf("expected 60 monthly periods, got %d", len(schedule))
+		}
+		if schedule[0].Depreciation != 500 || !schedule[0].PeriodStart.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected 500 in April 2025, got %+v", schedule[0])
+		}
+		if last := schedule[59]; last.Accumulated != 30000 || last.BookValue != 6000 {
+			t.Fatalf("expected to finish at salvage, got %+v", last)
+		}
+	})
+
+	t.Run("declining balance never depreciates below salvage", func(t *testing.T) {
+		asset, err := NewFixedAsset("Lathe", 10000, 1000, inService, 5, DecliningBalance, "Equipment", "Accumulated Depreciation")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		schedule, err := asset.Schedule()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// double-declining: 40% a year, so the first month is 10000 * 0.4 / 12
+		if schedule[0].Depreciation != 333.33 {
+			t.Fatalf("expected 333.33 in the first month, got %v", schedule[0].Depreciation)
+		}
+		for _, period := range schedule {
+			if period.BookValue < asset.SalvageValue || period.Depreciation < 0 {
+				t.Fatalf("unexpected period %+v", period)
+			}
+		}
+		if last := schedule[len(schedule)-1]; last.BookValue != 1000 {
+			t.Fatalf("expected to finish at salvage, got %+v", last)
+		}
+	})
+
+	t.Run("MACRS follows the half-year table by tax year", func(t *testing.T) {
+		asset, err := NewFixedAsset("Computer", 5000, 0, inService, 5, MACRS, "Equipment", "Accumulated Depreciation")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		schedule, err := asset.Schedule()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// 20% in 2025 over April-December; 32% in 2026
+		yearTotals := make(map[int]float64)
+		for _, period := range schedule {
+			yearTotals[period.PeriodStart.Year()] = RoundCents(yearTotals[period.PeriodStart.Year()] + period.Depreciation)
+		}
+		if yearTotals[2025] != 1000 || yearTotals[2026] != 1600 || yearTotals[2030] != 288 {
+			t.Fatalf("unexpected yearly totals %v", yearTotals)
+		}
+		if last := schedule[len(schedule)-1]; last.BookValue != 0 {
+			t.Fatalf("expected to fully depreciate, got %+v", last)
+		}
+	})
+
+	t.Run("rejects an unknown MACRS recovery period", func(t *testing.T) {
+		if _, err := NewFixedAsset("Thing", 5000, 0, inService, 6, MACRS, "Equipment", "Accumulated Depreciation"); err == nil {
+			t.Fatalf("expected an error, didn't receive one")
+		}
+	})
+}
+
+func TestBuildFixedAssetRegister(t *testing.T) {
+	truck, _ := NewFixedAsset("Truck", 36000, 6000, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 5, StraightLine, "Vehicles", "Accumulated Depreciation")
+	future, _ := NewFixedAsset("Van", 20000, 0, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 5, StraightLine, "Vehicles", "Accumulated Depreciation")
+
+	register, err := BuildFixedAssetRegister([]*FixedAsset{truck, future}, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(register.Lines) != 1 {
+		t.Fatalf("expected only assets in service, got %d lines", len(register.Lines))
+	}
+	if register.AccumulatedDepreciation != 3000 || register.NetBookValue != 33000 {
+		t.Fatalf("expected 3000 accumulated and 33000 net, got %+v", register)
+	}
+}
+
+func TestFixedAssetDisposal(t *testing.T) {
+	truck, _ := NewFixedAsset("Truck", 36000, 6000, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 5, StraightLine, "Vehicles", "Accumulated Depreciation")
+	disposed := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	t.Run("posts a gain when proceeds exceed book value", func(t *testing.T) {
+		disposal, err := NewFixedAssetDisposal(truck, disposed, 30000, "Cash", "Gain or Loss", 9000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if disposal.GainOrLoss != 3000 {
+			t.Fatalf("expected a 3000 gain, got %.2f", disposal.GainOrLoss)
+		}
+
+		je := disposal.JournalEntry(truck)
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		last := je.Lines[len(je.Lines)-1]
+		if last.AccountName != "Vehicles" || last.Amount != 36000 || last.Side != Credit {
+			t.Fatalf("expected the cost to be credited, got %+v", last)
+		}
+	})
+
+	t.Run("posts a loss on a retirement without proceeds", func(t *testing.T) {
+		disposal, err := NewFixedAssetDisposal(truck, disposed, 0, "", "Gain or Loss", 9000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		je := disposal.JournalEntry(truck)
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if len(je.Lines) != 3 || je.Lines[1].AccountName != "Gain or Loss" || je.Lines[1].Amount != 27000 || je.Lines[1].Side != Debit {
+			t.Fatalf("expected a 27000 loss, got %+v", je.Lines)
+		}
+	})
+
+	t.Run("requires an account for proceeds", func(t *testing.T) {
+		if _, err := NewFixedAssetDisposal(truck, disposed, 100, "", "Gain or Loss", 0); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+
+	t.Run("freezes accumulated depreciation and leaves the register", func(t *testing.T) {
+		sold := *truck
+		sold.Disposal, _ = NewFixedAssetDisposal(truck, disposed, 30000, "Cash", "Gain or Loss", 9000)
+
+		accumulated, err := sold.AccumulatedDepreciationAsOf(time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if accumulated != 9000 {
+			t.Fatalf("expected 9000 accumulated at disposal, got %.2f", accumulated)
+		}
+
+		register, err := BuildFixedAssetRegister([]*FixedAsset{&sold}, time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(register.Lines) != 0 {
+			t.Fatalf("expected disposed assets to be omitted, got %d lines", len(register.Lines))
+		}
+	})
+}
@@ -3,19 +3,29 @@ package accounting
 import (
 	// std
 	"math/rand"
+	"sync"
 	"time"
 
 	// external
 	ulid "github.com/oklog/ulid/v2"
 )
 
+// entropy shared by every ID, so that IDs generated within the same
+// millisecond still sort in the order they were generated
+var (
+	entropyMu sync.Mutex
+	entropy   = ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+)
+
 // generates a new unique ID using ULID;
 // the ID is lexicographically sortable and based on time,
 // allowing chronology to be indicated by the ID itself.
 func NewID() string {
+	entropyMu.Lock()
+	defer entropyMu.Unlock()
+
 	t := time.Now().UTC()
 
 	// Generate a ULID with a timestamp
-	entropy := rand.New(rand.NewSource(t.UnixNano()))
 	return ulid.MustNew(ulid.Timestamp(t), entropy).String()
 }
@@ -0,0 +1,317 @@
+package accounting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// the kinds of records the integrity check reports on, besides those audited
+const (
+	JournalLineEntity      EntityType = "journal_line"
+	AccountingPeriodEntity EntityType = "accounting_period"
+	LedgerEntity           EntityType = "ledger"
+)
+
+// the books as the integrity check examines them, read at a single instant
+type IntegrityData struct {
+	Accounts      []Account
+	Groups        []AccountGroup
+	Entries       []JournalEntry
+	StrayLines    []StrayLine // lines whose entry does not exist
+	ClosedPeriods []ClosedPeriodTotals
+}
+
+// a journal line whose entry does not exist
+type StrayLine struct {
+	ID      string
+	EntryID string
+	Line    JournalEntryLine
+}
+
+// a closed period's account totals as recorded on closing it, and as they
+// stand now
+type ClosedPeriodTotals struct {
+	Period  AccountingPeriod
+	AtClose []AccountTotal
+	Now     []AccountTotal
+}
+
+// a problem found by the integrity check, with the record it concerns and
+// what to do about it
+type IntegrityProblem struct {
+	EntityType EntityType `json:"entity_type"`
+	EntityID   string     `json:"entity_id"`
+	Problem    string     `json:"problem"`
+	Fix        string     `json:"fix"`
+}
+
+// the outcome of checking the books
+type IntegrityReport struct {
+	Entries       int                `json:"entries"`
+	Accounts      int                `json:"accounts"`
+	ClosedPeriods int                `json:"closed_periods"`
+	Problems      []IntegrityProblem `json:"problems"`
+}
+
+// reports whether the check found nothing wrong
+func (r IntegrityReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// ExpectedNormalBalance reports the normal balance of accounts of the type;
+// false for equity, which holds both credit-normal capital and debit-normal
+// draws, and for unknown types
+func ExpectedNormalBalance(accountType AccountType) (NormalBalance, bool) {
+	switch accountType {
+	case Asset, Expense:
+		return DebitNormal, true
+	case ContraAsset, Liability, Revenue:
+		return CreditNormal, true
+	default:
+		return "", false
+	}
+}
+
+// CheckIntegrity examines the books for entries which do not balance or
+// post to missing accounts, stray lines, accounts at odds with their type,
+// broken display orders, a trial balance which does not net to zero, and
+// closed periods whose balances have changed
+func CheckIntegrity(data IntegrityData) IntegrityReport {
+	report := IntegrityReport{
+		Entries:       len(data.Entries),
+		Accounts:      len(data.Accounts),
+		ClosedPeriods: len(data.ClosedPeriods),
+	}
+	add := func(entityType EntityType, id, fix, problem string, args ...any) {
+		report.Problems = append(report.Problems, IntegrityProblem{
+			EntityType: entityType,
+			EntityID:   id,
+			Problem:    fmt.Sprintf(problem, args...),
+			Fix:        fix,
+		})
+	}
+
+	accounts := make(map[string]bool, len(data.Accounts))
+	for _, account := range data.Accounts {
+		accounts[account.Name] = true
+	}
+
+	// entries
+	var debits, credits float64
+	for _, je := range data.Entries {
+		if len(je.Lines) < 2 {
+			add(JournalEntryEntity, je.ID, "Reverse the entry and post it again with both sides.",
+				"has %d line(s); an entry needs at least two", len(je.Lines))
+		}
+
+		var entryDebits, entryCredits float64
+		for _, line := range je.Lines {
+			switch line.Side {
+			case Debit:
+				entryDebits += line.Amount
+			case Credit:
+				entryCredits += line.Amount
+			}
+			if !accounts[line.AccountName] {
+				add(JournalEntryEntity, je.ID, fmt.Sprintf("Recreate the account %q, or restore the books from a backup.", line.AccountName),
+					"posts to the account %q, which does not exist", line.AccountName)
+			}
+		}
+		if RoundCents(entryDebits) != RoundCents(entryCredits) {
+			add(JournalEntryEntity, je.ID, "Reverse the entry and post a balanced one in its place.",
+				"debits of %.2f do not equal credits of %.2f", entryDebits, entryCredits)
+		}
+
+		debits += entryDebits
+		credits += entryCredits
+	}
+
+	for _, stray := range data.StrayLines {
+		add(JournalLineEntity, stray.ID, "Delete the line, or restore the books from a backup.",
+			"belongs to the entry %q, which does not exist", stray.EntryID)
+
+		switch stray.Line.Side {
+		case Debit:
+			debits += stray.Line.Amount
+		case Credit:
+			credits += stray.Line.Amount
+		}
+	}
+
+	if RoundCents(debits) != RoundCents(credits) {
+		add(LedgerEntity, "trial balance", "Correct the entries and lines reported above.",
+			"debits of %.2f and credits of %.2f differ by %.2f", debits, credits, RoundCents(debits-credits))
+	}
+
+	// accounts
+	for _, account := range data.Accounts {
+		expected, ok := ExpectedNormalBalance(account.AccountType)
+		switch {
+		case account.AccountType == Equity:
+		case !ok:
+			add(AccountEntity, account.Name, "Change the account to one of the known types.",
+				"has the unknown type %q", account.AccountType)
+		case account.NormalBalance != expected:
+			add(AccountEntity, account.Name, fmt.Sprintf("Change its normal balance to %s, or its type to one which is %s-normal.", expected, account.NormalBalance),
+				"is a %s account with a %s normal balance", account.AccountType, account.NormalBalance)
+		}
+	}
+
+	// display orders; accounts are ordered among all accounts, and groups
+	// among their siblings
+	accountOrder := make([]displayOrder, len(data.Accounts))
+	for i, account := range data.Accounts {
+		accountOrder[i] = displayOrder{account.Name, "", account.DisplayAfter.String, account.DisplayAfter.Valid}
+	}
+	groupOrder := make([]displayOrder, len(data.Groups))
+	for i, group := range data.Groups {
+		groupOrder[i] = displayOrder{group.Name, group.ParentName.String, group.DisplayAfter.String, group.DisplayAfter.Valid}
+	}
+	for _, order := range []struct {
+		entityType EntityType
+		items      []displayOrder
+		kind       string // what an item is displayed after
+		missing    string // why the item displayed after is not one
+	}{
+		{AccountEntity, accountOrder, "account", "which does not exist"},
+		{AccountGroupEntity, groupOrder, "group", "which is not a group within the same parent"},
+	} {
+		dangling, cycles := checkDisplayOrder(order.items)
+		for _, item := range dangling {
+			add(order.entityType, item.name, fmt.Sprintf("Move it after an existing %s, or to the top.", order.kind),
+				"is displayed after %q, %s", item.after, order.missing)
+		}
+		for _, cycle := range cycles {
+			add(order.entityType, cycle[0], fmt.Sprintf("Move one of them to the top, or after a %s outside the cycle.", order.kind),
+				"is displayed after itself by way of %s", strings.Join(append(cycle, cycle[0]), " → "))
+		}
+	}
+
+	// closed periods
+	for _, closed := range data.ClosedPeriods {
+		for _, change := range changedTotals(closed.AtClose, closed.Now) {
+			add(AccountingPeriodEntity, closed.Period.ID, "Reverse the entries dated within the period since it closed, or reopen the period to accept them and close it again.",
+				"%d-%02d (%s to %s): %s", closed.Period.FiscalYear, closed.Period.Number,
+				closed.Period.Start.Format("2006-01-02"), closed.Period.End.Format("2006-01-02"), change)
+		}
+	}
+
+	return report
+}
+
+// an item's place in a display order
+type displayOrder struct {
+	name, parent string
+	after        string
+	hasAfter     bool
+}
+
+// finds the items displayed after one missing from their parent, and the
+// cycles of items displayed after one another, each listed from its least
+// name, followed by the item it is displayed after, and so on
+func checkDisplayOrder(items []displayOrder) (dangling []displayOrder, cycles [][]string) {
+	type key struct{ parent, name string }
+	byKey := make(map[key]displayOrder, len(items))
+	for _, item := range items {
+		byKey[key{item.parent, item.name}] = item
+	}
+
+	// each item's predecessor, if it exists
+	next := make(map[key]key)
+	for _, item := range items {
+		if !item.hasAfter {
+			continue
+		}
+		after := key{item.parent, item.after}
+		if _, ok := byKey[after]; !ok {
+			dangling = append(dangling, item)
+			continue
+		}
+		next[key{item.parent, item.name}] = after
+	}
+
+	// follow each chain; one which returns to an item on the current path
+	// has found a cycle
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[key]int, len(items))
+	for _, item := range items {
+		start := key{item.parent, item.name}
+		var path []key
+		for k := start; state[k] == unvisited; {
+			state[k] = onPath
+			path = append(path, k)
+			after, ok := next[k]
+			if !ok {
+				break
+			}
+			if state[after] == onPath {
+				var cycle []string
+				for i := len(path) - 1; path[i] != after; i-- {
+					cycle = append([]string{path[i].name}, cycle...)
+				}
+				cycles = append(cycles, rotateToLeast(append([]string{after.name}, cycle...)))
+				break
+			}
+			k = after
+		}
+		for _, k := range path {
+			state[k] = done
+		}
+	}
+
+	sort.Slice(dangling, func(i, j int) bool { return dangling[i].name < dangling[j].name })
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+
+	return dangling, cycles
+}
+
+// rotates the cycle to begin at its least name, for a stable report
+func rotateToLeast(cycle []string) []string {
+	least := 0
+	for i, name := range cycle {
+		if name < cycle[least] {
+			least = i
+		}
+	}
+	return append(cycle[least:], cycle[:least]...)
+}
+
+// describes each account whose totals differ between the two sets
+func changedTotals(before, after []AccountTotal) []string {
+	totals := make(map[string][2]AccountTotal)
+	for _, t := range before {
+		pair := totals[t.AccountName]
+		pair[0] = t
+		totals[t.AccountName] = pair
+	}
+	for _, t := range after {
+		pair := totals[t.AccountName]
+		pair[1] = t
+		totals[t.AccountName] = pair
+	}
+
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, name := range names {
+		was, is := totals[name][0], totals[name][1]
+		if RoundCents(was.Debits) == RoundCents(is.Debits) && RoundCents(was.Credits) == RoundCents(is.Credits) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf(
+			"%s has changed from debits of %.2f and credits of %.2f to debits of %.2f and credits of %.2f since the period closed",
+			name, was.Debits, was.Credits, is.Debits, is.Credits,
+		))
+	}
+
+	return changes
+}
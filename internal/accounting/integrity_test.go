@@ -0,0 +1,141 @@
+package accounting
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	after := func(name string) sql.NullString {
+		return sql.NullString{String: name, Valid: name != ""}
+	}
+	books := func() IntegrityData {
+		return IntegrityData{
+			Accounts: []Account{
+				{Name: "Cash", ParentGroupName: "Assets", AccountType: Asset, NormalBalance: DebitNormal},
+				{Name: "Sales", ParentGroupName: "Revenues", AccountType: Revenue, NormalBalance: CreditNormal, DisplayAfter: after("Cash")},
+				{Name: "Owner Draws", ParentGroupName: "Equity", AccountType: Equity, NormalBalance: DebitNormal},
+			},
+			Groups: []AccountGroup{
+				{Name: "Assets"},
+				{Name: "Revenues", DisplayAfter: after("Assets")},
+			},
+			Entries: []JournalEntry{{
+				ID: "je-1",
+				Lines: []JournalEntryLine{
+					{AccountName: "Cash", Amount: 100, Side: Debit},
+					{AccountName: "Sales", Amount: 100, Side: Credit},
+				},
+			}},
+		}
+	}
+	problems := func(report IntegrityReport) map[string]string {
+		found := make(map[string]string)
+		for _, p := range report.Problems {
+			if p.Fix == "" {
+				t.Fatalf("expected a fix for every problem, got %+v", p)
+			}
+			found[string(p.EntityType)+" "+p.EntityID] += p.Problem + "; "
+		}
+		return found
+	}
+
+	t.Run("passes sound books", func(t *testing.T) {
+		report := CheckIntegrity(books())
+		if !report.OK() || report.Entries != 1 || report.Accounts != 3 {
+			t.Fatalf("expected no problems, got %+v", report)
+		}
+	})
+
+	t.Run("reports unbalanced and one-sided entries, and the trial balance", func(t *testing.T) {
+		data := books()
+		data.Entries[0].Lines[0].Amount = 90
+		data.Entries = append(data.Entries, JournalEntry{
+			ID:    "je-2",
+			Lines: []JournalEntryLine{{AccountName: "Cash", Amount: 10, Side: Debit}},
+		})
+
+		found := problems(CheckIntegrity(data))
+		if !strings.Contains(found["journal_entry je-1"], "do not equal") {
+			t.Fatalf("expected je-1 reported unbalanced, got %v", found)
+		}
+		if !strings.Contains(found["journal_entry je-2"], "1 line(s)") {
+			t.Fatalf("expected je-2 reported one-sided, got %v", found)
+		}
+		if _, ok := found["ledger trial balance"]; ok {
+			t.Fatalf("expected the trial balance to net to zero, got %v", found)
+		}
+
+		data.Entries = data.Entries[:1]
+		if found := problems(CheckIntegrity(data)); !strings.Contains(found["ledger trial balance"], "differ by -10.00") {
+			t.Fatalf("expected the trial balance reported, got %v", found)
+		}
+	})
+
+	t.Run("reports postings to missing accounts and stray lines", func(t *testing.T) {
+		data := books()
+		data.Entries[0].Lines[1].AccountName = "Gone"
+		data.StrayLines = []StrayLine{{ID: "line-9", EntryID: "je-9", Line: JournalEntryLine{AccountName: "Cash", Amount: 5, Side: Debit}}}
+
+		found := problems(CheckIntegrity(data))
+		if !strings.Contains(found["journal_entry je-1"], `"Gone"`) {
+			t.Fatalf("expected the missing account reported, got %v", found)
+		}
+		if !strings.Contains(found["journal_line line-9"], `"je-9"`) {
+			t.Fatalf("expected the stray line reported, got %v", found)
+		}
+		if _, ok := found["ledger trial balance"]; !ok {
+			t.Fatalf("expected the stray line to unbalance the trial balance, got %v", found)
+		}
+	})
+
+	t.Run("reports normal balances at odds with the type", func(t *testing.T) {
+		data := books()
+		data.Accounts[0].NormalBalance = CreditNormal
+		data.Accounts = append(data.Accounts, Account{Name: "Odd", AccountType: "Mystery", NormalBalance: DebitNormal})
+
+		found := problems(CheckIntegrity(data))
+		if len(found) != 2 || found["account Cash"] == "" || !strings.Contains(found["account Odd"], "unknown type") {
+			t.Fatalf("expected Cash and Odd reported, and equity let be, got %v", found)
+		}
+	})
+
+	t.Run("reports dangling and cyclic display orders", func(t *testing.T) {
+		data := books()
+		data.Accounts[0].DisplayAfter = after("Owner Draws")
+		data.Accounts[2].DisplayAfter = after("Sales")
+		data.Groups[0].DisplayAfter = after("Nowhere")
+
+		found := problems(CheckIntegrity(data))
+		if cycle := found["account Cash"]; !strings.Contains(cycle, "Cash → Owner Draws → Sales → Cash") {
+			t.Fatalf("expected the cycle reported from Cash, got %v", found)
+		}
+		if !strings.Contains(found["account_group Assets"], `"Nowhere"`) {
+			t.Fatalf("expected the dangling group reported, got %v", found)
+		}
+		if len(found) != 2 {
+			t.Fatalf("expected the cycle reported once, got %v", found)
+		}
+	})
+
+	t.Run("reports closed periods whose balances have changed", func(t *testing.T) {
+		data := books()
+		period := AccountingPeriod{
+			ID: "p-1", FiscalYear: 2025, Number: 1,
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		}
+		atClose := []AccountTotal{{AccountName: "Cash", Debits: 100}, {AccountName: "Sales", Credits: 100}}
+		data.ClosedPeriods = []ClosedPeriodTotals{{Period: period, AtClose: atClose, Now: atClose}}
+		if report := CheckIntegrity(data); !report.OK() || report.ClosedPeriods != 1 {
+			t.Fatalf("expected an unchanged period to pass, got %+v", report)
+		}
+
+		data.ClosedPeriods[0].Now = []AccountTotal{{AccountName: "Cash", Debits: 150}, {AccountName: "Sales", Credits: 100}, {AccountName: "Owner Draws", Credits: 50}}
+		report := CheckIntegrity(data)
+		if len(report.Problems) != 2 || report.Problems[0].EntityID != "p-1" || !strings.HasPrefix(report.Problems[0].Problem, "2025-01 (2025-01-01 to 2025-01-31): Cash") {
+			t.Fatalf("expected Cash and Owner Draws reported against the period, got %+v", report.Problems)
+		}
+	})
+}
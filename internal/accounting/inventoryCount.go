@@ -0,0 +1,186 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type CountStatus string
+
+const (
+	CountOpen   CountStatus = "Open"
+	CountPosted CountStatus = "Posted"
+)
+
+// representation of a physical count of stock: the quantities the books
+// expect as of the count date, against those counted
+type InventoryCount struct {
+	ID                   string               `json:"id"`
+	CountDate            time.Time            `json:"count_date"`
+	Status               CountStatus          `json:"status"`
+	ShrinkageAccountName string               `json:"shrinkage_account_name"`
+	Lines                []InventoryCountLine `json:"lines"`
+	EntryID              string               `json:"entry_id"` // empty until posted, or if nothing varied
+}
+
+// one item on a count sheet
+type InventoryCountLine struct {
+	ItemID           string  `json:"item_id"`
+	SKU              string  `json:"sku"`
+	ExpectedQuantity float64 `json:"expected_quantity"`
+	Counted          bool    `json:"counted"`
+	CountedQuantity  float64 `json:"counted_quantity"`
+	// the cost of the variance by the item's costing method, negative for a
+	// shortage; an estimate until the count is posted
+	VarianceCost float64 `json:"variance_cost"`
+	MovementID   string  `json:"movement_id"` // the movement which posted the variance
+}
+
+// counted less expected; zero until counted
+func (l *InventoryCountLine) Variance() float64 {
+	if !l.Counted {
+		return 0
+	}
+	variance := l.CountedQuantity - l.ExpectedQuantity
+	if variance > -quantityTolerance && variance < quantityTolerance {
+		return 0
+	}
+	return variance
+}
+
+// constructor for a new InventoryCount, with a line per item expecting the given quantities
+func NewInventoryCount(countDate time.Time, shrinkageAccountName string, items []*StockItem, expected []float64) (*InventoryCount, error) {
+	if countDate.IsZero() {
+		return nil, errors.New("InventoryCount requires a count date")
+	}
+
+	if strings.TrimSpace(shrinkageAccountName) == "" {
+		return nil, errors.New("InventoryCount requires a shrinkage account")
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("InventoryCount requires at least one item")
+	}
+
+	count := &InventoryCount{
+		ID:                   NewID(),
+		CountDate:            countDate,
+		Status:               CountOpen,
+		ShrinkageAccountName: shrinkageAccountName,
+	}
+	for i, item := range items {
+		count.Lines = append(count.Lines, InventoryCountLine{
+			ItemID:           item.ID,
+			SKU:              item.SKU,
+			ExpectedQuantity: expected[i],
+		})
+	}
+
+	return count, nil
+}
+
+// Record enters the quantity counted for an item
+func (c *InventoryCount) Record(itemID string, quantity float64) error {
+	if c.Status != CountOpen {
+		return fmt.Errorf("count of %s was already posted", c.CountDate.Format(time.DateOnly))
+	}
+
+	if quantity < 0 {
+		return errors.New("InventoryCount requires counted quantities of at least zero")
+	}
+
+	for i := range c.Lines {
+		if c.Lines[i].ItemID == itemID {
+			c.Lines[i].Counted = true
+			c.Lines[i].CountedQuantity = quantity
+			return nil
+		}
+	}
+
+	return &ErrStockItemNotFound{ID: itemID}
+}
+
+// Complete reports whether every line has been counted
+func (c *InventoryCount) Complete() bool {
+	for _, line := range c.Lines {
+		if !line.Counted {
+			return false
+		}
+	}
+	return true
+}
+
+// the net cost of the variances, negative for a net shrinkage
+func (c *InventoryCount) VarianceCost() float64 {
+	var total float64
+	for _, line := range c.Lines {
+		total += line.VarianceCost
+	}
+	return RoundCents(total)
+}
+
+// VarianceMovement produces the movement which brings the item to the
+// counted quantity: a shortage is issued to the shrinkage account, costed by
+// the item's method; an overage is received from it at unitCost (the
+// item's current average cost). Returns nil if nothing varied.
+func (c *InventoryCount) VarianceMovement(item *StockItem, unitCost float64) (*StockMovement, error) {
+	var line *InventoryCountLine
+	for i := range c.Lines {
+		if c.Lines[i].ItemID == item.ID {
+			line = &c.Lines[i]
+		}
+	}
+	if line == nil {
+		return nil, &ErrStockItemNotFound{ID: item.ID}
+	}
+
+	variance := line.Variance()
+
+	var (
+		movement *StockMovement
+		err      error
+	)
+	switch {
+	case variance < 0:
+		movement, err = NewStockIssue(item, c.CountDate, -variance, c.ShrinkageAccountName)
+	case variance > 0:
+		if unitCost <= 0 {
+			return nil, fmt.Errorf("no cost is known for the overage of \"%s\"", item.SKU)
+		}
+		movement, err = NewStockReceipt(item, c.CountDate, variance, unitCost, c.ShrinkageAccountName)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	movement.Memo = "Count of " + c.CountDate.Format(time.DateOnly)
+	return movement, nil
+}
+
+// JournalEntry produces the single entry which posts the count's variance
+// movements: shortages are debited to the shrinkage account and credited to
+// inventory, and overages the reverse.
+func (c *InventoryCount) JournalEntry(items []*StockItem, movements []*StockMovement) JournalEntry {
+	je := JournalEntry{
+		ID:          NewID(),
+		Timestamp:   c.CountDate,
+		Description: "Inventory count adjustment for " + c.CountDate.Format(time.DateOnly),
+	}
+
+	for i, movement := range movements {
+		debit, credit := items[i].InventoryAccountName, c.ShrinkageAccountName
+		if movement.Kind == StockIssue {
+			debit, credit = credit, debit
+		}
+		je.Lines = append(je.Lines,
+			JournalEntryLine{AccountName: debit, Amount: movement.Cost, Side: Debit},
+			JournalEntryLine{AccountName: credit, Amount: movement.Cost, Side: Credit},
+		)
+	}
+
+	return je
+}
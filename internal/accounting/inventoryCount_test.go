@@ -0,0 +1,82 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInventoryCount(t *testing.T) {
+	countDate := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	bolts, _ := NewStockItem("BOLT", "Bolt", FIFO, "Inventory", "Cost of Goods Sold")
+	nuts, _ := NewStockItem("NUT", "Nut", WeightedAverage, "Inventory", "Cost of Goods Sold")
+
+	newCount := func(t *testing.T) *InventoryCount {
+		count, err := NewInventoryCount(countDate, "Inventory Shrinkage", []*StockItem{bolts, nuts}, []float64{10, 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return count
+	}
+
+	t.Run("is complete once every item is counted", func(t *testing.T) {
+		count := newCount(t)
+		if err := count.Record(bolts.ID, 8); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count.Complete() {
+			t.Fatalf("expected the count to be incomplete")
+		}
+		if err := count.Record(nuts.ID, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !count.Complete() || count.Lines[0].Variance() != -2 || count.Lines[1].Variance() != 1 {
+			t.Fatalf("expected variances of -2 and 1, got %+v", count.Lines)
+		}
+	})
+
+	t.Run("rejects items not on the sheet", func(t *testing.T) {
+		if err := newCount(t).Record("missing", 1); !IsStockItemNotFound(err) {
+			t.Fatalf("expected an ErrStockItemNotFound, got %v", err)
+		}
+	})
+
+	t.Run("issues shortages and receives overages through the shrinkage account", func(t *testing.T) {
+		count := newCount(t)
+		count.Record(bolts.ID, 8)
+		count.Record(nuts.ID, 5)
+
+		shortage, err := count.VarianceMovement(bolts, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		overage, err := count.VarianceMovement(nuts, 0.5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shortage.Kind != StockIssue || shortage.Quantity != 2 || shortage.AccountName != "Inventory Shrinkage" {
+			t.Fatalf("expected an issue of 2 to shrinkage, got %+v", shortage)
+		}
+		if overage.Kind != StockReceipt || overage.Quantity != 1 || overage.Cost != 0.5 {
+			t.Fatalf("expected a receipt of 1 at 0.50, got %+v", overage)
+		}
+
+		shortage.Cost = 3
+		je := count.JournalEntry([]*StockItem{bolts, nuts}, []*StockMovement{shortage, overage})
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if je.Lines[0].AccountName != "Inventory Shrinkage" || je.Lines[0].Side != Debit || je.Lines[2].AccountName != "Inventory" || je.Lines[2].Side != Debit {
+			t.Fatalf("expected shrinkage debited for the shortage and inventory for the overage, got %+v", je.Lines)
+		}
+	})
+
+	t.Run("produces no movement without a variance", func(t *testing.T) {
+		count := newCount(t)
+		count.Record(bolts.ID, 10)
+
+		movement, err := count.VarianceMovement(bolts, 0)
+		if err != nil || movement != nil {
+			t.Fatalf("expected no movement, got %+v, %v", movement, err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package accounting
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrInventoryUnitNotFound struct {
+	ID string
+}
+
+type ErrUnitAlreadySold struct {
+	SerialNumber string
+	InvoiceID    string
+}
+
+type ErrStockItemNotFound struct {
+	ID string
+}
+
+type ErrInsufficientStock struct {
+	SKU       string
+	Date      time.Time
+	Requested float64
+	OnHand    float64
+}
+
+type ErrInventoryCountNotFound struct {
+	ID string
+}
+
+func (e *ErrInventoryUnitNotFound) Error() string {
+	return fmt.Sprintf("inventory unit \"%s\" not found", e.ID)
+}
+
+func (e *ErrUnitAlreadySold) Error() string {
+	return fmt.Sprintf("inventory unit \"%s\" was already sold on invoice \"%s\"", e.SerialNumber, e.InvoiceID)
+}
+
+func (e *ErrStockItemNotFound) Error() string {
+	return fmt.Sprintf("stock item \"%s\" not found", e.ID)
+}
+
+func (e *ErrInsufficientStock) Error() string {
+	return fmt.Sprintf(
+		"cannot issue %s of \"%s\" on %s; only %s on hand",
+		formatQuantity(e.Requested),
+		e.SKU,
+		e.Date.Format(time.DateOnly),
+		formatQuantity(e.OnHand),
+	)
+}
+
+func (e *ErrInventoryCountNotFound) Error() string {
+	return fmt.Sprintf("inventory count \"%s\" not found", e.ID)
+}
+
+// --------- helper utilities ------------
+func IsInventoryUnitNotFound(err error) bool {
+	_, ok := err.(*ErrInventoryUnitNotFound)
+	return ok
+}
+
+func IsUnitAlreadySold(err error) bool {
+	_, ok := err.(*ErrUnitAlreadySold)
+	return ok
+}
+
+func IsStockItemNotFound(err error) bool {
+	_, ok := err.(*ErrStockItemNotFound)
+	return ok
+}
+
+func IsInsufficientStock(err error) bool {
+	_, ok := err.(*ErrInsufficientStock)
+	return ok
+}
+
+func IsInventoryCountNotFound(err error) bool {
+	_, ok := err.(*ErrInventoryCountNotFound)
+	return ok
+}
@@ -0,0 +1,187 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// representation of a single, individually tracked unit of inventory
+// (specific identification): the unit carries its own cost, which moves
+// to cost of goods sold when that exact unit is sold.
+type InventoryUnit struct {
+	ID                   string    `json:"id"`
+	SerialNumber         string    `json:"serial_number"` // serial number or SKU; unique
+	Description          string    `json:"description"`
+	AcquisitionCost      float64   `json:"acquisition_cost"`
+	AcquisitionDate      time.Time `json:"acquisition_date"`
+	AcquisitionEntryID   string    `json:"acquisition_entry_id"`
+	Location             string    `json:"location"`
+	InventoryAccountName string    `json:"inventory_account_name"`
+
+	Sale *UnitSale `json:"sale"` // nil while the unit is on hand
+}
+
+// representation of the sale of an inventory unit on an invoice
+type UnitSale struct {
+	UnitID          string    `json:"unit_id"`
+	InvoiceID       string    `json:"invoice_id"`
+	SaleDate        time.Time `json:"sale_date"`
+	Price           float64   `json:"price"`
+	COGSAccountName string    `json:"cogs_account_name"`
+	EntryID         string    `json:"entry_id"` // the invoice's entry, which also relieves inventory
+}
+
+// constructor for a new InventoryUnit
+func NewInventoryUnit(
+	serialNumber string,
+	description string,
+	cost float64,
+	acquisitionDate time.Time,
+	location string,
+	inventoryAccountName string,
+) (*InventoryUnit, error) {
+	if strings.TrimSpace(serialNumber) == "" {
+		return nil, errors.New("InventoryUnit requires a serial number or SKU")
+	}
+
+	if cost <= 0 {
+		return nil, errors.New("InventoryUnit requires a positive acquisition cost")
+	}
+
+	if acquisitionDate.IsZero() {
+		return nil, errors.New("InventoryUnit requires an acquisition date")
+	}
+
+	if strings.TrimSpace(inventoryAccountName) == "" {
+		return nil, errors.New("InventoryUnit requires an inventory account")
+	}
+
+	return &InventoryUnit{
+		ID:                   NewID(),
+		SerialNumber:         strings.TrimSpace(serialNumber),
+		Description:          strings.TrimSpace(description),
+		AcquisitionCost:      RoundCents(cost),
+		AcquisitionDate:      acquisitionDate,
+		Location:             strings.TrimSpace(location),
+		InventoryAccountName: inventoryAccountName,
+	}, nil
+}
+
+// OnHand reports whether the unit had been acquired and not yet sold as of the end of asOf
+func (u *InventoryUnit) OnHand(asOf time.Time) bool {
+	if u.AcquisitionDate.After(asOf) {
+		return false
+	}
+	return u.Sale == nil || u.Sale.SaleDate.After(asOf)
+}
+
+// AcquisitionEntry produces the entry which brings the unit onto the books:
+// the inventory account is debited, and the given account (e.g. cash) credited.
+func (u *InventoryUnit) AcquisitionEntry(creditAccountName string) JournalEntry {
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   u.AcquisitionDate,
+		Description: "Acquisition of " + u.SerialNumber,
+		Lines: []JournalEntryLine{
+			{AccountName: u.InventoryAccountName, Amount: u.AcquisitionCost, Side: Debit},
+			{AccountName: creditAccountName, Amount: u.AcquisitionCost, Side: Credit},
+		},
+	}
+}
+
+// NewUnitSale records the sale of the unit on the invoice, dated and priced by the invoice
+func NewUnitSale(unit *InventoryUnit, invoice *Invoice, cogsAccountName string) (*UnitSale, error) {
+	if unit.Sale != nil {
+		return nil, &ErrUnitAlreadySold{SerialNumber: unit.SerialNumber, InvoiceID: unit.Sale.InvoiceID}
+	}
+
+	if invoice.InvoiceDate.Before(unit.AcquisitionDate) {
+		return nil, fmt.Errorf("unit \"%s\" cannot be sold before it was acquired", unit.SerialNumber)
+	}
+
+	if strings.TrimSpace(cogsAccountName) == "" {
+		return nil, errors.New("UnitSale requires a cost of goods sold account")
+	}
+
+	return &UnitSale{
+		UnitID:          unit.ID,
+		InvoiceID:       invoice.ID,
+		SaleDate:        invoice.InvoiceDate,
+		Price:           invoice.Total(),
+		COGSAccountName: cogsAccountName,
+	}, nil
+}
+
+// JournalEntry produces the invoice's entry extended to relieve inventory:
+// alongside the receivable and revenue, the unit's cost is debited to
+// cost of goods sold and credited to its inventory account.
+func (s *UnitSale) JournalEntry(unit *InventoryUnit, invoice *Invoice) JournalEntry {
+	je := invoice.JournalEntry()
+	je.Description += "; sale of " + unit.SerialNumber
+	je.Lines = append(je.Lines,
+		JournalEntryLine{AccountName: s.COGSAccountName, Amount: unit.AcquisitionCost, Side: Debit, ProjectID: invoice.Lines[0].ProjectID},
+		JournalEntryLine{AccountName: unit.InventoryAccountName, Amount: unit.AcquisitionCost, Side: Credit},
+	)
+	return je
+}
+
+// a stock item's quantity and value on hand at a date
+type StockOnHand struct {
+	Item      *StockItem     `json:"item"`
+	Valuation StockValuation `json:"valuation"`
+}
+
+// the units and stock on hand at a date, reconciled to the inventory accounts
+type OnHandReport struct {
+	AsOf            time.Time        `json:"as_of"`
+	Units           []*InventoryUnit `json:"units"`
+	Stock           []StockOnHand    `json:"stock"`
+	Total           float64          `json:"total"`
+	ControlAccounts []string         `json:"control_accounts"`
+	ControlBalance  float64          `json:"control_balance"`
+	Difference      float64          `json:"difference"` // Total - ControlBalance
+}
+
+// Ties reports whether the units on hand agree with the inventory accounts
+func (r *OnHandReport) Ties() bool {
+	return r.Difference == 0
+}
+
+// BuildOnHandReport lists the units on hand as of the end of asOf, ordered by
+// serial number, alongside the valued stock items, and compares their combined
+// cost to the balance of the inventory accounts.
+func BuildOnHandReport(units []*InventoryUnit, stock []StockOnHand, asOf time.Time, controlAccounts []string, controlBalance float64) OnHandReport {
+	report := OnHandReport{
+		AsOf:            asOf,
+		ControlAccounts: controlAccounts,
+		ControlBalance:  RoundCents(controlBalance),
+	}
+
+	for _, line := range stock {
+		if line.Valuation.Quantity == 0 {
+			continue
+		}
+		report.Stock = append(report.Stock, line)
+		report.Total += line.Valuation.Value
+	}
+
+	for _, unit := range units {
+		if !unit.OnHand(asOf) {
+			continue
+		}
+		report.Units = append(report.Units, unit)
+		report.Total += unit.AcquisitionCost
+	}
+
+	slices.SortFunc(report.Units, func(a, b *InventoryUnit) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+
+	report.Total = RoundCents(report.Total)
+	report.Difference = RoundCents(report.Total - report.ControlBalance)
+
+	return report
+}
@@ -0,0 +1,76 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitSale(t *testing.T) {
+	acquired := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	sold := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	unit, err := NewInventoryUnit("SN-1001", "Walnut Desk", 450, acquired, "Showroom", "Inventory")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	invoice, err := NewInvoice("customer", "2001", sold, DefaultPaymentTerms, "Accounts Receivable",
+		[]InvoiceLine{{AccountName: "Sales", Amount: 1200}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("posts revenue and relieves the unit's cost in one entry", func(t *testing.T) {
+		sale, err := NewUnitSale(unit, invoice, "Cost of Goods Sold")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		je := sale.JournalEntry(unit, invoice)
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if len(je.Lines) != 4 {
+			t.Fatalf("expected receivable, revenue, COGS and inventory lines, got %+v", je.Lines)
+		}
+		cogs, inventory := je.Lines[2], je.Lines[3]
+		if cogs.AccountName != "Cost of Goods Sold" || cogs.Amount != 450 || cogs.Side != Debit {
+			t.Fatalf("expected the unit's cost debited to COGS, got %+v", cogs)
+		}
+		if inventory.AccountName != "Inventory" || inventory.Amount != 450 || inventory.Side != Credit {
+			t.Fatalf("expected the unit's cost credited to inventory, got %+v", inventory)
+		}
+	})
+
+	t.Run("refuses to sell a unit twice", func(t *testing.T) {
+		soldUnit := *unit
+		soldUnit.Sale = &UnitSale{UnitID: unit.ID, InvoiceID: "earlier", SaleDate: sold}
+
+		if _, err := NewUnitSale(&soldUnit, invoice, "Cost of Goods Sold"); !IsUnitAlreadySold(err) {
+			t.Fatalf("expected an ErrUnitAlreadySold, got %v", err)
+		}
+	})
+
+	t.Run("counts a unit on hand from acquisition until sale", func(t *testing.T) {
+		soldUnit := *unit
+		soldUnit.Sale = &UnitSale{UnitID: unit.ID, SaleDate: sold}
+
+		if soldUnit.OnHand(acquired.AddDate(0, 0, -1)) || !soldUnit.OnHand(acquired) || soldUnit.OnHand(sold) {
+			t.Fatalf("expected the unit on hand only between acquisition and sale")
+		}
+	})
+}
+
+func TestBuildOnHandReport(t *testing.T) {
+	desk, _ := NewInventoryUnit("SN-2", "Desk", 450, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), "", "Inventory")
+	chair, _ := NewInventoryUnit("SN-1", "Chair", 120, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), "", "Inventory")
+	lamp, _ := NewInventoryUnit("SN-3", "Lamp", 80, time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC), "", "Inventory")
+
+	report := BuildOnHandReport([]*InventoryUnit{desk, chair, lamp}, nil, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), []string{"Inventory"}, 570)
+
+	if len(report.Units) != 2 || report.Units[0] != chair || report.Units[1] != desk {
+		t.Fatalf("expected the chair and desk in serial order, got %+v", report.Units)
+	}
+	if report.Total != 570 || !report.Ties() {
+		t.Fatalf("expected 570 on hand tying to the control account, got %+v", report)
+	}
+}
@@ -0,0 +1,201 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// representation of a single line on a customer invoice; each line
+// is credited to a revenue account
+type InvoiceLine struct {
+	AccountName string  `json:"account_name"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	ProjectID   string  `json:"project_id"` // empty if not earned on a project
+	TaxCode     string  `json:"tax_code"`   // empty if sales tax does not apply
+}
+
+// representation of an invoice issued to a customer
+type Invoice struct {
+	ID                    string        `json:"id"`
+	CustomerID            string        `json:"customer_id"`
+	Number                string        `json:"number"`
+	InvoiceDate           time.Time     `json:"invoice_date"`
+	DueDate               time.Time     `json:"due_date"`
+	Terms                 PaymentTerms  `json:"terms"`
+	ReceivableAccountName string        `json:"receivable_account_name"` // the A/R control account
+	Memo                  string        `json:"memo"`
+	Lines                 []InvoiceLine `json:"lines"`
+	Taxes                 []InvoiceTax  `json:"taxes"`    // the sales tax charged on the lines; see SalesTaxSchedule.Apply
+	EntryID               string        `json:"entry_id"` // the journal entry which posted the invoice
+}
+
+// representation of a payment received against an invoice
+type InvoicePayment struct {
+	ID              string    `json:"id"`
+	InvoiceID       string    `json:"invoice_id"`
+	PaymentDate     time.Time `json:"payment_date"`
+	CashAccountName string    `json:"cash_account_name"`
+	Amount          float64   `json:"amount"`
+	EntryID         string    `json:"entry_id"`
+}
+
+// constructor for a new Invoice
+//
+// The due date is derived from the invoice date and the payment terms.
+func NewInvoice(
+	customerID string,
+	number string,
+	invoiceDate time.Time,
+	terms PaymentTerms,
+	receivableAccountName string,
+	lines []InvoiceLine,
+) (*Invoice, error) {
+	if customerID == "" {
+		return nil, errors.New("Invoice requires a customer")
+	}
+
+	if strings.TrimSpace(receivableAccountName) == "" {
+		return nil, errors.New("Invoice requires a receivable account")
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("Invoice requires at least one line")
+	}
+
+	for i, line := range lines {
+		if line.AccountName == "" {
+			return nil, fmt.Errorf("Invoice line %d requires an account", i+1)
+		}
+		if line.Amount <= 0 {
+			return nil, fmt.Errorf("Invoice line %d requires a positive amount", i+1)
+		}
+	}
+
+	return &Invoice{
+		ID:                    NewID(),
+		CustomerID:            customerID,
+		Number:                strings.TrimSpace(number),
+		InvoiceDate:           invoiceDate,
+		DueDate:               terms.DueDate(invoiceDate),
+		Terms:                 terms,
+		ReceivableAccountName: receivableAccountName,
+		Lines:                 lines,
+	}, nil
+}
+
+// the sum of the invoice's lines, before sales tax
+func (i *Invoice) Subtotal() float64 {
+	var total float64
+	for _, line := range i.Lines {
+		total += line.Amount
+	}
+	return RoundCents(total)
+}
+
+// the sales tax charged on the invoice
+func (i *Invoice) Tax() float64 {
+	var total float64
+	for _, tax := range i.Taxes {
+		total += tax.Amount
+	}
+	return RoundCents(total)
+}
+
+// the amount due from the customer: the lines plus sales tax
+func (i *Invoice) Total() float64 {
+	return RoundCents(i.Subtotal() + i.Tax())
+}
+
+// the balance left on the invoice after the given payments
+func (i *Invoice) OpenBalance(payments []InvoicePayment) float64 {
+	open := i.Total()
+	for _, p := range payments {
+		open -= p.Amount
+	}
+	return RoundCents(open)
+}
+
+// JournalEntry produces the entry which posts the invoice: the total is
+// debited to the receivable account, each line is credited, and the sales
+// tax is credited to each jurisdiction's liability account.
+func (i *Invoice) JournalEntry() JournalEntry {
+	lines := make([]JournalEntryLine, 0, len(i.Lines)+len(i.Taxes)+1)
+	lines = append(lines, JournalEntryLine{
+		AccountName: i.ReceivableAccountName,
+		Amount:      i.Total(),
+		Side:        Debit,
+	})
+	for _, line := range i.Lines {
+		lines = append(lines, JournalEntryLine{
+			AccountName: line.AccountName,
+			Amount:      line.Amount,
+			Side:        Credit,
+			ProjectID:   line.ProjectID,
+		})
+	}
+	lines = append(lines, i.taxLiabilities()...)
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   i.InvoiceDate,
+		Description: i.description(),
+		Lines:       lines,
+	}
+}
+
+// NewInvoicePayment applies `amount` received into a cash account against an invoice.
+func NewInvoicePayment(
+	invoice *Invoice,
+	openBalance float64,
+	paymentDate time.Time,
+	cashAccountName string,
+	amount float64,
+) (*InvoicePayment, error) {
+	if cashAccountName == "" {
+		return nil, errors.New("InvoicePayment requires a cash account")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("InvoicePayment requires a positive amount")
+	}
+
+	if paymentDate.Before(invoice.InvoiceDate) {
+		return nil, errors.New("InvoicePayment cannot precede the invoice date")
+	}
+
+	if RoundCents(amount) > openBalance {
+		return nil, &ErrOverpayment{DocumentID: invoice.ID, OpenBalance: openBalance, Amount: amount}
+	}
+
+	return &InvoicePayment{
+		ID:              NewID(),
+		InvoiceID:       invoice.ID,
+		PaymentDate:     paymentDate,
+		CashAccountName: cashAccountName,
+		Amount:          RoundCents(amount),
+	}, nil
+}
+
+// JournalEntry produces the entry which posts the payment:
+// cash is debited, and the receivable account is credited.
+func (p *InvoicePayment) JournalEntry(invoice *Invoice) JournalEntry {
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   p.PaymentDate,
+		Description: "Payment on " + invoice.description(),
+		Lines: []JournalEntryLine{
+			{AccountName: p.CashAccountName, Amount: p.Amount, Side: Debit},
+			{AccountName: invoice.ReceivableAccountName, Amount: p.Amount, Side: Credit},
+		},
+	}
+}
+
+func (i *Invoice) description() string {
+	if i.Number != "" {
+		return "Invoice " + i.Number
+	}
+	return "Invoice " + i.ID
+}
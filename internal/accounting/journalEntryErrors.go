@@ -0,0 +1,59 @@
+package accounting
+
+import "fmt"
+
+type ErrEntryNotFound struct {
+	ID string
+}
+
+type ErrUnbalancedEntry struct {
+	ID string
+}
+
+type ErrInvalidEntry struct {
+	ID     string
+	Reason string
+}
+
+// the hash chain over posted entries no longer matches, from the entry given
+type ErrChainBroken struct {
+	EntryID string
+	Reason  string
+}
+
+func (e *ErrEntryNotFound) Error() string {
+	return fmt.Sprintf("journal entry \"%s\" not found", e.ID)
+}
+
+func (e *ErrUnbalancedEntry) Error() string {
+	return fmt.Sprintf("journal entry \"%s\" does not balance", e.ID)
+}
+
+func (e *ErrInvalidEntry) Error() string {
+	return fmt.Sprintf("journal entry \"%s\" is invalid: %s", e.ID, e.Reason)
+}
+
+func (e *ErrChainBroken) Error() string {
+	return fmt.Sprintf("hash chain broken at journal entry \"%s\": %s", e.EntryID, e.Reason)
+}
+
+// --------- helper utilities ------------
+func IsEntryNotFound(err error) bool {
+	_, ok := err.(*ErrEntryNotFound)
+	return ok
+}
+
+func IsUnbalancedEntry(err error) bool {
+	_, ok := err.(*ErrUnbalancedEntry)
+	return ok
+}
+
+func IsInvalidEntry(err error) bool {
+	_, ok := err.(*ErrInvalidEntry)
+	return ok
+}
+
+func IsChainBroken(err error) bool {
+	_, ok := err.(*ErrChainBroken)
+	return ok
+}
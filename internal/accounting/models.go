@@ -59,13 +59,15 @@ type JournalEntryLine struct {
 	Amount         float64        `json:"amount"`
 	Side           EntrySide      `json:"side"`
 	CrossReference sql.NullString `json:"cross_reference"` // e.g. in a reversal; empty -> null
+	ProjectID      string         `json:"project_id"`      // the project the line is charged to; empty if none
 }
 
 // representation of a journal entry, comprised of
 // multiple journal entry lines
 type JournalEntry struct {
-	ID          string             `json:"id"`
-	Timestamp   time.Time          `json:"timestamp"`
-	Description string             `json:"description"`
-	Lines       []JournalEntryLine `json:"lines"`
+	ID             string             `json:"id"`
+	Timestamp      time.Time          `json:"timestamp"`
+	Description    string             `json:"description"`
+	Lines          []JournalEntryLine `json:"lines"`
+	CrossReference sql.NullString     `json:"cross_reference"` // the entry this one adjusts; empty -> null
 }
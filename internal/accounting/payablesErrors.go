@@ -0,0 +1,60 @@
+package accounting
+
+import "fmt"
+
+type ErrVendorNotFound struct {
+	ID string
+}
+
+type ErrBillNotFound struct {
+	ID string
+}
+
+type ErrOverpayment struct {
+	DocumentID  string
+	OpenBalance float64
+	Amount      float64
+}
+
+type ErrAccountTypeNotAllowed struct {
+	Name        string
+	AccountType AccountType
+	Purpose     string
+}
+
+func (e *ErrVendorNotFound) Error() string {
+	return fmt.Sprintf("vendor \"%s\" not found", e.ID)
+}
+
+func (e *ErrBillNotFound) Error() string {
+	return fmt.Sprintf("bill \"%s\" not found", e.ID)
+}
+
+func (e *ErrOverpayment) Error() string {
+	return fmt.Sprintf("payment of %.2f exceeds the open balance of %.2f on \"%s\"", e.Amount, e.OpenBalance, e.DocumentID)
+}
+
+func (e *ErrAccountTypeNotAllowed) Error() string {
+	return fmt.Sprintf("account \"%s\" of type %s cannot be used as %s", e.Name, e.AccountType, e.Purpose)
+}
+
+// --------- helper utilities ------------
+func IsVendorNotFound(err error) bool {
+	_, ok := err.(*ErrVendorNotFound)
+	return ok
+}
+
+func IsBillNotFound(err error) bool {
+	_, ok := err.(*ErrBillNotFound)
+	return ok
+}
+
+func IsOverpayment(err error) bool {
+	_, ok := err.(*ErrOverpayment)
+	return ok
+}
+
+func IsAccountTypeNotAllowed(err error) bool {
+	_, ok := err.(*ErrAccountTypeNotAllowed)
+	return ok
+}
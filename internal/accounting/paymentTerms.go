@@ -0,0 +1,111 @@
+package accounting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// representation of payment terms, e.g. "Net 30" or "2/10 Net 30".
+//
+// DiscountPercent is earned if the document is settled within
+// DiscountDays of its date; the full balance is due NetDays after it.
+type PaymentTerms struct {
+	DiscountPercent float64 `json:"discount_percent"`
+	DiscountDays    int     `json:"discount_days"`
+	NetDays         int     `json:"net_days"`
+}
+
+// the terms used when none are given
+var DefaultPaymentTerms = PaymentTerms{NetDays: 30}
+
+// ParsePaymentTerms parses terms written as "Net N", "D/E Net N", or "Due on receipt".
+func ParsePaymentTerms(s string) (PaymentTerms, error) {
+	fields := strings.Fields(strings.TrimSpace(s))
+
+	if len(fields) == 0 {
+		return DefaultPaymentTerms, nil
+	}
+
+	if strings.EqualFold(strings.Join(fields, " "), "Due on receipt") {
+		return PaymentTerms{}, nil
+	}
+
+	var terms PaymentTerms
+
+	// optional discount clause, e.g. "2/10"
+	if len(fields) == 3 {
+		pct, days, found := strings.Cut(fields[0], "/")
+		if !found {
+			return PaymentTerms{}, fmt.Errorf("invalid discount clause %q in payment terms %q", fields[0], s)
+		}
+
+		discountPercent, err := strconv.ParseFloat(pct, 64)
+		if err != nil || discountPercent <= 0 || discountPercent >= 100 {
+			return PaymentTerms{}, fmt.Errorf("invalid discount percent %q in payment terms %q", pct, s)
+		}
+
+		discountDays, err := strconv.Atoi(days)
+		if err != nil || discountDays <= 0 {
+			return PaymentTerms{}, fmt.Errorf("invalid discount days %q in payment terms %q", days, s)
+		}
+
+		terms.DiscountPercent = discountPercent
+		terms.DiscountDays = discountDays
+		fields = fields[1:]
+	}
+
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "Net") {
+		return PaymentTerms{}, fmt.Errorf("invalid payment terms %q", s)
+	}
+
+	netDays, err := strconv.Atoi(fields[1])
+	if err != nil || netDays < 0 {
+		return PaymentTerms{}, fmt.Errorf("invalid net days %q in payment terms %q", fields[1], s)
+	}
+	terms.NetDays = netDays
+
+	if terms.DiscountDays > terms.NetDays {
+		return PaymentTerms{}, fmt.Errorf("discount period exceeds net period in payment terms %q", s)
+	}
+
+	return terms, nil
+}
+
+// formats the terms the way ParsePaymentTerms reads them
+func (t PaymentTerms) String() string {
+	if t.NetDays == 0 && t.DiscountDays == 0 {
+		return "Due on receipt"
+	}
+
+	net := fmt.Sprintf("Net %d", t.NetDays)
+	if t.DiscountDays == 0 {
+		return net
+	}
+
+	return fmt.Sprintf("%s/%d %s", strconv.FormatFloat(t.DiscountPercent, 'f', -1, 64), t.DiscountDays, net)
+}
+
+// the date on which a document dated `from` becomes due
+func (t PaymentTerms) DueDate(from time.Time) time.Time {
+	return from.AddDate(0, 0, t.NetDays)
+}
+
+// the last date on which the early-payment discount may be taken;
+// false if the terms offer no discount
+func (t PaymentTerms) DiscountDeadline(from time.Time) (time.Time, bool) {
+	if t.DiscountDays == 0 {
+		return time.Time{}, false
+	}
+	return from.AddDate(0, 0, t.DiscountDays), true
+}
+
+// the discount earned by settling `balance` on `paidOn` for a document dated `from`
+func (t PaymentTerms) DiscountFor(from, paidOn time.Time, balance float64) float64 {
+	deadline, ok := t.DiscountDeadline(from)
+	if !ok || paidOn.After(deadline) {
+		return 0
+	}
+	return RoundCents(balance * t.DiscountPercent / 100)
+}
@@ -0,0 +1,166 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// how a fiscal year is divided into periods
+type PeriodPattern string
+
+const (
+	MonthlyPeriods PeriodPattern = "Monthly"
+	// four quarters of 4, 4 and 5 weeks; the twelfth period runs to the end
+	// of the fiscal year, taking up the day or two beyond 52 weeks
+	FourFourFivePeriods PeriodPattern = "4-4-5"
+)
+
+type PeriodStatus string
+
+const (
+	PeriodOpen       PeriodStatus = "Open"
+	PeriodSoftClosed PeriodStatus = "Soft Closed" // only admins may post
+	PeriodClosed     PeriodStatus = "Closed"
+)
+
+// the weeks in each period of a 4-4-5 year
+var fourFourFiveWeeks = [12]int{4, 4, 5, 4, 4, 5, 4, 4, 5, 4, 4, 5}
+
+// the shape of the book's fiscal years
+type FiscalCalendar struct {
+	StartMonth time.Month    `json:"start_month"` // fiscal years begin on the first of this month
+	Pattern    PeriodPattern `json:"pattern"`
+}
+
+// the calendar of a book which has not configured one: calendar years, in months
+var DefaultFiscalCalendar = FiscalCalendar{StartMonth: time.January, Pattern: MonthlyPeriods}
+
+// a division of a fiscal year, to which postings may be locked
+type AccountingPeriod struct {
+	ID         string       `json:"id"`
+	FiscalYear int          `json:"fiscal_year"`
+	Number     int          `json:"number"` // 1 through 12 within the year
+	Start      time.Time    `json:"start"`
+	End        time.Time    `json:"end"` // inclusive
+	Status     PeriodStatus `json:"status"`
+}
+
+// a record of a period's status having been changed
+type PeriodStatusChange struct {
+	ID        string       `json:"id"`
+	PeriodID  string       `json:"period_id"`
+	From      PeriodStatus `json:"from"`
+	To        PeriodStatus `json:"to"`
+	Reason    string       `json:"reason"`
+	ChangedAt time.Time    `json:"changed_at"`
+}
+
+// Validate checks that the calendar can produce periods
+func (c FiscalCalendar) Validate() error {
+	if c.StartMonth < time.January || c.StartMonth > time.December {
+		return fmt.Errorf("fiscal years cannot start in month %d", c.StartMonth)
+	}
+
+	switch c.Pattern {
+	case MonthlyPeriods, FourFourFivePeriods:
+	default:
+		return fmt.Errorf("unknown period pattern \"%s\"", c.Pattern)
+	}
+
+	return nil
+}
+
+// YearBounds reports the first and last days of the fiscal year, which is
+// named for the calendar year in which it ends
+func (c FiscalCalendar) YearBounds(fiscalYear int) (time.Time, time.Time) {
+	startYear := fiscalYear
+	if c.StartMonth != time.January {
+		startYear--
+	}
+
+	start := time.Date(startYear, c.StartMonth, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, -1)
+}
+
+// FiscalYearOf reports the fiscal year in which the date falls
+func (c FiscalCalendar) FiscalYearOf(date time.Time) int {
+	year := date.Year()
+	if c.StartMonth != time.January && date.Month() >= c.StartMonth {
+		year++
+	}
+	return year
+}
+
+// Periods divides the fiscal year into twelve open periods
+func (c FiscalCalendar) Periods(fiscalYear int) []AccountingPeriod {
+	yearStart, yearEnd := c.YearBounds(fiscalYear)
+
+	periods := make([]AccountingPeriod, 12)
+	start := yearStart
+	for i := range periods {
+		var end time.Time
+		switch {
+		case i == len(periods)-1:
+			end = yearEnd
+		case c.Pattern == FourFourFivePeriods:
+			end = start.AddDate(0, 0, 7*fourFourFiveWeeks[i]-1)
+		default:
+			end = start.AddDate(0, 1, -1)
+		}
+
+		periods[i] = AccountingPeriod{
+			ID:         NewID(),
+			FiscalYear: fiscalYear,
+			Number:     i + 1,
+			Start:      start,
+			End:        end,
+			Status:     PeriodOpen,
+		}
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return periods
+}
+
+// Covers reports whether the date falls within the period
+func (p *AccountingPeriod) Covers(date time.Time) bool {
+	return withinDays(date, p.Start, p.End)
+}
+
+// SetStatus moves the period to a new status, returning the record of the change
+func (p *AccountingPeriod) SetStatus(status PeriodStatus, reason string, at time.Time) (PeriodStatusChange, error) {
+	switch status {
+	case PeriodOpen, PeriodSoftClosed, PeriodClosed:
+	default:
+		return PeriodStatusChange{}, fmt.Errorf("unknown period status \"%s\"", status)
+	}
+
+	if status == p.Status {
+		return PeriodStatusChange{}, fmt.Errorf("period %d of %d is already %s", p.Number, p.FiscalYear, strings.ToLower(string(status)))
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return PeriodStatusChange{}, errors.New("changing a period's status requires a reason")
+	}
+
+	change := PeriodStatusChange{
+		ID:        NewID(),
+		PeriodID:  p.ID,
+		From:      p.Status,
+		To:        status,
+		Reason:    strings.TrimSpace(reason),
+		ChangedAt: at,
+	}
+	p.Status = status
+
+	return change, nil
+}
+
+// SoftClosedPostingsAllowed reports whether the context may post into
+// soft-closed periods: if acting for the system or an admin
+func SoftClosedPostingsAllowed(ctx context.Context) bool {
+	return Authorize(ctx, AdminRole, "post into a soft-closed period") == nil
+}
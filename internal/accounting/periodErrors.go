@@ -0,0 +1,62 @@
+package accounting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ErrPeriodNotFound struct {
+	ID string
+}
+
+// an entry dated within a closed period, or a soft-closed one by someone who
+// may not post there
+type ErrPeriodClosed struct {
+	EntryID     string
+	Date        time.Time
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Status      PeriodStatus
+}
+
+// a fiscal calendar change once periods carry a close or reopen history,
+// which regenerating them would discard
+type ErrCalendarLocked struct {
+	Periods int
+}
+
+func (e *ErrPeriodNotFound) Error() string {
+	return fmt.Sprintf("accounting period \"%s\" not found", e.ID)
+}
+
+func (e *ErrPeriodClosed) Error() string {
+	return fmt.Sprintf(
+		"journal entry \"%s\" is dated %s, within the period %s to %s, which is %s",
+		e.EntryID,
+		e.Date.Format(time.DateOnly),
+		e.PeriodStart.Format(time.DateOnly),
+		e.PeriodEnd.Format(time.DateOnly),
+		strings.ToLower(string(e.Status)),
+	)
+}
+
+func (e *ErrCalendarLocked) Error() string {
+	return fmt.Sprintf("the fiscal calendar cannot change once a period has been closed; %d period(s) have a status history", e.Periods)
+}
+
+// --------- helper utilities ------------
+func IsPeriodNotFound(err error) bool {
+	_, ok := err.(*ErrPeriodNotFound)
+	return ok
+}
+
+func IsPeriodClosed(err error) bool {
+	_, ok := err.(*ErrPeriodClosed)
+	return ok
+}
+
+func IsCalendarLocked(err error) bool {
+	_, ok := err.(*ErrCalendarLocked)
+	return ok
+}
@@ -0,0 +1,109 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFiscalCalendar(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("names non-calendar years for the year in which they end", func(t *testing.T) {
+		calendar := FiscalCalendar{StartMonth: time.July, Pattern: MonthlyPeriods}
+
+		start, end := calendar.YearBounds(2025)
+		if !start.Equal(day(2024, time.July, 1)) || !end.Equal(day(2025, time.June, 30)) {
+			t.Fatalf("expected fiscal 2025 to run 2024-07-01 to 2025-06-30, got %v to %v", start, end)
+		}
+		if fy := calendar.FiscalYearOf(day(2024, time.July, 1)); fy != 2025 {
+			t.Fatalf("expected 2024-07-01 to fall in fiscal 2025, got %d", fy)
+		}
+		if fy := calendar.FiscalYearOf(day(2024, time.June, 30)); fy != 2024 {
+			t.Fatalf("expected 2024-06-30 to fall in fiscal 2024, got %d", fy)
+		}
+	})
+
+	t.Run("divides a year into contiguous months", func(t *testing.T) {
+		calendar := FiscalCalendar{StartMonth: time.July, Pattern: MonthlyPeriods}
+
+		periods := calendar.Periods(2025)
+		if len(periods) != 12 {
+			t.Fatalf("expected 12 periods, got %d", len(periods))
+		}
+		if !periods[7].Start.Equal(day(2025, time.February, 1)) || !periods[7].End.Equal(day(2025, time.February, 28)) {
+			t.Fatalf("expected period 8 to be February 2025, got %v to %v", periods[7].Start, periods[7].End)
+		}
+		for i := 1; i < len(periods); i++ {
+			if !periods[i].Start.Equal(periods[i-1].End.AddDate(0, 0, 1)) {
+				t.Fatalf("expected period %d to begin the day after period %d ends", i+1, i)
+			}
+		}
+	})
+
+	t.Run("divides a year into 4-4-5 periods ending on the year end", func(t *testing.T) {
+		calendar := FiscalCalendar{StartMonth: time.January, Pattern: FourFourFivePeriods}
+
+		periods := calendar.Periods(2025)
+		if !periods[0].End.Equal(day(2025, time.January, 28)) {
+			t.Fatalf("expected a four week first period, got one ending %v", periods[0].End)
+		}
+		if !periods[2].Start.Equal(day(2025, time.February, 26)) || !periods[2].End.Equal(day(2025, time.April, 1)) {
+			t.Fatalf("expected a five week third period, got %v to %v", periods[2].Start, periods[2].End)
+		}
+		if !periods[11].End.Equal(day(2025, time.December, 31)) {
+			t.Fatalf("expected the last period to end on the year end, got %v", periods[11].End)
+		}
+		if !periods[11].Covers(day(2025, time.December, 31)) || periods[10].Covers(day(2025, time.December, 31)) {
+			t.Fatal("expected only the last period to cover the year end")
+		}
+	})
+
+	t.Run("rejects an invalid calendar", func(t *testing.T) {
+		if err := (FiscalCalendar{StartMonth: 13, Pattern: MonthlyPeriods}).Validate(); err == nil {
+			t.Fatal("expected an error for month 13")
+		}
+		if err := (FiscalCalendar{StartMonth: time.March, Pattern: "Weekly"}).Validate(); err == nil {
+			t.Fatal("expected an error for an unknown pattern")
+		}
+	})
+}
+
+func TestAccountingPeriodSetStatus(t *testing.T) {
+	at := time.Date(2025, 2, 5, 9, 0, 0, 0, time.UTC)
+
+	t.Run("records the change", func(t *testing.T) {
+		period := DefaultFiscalCalendar.Periods(2025)[0]
+
+		change, err := period.SetStatus(PeriodClosed, "January reconciled", at)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if period.Status != PeriodClosed {
+			t.Fatalf("expected the period to be closed, got %s", period.Status)
+		}
+		if change.PeriodID != period.ID || change.From != PeriodOpen || change.To != PeriodClosed || !change.ChangedAt.Equal(at) {
+			t.Fatalf("unexpected change %+v", change)
+		}
+	})
+
+	t.Run("requires a reason", func(t *testing.T) {
+		period := DefaultFiscalCalendar.Periods(2025)[0]
+
+		if _, err := period.SetStatus(PeriodSoftClosed, "  ", at); err == nil {
+			t.Fatal("expected an error without a reason")
+		}
+		if period.Status != PeriodOpen {
+			t.Fatalf("expected the period to remain open, got %s", period.Status)
+		}
+	})
+
+	t.Run("rejects the current status", func(t *testing.T) {
+		period := DefaultFiscalCalendar.Periods(2025)[0]
+
+		if _, err := period.SetStatus(PeriodOpen, "no change", at); err == nil {
+			t.Fatal("expected an error reopening an open period")
+		}
+	})
+}
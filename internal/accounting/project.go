@@ -0,0 +1,132 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ProjectStatus string
+
+const (
+	ProjectActive ProjectStatus = "Active"
+	ProjectClosed ProjectStatus = "Closed" // no further time may be recorded
+)
+
+// representation of a project; a job against which revenue, materials and
+// labor can be tagged so its margin can be measured
+type Project struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	CustomerID  string        `json:"customer_id"` // empty for internal projects
+	StartDate   time.Time     `json:"start_date"`
+	Status      ProjectStatus `json:"status"`
+	Description string        `json:"description"`
+}
+
+// representation of direct labor hours worked by a person on a project
+type TimeEntry struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"project_id"`
+	Person    string    `json:"person"`
+	Date      time.Time `json:"date"`
+	Hours     float64   `json:"hours"`
+	Rate      float64   `json:"rate"` // cost per hour
+	// the account charged with the labor cost, e.g. work in process or direct labor
+	DebitAccountName string `json:"debit_account_name"`
+	// the account credited, e.g. accrued payroll
+	CreditAccountName string `json:"credit_account_name"`
+	Memo              string `json:"memo"`
+	EntryID           string `json:"entry_id"` // empty when the hours carry no cost
+}
+
+// constructor for a new, active Project
+func NewProject(name string, customerID string, startDate time.Time, description string) (*Project, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("Project requires a non-empty string for its Name attribute")
+	}
+
+	if startDate.IsZero() {
+		return nil, errors.New("Project requires a start date")
+	}
+
+	return &Project{
+		ID:          NewID(),
+		Name:        strings.TrimSpace(name),
+		CustomerID:  customerID,
+		StartDate:   startDate,
+		Status:      ProjectActive,
+		Description: strings.TrimSpace(description),
+	}, nil
+}
+
+// constructor for a new TimeEntry against an active project
+func NewTimeEntry(
+	project *Project,
+	person string,
+	date time.Time,
+	hours float64,
+	rate float64,
+	debitAccountName string,
+	creditAccountName string,
+) (*TimeEntry, error) {
+	if project.Status != ProjectActive {
+		return nil, fmt.Errorf("project \"%s\" is closed", project.Name)
+	}
+
+	if strings.TrimSpace(person) == "" {
+		return nil, errors.New("TimeEntry requires the person who worked the hours")
+	}
+
+	if date.IsZero() {
+		return nil, errors.New("TimeEntry requires a work date")
+	}
+
+	if hours <= 0 {
+		return nil, errors.New("TimeEntry requires a positive number of hours")
+	}
+
+	if rate < 0 {
+		return nil, errors.New("TimeEntry requires a rate of at least zero")
+	}
+
+	if strings.TrimSpace(debitAccountName) == "" || strings.TrimSpace(creditAccountName) == "" {
+		return nil, errors.New("TimeEntry requires accounts to debit and credit")
+	}
+
+	return &TimeEntry{
+		ID:                NewID(),
+		ProjectID:         project.ID,
+		Person:            strings.TrimSpace(person),
+		Date:              date,
+		Hours:             hours,
+		Rate:              rate,
+		DebitAccountName:  debitAccountName,
+		CreditAccountName: creditAccountName,
+	}, nil
+}
+
+// the labor cost of the entry
+func (t *TimeEntry) Cost() float64 {
+	return RoundCents(t.Hours * t.Rate)
+}
+
+// JournalEntry produces the entry which posts the labor cost: the debit
+// account, tagged with the project, against the credit account.
+func (t *TimeEntry) JournalEntry(project *Project) JournalEntry {
+	description := fmt.Sprintf("%s hours by %s on %s", formatQuantity(t.Hours), t.Person, project.Name)
+	if t.Memo != "" {
+		description += "; " + t.Memo
+	}
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   t.Date,
+		Description: description,
+		Lines: []JournalEntryLine{
+			{AccountName: t.DebitAccountName, Amount: t.Cost(), Side: Debit, ProjectID: t.ProjectID},
+			{AccountName: t.CreditAccountName, Amount: t.Cost(), Side: Credit},
+		},
+	}
+}
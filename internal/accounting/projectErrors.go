@@ -0,0 +1,17 @@
+package accounting
+
+import "fmt"
+
+type ErrProjectNotFound struct {
+	ID string
+}
+
+func (e *ErrProjectNotFound) Error() string {
+	return fmt.Sprintf("project \"%s\" not found", e.ID)
+}
+
+// --------- helper utilities ------------
+func IsProjectNotFound(err error) bool {
+	_, ok := err.(*ErrProjectNotFound)
+	return ok
+}
@@ -0,0 +1,170 @@
+package accounting
+
+import (
+	"sort"
+	"time"
+)
+
+// the kind of document which posted a project's journal line
+type ProjectLineSource string
+
+const (
+	SourceTimeEntry       ProjectLineSource = "Time Entry"
+	SourceUnitSale        ProjectLineSource = "Unit Sale"
+	SourceStockMovement   ProjectLineSource = "Stock Movement"
+	SourceStockAdjustment ProjectLineSource = "Stock Cost Adjustment"
+	SourceInvoice         ProjectLineSource = "Invoice"
+	SourceBill            ProjectLineSource = "Bill"
+	SourceJournal         ProjectLineSource = "Journal Entry" // posted directly
+)
+
+// the margin report's classification of a project's journal line
+type ProjectCategory string
+
+const (
+	ProjectRevenue     ProjectCategory = "Revenue"
+	ProjectMaterials   ProjectCategory = "Materials"
+	ProjectLabor       ProjectCategory = "Labor"
+	ProjectOtherDirect ProjectCategory = "Other Direct Costs"
+)
+
+// a journal line tagged with a project, with the entry and document it belongs to
+type ProjectLine struct {
+	EntryID     string            `json:"entry_id"`
+	Date        time.Time         `json:"date"`
+	Description string            `json:"description"` // the entry's
+	AccountName string            `json:"account_name"`
+	AccountType AccountType       `json:"account_type"`
+	Amount      float64           `json:"amount"`
+	Side        EntrySide         `json:"side"`
+	ProjectID   string            `json:"project_id"`
+	Source      ProjectLineSource `json:"source"`
+}
+
+// Category classifies the line: revenue by its account, and costs by the
+// document which posted them, so that labor charged to work in process and
+// materials issued to it are told apart.
+func (l *ProjectLine) Category() ProjectCategory {
+	switch {
+	case l.AccountType == Revenue:
+		return ProjectRevenue
+	case l.Source == SourceTimeEntry:
+		return ProjectLabor
+	case l.Source == SourceUnitSale || l.Source == SourceStockMovement || l.Source == SourceStockAdjustment:
+		return ProjectMaterials
+	default:
+		return ProjectOtherDirect
+	}
+}
+
+// the signed amount the line contributes to its category: credits increase
+// revenue, and debits increase costs
+func (l *ProjectLine) Contribution() float64 {
+	if (l.Category() == ProjectRevenue) == (l.Side == Credit) {
+		return l.Amount
+	}
+	return -l.Amount
+}
+
+// a project's profitability over a date range, with the lines and hours behind it
+type ProjectMargin struct {
+	Project       *Project      `json:"project"`
+	Revenue       float64       `json:"revenue"`
+	Materials     float64       `json:"materials"`
+	Labor         float64       `json:"labor"`
+	OtherDirect   float64       `json:"other_direct"`
+	GrossMargin   float64       `json:"gross_margin"`
+	MarginPercent float64       `json:"margin_percent"` // of revenue; zero without revenue
+	Hours         float64       `json:"hours"`          // direct labor hours
+	ProfitPerHour float64       `json:"profit_per_hour"`
+	Lines         []ProjectLine `json:"lines"`
+	TimeEntries   []TimeEntry   `json:"time_entries"`
+}
+
+// HasHours reports whether any direct labor was recorded, without which
+// profit per hour is undefined
+func (m *ProjectMargin) HasHours() bool {
+	return m.Hours > quantityTolerance
+}
+
+// the margins of every project over a date range, most profitable per hour first
+type ProjectMarginReport struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"` // inclusive
+	Projects []ProjectMargin `json:"projects"`
+}
+
+// BuildProjectMargin totals the project's lines and time entries dated
+// within [from, to], inclusive of both days.
+func BuildProjectMargin(project *Project, lines []ProjectLine, entries []TimeEntry, from, to time.Time) ProjectMargin {
+	margin := ProjectMargin{Project: project}
+
+	for _, line := range lines {
+		if line.ProjectID != project.ID || !withinDays(line.Date, from, to) {
+			continue
+		}
+		margin.Lines = append(margin.Lines, line)
+
+		switch line.Category() {
+		case ProjectRevenue:
+			margin.Revenue += line.Contribution()
+		case ProjectMaterials:
+			margin.Materials += line.Contribution()
+		case ProjectLabor:
+			margin.Labor += line.Contribution()
+		default:
+			margin.OtherDirect += line.Contribution()
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.ProjectID != project.ID || !withinDays(entry.Date, from, to) {
+			continue
+		}
+		margin.TimeEntries = append(margin.TimeEntries, entry)
+		margin.Hours += entry.Hours
+	}
+
+	margin.Revenue = RoundCents(margin.Revenue)
+	margin.Materials = RoundCents(margin.Materials)
+	margin.Labor = RoundCents(margin.Labor)
+	margin.OtherDirect = RoundCents(margin.OtherDirect)
+	margin.GrossMargin = RoundCents(margin.Revenue - margin.Materials - margin.Labor - margin.OtherDirect)
+
+	if margin.Revenue != 0 {
+		margin.MarginPercent = RoundCents(margin.GrossMargin / margin.Revenue * 100)
+	}
+	if margin.HasHours() {
+		margin.ProfitPerHour = RoundCents(margin.GrossMargin / margin.Hours)
+	}
+
+	return margin
+}
+
+// BuildProjectMarginReport compares the projects over [from, to], sorted by
+// profit per direct labor hour; projects without hours follow, by gross margin.
+func BuildProjectMarginReport(projects []*Project, lines []ProjectLine, entries []TimeEntry, from, to time.Time) ProjectMarginReport {
+	report := ProjectMarginReport{From: from, To: to}
+
+	for _, project := range projects {
+		report.Projects = append(report.Projects, BuildProjectMargin(project, lines, entries, from, to))
+	}
+
+	sort.SliceStable(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.HasHours() != b.HasHours() {
+			return a.HasHours()
+		}
+		if a.HasHours() && a.ProfitPerHour != b.ProfitPerHour {
+			return a.ProfitPerHour > b.ProfitPerHour
+		}
+		return a.GrossMargin > b.GrossMargin
+	})
+
+	return report
+}
+
+// whether t falls on a day within [from, to]
+func withinDays(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to.AddDate(0, 0, 1))
+}
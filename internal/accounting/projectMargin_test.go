@@ -0,0 +1,71 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildProjectMarginReport(t *testing.T) {
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC) }
+
+	kitchen := &Project{ID: "kitchen", Name: "Kitchen", Status: ProjectActive}
+	deck := &Project{ID: "deck", Name: "Deck", Status: ProjectActive}
+	shed := &Project{ID: "shed", Name: "Shed", Status: ProjectActive}
+
+	lines := []ProjectLine{
+		{ProjectID: "kitchen", Date: day(20), AccountName: "Sales", AccountType: Revenue, Amount: 5000, Side: Credit, Source: SourceInvoice},
+		{ProjectID: "kitchen", Date: day(5), AccountName: "Work in Process", AccountType: Asset, Amount: 1200, Side: Debit, Source: SourceStockMovement},
+		{ProjectID: "kitchen", Date: day(6), AccountName: "Work in Process", AccountType: Asset, Amount: 800, Side: Debit, Source: SourceTimeEntry},
+		{ProjectID: "kitchen", Date: day(7), AccountName: "Permits", AccountType: Expense, Amount: 100, Side: Debit, Source: SourceBill},
+		{ProjectID: "kitchen", Date: day(8), AccountName: "Work in Process", AccountType: Asset, Amount: 50, Side: Credit, Source: SourceStockAdjustment},
+		// outside the range
+		{ProjectID: "kitchen", Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), AccountName: "Sales", AccountType: Revenue, Amount: 999, Side: Credit, Source: SourceInvoice},
+
+		{ProjectID: "deck", Date: day(30), AccountName: "Sales", AccountType: Revenue, Amount: 3000, Side: Credit, Source: SourceInvoice},
+		{ProjectID: "deck", Date: day(10), AccountName: "Direct Labor", AccountType: Expense, Amount: 600, Side: Debit, Source: SourceTimeEntry},
+
+		{ProjectID: "shed", Date: day(12), AccountName: "Sales", AccountType: Revenue, Amount: 400, Side: Credit, Source: SourceJournal},
+	}
+	entries := []TimeEntry{
+		{ProjectID: "kitchen", Date: day(6), Hours: 20, Rate: 40},
+		{ProjectID: "kitchen", Date: day(9), Hours: 5, Rate: 0},
+		{ProjectID: "deck", Date: day(10), Hours: 15, Rate: 40},
+		{ProjectID: "deck", Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Hours: 100, Rate: 40},
+	}
+
+	report := BuildProjectMarginReport([]*Project{shed, kitchen, deck}, lines, entries, from, to)
+
+	t.Run("classifies revenue and costs", func(t *testing.T) {
+		var kitchenMargin ProjectMargin
+		for _, m := range report.Projects {
+			if m.Project == kitchen {
+				kitchenMargin = m
+			}
+		}
+
+		if kitchenMargin.Revenue != 5000 || kitchenMargin.Materials != 1150 || kitchenMargin.Labor != 800 || kitchenMargin.OtherDirect != 100 {
+			t.Fatalf("unexpected totals %+v", kitchenMargin)
+		}
+		if kitchenMargin.GrossMargin != 2950 || kitchenMargin.MarginPercent != 59 {
+			t.Fatalf("expected a 2950 margin at 59%%, got %v at %v%%", kitchenMargin.GrossMargin, kitchenMargin.MarginPercent)
+		}
+		if kitchenMargin.Hours != 25 || kitchenMargin.ProfitPerHour != 118 {
+			t.Fatalf("expected 118 per hour over 25 hours, got %v over %v", kitchenMargin.ProfitPerHour, kitchenMargin.Hours)
+		}
+		if len(kitchenMargin.Lines) != 5 || len(kitchenMargin.TimeEntries) != 2 {
+			t.Fatalf("expected the drill-down limited to the range, got %d lines and %d entries", len(kitchenMargin.Lines), len(kitchenMargin.TimeEntries))
+		}
+	})
+
+	t.Run("sorts by profit per hour, then projects without hours", func(t *testing.T) {
+		if report.Projects[0].Project != deck || report.Projects[1].Project != kitchen || report.Projects[2].Project != shed {
+			t.Fatalf("expected deck (160/h), kitchen (118/h), then shed, got %s, %s, %s",
+				report.Projects[0].Project.Name, report.Projects[1].Project.Name, report.Projects[2].Project.Name)
+		}
+		if report.Projects[2].HasHours() || report.Projects[2].ProfitPerHour != 0 {
+			t.Fatalf("expected no profit per hour without hours, got %+v", report.Projects[2])
+		}
+	})
+}
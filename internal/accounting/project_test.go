@@ -0,0 +1,64 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeEntry(t *testing.T) {
+	worked := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	project, err := NewProject("Kitchen Remodel", "", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("posts the labor cost tagged with the project", func(t *testing.T) {
+		entry, err := NewTimeEntry(project, "Dana", worked, 7.5, 40, "Work in Process", "Accrued Payroll")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		je := entry.JournalEntry(project)
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		labor, payroll := je.Lines[0], je.Lines[1]
+		if labor.AccountName != "Work in Process" || labor.Amount != 300 || labor.Side != Debit || labor.ProjectID != project.ID {
+			t.Fatalf("expected 300 debited to WIP for the project, got %+v", labor)
+		}
+		if payroll.AccountName != "Accrued Payroll" || payroll.Side != Credit || payroll.ProjectID != "" {
+			t.Fatalf("expected an untagged credit to accrued payroll, got %+v", payroll)
+		}
+	})
+
+	t.Run("refuses hours on a closed project", func(t *testing.T) {
+		closed := *project
+		closed.Status = ProjectClosed
+
+		if _, err := NewTimeEntry(&closed, "Dana", worked, 1, 40, "Work in Process", "Accrued Payroll"); err == nil {
+			t.Fatalf("expected an error recording time on a closed project")
+		}
+	})
+
+	t.Run("requires positive hours", func(t *testing.T) {
+		if _, err := NewTimeEntry(project, "Dana", worked, 0, 40, "Work in Process", "Accrued Payroll"); err == nil {
+			t.Fatalf("expected an error for zero hours")
+		}
+	})
+}
+
+func TestStockIssueTaggedWithProject(t *testing.T) {
+	item, _ := NewStockItem("OAK-1", "Oak board", FIFO, "Inventory", "Cost of Goods Sold")
+	issue, _ := NewStockIssue(item, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC), 2, "Work in Process")
+	issue.ProjectID = "project"
+	issue.Cost = 50
+
+	je := issue.JournalEntry(item)
+	if je.Lines[0].AccountName != "Work in Process" || je.Lines[0].Side != Debit || je.Lines[0].ProjectID != "project" {
+		t.Fatalf("expected the debit to carry the project, got %+v", je.Lines[0])
+	}
+	if je.Lines[1].AccountName != "Inventory" || je.Lines[1].ProjectID != "" {
+		t.Fatalf("expected an untagged credit to inventory, got %+v", je.Lines[1])
+	}
+}
@@ -0,0 +1,30 @@
+package accounting
+
+import "fmt"
+
+type ErrCustomerNotFound struct {
+	ID string
+}
+
+type ErrInvoiceNotFound struct {
+	ID string
+}
+
+func (e *ErrCustomerNotFound) Error() string {
+	return fmt.Sprintf("customer \"%s\" not found", e.ID)
+}
+
+func (e *ErrInvoiceNotFound) Error() string {
+	return fmt.Sprintf("invoice \"%s\" not found", e.ID)
+}
+
+// --------- helper utilities ------------
+func IsCustomerNotFound(err error) bool {
+	_, ok := err.(*ErrCustomerNotFound)
+	return ok
+}
+
+func IsInvoiceNotFound(err error) bool {
+	_, ok := err.(*ErrInvoiceNotFound)
+	return ok
+}
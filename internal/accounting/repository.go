@@ -1,6 +1,9 @@
 package accounting
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AccountGroupRepository interface {
 	Insert(ctx context.Context, group *AccountGroup) error
@@ -18,6 +21,230 @@ type AccountRepository interface {
 
 type JournalEntryRepository interface {
 	Save(ctx context.Context, je JournalEntry) error
-	// ByID(ctx context.Context, id string) (JournalEntry, error)
+	ByID(ctx context.Context, id string) (JournalEntry, error)
+	// totals per account for entries dated within [from, to)
+	AccountTotals(ctx context.Context, from, to time.Time) ([]AccountTotal, error)
+	// lines tagged with a project on entries dated within [from, to)
+	ProjectLines(ctx context.Context, from, to time.Time) ([]ProjectLine, error)
+	// every posted entry with its stored hashes, in ID order
+	Chain(ctx context.Context) ([]ChainLink, error)
 	// ListByAccount(ctx context.Context, accountID string) ([]JournalEntry, error)
 }
+
+// the audit trail is written by the repositories of the records it covers,
+// in the same transaction as each change, and is never updated
+type AuditRepository interface {
+	// the records matching the filter, newest first
+	Records(ctx context.Context, filter AuditFilter) ([]AuditRecord, error)
+	// the records of a single entity, in the order they occurred
+	History(ctx context.Context, entityType EntityType, entityID string) ([]AuditRecord, error)
+}
+
+// reads the books whole for the integrity check, including what the other
+// repositories cannot represent, such as lines without an entry
+type IntegrityRepository interface {
+	Load(ctx context.Context) (IntegrityData, error)
+}
+
+type VendorRepository interface {
+	Save(ctx context.Context, vendor *Vendor) error
+	ByID(ctx context.Context, id string) (Vendor, error)
+	GetAll(ctx context.Context) ([]*Vendor, error)
+}
+
+type BillRepository interface {
+	// saves the bill and its journal entry atomically
+	Post(ctx context.Context, bill *Bill, je JournalEntry) error
+	ByID(ctx context.Context, id string) (Bill, error)
+	GetAll(ctx context.Context) ([]*Bill, error)
+	// saves the payment and its journal entry atomically
+	RecordPayment(ctx context.Context, payment *BillPayment, je JournalEntry) error
+	Payments(ctx context.Context, billID string) ([]BillPayment, error)
+}
+
+type CustomerRepository interface {
+	Save(ctx context.Context, customer *Customer) error
+	ByID(ctx context.Context, id string) (Customer, error)
+	GetAll(ctx context.Context) ([]*Customer, error)
+}
+
+type InvoiceRepository interface {
+	// saves the invoice and its journal entry atomically
+	Post(ctx context.Context, invoice *Invoice, je JournalEntry) error
+	ByID(ctx context.Context, id string) (Invoice, error)
+	GetAll(ctx context.Context) ([]*Invoice, error)
+	// saves the payment and its journal entry atomically
+	RecordPayment(ctx context.Context, payment *InvoicePayment, je JournalEntry) error
+	Payments(ctx context.Context, invoiceID string) ([]InvoicePayment, error)
+}
+
+type BookSettingsRepository interface {
+	Get(ctx context.Context) (BookSettings, error)
+	Save(ctx context.Context, settings *BookSettings) error
+}
+
+type AttachmentRepository interface {
+	Save(ctx context.Context, attachment *Attachment) error
+	ByID(ctx context.Context, id string) (Attachment, error)
+	// lists the attachments of a record, newest first, without their data
+	ListFor(ctx context.Context, entityType EntityType, entityID string) ([]Attachment, error)
+}
+
+type FixedAssetRepository interface {
+	Save(ctx context.Context, asset *FixedAsset) error
+	ByID(ctx context.Context, id string) (FixedAsset, error)
+	GetAll(ctx context.Context) ([]*FixedAsset, error)
+	DepreciationPostings(ctx context.Context) ([]DepreciationPosting, error)
+	// saves the postings and their shared journal entry atomically;
+	// fails without writing anything if any period was already posted
+	PostDepreciation(ctx context.Context, postings []DepreciationPosting, je JournalEntry) error
+	// saves the disposal and its journal entry atomically
+	PostDisposal(ctx context.Context, disposal *FixedAssetDisposal, je JournalEntry) error
+}
+
+type InventoryUnitRepository interface {
+	// saves the unit and its acquisition entry atomically
+	Acquire(ctx context.Context, unit *InventoryUnit, je JournalEntry) error
+	ByID(ctx context.Context, id string) (InventoryUnit, error)
+	GetAll(ctx context.Context) ([]*InventoryUnit, error)
+	Move(ctx context.Context, id string, location string) error
+	// saves the invoice, the sale and their shared journal entry atomically
+	Sell(ctx context.Context, sale *UnitSale, invoice *Invoice, je JournalEntry) error
+}
+
+type StockRepository interface {
+	SaveItem(ctx context.Context, item *StockItem) error
+	ItemByID(ctx context.Context, id string) (StockItem, error)
+	Items(ctx context.Context) ([]*StockItem, error)
+	// an item's movements, ordered by date then sequence
+	Movements(ctx context.Context, itemID string) ([]StockMovement, error)
+	// saves the movement, its entry, and the adjustments (with their entries)
+	// to issues it recosted, atomically
+	Record(ctx context.Context, movement *StockMovement, je JournalEntry, adjustments []StockCostAdjustment, adjustmentEntries []JournalEntry) error
+	Adjustments(ctx context.Context, movementID string) ([]StockCostAdjustment, error)
+}
+
+type InventoryCountRepository interface {
+	// inserts or updates an open count and its lines
+	Save(ctx context.Context, count *InventoryCount) error
+	ByID(ctx context.Context, id string) (InventoryCount, error)
+	GetAll(ctx context.Context) ([]*InventoryCount, error)
+	// saves the posted count with its variance movements, the entry they
+	// share, and the adjustments (with their entries) to issues they
+	// recosted, atomically; with no movements, no entry is posted
+	Post(
+		ctx context.Context,
+		count *InventoryCount,
+		movements []*StockMovement,
+		je JournalEntry,
+		adjustments []StockCostAdjustment,
+		adjustmentEntries []JournalEntry,
+	) error
+}
+
+type ProjectRepository interface {
+	Save(ctx context.Context, project *Project) error
+	ByID(ctx context.Context, id string) (Project, error)
+	GetAll(ctx context.Context) ([]*Project, error)
+	// saves the time entry and its journal entry atomically; hours without
+	// cost are saved without an entry
+	RecordTime(ctx context.Context, entry *TimeEntry, je JournalEntry) error
+	// a project's time entries, ordered by date
+	TimeEntries(ctx context.Context, projectID string) ([]TimeEntry, error)
+}
+
+type YearEndRepository interface {
+	// saves the close, its closing entry and an audit record atomically;
+	// fails with ErrYearAlreadyClosed if the year is closed
+	Close(ctx context.Context, close *YearEndClose, je JournalEntry, record YearEndAuditRecord) error
+	ByID(ctx context.Context, id string) (YearEndClose, error)
+	// every close, including those since reopened, latest year first
+	GetAll(ctx context.Context) ([]*YearEndClose, error)
+	// lifts the close, then saves the reversing entry and an audit record, atomically
+	Reopen(ctx context.Context, close *YearEndClose, reversal JournalEntry, record YearEndAuditRecord) error
+	AuditRecords(ctx context.Context, closeID string) ([]YearEndAuditRecord, error)
+}
+
+type PeriodRepository interface {
+	// the book's fiscal calendar; DefaultFiscalCalendar until one is saved
+	Calendar(ctx context.Context) (FiscalCalendar, error)
+	// replaces the calendar along with every period generated under it, atomically
+	SaveCalendar(ctx context.Context, calendar FiscalCalendar) error
+	// inserts newly generated periods
+	Insert(ctx context.Context, periods []AccountingPeriod) error
+	ByID(ctx context.Context, id string) (AccountingPeriod, error)
+	// a fiscal year's periods in order; empty if none have been generated
+	ForYear(ctx context.Context, fiscalYear int) ([]AccountingPeriod, error)
+	// saves the period's new status and the record of the change atomically
+	SetStatus(ctx context.Context, period *AccountingPeriod, change PeriodStatusChange) error
+	StatusChanges(ctx context.Context, periodID string) ([]PeriodStatusChange, error)
+}
+
+type SETaxRepository interface {
+	// the latest version of the rates for the latest tax year no later than
+	// taxYear; fails with ErrSETaxRatesNotFound if there are none
+	Rates(ctx context.Context, taxYear int) (SETaxRates, error)
+	// every version of a tax year's rates, latest first
+	RateVersions(ctx context.Context, taxYear int) ([]SETaxRates, error)
+	// saves the rates as the next version for their tax year
+	SaveRates(ctx context.Context, rates *SETaxRates) error
+	// saves the transfer and the entry which posts it atomically
+	PostReserve(ctx context.Context, transfer *TaxReserveTransfer, je JournalEntry) error
+	Reserves(ctx context.Context, taxYear int) ([]TaxReserveTransfer, error)
+	// saves the payment and the entry which posts it atomically
+	RecordPayment(ctx context.Context, payment *EstimatedTaxPayment, je JournalEntry) error
+	Payments(ctx context.Context, taxYear int) ([]EstimatedTaxPayment, error)
+}
+
+type TaxLineRepository interface {
+	// the forms with lines for a tax year, by name
+	Forms(ctx context.Context, taxYear int) ([]string, error)
+	// a form's lines for a tax year in display order
+	Lines(ctx context.Context, taxYear int, form string) ([]TaxLine, error)
+	// inserts the line, or replaces the description and order of an existing one
+	SaveLine(ctx context.Context, line TaxLine) error
+	// fails with ErrTaxLineNotFound if the line does not exist
+	DeleteLine(ctx context.Context, taxYear int, form, code string) error
+	Mappings(ctx context.Context, taxYear int, form string) ([]TaxLineMapping, error)
+	// maps the account to the line, replacing any mapping it had on the form that year
+	Map(ctx context.Context, mapping TaxLineMapping) error
+	Unmap(ctx context.Context, taxYear int, form, accountName string) error
+	// copies a year's lines and mappings to another year, keeping any the
+	// other year already has
+	CopyYear(ctx context.Context, fromYear, toYear int) error
+}
+
+type SalesTaxRepository interface {
+	// the jurisdictions, rates and codes currently configured, by name
+	Schedule(ctx context.Context) (SalesTaxSchedule, error)
+	// inserts the jurisdiction, or renames an existing one
+	SaveJurisdiction(ctx context.Context, jurisdiction SalesTaxJurisdiction) error
+	// inserts the rate, or replaces the name and rate of an existing one
+	SaveRate(ctx context.Context, rate SalesTaxRate) error
+	// inserts the code, or replaces the description and rates of an existing one
+	SaveCode(ctx context.Context, code SalesTaxCode) error
+	// saves the remittance and the entry which posts it atomically
+	RecordRemittance(ctx context.Context, remittance *SalesTaxRemittance, je JournalEntry) error
+	// remittances for periods ending within [from, to], by period end
+	Remittances(ctx context.Context, from, to time.Time) ([]SalesTaxRemittance, error)
+}
+
+type UserRepository interface {
+	// fails with ErrUserExists if the username is taken, regardless of case
+	Save(ctx context.Context, user *User) error
+	// fails with ErrUserNotFound if there is no such user
+	ByID(ctx context.Context, id string) (*User, error)
+	// matches regardless of case; fails with ErrUserNotFound if there is no such user
+	ByUsername(ctx context.Context, username string) (*User, error)
+	// every user by username
+	GetAll(ctx context.Context) ([]*User, error)
+}
+
+type SessionRepository interface {
+	Save(ctx context.Context, session *Session) error
+	// fails with ErrSessionNotFound if there is no such session
+	ByID(ctx context.Context, id string) (*Session, error)
+	Delete(ctx context.Context, id string) error
+	// removes the sessions which have expired by the given time
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,62 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// what a user may do with the books; each role may do everything the roles
+// before it may
+type Role string
+
+const (
+	// sees reports only
+	ViewerRole Role = "viewer"
+	// also posts entries: invoices, bills, payments, inventory, time and the like
+	BookkeeperRole Role = "bookkeeper"
+	// also edits the chart and tax settings, closes periods and years, and manages users
+	AdminRole Role = "admin"
+)
+
+// the roles from least to most privileged
+var Roles = []Role{ViewerRole, BookkeeperRole, AdminRole}
+
+// Validate checks that the role is one of Roles
+func (r Role) Validate() error {
+	if !slices.Contains(Roles, r) {
+		return fmt.Errorf("invalid role %q: use viewer, bookkeeper or admin", r)
+	}
+	return nil
+}
+
+// Includes reports whether the role may do everything the other may
+func (r Role) Includes(other Role) bool {
+	return slices.Index(Roles, r) >= slices.Index(Roles, other) && slices.Contains(Roles, other)
+}
+
+type systemKey struct{}
+
+// AsSystem marks the context as acting for the system itself, e.g. the
+// command line run by whoever administers the server, which may do anything
+func AsSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// Authorize fails with ErrPermissionDenied unless the context acts for the
+// system or for a user whose role includes the one required for the action
+func Authorize(ctx context.Context, required Role, action string) error {
+	if system, _ := ctx.Value(systemKey{}).(bool); system {
+		return nil
+	}
+
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return &ErrPermissionDenied{Action: action, Required: required}
+	}
+	if !user.Role.Includes(required) {
+		return &ErrPermissionDenied{Username: user.Username, Role: user.Role, Action: action, Required: required}
+	}
+
+	return nil
+}
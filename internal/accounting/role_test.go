@@ -0,0 +1,56 @@
+package accounting
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRole(t *testing.T) {
+	t.Run("includes the roles before it", func(t *testing.T) {
+		if !AdminRole.Includes(BookkeeperRole) || !BookkeeperRole.Includes(ViewerRole) || !ViewerRole.Includes(ViewerRole) {
+			t.Fatal("expected each role to include itself and those before it")
+		}
+		if ViewerRole.Includes(BookkeeperRole) || BookkeeperRole.Includes(AdminRole) {
+			t.Fatal("expected no role to include those after it")
+		}
+		if AdminRole.Includes("owner") || Role("owner").Includes(ViewerRole) {
+			t.Fatal("expected unknown roles to include and be included by nothing")
+		}
+	})
+
+	t.Run("rejects unknown roles", func(t *testing.T) {
+		if err := Role("owner").Validate(); err == nil {
+			t.Fatal("expected an error for an unknown role")
+		}
+		if _, err := NewUser("alice", "correct horse battery", ""); err == nil {
+			t.Fatal("expected an error for a user without a role")
+		}
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	bookkeeper, err := NewUser("alice", "correct horse battery", BookkeeperRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	ctx := WithUser(context.Background(), bookkeeper)
+
+	if err := Authorize(ctx, BookkeeperRole, "issue invoices"); err != nil {
+		t.Fatalf("expected a bookkeeper to issue invoices, got %v", err)
+	}
+
+	err = Authorize(ctx, AdminRole, "close periods")
+	if !IsPermissionDenied(err) {
+		t.Fatalf("expected ErrPermissionDenied, received %v", err)
+	}
+	if err.Error() != "permission denied: alice (bookkeeper) cannot close periods; the admin role is required" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+
+	if err := Authorize(context.Background(), ViewerRole, "see reports"); !IsPermissionDenied(err) {
+		t.Fatalf("expected ErrPermissionDenied without a user, received %v", err)
+	}
+	if err := Authorize(AsSystem(context.Background()), AdminRole, "manage users"); err != nil {
+		t.Fatalf("expected the system to do anything, got %v", err)
+	}
+}
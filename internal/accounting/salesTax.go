@@ -0,0 +1,391 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// a taxing authority to which collected sales tax is remitted; each carries
+// its own liability account, so what is owed to one is never netted against another
+type SalesTaxJurisdiction struct {
+	ID                   string `json:"id"`
+	Name                 string `json:"name"`
+	LiabilityAccountName string `json:"liability_account_name"`
+}
+
+// a rate levied by a jurisdiction, e.g. its general rate; editing a rate
+// affects invoices issued afterward, as invoices keep the tax they charged
+type SalesTaxRate struct {
+	ID             string  `json:"id"`
+	JurisdictionID string  `json:"jurisdiction_id"`
+	Name           string  `json:"name"`
+	Rate           float64 `json:"rate"` // e.g. 0.0625 for 6.25%
+}
+
+// a tax group: the code an invoice line carries, naming the rates which
+// apply to it. A code without rates marks the line exempt.
+type SalesTaxCode struct {
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	RateIDs     []string `json:"rate_ids"`
+}
+
+// the tax charged by one rate on one invoice line
+type InvoiceTax struct {
+	LineNo               int     `json:"line_no"` // 1-based, per the invoice's lines
+	RateID               string  `json:"rate_id"`
+	JurisdictionID       string  `json:"jurisdiction_id"`
+	Name                 string  `json:"name"` // the rate's name when charged
+	LiabilityAccountName string  `json:"liability_account_name"`
+	TaxableAmount        float64 `json:"taxable_amount"`
+	Amount               float64 `json:"amount"`
+}
+
+// the jurisdictions, rates and codes in effect, against which invoice lines are taxed
+type SalesTaxSchedule struct {
+	Jurisdictions []SalesTaxJurisdiction `json:"jurisdictions"`
+	Rates         []SalesTaxRate         `json:"rates"`
+	Codes         []SalesTaxCode         `json:"codes"`
+}
+
+// representation of collected tax paid over to a jurisdiction for a period
+type SalesTaxRemittance struct {
+	ID                   string    `json:"id"`
+	JurisdictionID       string    `json:"jurisdiction_id"`
+	PeriodStart          time.Time `json:"period_start"`
+	PeriodEnd            time.Time `json:"period_end"` // inclusive
+	PaidOn               time.Time `json:"paid_on"`
+	Amount               float64   `json:"amount"`
+	LiabilityAccountName string    `json:"liability_account_name"`
+	CashAccountName      string    `json:"cash_account_name"`
+	Memo                 string    `json:"memo"`
+	EntryID              string    `json:"entry_id"`
+}
+
+// a jurisdiction's figures on a sales tax return
+type SalesTaxReturnLine struct {
+	JurisdictionID string  `json:"jurisdiction_id"`
+	Jurisdiction   string  `json:"jurisdiction"`
+	TaxableSales   float64 `json:"taxable_sales"`
+	TaxCollected   float64 `json:"tax_collected"`
+	TaxRemitted    float64 `json:"tax_remitted"`
+	Due            float64 `json:"due"` // collected less remitted
+}
+
+// the sales and tax of invoices dated within a period, with the tax
+// remitted for it, per jurisdiction
+type SalesTaxReturn struct {
+	PeriodStart   time.Time            `json:"period_start"`
+	PeriodEnd     time.Time            `json:"period_end"` // inclusive
+	GrossSales    float64              `json:"gross_sales"`
+	TaxableSales  float64              `json:"taxable_sales"`
+	ExemptSales   float64              `json:"exempt_sales"` // lines no rate applied to
+	TaxCollected  float64              `json:"tax_collected"`
+	TaxRemitted   float64              `json:"tax_remitted"`
+	Jurisdictions []SalesTaxReturnLine `json:"jurisdictions"`
+}
+
+// constructor for a new SalesTaxJurisdiction
+func NewSalesTaxJurisdiction(name, liabilityAccountName string) (*SalesTaxJurisdiction, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("SalesTaxJurisdiction requires a name")
+	}
+
+	if strings.TrimSpace(liabilityAccountName) == "" {
+		return nil, errors.New("SalesTaxJurisdiction requires a liability account")
+	}
+
+	return &SalesTaxJurisdiction{
+		ID:                   NewID(),
+		Name:                 strings.TrimSpace(name),
+		LiabilityAccountName: liabilityAccountName,
+	}, nil
+}
+
+// Validate checks that the rate can be saved
+func (r SalesTaxRate) Validate() error {
+	if r.JurisdictionID == "" {
+		return errors.New("a sales tax rate requires a jurisdiction")
+	}
+
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("a sales tax rate requires a name")
+	}
+
+	if r.Rate < 0 || r.Rate >= 1 {
+		return fmt.Errorf("sales tax rate %s must be at least 0 and less than 1, got %v", r.Name, r.Rate)
+	}
+
+	return nil
+}
+
+// Validate checks that the code can be saved
+func (c SalesTaxCode) Validate() error {
+	if strings.TrimSpace(c.Code) == "" {
+		return errors.New("a sales tax code requires a code")
+	}
+
+	seen := make(map[string]bool, len(c.RateIDs))
+	for _, id := range c.RateIDs {
+		if seen[id] {
+			return fmt.Errorf("sales tax code %s lists a rate more than once", c.Code)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// reports whether no rate applies to lines carrying the code
+func (c SalesTaxCode) Exempt() bool {
+	return len(c.RateIDs) == 0
+}
+
+// Apply charges tax on each line of the invoice carrying a tax code,
+// replacing any tax already charged. Each rate of the line's code is
+// charged on the line's amount, rounded to the cent per line.
+//
+// Returns ErrSalesTaxCodeNotFound if a line carries an unknown code.
+func (s SalesTaxSchedule) Apply(invoice *Invoice) error {
+	codes := make(map[string]SalesTaxCode, len(s.Codes))
+	for _, code := range s.Codes {
+		codes[code.Code] = code
+	}
+	rates := make(map[string]SalesTaxRate, len(s.Rates))
+	for _, rate := range s.Rates {
+		rates[rate.ID] = rate
+	}
+	jurisdictions := make(map[string]SalesTaxJurisdiction, len(s.Jurisdictions))
+	for _, jurisdiction := range s.Jurisdictions {
+		jurisdictions[jurisdiction.ID] = jurisdiction
+	}
+
+	var taxes []InvoiceTax
+	for i, line := range invoice.Lines {
+		if line.TaxCode == "" {
+			continue
+		}
+
+		code, ok := codes[line.TaxCode]
+		if !ok {
+			return &ErrSalesTaxCodeNotFound{Code: line.TaxCode}
+		}
+
+		for _, rateID := range code.RateIDs {
+			rate, ok := rates[rateID]
+			if !ok {
+				return &ErrSalesTaxRateNotFound{ID: rateID}
+			}
+			jurisdiction, ok := jurisdictions[rate.JurisdictionID]
+			if !ok {
+				return &ErrSalesTaxJurisdictionNotFound{ID: rate.JurisdictionID}
+			}
+
+			taxes = append(taxes, InvoiceTax{
+				LineNo:               i + 1,
+				RateID:               rate.ID,
+				JurisdictionID:       jurisdiction.ID,
+				Name:                 rate.Name,
+				LiabilityAccountName: jurisdiction.LiabilityAccountName,
+				TaxableAmount:        line.Amount,
+				Amount:               RoundCents(line.Amount * rate.Rate),
+			})
+		}
+	}
+
+	invoice.Taxes = taxes
+	return nil
+}
+
+// TaxesByRate totals the invoice's tax per rate, in the order the rates are
+// first charged, e.g. for printing; the line numbers of the totals are zero
+func (i *Invoice) TaxesByRate() []InvoiceTax {
+	var totals []InvoiceTax
+	index := make(map[string]int)
+	for _, tax := range i.Taxes {
+		j, ok := index[tax.RateID]
+		if !ok {
+			j = len(totals)
+			index[tax.RateID] = j
+			totals = append(totals, InvoiceTax{
+				RateID:               tax.RateID,
+				JurisdictionID:       tax.JurisdictionID,
+				Name:                 tax.Name,
+				LiabilityAccountName: tax.LiabilityAccountName,
+			})
+		}
+		totals[j].TaxableAmount = RoundCents(totals[j].TaxableAmount + tax.TaxableAmount)
+		totals[j].Amount = RoundCents(totals[j].Amount + tax.Amount)
+	}
+
+	return totals
+}
+
+// the credits of the invoice's tax to each liability account, in the order
+// the accounts are first charged; accounts charged nothing are omitted
+func (i *Invoice) taxLiabilities() []JournalEntryLine {
+	var lines []JournalEntryLine
+	index := make(map[string]int)
+	for _, tax := range i.Taxes {
+		j, ok := index[tax.LiabilityAccountName]
+		if !ok {
+			j = len(lines)
+			index[tax.LiabilityAccountName] = j
+			lines = append(lines, JournalEntryLine{AccountName: tax.LiabilityAccountName, Side: Credit})
+		}
+		lines[j].Amount = RoundCents(lines[j].Amount + tax.Amount)
+	}
+
+	credits := lines[:0]
+	for _, line := range lines {
+		if line.Amount > 0 {
+			credits = append(credits, line)
+		}
+	}
+
+	return credits
+}
+
+// NewSalesTaxRemittance pays `amount` of the tax collected for a jurisdiction
+// over a period from a cash account.
+func NewSalesTaxRemittance(
+	jurisdiction SalesTaxJurisdiction,
+	periodStart, periodEnd time.Time,
+	paidOn time.Time,
+	cashAccountName string,
+	amount float64,
+	memo string,
+) (*SalesTaxRemittance, error) {
+	if periodEnd.Before(periodStart) {
+		return nil, errors.New("SalesTaxRemittance period cannot end before it starts")
+	}
+
+	if strings.TrimSpace(cashAccountName) == "" {
+		return nil, errors.New("SalesTaxRemittance requires a cash account")
+	}
+
+	if RoundCents(amount) <= 0 {
+		return nil, errors.New("SalesTaxRemittance requires a positive amount")
+	}
+
+	return &SalesTaxRemittance{
+		ID:                   NewID(),
+		JurisdictionID:       jurisdiction.ID,
+		PeriodStart:          periodStart,
+		PeriodEnd:            periodEnd,
+		PaidOn:               paidOn,
+		Amount:               RoundCents(amount),
+		LiabilityAccountName: jurisdiction.LiabilityAccountName,
+		CashAccountName:      cashAccountName,
+		Memo:                 strings.TrimSpace(memo),
+	}, nil
+}
+
+// JournalEntry produces the entry which posts the remittance:
+// the jurisdiction's liability is debited, and cash is credited.
+func (r *SalesTaxRemittance) JournalEntry(jurisdiction SalesTaxJurisdiction) JournalEntry {
+	return JournalEntry{
+		ID:        NewID(),
+		Timestamp: r.PaidOn,
+		Description: fmt.Sprintf(
+			"Sales tax remittance to %s for %s to %s",
+			jurisdiction.Name,
+			r.PeriodStart.Format(time.DateOnly),
+			r.PeriodEnd.Format(time.DateOnly),
+		),
+		Lines: []JournalEntryLine{
+			{AccountName: r.LiabilityAccountName, Amount: r.Amount, Side: Debit},
+			{AccountName: r.CashAccountName, Amount: r.Amount, Side: Credit},
+		},
+	}
+}
+
+// BuildSalesTaxReturn summarizes the invoices dated within the period and
+// the remittances made for periods ending within it. A line is taxable if
+// any rate was charged on it, and exempt otherwise; a jurisdiction's taxable
+// sales are the lines it charged tax on. Jurisdictions are listed in the
+// order given, whether or not they have activity.
+func BuildSalesTaxReturn(
+	periodStart, periodEnd time.Time,
+	jurisdictions []SalesTaxJurisdiction,
+	invoices []*Invoice,
+	remittances []SalesTaxRemittance,
+) SalesTaxReturn {
+	report := SalesTaxReturn{
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		Jurisdictions: make([]SalesTaxReturnLine, 0, len(jurisdictions)),
+	}
+
+	within := func(date time.Time) bool {
+		return !date.Before(periodStart) && !date.After(periodEnd)
+	}
+
+	index := make(map[string]int, len(jurisdictions))
+	for i, jurisdiction := range jurisdictions {
+		index[jurisdiction.ID] = i
+		report.Jurisdictions = append(report.Jurisdictions, SalesTaxReturnLine{
+			JurisdictionID: jurisdiction.ID,
+			Jurisdiction:   jurisdiction.Name,
+		})
+	}
+
+	for _, invoice := range invoices {
+		if !within(invoice.InvoiceDate) {
+			continue
+		}
+
+		taxed := make(map[int]bool)
+		counted := make(map[string]bool) // a line is taxable sales once per jurisdiction
+		for _, tax := range invoice.Taxes {
+			taxed[tax.LineNo] = true
+			report.TaxCollected += tax.Amount
+
+			i, ok := index[tax.JurisdictionID]
+			if !ok {
+				continue
+			}
+			report.Jurisdictions[i].TaxCollected += tax.Amount
+			if key := fmt.Sprintf("%s/%d", tax.JurisdictionID, tax.LineNo); !counted[key] {
+				counted[key] = true
+				report.Jurisdictions[i].TaxableSales += tax.TaxableAmount
+			}
+		}
+
+		for n, line := range invoice.Lines {
+			report.GrossSales += line.Amount
+			if taxed[n+1] {
+				report.TaxableSales += line.Amount
+			} else {
+				report.ExemptSales += line.Amount
+			}
+		}
+	}
+
+	for _, remittance := range remittances {
+		if !within(remittance.PeriodEnd) {
+			continue
+		}
+		report.TaxRemitted += remittance.Amount
+		if i, ok := index[remittance.JurisdictionID]; ok {
+			report.Jurisdictions[i].TaxRemitted += remittance.Amount
+		}
+	}
+
+	report.GrossSales = RoundCents(report.GrossSales)
+	report.TaxableSales = RoundCents(report.TaxableSales)
+	report.ExemptSales = RoundCents(report.ExemptSales)
+	report.TaxCollected = RoundCents(report.TaxCollected)
+	report.TaxRemitted = RoundCents(report.TaxRemitted)
+	for i := range report.Jurisdictions {
+		line := &report.Jurisdictions[i]
+		line.TaxableSales = RoundCents(line.TaxableSales)
+		line.TaxCollected = RoundCents(line.TaxCollected)
+		line.TaxRemitted = RoundCents(line.TaxRemitted)
+		line.Due = RoundCents(line.TaxCollected - line.TaxRemitted)
+	}
+
+	return report
+}
@@ -0,0 +1,43 @@
+package accounting
+
+import "fmt"
+
+type ErrSalesTaxJurisdictionNotFound struct {
+	ID string
+}
+
+type ErrSalesTaxRateNotFound struct {
+	ID string
+}
+
+type ErrSalesTaxCodeNotFound struct {
+	Code string
+}
+
+func (e *ErrSalesTaxJurisdictionNotFound) Error() string {
+	return fmt.Sprintf("sales tax jurisdiction \"%s\" not found", e.ID)
+}
+
+func (e *ErrSalesTaxRateNotFound) Error() string {
+	return fmt.Sprintf("sales tax rate \"%s\" not found", e.ID)
+}
+
+func (e *ErrSalesTaxCodeNotFound) Error() string {
+	return fmt.Sprintf("sales tax code \"%s\" not found", e.Code)
+}
+
+// --------- helper utilities ------------
+func IsSalesTaxJurisdictionNotFound(err error) bool {
+	_, ok := err.(*ErrSalesTaxJurisdictionNotFound)
+	return ok
+}
+
+func IsSalesTaxRateNotFound(err error) bool {
+	_, ok := err.(*ErrSalesTaxRateNotFound)
+	return ok
+}
+
+func IsSalesTaxCodeNotFound(err error) bool {
+	_, ok := err.(*ErrSalesTaxCodeNotFound)
+	return ok
+}
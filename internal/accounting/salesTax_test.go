@@ -0,0 +1,134 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+// a state and a city, each with a general rate, taxing lines coded TAX and
+// exempting lines coded EXEMPT
+func testSalesTaxSchedule() SalesTaxSchedule {
+	return SalesTaxSchedule{
+		Jurisdictions: []SalesTaxJurisdiction{
+			{ID: "state", Name: "State", LiabilityAccountName: "State Sales Tax Payable"},
+			{ID: "city", Name: "City", LiabilityAccountName: "City Sales Tax Payable"},
+		},
+		Rates: []SalesTaxRate{
+			{ID: "state-general", JurisdictionID: "state", Name: "State 6%", Rate: 0.06},
+			{ID: "city-general", JurisdictionID: "city", Name: "City 1.5%", Rate: 0.015},
+		},
+		Codes: []SalesTaxCode{
+			{Code: "TAX", RateIDs: []string{"state-general", "city-general"}},
+			{Code: "EXEMPT"},
+		},
+	}
+}
+
+func TestSalesTaxSchedule_Apply(t *testing.T) {
+	t.Run("charges each rate of a line's code and posts it per jurisdiction", func(t *testing.T) {
+		invoice, _ := NewInvoice("c1", "1001", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), DefaultPaymentTerms, "Accounts Receivable", []InvoiceLine{
+			{AccountName: "Sales", Amount: 1000.10, TaxCode: "TAX"},
+			{AccountName: "Sales", Amount: 500, TaxCode: "EXEMPT"},
+			{AccountName: "Consulting", Amount: 250},
+		})
+
+		if err := testSalesTaxSchedule().Apply(invoice); err != nil {
+			t.Fatalf("failed to apply sales tax with error %v", err)
+		}
+
+		if len(invoice.Taxes) != 2 || invoice.Taxes[0].Amount != 60.01 || invoice.Taxes[1].Amount != 15 {
+			t.Fatalf("expected 60.01 of state and 15.00 of city tax on the first line, got %+v", invoice.Taxes)
+		}
+		if invoice.Subtotal() != 1750.10 || invoice.Tax() != 75.01 || invoice.Total() != 1825.11 {
+			t.Fatalf("expected 1750.10 + 75.01 = 1825.11, got %v + %v = %v", invoice.Subtotal(), invoice.Tax(), invoice.Total())
+		}
+
+		je := invoice.JournalEntry()
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a balanced entry, got %v", err)
+		}
+		credits := map[string]float64{}
+		for _, line := range je.Lines {
+			if line.Side == Credit {
+				credits[line.AccountName] += line.Amount
+			}
+		}
+		if credits["State Sales Tax Payable"] != 60.01 || credits["City Sales Tax Payable"] != 15 {
+			t.Fatalf("expected the tax credited to each jurisdiction's liability, got %+v", credits)
+		}
+	})
+
+	t.Run("rejects an unknown tax code", func(t *testing.T) {
+		invoice, _ := NewInvoice("c1", "1002", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), DefaultPaymentTerms, "Accounts Receivable", []InvoiceLine{
+			{AccountName: "Sales", Amount: 100, TaxCode: "NOPE"},
+		})
+
+		if err := testSalesTaxSchedule().Apply(invoice); !IsSalesTaxCodeNotFound(err) {
+			t.Fatalf("expected an ErrSalesTaxCodeNotFound, received %v", err)
+		}
+	})
+}
+
+func TestBuildSalesTaxReturn(t *testing.T) {
+	schedule := testSalesTaxSchedule()
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	endOfMarch := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	invoice := func(date time.Time, lines ...InvoiceLine) *Invoice {
+		i, _ := NewInvoice("c1", "", date, DefaultPaymentTerms, "Accounts Receivable", lines)
+		if err := schedule.Apply(i); err != nil {
+			t.Fatalf("failed to apply sales tax with error %v", err)
+		}
+		return i
+	}
+	invoices := []*Invoice{
+		invoice(march, InvoiceLine{AccountName: "Sales", Amount: 1000, TaxCode: "TAX"}, InvoiceLine{AccountName: "Sales", Amount: 300, TaxCode: "EXEMPT"}),
+		invoice(endOfMarch, InvoiceLine{AccountName: "Sales", Amount: 200, TaxCode: "TAX"}),
+		// outside the period
+		invoice(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), InvoiceLine{AccountName: "Sales", Amount: 5000, TaxCode: "TAX"}),
+	}
+	remittances := []SalesTaxRemittance{
+		{JurisdictionID: "state", PeriodStart: march, PeriodEnd: endOfMarch, Amount: 50},
+		// for an earlier period
+		{JurisdictionID: "state", PeriodStart: march.AddDate(0, -1, 0), PeriodEnd: march.AddDate(0, 0, -1), Amount: 999},
+	}
+
+	report := BuildSalesTaxReturn(march, endOfMarch, schedule.Jurisdictions, invoices, remittances)
+
+	if report.GrossSales != 1500 || report.TaxableSales != 1200 || report.ExemptSales != 300 {
+		t.Fatalf("expected 1500 gross, 1200 taxable and 300 exempt sales, got %+v", report)
+	}
+	if report.TaxCollected != 90 || report.TaxRemitted != 50 {
+		t.Fatalf("expected 90 collected and 50 remitted, got %v and %v", report.TaxCollected, report.TaxRemitted)
+	}
+
+	state, city := report.Jurisdictions[0], report.Jurisdictions[1]
+	if state.TaxableSales != 1200 || state.TaxCollected != 72 || state.TaxRemitted != 50 || state.Due != 22 {
+		t.Fatalf("unexpected state return %+v", state)
+	}
+	if city.TaxableSales != 1200 || city.TaxCollected != 18 || city.TaxRemitted != 0 || city.Due != 18 {
+		t.Fatalf("unexpected city return %+v", city)
+	}
+}
+
+func TestSalesTaxRemittance_JournalEntry(t *testing.T) {
+	state := testSalesTaxSchedule().Jurisdictions[0]
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewSalesTaxRemittance(state, march, march.AddDate(0, 1, -1), march.AddDate(0, 1, 10), "Cash", 0, ""); err == nil {
+		t.Fatal("expected a remittance without an amount to be rejected")
+	}
+
+	remittance, err := NewSalesTaxRemittance(state, march, march.AddDate(0, 1, -1), march.AddDate(0, 1, 10), "Cash", 72, "March return")
+	if err != nil {
+		t.Fatalf("failed to create remittance with error %v", err)
+	}
+
+	je := remittance.JournalEntry(state)
+	if err := ValidateJournalEntry(je); err != nil {
+		t.Fatalf("expected a balanced entry, got %v", err)
+	}
+	if je.Lines[0].AccountName != "State Sales Tax Payable" || je.Lines[0].Side != Debit || je.Lines[1].AccountName != "Cash" {
+		t.Fatalf("expected the liability debited and cash credited, got %+v", je.Lines)
+	}
+}
@@ -0,0 +1,326 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// the rates and thresholds of self-employment tax for a tax year. Rates
+// are versioned: an edit saves a new version, and the latest applies.
+type SETaxRates struct {
+	TaxYear int `json:"tax_year"`
+	Version int `json:"version"`
+	// the share of net profit subject to the tax, 92.35%
+	NetEarningsFactor float64 `json:"net_earnings_factor"`
+	// Social Security, 12.4% of net earnings up to the wage base
+	SocialSecurityRate     float64 `json:"social_security_rate"`
+	SocialSecurityWageBase float64 `json:"social_security_wage_base"`
+	// Medicare, 2.9% of all net earnings
+	MedicareRate float64 `json:"medicare_rate"`
+	// additional Medicare, 0.9% of net earnings beyond the threshold
+	AdditionalMedicareRate      float64 `json:"additional_medicare_rate"`
+	AdditionalMedicareThreshold float64 `json:"additional_medicare_threshold"`
+	// net earnings below this owe no tax
+	MinimumNetEarnings float64   `json:"minimum_net_earnings"`
+	Note               string    `json:"note"`
+	EnteredAt          time.Time `json:"entered_at"`
+}
+
+// the tax owed on a year's net profit, by component
+type SETaxComputation struct {
+	NetProfit             float64 `json:"net_profit"`
+	NetEarnings           float64 `json:"net_earnings"`
+	SocialSecurityTax     float64 `json:"social_security_tax"`
+	MedicareTax           float64 `json:"medicare_tax"`
+	AdditionalMedicareTax float64 `json:"additional_medicare_tax"`
+	Total                 float64 `json:"total"`
+	// the half of Social Security and Medicare deductible from income
+	Deduction float64 `json:"deduction"`
+}
+
+type InstallmentStatus string
+
+const (
+	InstallmentPaid     InstallmentStatus = "Paid"
+	InstallmentUpcoming InstallmentStatus = "Upcoming"
+	InstallmentOverdue  InstallmentStatus = "Overdue"
+)
+
+// one of the four estimated payments due for a tax year
+type EstimatedTaxInstallment struct {
+	Quarter  int               `json:"quarter"`
+	DueDate  time.Time         `json:"due_date"`
+	Required float64           `json:"required"` // this installment's share of the annual tax
+	Paid     float64           `json:"paid"`
+	Status   InstallmentStatus `json:"status"`
+}
+
+// an estimate of a tax year's self-employment tax, annualized from the
+// year to date, with the reserve and payments made against it
+type SETaxEstimate struct {
+	TaxYear int `json:"tax_year"`
+	// the rates applied; those of an earlier year when none are entered for this one
+	Rates               SETaxRates                `json:"rates"`
+	AsOf                time.Time                 `json:"as_of"`
+	YearToDateNetIncome float64                   `json:"year_to_date_net_income"`
+	AnnualizedNetIncome float64                   `json:"annualized_net_income"`
+	Tax                 SETaxComputation          `json:"tax"`
+	Installments        []EstimatedTaxInstallment `json:"installments"`
+	Paid                float64                   `json:"paid"`
+	ReserveBalance      float64                   `json:"reserve_balance"`
+	// the transfer which brings the reserve and payments up to the
+	// installments due through the next due date
+	SuggestedReserve float64 `json:"suggested_reserve"`
+}
+
+// a transfer of cash set aside for the tax
+type TaxReserveTransfer struct {
+	ID                 string    `json:"id"`
+	TaxYear            int       `json:"tax_year"`
+	Date               time.Time `json:"date"`
+	Amount             float64   `json:"amount"`
+	FromAccountName    string    `json:"from_account_name"`
+	ReserveAccountName string    `json:"reserve_account_name"`
+	EntryID            string    `json:"entry_id"`
+}
+
+// an estimated tax payment against one of a year's installments
+type EstimatedTaxPayment struct {
+	ID              string    `json:"id"`
+	TaxYear         int       `json:"tax_year"`
+	Quarter         int       `json:"quarter"`
+	PaidOn          time.Time `json:"paid_on"`
+	Amount          float64   `json:"amount"`
+	FromAccountName string    `json:"from_account_name"`
+	// the owner's equity account charged; the tax is the owner's, not the business's
+	DrawAccountName string `json:"draw_account_name"`
+	Memo            string `json:"memo"`
+	EntryID         string `json:"entry_id"`
+}
+
+// Validate checks that the rates can compute a tax
+func (r SETaxRates) Validate() error {
+	if r.TaxYear < 1 {
+		return errors.New("self-employment tax rates require a tax year")
+	}
+
+	for name, rate := range map[string]float64{
+		"net earnings factor":      r.NetEarningsFactor,
+		"Social Security rate":     r.SocialSecurityRate,
+		"Medicare rate":            r.MedicareRate,
+		"additional Medicare rate": r.AdditionalMedicareRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("the %s must be between 0 and 1, received %v", name, rate)
+		}
+	}
+
+	if r.SocialSecurityWageBase <= 0 {
+		return errors.New("the Social Security wage base must be positive")
+	}
+	if r.AdditionalMedicareThreshold < 0 || r.MinimumNetEarnings < 0 {
+		return errors.New("thresholds cannot be negative")
+	}
+
+	return nil
+}
+
+// Compute applies the rates to a year's net profit
+func (r SETaxRates) Compute(netProfit float64) SETaxComputation {
+	tax := SETaxComputation{NetProfit: RoundCents(netProfit)}
+
+	netEarnings := RoundCents(netProfit * r.NetEarningsFactor)
+	if netEarnings < r.MinimumNetEarnings || netEarnings <= 0 {
+		return tax
+	}
+	tax.NetEarnings = netEarnings
+
+	tax.SocialSecurityTax = RoundCents(min(netEarnings, r.SocialSecurityWageBase) * r.SocialSecurityRate)
+	tax.MedicareTax = RoundCents(netEarnings * r.MedicareRate)
+	if netEarnings > r.AdditionalMedicareThreshold {
+		tax.AdditionalMedicareTax = RoundCents((netEarnings - r.AdditionalMedicareThreshold) * r.AdditionalMedicareRate)
+	}
+
+	tax.Total = RoundCents(tax.SocialSecurityTax + tax.MedicareTax + tax.AdditionalMedicareTax)
+	tax.Deduction = RoundCents((tax.SocialSecurityTax + tax.MedicareTax) / 2)
+	return tax
+}
+
+// EstimatedTaxDueDates reports the due dates of a tax year's four
+// installments: April 15, June 15 and September 15 of the year, and January
+// 15 of the next, each moved past a weekend to the following Monday.
+func EstimatedTaxDueDates(taxYear int) [4]time.Time {
+	dates := [4]time.Time{
+		time.Date(taxYear, time.April, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(taxYear, time.June, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(taxYear, time.September, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(taxYear+1, time.January, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	for i, date := range dates {
+		switch date.Weekday() {
+		case time.Saturday:
+			dates[i] = date.AddDate(0, 0, 2)
+		case time.Sunday:
+			dates[i] = date.AddDate(0, 0, 1)
+		}
+	}
+
+	return dates
+}
+
+// BuildSETaxEstimate annualizes the net income of asOf's calendar year
+// through asOf, computes the tax on it in four equal installments, and
+// suggests the reserve transfer which covers the installments due through
+// the next due date, net of the payments made and the reserve on hand.
+func BuildSETaxEstimate(
+	rates SETaxRates,
+	asOf time.Time,
+	yearToDateNetIncome float64,
+	payments []EstimatedTaxPayment,
+	reserveBalance float64,
+) SETaxEstimate {
+	taxYear := asOf.Year()
+	yearStart := time.Date(taxYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearDays := yearStart.AddDate(1, 0, 0).Sub(yearStart).Hours() / 24
+	elapsed := min(max(asOf.UTC().Sub(yearStart).Hours()/24+1, 1), yearDays)
+
+	estimate := SETaxEstimate{
+		TaxYear:             taxYear,
+		Rates:               rates,
+		AsOf:                asOf,
+		YearToDateNetIncome: RoundCents(yearToDateNetIncome),
+		AnnualizedNetIncome: RoundCents(yearToDateNetIncome * yearDays / elapsed),
+		ReserveBalance:      RoundCents(reserveBalance),
+	}
+	estimate.Tax = rates.Compute(estimate.AnnualizedNetIncome)
+
+	paid := make(map[int]float64)
+	for _, payment := range payments {
+		if payment.TaxYear != taxYear {
+			continue
+		}
+		paid[payment.Quarter] += payment.Amount
+		estimate.Paid += payment.Amount
+	}
+	estimate.Paid = RoundCents(estimate.Paid)
+
+	// the installments are equal shares; the last takes up any rounding
+	share := RoundCents(estimate.Tax.Total / 4)
+	required, next := 0.0, -1
+	for i, dueDate := range EstimatedTaxDueDates(taxYear) {
+		installment := EstimatedTaxInstallment{
+			Quarter:  i + 1,
+			DueDate:  dueDate,
+			Required: share,
+			Paid:     RoundCents(paid[i+1]),
+		}
+		if i == 3 {
+			installment.Required = RoundCents(estimate.Tax.Total - 3*share)
+		}
+
+		switch {
+		case installment.Paid >= installment.Required:
+			installment.Status = InstallmentPaid
+		case asOf.After(dueDate):
+			installment.Status = InstallmentOverdue
+		default:
+			installment.Status = InstallmentUpcoming
+		}
+
+		if next < 0 {
+			required += installment.Required
+			if !asOf.After(dueDate) {
+				next = i
+			}
+		}
+
+		estimate.Installments = append(estimate.Installments, installment)
+	}
+
+	estimate.SuggestedReserve = RoundCents(max(required-estimate.Paid-estimate.ReserveBalance, 0))
+	return estimate
+}
+
+// NewTaxReserveTransfer validates and creates a transfer into the reserve
+func NewTaxReserveTransfer(taxYear int, date time.Time, amount float64, fromAccountName, reserveAccountName string) (*TaxReserveTransfer, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("a reserve transfer must be positive, received %v", amount)
+	}
+
+	if fromAccountName == "" || reserveAccountName == "" {
+		return nil, errors.New("a reserve transfer requires the accounts it moves cash between")
+	}
+
+	if fromAccountName == reserveAccountName {
+		return nil, fmt.Errorf("cannot transfer from \"%s\" to itself", fromAccountName)
+	}
+
+	return &TaxReserveTransfer{
+		ID:                 NewID(),
+		TaxYear:            taxYear,
+		Date:               date,
+		Amount:             RoundCents(amount),
+		FromAccountName:    fromAccountName,
+		ReserveAccountName: reserveAccountName,
+	}, nil
+}
+
+// JournalEntry produces the entry which moves the cash into the reserve
+func (t *TaxReserveTransfer) JournalEntry() JournalEntry {
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   t.Date,
+		Description: fmt.Sprintf("Self-employment tax reserve for %d", t.TaxYear),
+		Lines: []JournalEntryLine{
+			{AccountName: t.ReserveAccountName, Amount: t.Amount, Side: Debit},
+			{AccountName: t.FromAccountName, Amount: t.Amount, Side: Credit},
+		},
+	}
+}
+
+// NewEstimatedTaxPayment validates and creates a payment against an installment
+func NewEstimatedTaxPayment(taxYear, quarter int, paidOn time.Time, amount float64, fromAccountName, drawAccountName, memo string) (*EstimatedTaxPayment, error) {
+	if quarter < 1 || quarter > 4 {
+		return nil, fmt.Errorf("estimated payments are made for quarters 1 through 4, received %d", quarter)
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("an estimated payment must be positive, received %v", amount)
+	}
+
+	if fromAccountName == "" || drawAccountName == "" {
+		return nil, errors.New("an estimated payment requires the accounts it is paid from and charged to")
+	}
+
+	return &EstimatedTaxPayment{
+		ID:              NewID(),
+		TaxYear:         taxYear,
+		Quarter:         quarter,
+		PaidOn:          paidOn,
+		Amount:          RoundCents(amount),
+		FromAccountName: fromAccountName,
+		DrawAccountName: drawAccountName,
+		Memo:            strings.TrimSpace(memo),
+	}, nil
+}
+
+// JournalEntry produces the entry which posts the payment
+func (p *EstimatedTaxPayment) JournalEntry() JournalEntry {
+	description := fmt.Sprintf("Estimated self-employment tax, Q%d %d", p.Quarter, p.TaxYear)
+	if p.Memo != "" {
+		description += ": " + p.Memo
+	}
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   p.PaidOn,
+		Description: description,
+		Lines: []JournalEntryLine{
+			{AccountName: p.DrawAccountName, Amount: p.Amount, Side: Debit},
+			{AccountName: p.FromAccountName, Amount: p.Amount, Side: Credit},
+		},
+	}
+}
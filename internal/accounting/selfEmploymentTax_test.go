@@ -0,0 +1,173 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func testSETaxRates() SETaxRates {
+	return SETaxRates{
+		TaxYear:                     2025,
+		Version:                     1,
+		NetEarningsFactor:           0.9235,
+		SocialSecurityRate:          0.124,
+		SocialSecurityWageBase:      176100,
+		MedicareRate:                0.029,
+		AdditionalMedicareRate:      0.009,
+		AdditionalMedicareThreshold: 200000,
+		MinimumNetEarnings:          400,
+	}
+}
+
+func TestSETaxRatesCompute(t *testing.T) {
+	rates := testSETaxRates()
+
+	t.Run("taxes net earnings below the wage base", func(t *testing.T) {
+		tax := rates.Compute(100000)
+
+		expected := SETaxComputation{
+			NetProfit:         100000,
+			NetEarnings:       92350,
+			SocialSecurityTax: 11451.4,
+			MedicareTax:       2678.15,
+			Total:             14129.55,
+			Deduction:         7064.78,
+		}
+		if tax != expected {
+			t.Fatalf("expected %+v, got %+v", expected, tax)
+		}
+	})
+
+	t.Run("caps Social Security at the wage base and adds additional Medicare", func(t *testing.T) {
+		tax := rates.Compute(300000)
+
+		// net earnings of 277,050
+		if tax.SocialSecurityTax != 21836.4 {
+			t.Fatalf("expected Social Security on the wage base of 21836.40, got %v", tax.SocialSecurityTax)
+		}
+		if tax.MedicareTax != 8034.45 || tax.AdditionalMedicareTax != 693.45 {
+			t.Fatalf("expected Medicare of 8034.45 and additional Medicare of 693.45, got %v and %v", tax.MedicareTax, tax.AdditionalMedicareTax)
+		}
+		if tax.Total != 30564.3 {
+			t.Fatalf("expected a total of 30564.30, got %v", tax.Total)
+		}
+	})
+
+	t.Run("owes nothing below the minimum or on a loss", func(t *testing.T) {
+		if tax := rates.Compute(400); tax.Total != 0 {
+			t.Fatalf("expected no tax on net earnings under 400, got %v", tax.Total)
+		}
+		if tax := rates.Compute(-5000); tax.Total != 0 || tax.NetEarnings != 0 {
+			t.Fatalf("expected no tax on a loss, got %+v", tax)
+		}
+	})
+
+	t.Run("rejects invalid rates", func(t *testing.T) {
+		invalid := rates
+		invalid.MedicareRate = 2.9
+		if err := invalid.Validate(); err == nil {
+			t.Fatal("expected an error for a rate above 1")
+		}
+
+		invalid = rates
+		invalid.SocialSecurityWageBase = 0
+		if err := invalid.Validate(); err == nil {
+			t.Fatal("expected an error for a zero wage base")
+		}
+	})
+}
+
+func TestEstimatedTaxDueDates(t *testing.T) {
+	// June 15, 2025 falls on a Sunday, and January 15, 2028 on a Saturday
+	dates := EstimatedTaxDueDates(2025)
+	if !dates[1].Equal(time.Date(2025, time.June, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the Q2 2025 installment to be due Monday June 16, got %v", dates[1])
+	}
+	if !dates[3].Equal(time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the Q4 2025 installment to be due January 15, 2026, got %v", dates[3])
+	}
+
+	if q4 := EstimatedTaxDueDates(2027)[3]; !q4.Equal(time.Date(2028, time.January, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the Q4 2027 installment to be due Monday January 17, 2028, got %v", q4)
+	}
+}
+
+func TestBuildSETaxEstimate(t *testing.T) {
+	rates := testSETaxRates()
+
+	t.Run("annualizes the year to date and suggests the next installment", func(t *testing.T) {
+		// March 31 is the 90th day of 365
+		asOf := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+		estimate := BuildSETaxEstimate(rates, asOf, 18000, nil, 0)
+
+		if estimate.AnnualizedNetIncome != 73000 {
+			t.Fatalf("expected annualized net income of 73000, got %v", estimate.AnnualizedNetIncome)
+		}
+		if len(estimate.Installments) != 4 || estimate.Installments[0].Status != InstallmentUpcoming {
+			t.Fatalf("expected four installments, the first upcoming, got %+v", estimate.Installments)
+		}
+		if estimate.SuggestedReserve != estimate.Installments[0].Required {
+			t.Fatalf("expected to reserve the first installment of %v, got %v", estimate.Installments[0].Required, estimate.SuggestedReserve)
+		}
+
+		var total float64
+		for _, installment := range estimate.Installments {
+			total += installment.Required
+		}
+		if RoundCents(total) != estimate.Tax.Total {
+			t.Fatalf("expected the installments to sum to the tax of %v, got %v", estimate.Tax.Total, total)
+		}
+	})
+
+	t.Run("nets payments and the reserve on hand against the installments due", func(t *testing.T) {
+		asOf := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+		payments := []EstimatedTaxPayment{
+			{TaxYear: 2025, Quarter: 1, Amount: 1000},
+			{TaxYear: 2024, Quarter: 4, Amount: 5000},
+		}
+		estimate := BuildSETaxEstimate(rates, asOf, 40000, payments, 500)
+
+		first := estimate.Installments[0]
+		if first.Paid != 1000 || first.Status != InstallmentOverdue {
+			t.Fatalf("expected an overdue, partly paid first installment, got %+v", first)
+		}
+		if estimate.Paid != 1000 {
+			t.Fatalf("expected only payments for 2025 to count, got %v", estimate.Paid)
+		}
+
+		due := first.Required + estimate.Installments[1].Required
+		if expected := RoundCents(due - 1000 - 500); estimate.SuggestedReserve != expected {
+			t.Fatalf("expected a suggested reserve of %v, got %v", expected, estimate.SuggestedReserve)
+		}
+	})
+
+	t.Run("suggests nothing once covered", func(t *testing.T) {
+		asOf := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+		estimate := BuildSETaxEstimate(rates, asOf, 10000, nil, 100000)
+
+		if estimate.SuggestedReserve != 0 {
+			t.Fatalf("expected no suggested reserve, got %v", estimate.SuggestedReserve)
+		}
+	})
+}
+
+func TestEstimatedTaxPayment(t *testing.T) {
+	paidOn := time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewEstimatedTaxPayment(2025, 5, paidOn, 100, "Tax Reserve", "Owner Draws", ""); err == nil {
+		t.Fatal("expected an error for quarter 5")
+	}
+
+	payment, err := NewEstimatedTaxPayment(2025, 1, paidOn, 1200, "Tax Reserve", "Owner Draws", "IRS Direct Pay")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	je := payment.JournalEntry()
+	if err := ValidateJournalEntry(je); err != nil {
+		t.Fatalf("expected a valid entry, got %v", err)
+	}
+	if je.Lines[0].AccountName != "Owner Draws" || je.Lines[0].Side != Debit || je.Lines[1].AccountName != "Tax Reserve" {
+		t.Fatalf("expected a debit to the draw account from the reserve, got %+v", je.Lines)
+	}
+}
@@ -0,0 +1,87 @@
+package accounting
+
+import (
+	"sort"
+	"time"
+)
+
+// a single line of activity on a customer statement
+type StatementLine struct {
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Charges     float64   `json:"charges"`
+	Payments    float64   `json:"payments"`
+	Balance     float64   `json:"balance"` // running balance after this line
+}
+
+// representation of a customer statement for a period
+type CustomerStatement struct {
+	Customer       Customer        `json:"customer"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"` // inclusive
+	OpeningBalance float64         `json:"opening_balance"`
+	Lines          []StatementLine `json:"lines"`
+	ClosingBalance float64         `json:"closing_balance"`
+}
+
+// BuildCustomerStatement summarizes a customer's invoices and payments:
+// activity before the period forms the opening balance, and activity
+// within the period is listed with a running balance.
+func BuildCustomerStatement(
+	customer Customer,
+	invoices []Invoice,
+	payments map[string][]InvoicePayment, // keyed by invoice ID
+	periodStart, periodEnd time.Time,
+) CustomerStatement {
+	statement := CustomerStatement{
+		Customer:    customer,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Lines:       []StatementLine{},
+	}
+
+	var activity []StatementLine
+	include := func(line StatementLine) {
+		switch {
+		case line.Date.Before(periodStart):
+			statement.OpeningBalance += line.Charges - line.Payments
+		case !line.Date.After(periodEnd):
+			activity = append(activity, line)
+		}
+	}
+
+	for _, invoice := range invoices {
+		if invoice.CustomerID != customer.ID {
+			continue
+		}
+
+		include(StatementLine{
+			Date:        invoice.InvoiceDate,
+			Description: invoice.description(),
+			Charges:     invoice.Total(),
+		})
+
+		for _, payment := range payments[invoice.ID] {
+			include(StatementLine{
+				Date:        payment.PaymentDate,
+				Description: "Payment on " + invoice.description(),
+				Payments:    payment.Amount,
+			})
+		}
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].Date.Before(activity[j].Date)
+	})
+
+	statement.OpeningBalance = RoundCents(statement.OpeningBalance)
+	balance := statement.OpeningBalance
+	for _, line := range activity {
+		balance = RoundCents(balance + line.Charges - line.Payments)
+		line.Balance = balance
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalance = balance
+
+	return statement
+}
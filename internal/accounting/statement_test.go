@@ -0,0 +1,50 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildCustomerStatement(t *testing.T) {
+	customer := Customer{ID: "c1", Name: "Acme Corp."}
+	other := Customer{ID: "c2", Name: "Other"}
+
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	invoices := []Invoice{
+		{ID: "i1", CustomerID: "c1", Number: "1001", InvoiceDate: date(1, 10), Lines: []InvoiceLine{{Amount: 300}}},
+		{ID: "i2", CustomerID: "c1", Number: "1002", InvoiceDate: date(2, 5), Lines: []InvoiceLine{{Amount: 150}}},
+		{ID: "i3", CustomerID: "c2", Number: "1003", InvoiceDate: date(2, 6), Lines: []InvoiceLine{{Amount: 999}}},
+		{ID: "i4", CustomerID: "c1", Number: "1004", InvoiceDate: date(3, 1), Lines: []InvoiceLine{{Amount: 75}}},
+	}
+	payments := map[string][]InvoicePayment{
+		"i1": {
+			{InvoiceID: "i1", PaymentDate: date(1, 20), Amount: 100},
+			{InvoiceID: "i1", PaymentDate: date(2, 2), Amount: 200},
+		},
+	}
+
+	statement := BuildCustomerStatement(customer, invoices, payments, date(2, 1), date(2, 28))
+
+	if statement.OpeningBalance != 200 {
+		t.Fatalf("expected an opening balance of 200, got %v", statement.OpeningBalance)
+	}
+
+	if len(statement.Lines) != 2 {
+		t.Fatalf("expected 2 lines of activity, got %+v", statement.Lines)
+	}
+
+	if statement.Lines[0].Payments != 200 || statement.Lines[0].Balance != 0 {
+		t.Fatalf("expected the payment first with a running balance of 0, got %+v", statement.Lines[0])
+	}
+
+	if statement.ClosingBalance != 150 {
+		t.Fatalf("expected a closing balance of 150, got %v", statement.ClosingBalance)
+	}
+
+	if len(BuildCustomerStatement(other, invoices, payments, date(1, 1), date(1, 31)).Lines) != 0 {
+		t.Fatalf("expected no January activity for another customer")
+	}
+}
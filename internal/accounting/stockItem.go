@@ -0,0 +1,352 @@
+package accounting
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// how issues of a stock item are costed
+type CostingMethod string
+
+const (
+	FIFO            CostingMethod = "FIFO"
+	LIFO            CostingMethod = "LIFO"
+	WeightedAverage CostingMethod = "Weighted Average" // moving: re-averaged on every receipt
+)
+
+type StockMovementKind string
+
+const (
+	StockReceipt StockMovementKind = "Receipt"
+	StockIssue   StockMovementKind = "Issue"
+)
+
+// representation of a fungible, quantity-tracked inventory item
+type StockItem struct {
+	ID                   string        `json:"id"`
+	SKU                  string        `json:"sku"`
+	Name                 string        `json:"name"`
+	Method               CostingMethod `json:"method"`
+	InventoryAccountName string        `json:"inventory_account_name"`
+	COGSAccountName      string        `json:"cogs_account_name"` // debited by issues unless they name another account
+}
+
+// representation of a receipt into, or issue out of, a stock item
+type StockMovement struct {
+	ID       string            `json:"id"`
+	ItemID   string            `json:"item_id"`
+	Kind     StockMovementKind `json:"kind"`
+	Date     time.Time         `json:"date"`
+	Sequence int               `json:"sequence"` // orders movements on the same date; assigned when recorded
+	Quantity float64           `json:"quantity"`
+	// for receipts, the total cost received; for issues, the cost of the
+	// units consumed, as computed by the item's costing method
+	Cost float64 `json:"cost"`
+	// the offsetting account: credited by receipts (e.g. cash or a payable),
+	// debited by issues (e.g. cost of goods sold)
+	AccountName string `json:"account_name"`
+	ProjectID   string `json:"project_id"` // the project an issue is charged to; empty if none
+	Memo        string `json:"memo"`
+	EntryID     string `json:"entry_id"`
+}
+
+// a quantity received at a cost, not yet consumed by issues
+type CostLayer struct {
+	ReceiptID string    `json:"receipt_id"`
+	Date      time.Time `json:"date"`
+	Quantity  float64   `json:"quantity"`
+	Cost      float64   `json:"cost"` // of the remaining quantity
+}
+
+// the correction to an issue's cost after a backdated movement changed the layers it consumed
+type StockCostAdjustment struct {
+	ID              string  `json:"id"`
+	MovementID      string  `json:"movement_id"`
+	AffectedEntryID string  `json:"affected_entry_id"` // the issue's original entry
+	PreviousCost    float64 `json:"previous_cost"`
+	NewCost         float64 `json:"new_cost"`
+	EntryID         string  `json:"entry_id"`
+}
+
+// constructor for a new StockItem
+func NewStockItem(sku string, name string, method CostingMethod, inventoryAccountName string, cogsAccountName string) (*StockItem, error) {
+	if strings.TrimSpace(sku) == "" {
+		return nil, errors.New("StockItem requires a SKU")
+	}
+
+	switch method {
+	case FIFO, LIFO, WeightedAverage:
+	default:
+		return nil, fmt.Errorf("unknown costing method \"%s\"", method)
+	}
+
+	if strings.TrimSpace(inventoryAccountName) == "" {
+		return nil, errors.New("StockItem requires an inventory account")
+	}
+
+	if strings.TrimSpace(cogsAccountName) == "" {
+		return nil, errors.New("StockItem requires a cost of goods sold account")
+	}
+
+	return &StockItem{
+		ID:                   NewID(),
+		SKU:                  strings.TrimSpace(sku),
+		Name:                 strings.TrimSpace(name),
+		Method:               method,
+		InventoryAccountName: inventoryAccountName,
+		COGSAccountName:      cogsAccountName,
+	}, nil
+}
+
+// NewStockReceipt records a quantity received at a unit cost, paid from the credit account
+func NewStockReceipt(item *StockItem, date time.Time, quantity float64, unitCost float64, creditAccountName string) (*StockMovement, error) {
+	if quantity <= 0 {
+		return nil, errors.New("StockMovement requires a positive quantity")
+	}
+
+	if unitCost <= 0 {
+		return nil, errors.New("StockMovement receipt requires a positive unit cost")
+	}
+
+	if strings.TrimSpace(creditAccountName) == "" {
+		return nil, errors.New("StockMovement receipt requires an account to credit")
+	}
+
+	return &StockMovement{
+		ID:          NewID(),
+		ItemID:      item.ID,
+		Kind:        StockReceipt,
+		Date:        date,
+		Quantity:    quantity,
+		Cost:        RoundCents(quantity * unitCost),
+		AccountName: creditAccountName,
+	}, nil
+}
+
+// NewStockIssue records a quantity consumed, costed to the debit account
+// (the item's cost of goods sold account if empty). Its cost is computed
+// when the movement is applied.
+func NewStockIssue(item *StockItem, date time.Time, quantity float64, debitAccountName string) (*StockMovement, error) {
+	if quantity <= 0 {
+		return nil, errors.New("StockMovement requires a positive quantity")
+	}
+
+	if strings.TrimSpace(debitAccountName) == "" {
+		debitAccountName = item.COGSAccountName
+	}
+
+	return &StockMovement{
+		ID:          NewID(),
+		ItemID:      item.ID,
+		Kind:        StockIssue,
+		Date:        date,
+		Quantity:    quantity,
+		AccountName: debitAccountName,
+	}, nil
+}
+
+// the cost per unit of the movement
+func (m *StockMovement) UnitCost() float64 {
+	return m.Cost / m.Quantity
+}
+
+// JournalEntry produces the entry which posts the movement: receipts debit
+// inventory and credit the offsetting account; issues the reverse.
+func (m *StockMovement) JournalEntry(item *StockItem) JournalEntry {
+	inventory := JournalEntryLine{AccountName: item.InventoryAccountName, Amount: m.Cost, Side: Debit}
+	offset := JournalEntryLine{AccountName: m.AccountName, Amount: m.Cost, Side: Credit, ProjectID: m.ProjectID}
+	description := fmt.Sprintf("Receipt of %s %s", formatQuantity(m.Quantity), item.SKU)
+	if m.Kind == StockIssue {
+		inventory.Side, offset.Side = Credit, Debit
+		description = fmt.Sprintf("Issue of %s %s", formatQuantity(m.Quantity), item.SKU)
+	}
+	if m.Memo != "" {
+		description += "; " + m.Memo
+	}
+
+	lines := []JournalEntryLine{inventory, offset}
+	if offset.Side == Debit {
+		lines = []JournalEntryLine{offset, inventory}
+	}
+
+	return JournalEntry{
+		ID:          NewID(),
+		Timestamp:   m.Date,
+		Description: description,
+		Lines:       lines,
+	}
+}
+
+// JournalEntry produces the entry which brings an issue to its recomputed
+// cost, dated with the issue and cross-referencing its original entry.
+func (a *StockCostAdjustment) JournalEntry(item *StockItem, issue *StockMovement) JournalEntry {
+	difference := RoundCents(a.NewCost - a.PreviousCost)
+	debit := JournalEntryLine{AccountName: issue.AccountName, Amount: difference, Side: Debit, ProjectID: issue.ProjectID}
+	credit := JournalEntryLine{AccountName: item.InventoryAccountName, Amount: difference, Side: Credit}
+	if difference < 0 {
+		debit, credit = credit, debit
+		debit.Side, credit.Side = Debit, Credit
+		debit.Amount, credit.Amount = -difference, -difference
+	}
+
+	return JournalEntry{
+		ID:        NewID(),
+		Timestamp: issue.Date,
+		Description: fmt.Sprintf(
+			"Cost adjustment to issue of %s %s on %s",
+			formatQuantity(issue.Quantity),
+			item.SKU,
+			issue.Date.Format(time.DateOnly),
+		),
+		Lines:          []JournalEntryLine{debit, credit},
+		CrossReference: sql.NullString{String: a.AffectedEntryID, Valid: a.AffectedEntryID != ""},
+	}
+}
+
+// the quantity and cost of an item on hand, and the layers they remain in
+type StockValuation struct {
+	Quantity float64     `json:"quantity"`
+	Value    float64     `json:"value"`
+	Layers   []CostLayer `json:"layers"`
+}
+
+// SortStockMovements orders movements by date, then by the order they were recorded
+func SortStockMovements(movements []StockMovement) {
+	slices.SortStableFunc(movements, func(a, b StockMovement) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return a.Sequence - b.Sequence
+	})
+}
+
+// CostMovements replays an item's movements in order, returning the cost of
+// each issue by movement ID and the valuation once all have been applied.
+//
+// Issues consume layers oldest-first (FIFO), newest-first (LIFO), or from a
+// single pool re-averaged on each receipt (weighted average). A partially
+// consumed layer gives up its cost pro rata; the last unit takes what is left,
+// so layer values always sum to the cents posted to the inventory account.
+//
+// Returns ErrInsufficientStock if an issue exceeds the quantity on hand.
+func CostMovements(item *StockItem, movements []StockMovement) (map[string]float64, StockValuation, error) {
+	ordered := slices.Clone(movements)
+	SortStockMovements(ordered)
+
+	costs := make(map[string]float64)
+	var layers []CostLayer
+
+	for _, m := range ordered {
+		switch m.Kind {
+		case StockReceipt:
+			layer := CostLayer{ReceiptID: m.ID, Date: m.Date, Quantity: m.Quantity, Cost: m.Cost}
+			if item.Method == WeightedAverage && len(layers) > 0 {
+				layers[0].Quantity += layer.Quantity
+				layers[0].Cost = RoundCents(layers[0].Cost + layer.Cost)
+				continue
+			}
+			layers = append(layers, layer)
+
+		case StockIssue:
+			var onHand float64
+			for _, layer := range layers {
+				onHand += layer.Quantity
+			}
+			if m.Quantity > onHand+quantityTolerance {
+				return nil, StockValuation{}, &ErrInsufficientStock{SKU: item.SKU, Date: m.Date, Requested: m.Quantity, OnHand: onHand}
+			}
+
+			var cost float64
+			remaining := m.Quantity
+			for remaining > quantityTolerance {
+				i := 0
+				if item.Method == LIFO {
+					i = len(layers) - 1
+				}
+
+				taken := min(remaining, layers[i].Quantity)
+				if layers[i].Quantity-taken <= quantityTolerance {
+					cost += layers[i].Cost
+					layers = slices.Delete(layers, i, i+1)
+				} else {
+					share := RoundCents(layers[i].Cost * taken / layers[i].Quantity)
+					cost += share
+					layers[i].Quantity -= taken
+					layers[i].Cost = RoundCents(layers[i].Cost - share)
+				}
+				remaining -= taken
+			}
+			costs[m.ID] = RoundCents(cost)
+
+		default:
+			return nil, StockValuation{}, fmt.Errorf("unknown stock movement kind \"%s\"", m.Kind)
+		}
+	}
+
+	valuation := StockValuation{Layers: layers}
+	for _, layer := range layers {
+		valuation.Quantity += layer.Quantity
+		valuation.Value += layer.Cost
+	}
+	valuation.Value = RoundCents(valuation.Value)
+
+	return costs, valuation, nil
+}
+
+// ValuationAsOf values the item from its movements dated on or before the end of asOf
+func ValuationAsOf(item *StockItem, movements []StockMovement, asOf time.Time) (StockValuation, error) {
+	through := slices.DeleteFunc(slices.Clone(movements), func(m StockMovement) bool {
+		return m.Date.After(asOf)
+	})
+
+	_, valuation, err := CostMovements(item, through)
+	return valuation, err
+}
+
+// ApplyStockMovement fits a new movement among an item's recorded ones: an
+// issue is costed, and every recorded issue whose cost changes as a result
+// (as when the movement is backdated) gets an adjustment.
+func ApplyStockMovement(item *StockItem, recorded []StockMovement, movement *StockMovement) ([]StockCostAdjustment, error) {
+	if movement.Sequence == 0 {
+		for _, m := range recorded {
+			movement.Sequence = max(movement.Sequence, m.Sequence)
+		}
+		movement.Sequence++
+	}
+
+	costs, _, err := CostMovements(item, append(slices.Clone(recorded), *movement))
+	if err != nil {
+		return nil, err
+	}
+
+	if movement.Kind == StockIssue {
+		movement.Cost = costs[movement.ID]
+	}
+
+	var adjustments []StockCostAdjustment
+	for _, m := range recorded {
+		if m.Kind != StockIssue || costs[m.ID] == m.Cost {
+			continue
+		}
+		adjustments = append(adjustments, StockCostAdjustment{
+			ID:              NewID(),
+			MovementID:      m.ID,
+			AffectedEntryID: m.EntryID,
+			PreviousCost:    m.Cost,
+			NewCost:         costs[m.ID],
+		})
+	}
+
+	return adjustments, nil
+}
+
+// quantities within this of each other are considered equal
+const quantityTolerance = 1e-9
+
+func formatQuantity(quantity float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.4f", quantity), "0"), ".")
+}
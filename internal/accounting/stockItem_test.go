@@ -0,0 +1,140 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+// receipts of 10 @ 1.00 and 10 @ 2.00, then an issue of 15
+func layeredMovements(item *StockItem) []StockMovement {
+	first, _ := NewStockReceipt(item, day(1), 10, 1, "Cash")
+	second, _ := NewStockReceipt(item, day(2), 10, 2, "Cash")
+	issue, _ := NewStockIssue(item, day(3), 15, "")
+	first.Sequence, second.Sequence, issue.Sequence = 1, 2, 3
+	return []StockMovement{*first, *second, *issue}
+}
+
+func TestCostMovements(t *testing.T) {
+	cases := []struct {
+		method    CostingMethod
+		issueCost float64
+		remaining float64
+	}{
+		{FIFO, 20, 10},
+		{LIFO, 25, 5},
+		{WeightedAverage, 22.5, 7.5},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.method), func(t *testing.T) {
+			item, err := NewStockItem("BOLT", "Bolt", c.method, "Inventory", "Cost of Goods Sold")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			movements := layeredMovements(item)
+
+			costs, valuation, err := CostMovements(item, movements)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if costs[movements[2].ID] != c.issueCost {
+				t.Fatalf("expected the issue to cost %.2f, got %.2f", c.issueCost, costs[movements[2].ID])
+			}
+			if valuation.Quantity != 5 || valuation.Value != c.remaining {
+				t.Fatalf("expected 5 remaining at %.2f, got %+v", c.remaining, valuation)
+			}
+		})
+	}
+
+	t.Run("the last unit takes the remaining cents", func(t *testing.T) {
+		item, _ := NewStockItem("NUT", "Nut", WeightedAverage, "Inventory", "Cost of Goods Sold")
+		receipt, _ := NewStockReceipt(item, day(1), 3, 1.0/3, "Cash") // three units for a dollar
+		receipt.Sequence = 1
+		movements := []StockMovement{*receipt}
+		for i := range 3 {
+			issue, _ := NewStockIssue(item, day(2), 1, "")
+			issue.Sequence = i + 2
+			movements = append(movements, *issue)
+		}
+
+		costs, valuation, err := CostMovements(item, movements)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var total float64
+		for _, cost := range costs {
+			total += cost
+		}
+		if RoundCents(total) != 1 || valuation.Value != 0 {
+			t.Fatalf("expected the issues to use exactly the dollar, got %.2f leaving %+v", total, valuation)
+		}
+	})
+
+	t.Run("rejects an issue beyond the quantity on hand", func(t *testing.T) {
+		item, _ := NewStockItem("BOLT", "Bolt", FIFO, "Inventory", "Cost of Goods Sold")
+		movements := layeredMovements(item)
+		movements[2].Quantity = 21
+
+		if _, _, err := CostMovements(item, movements); !IsInsufficientStock(err) {
+			t.Fatalf("expected an ErrInsufficientStock, got %v", err)
+		}
+	})
+}
+
+func TestApplyStockMovement(t *testing.T) {
+	item, _ := NewStockItem("BOLT", "Bolt", FIFO, "Inventory", "Cost of Goods Sold")
+
+	t.Run("costs a new issue", func(t *testing.T) {
+		recorded := layeredMovements(item)[:2]
+		issue, _ := NewStockIssue(item, day(5), 12, "")
+
+		adjustments, err := ApplyStockMovement(item, recorded, issue)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if issue.Cost != 14 || issue.Sequence != 3 || len(adjustments) != 0 {
+			t.Fatalf("expected the issue to cost 14 with no adjustments, got %+v, %+v", issue, adjustments)
+		}
+	})
+
+	t.Run("recosts later issues after a backdated receipt", func(t *testing.T) {
+		recorded := layeredMovements(item)
+		recorded[2].Cost, recorded[2].EntryID = 20, "original"
+
+		backdated, _ := NewStockReceipt(item, day(1), 10, 0.5, "Cash")
+		backdated.Date = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+		adjustments, err := ApplyStockMovement(item, recorded, backdated)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(adjustments) != 1 {
+			t.Fatalf("expected the issue to be recosted, got %+v", adjustments)
+		}
+		adjustment := adjustments[0]
+		if adjustment.AffectedEntryID != "original" || adjustment.PreviousCost != 20 || adjustment.NewCost != 10 {
+			t.Fatalf("expected the issue to fall from 20 to 10, got %+v", adjustment)
+		}
+
+		je := adjustment.JournalEntry(item, &recorded[2])
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if je.CrossReference.String != "original" || je.Lines[0].AccountName != "Inventory" || je.Lines[0].Amount != 10 {
+			t.Fatalf("expected 10 returned to inventory referencing the issue, got %+v", je)
+		}
+	})
+
+	t.Run("rejects a backdated issue which leaves a later one short", func(t *testing.T) {
+		recorded := layeredMovements(item)
+		issue, _ := NewStockIssue(item, day(2), 6, "")
+
+		if _, err := ApplyStockMovement(item, recorded, issue); !IsInsufficientStock(err) {
+			t.Fatalf("expected an ErrInsufficientStock, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package accounting
+
+import "fmt"
+
+// no self-employment tax rates have been entered for the year or any before it
+type ErrSETaxRatesNotFound struct {
+	TaxYear int
+}
+
+type ErrTaxLineNotFound struct {
+	TaxYear int
+	Form    string
+	Code    string
+}
+
+func (e *ErrSETaxRatesNotFound) Error() string {
+	return fmt.Sprintf("no self-employment tax rates for %d or earlier", e.TaxYear)
+}
+
+func (e *ErrTaxLineNotFound) Error() string {
+	return fmt.Sprintf("%s for %d has no line \"%s\"", e.Form, e.TaxYear, e.Code)
+}
+
+// --------- helper utilities ------------
+func IsSETaxRatesNotFound(err error) bool {
+	_, ok := err.(*ErrSETaxRatesNotFound)
+	return ok
+}
+
+func IsTaxLineNotFound(err error) bool {
+	_, ok := err.(*ErrTaxLineNotFound)
+	return ok
+}
@@ -0,0 +1,161 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the form whose lines the book is seeded with
+const ScheduleC = "Schedule C"
+
+// a line of a tax form as numbered for a tax year; line numbers change
+// between years, so each year carries its own
+type TaxLine struct {
+	TaxYear      int    `json:"tax_year"`
+	Form         string `json:"form"`
+	Code         string `json:"code"` // the line number, e.g. "16a"
+	Description  string `json:"description"`
+	DisplayOrder int    `json:"display_order"`
+}
+
+// the line of a form an account reports on for a tax year
+type TaxLineMapping struct {
+	TaxYear     int    `json:"tax_year"`
+	Form        string `json:"form"`
+	AccountName string `json:"account_name"`
+	LineCode    string `json:"line_code"`
+}
+
+// an account's activity for the year, from the perspective of its normal balance
+type TaxReportAccount struct {
+	AccountName string      `json:"account_name"`
+	AccountType AccountType `json:"account_type"`
+	Amount      float64     `json:"amount"`
+}
+
+type TaxReportLine struct {
+	Line     TaxLine            `json:"line"`
+	Accounts []TaxReportAccount `json:"accounts"`
+	Amount   float64            `json:"amount"`
+}
+
+// a form's lines for a tax year, each with the activity of the accounts
+// mapped to it, and the revenue and expense accounts with activity which
+// are mapped to none of them
+type TaxReport struct {
+	TaxYear  int                `json:"tax_year"`
+	Form     string             `json:"form"`
+	Lines    []TaxReportLine    `json:"lines"`
+	Unmapped []TaxReportAccount `json:"unmapped"`
+}
+
+// TaxYearBounds reports the first day of the (calendar) tax year and the day after it ends
+func TaxYearBounds(taxYear int) (time.Time, time.Time) {
+	start := time.Date(taxYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// Validate checks that the line can be saved
+func (l TaxLine) Validate() error {
+	if l.TaxYear < 1 {
+		return errors.New("a tax line requires a tax year")
+	}
+
+	if strings.TrimSpace(l.Form) == "" || strings.TrimSpace(l.Code) == "" {
+		return errors.New("a tax line requires a form and a line number")
+	}
+
+	if strings.TrimSpace(l.Description) == "" {
+		return fmt.Errorf("line %s of %s requires a description", l.Code, l.Form)
+	}
+
+	return nil
+}
+
+// BuildTaxReport aggregates the year's account totals onto the form's
+// lines per the year's mappings. Lines are listed in display order whether
+// or not any activity maps to them.
+func BuildTaxReport(
+	taxYear int,
+	form string,
+	lines []TaxLine,
+	mappings []TaxLineMapping,
+	accounts []*Account,
+	totals []AccountTotal,
+) TaxReport {
+	report := TaxReport{TaxYear: taxYear, Form: form, Lines: []TaxReportLine{}, Unmapped: []TaxReportAccount{}}
+
+	sorted := append([]TaxLine(nil), lines...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DisplayOrder < sorted[j].DisplayOrder })
+
+	index := make(map[string]int, len(sorted))
+	for _, line := range sorted {
+		if line.TaxYear != taxYear || line.Form != form {
+			continue
+		}
+		index[line.Code] = len(report.Lines)
+		report.Lines = append(report.Lines, TaxReportLine{Line: line, Accounts: []TaxReportAccount{}})
+	}
+
+	mapped := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		if mapping.TaxYear == taxYear && mapping.Form == form {
+			mapped[mapping.AccountName] = mapping.LineCode
+		}
+	}
+
+	byName := make(map[string]*Account, len(accounts))
+	for _, account := range accounts {
+		byName[account.Name] = account
+	}
+
+	byAccount := append([]AccountTotal(nil), totals...)
+	sort.Slice(byAccount, func(i, j int) bool { return byAccount[i].AccountName < byAccount[j].AccountName })
+
+	for _, total := range byAccount {
+		account, ok := byName[total.AccountName]
+		if !ok {
+			continue
+		}
+
+		activity := TaxReportAccount{
+			AccountName: account.Name,
+			AccountType: account.AccountType,
+			Amount:      total.Balance(account.NormalBalance),
+		}
+
+		i, ok := index[mapped[account.Name]]
+		switch {
+		case ok:
+			report.Lines[i].Accounts = append(report.Lines[i].Accounts, activity)
+			report.Lines[i].Amount = RoundCents(report.Lines[i].Amount + activity.Amount)
+		case (account.AccountType == Revenue || account.AccountType == Expense) && (total.Debits != 0 || total.Credits != 0):
+			report.Unmapped = append(report.Unmapped, activity)
+		}
+	}
+
+	return report
+}
+
+// WithoutEntry removes an entry's lines from account totals, e.g. to report
+// a year's activity before its closing entry zeroed it
+func WithoutEntry(totals []AccountTotal, je JournalEntry) []AccountTotal {
+	adjusted := append([]AccountTotal(nil), totals...)
+	for _, line := range je.Lines {
+		for i := range adjusted {
+			if adjusted[i].AccountName != line.AccountName {
+				continue
+			}
+			if line.Side == Debit {
+				adjusted[i].Debits = RoundCents(adjusted[i].Debits - line.Amount)
+			} else {
+				adjusted[i].Credits = RoundCents(adjusted[i].Credits - line.Amount)
+			}
+		}
+	}
+
+	return adjusted
+}
@@ -0,0 +1,80 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTaxReport(t *testing.T) {
+	accounts := []*Account{
+		{Name: "Cash", AccountType: Asset, NormalBalance: DebitNormal},
+		{Name: "Sales", AccountType: Revenue, NormalBalance: CreditNormal},
+		{Name: "Consulting", AccountType: Revenue, NormalBalance: CreditNormal},
+		{Name: "Rent", AccountType: Expense, NormalBalance: DebitNormal},
+		{Name: "Postage", AccountType: Expense, NormalBalance: DebitNormal},
+	}
+	lines := []TaxLine{
+		{TaxYear: 2025, Form: ScheduleC, Code: "20b", Description: "Rent or lease: other business property", DisplayOrder: 205},
+		{TaxYear: 2025, Form: ScheduleC, Code: "1", Description: "Gross receipts or sales", DisplayOrder: 10},
+		{TaxYear: 2025, Form: ScheduleC, Code: "18", Description: "Office expense", DisplayOrder: 180},
+	}
+	mappings := []TaxLineMapping{
+		{TaxYear: 2025, Form: ScheduleC, AccountName: "Sales", LineCode: "1"},
+		{TaxYear: 2025, Form: ScheduleC, AccountName: "Consulting", LineCode: "1"},
+		{TaxYear: 2025, Form: ScheduleC, AccountName: "Rent", LineCode: "20b"},
+		// a mapping of another year does not apply
+		{TaxYear: 2024, Form: ScheduleC, AccountName: "Postage", LineCode: "18"},
+	}
+	totals := []AccountTotal{
+		{AccountName: "Cash", Debits: 20000, Credits: 6000},
+		{AccountName: "Sales", Debits: 500, Credits: 15500},
+		{AccountName: "Consulting", Credits: 4500},
+		{AccountName: "Rent", Debits: 6000},
+		{AccountName: "Postage", Debits: 120},
+	}
+
+	report := BuildTaxReport(2025, ScheduleC, lines, mappings, accounts, totals)
+
+	if len(report.Lines) != 3 || report.Lines[0].Line.Code != "1" || report.Lines[2].Line.Code != "20b" {
+		t.Fatalf("expected the lines in display order, got %+v", report.Lines)
+	}
+	if report.Lines[0].Amount != 19500 || len(report.Lines[0].Accounts) != 2 {
+		t.Fatalf("expected gross receipts of 19500 from two accounts, got %+v", report.Lines[0])
+	}
+	if report.Lines[1].Amount != 0 || len(report.Lines[1].Accounts) != 0 {
+		t.Fatalf("expected an empty office expense line, got %+v", report.Lines[1])
+	}
+	if report.Lines[2].Amount != 6000 {
+		t.Fatalf("expected rent of 6000, got %v", report.Lines[2].Amount)
+	}
+
+	if len(report.Unmapped) != 1 || report.Unmapped[0].AccountName != "Postage" || report.Unmapped[0].Amount != 120 {
+		t.Fatalf("expected only postage to be unmapped, got %+v", report.Unmapped)
+	}
+}
+
+func TestWithoutEntry(t *testing.T) {
+	totals := []AccountTotal{
+		{AccountName: "Sales", Debits: 10000, Credits: 10000},
+		{AccountName: "Retained Earnings", Credits: 10000},
+	}
+	closing := JournalEntry{
+		Timestamp: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []JournalEntryLine{
+			{AccountName: "Sales", Amount: 10000, Side: Debit},
+			{AccountName: "Retained Earnings", Amount: 10000, Side: Credit},
+		},
+	}
+
+	adjusted := WithoutEntry(totals, closing)
+
+	if adjusted[0] != (AccountTotal{AccountName: "Sales", Credits: 10000}) {
+		t.Fatalf("expected sales activity before the close, got %+v", adjusted[0])
+	}
+	if adjusted[1] != (AccountTotal{AccountName: "Retained Earnings"}) {
+		t.Fatalf("expected no retained earnings activity, got %+v", adjusted[1])
+	}
+	if totals[0].Debits != 10000 {
+		t.Fatal("expected the original totals to be left unchanged")
+	}
+}
@@ -1,9 +1,11 @@
 package accounting
 
+import "math"
+
 // Checks if a journal entry is balanced.
 //
 // A journal entry is considered balanced if the total
-// line debits equal the total line credits.
+// line debits equal the total line credits, to the cent.
 func IsBalanced(je JournalEntry) bool {
 	var debitTotal, creditTotal float64
 
@@ -16,5 +18,41 @@ func IsBalanced(je JournalEntry) bool {
 		}
 	}
 
-	return debitTotal == creditTotal
+	return RoundCents(debitTotal) == RoundCents(creditTotal)
+}
+
+// ValidateJournalEntry checks that a journal entry may be posted:
+// it must have an ID, at least two lines, only positive line amounts,
+// and balanced debits and credits.
+func ValidateJournalEntry(je JournalEntry) error {
+	if je.ID == "" {
+		return &ErrInvalidEntry{Reason: "entry requires an ID"}
+	}
+
+	if len(je.Lines) < 2 {
+		return &ErrInvalidEntry{ID: je.ID, Reason: "entry requires at least two lines"}
+	}
+
+	for _, line := range je.Lines {
+		if line.AccountName == "" {
+			return &ErrInvalidEntry{ID: je.ID, Reason: "every line requires an account"}
+		}
+		if line.Amount <= 0 {
+			return &ErrInvalidEntry{ID: je.ID, Reason: "line amounts must be positive"}
+		}
+		if line.Side != Debit && line.Side != Credit {
+			return &ErrInvalidEntry{ID: je.ID, Reason: "line side must be Debit or Credit"}
+		}
+	}
+
+	if !IsBalanced(je) {
+		return &ErrUnbalancedEntry{ID: je.ID}
+	}
+
+	return nil
+}
+
+// RoundCents rounds an amount to the nearest cent.
+func RoundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
 }
@@ -54,3 +54,45 @@ func TestTransactionBalanced(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateJournalEntry(t *testing.T) {
+	t.Run("accepts amounts which balance to the cent", func(t *testing.T) {
+		je := accounting.JournalEntry{
+			ID: "1",
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "1", Amount: 0.1, Side: accounting.Debit},
+				{AccountName: "2", Amount: 0.2, Side: accounting.Debit},
+				{AccountName: "3", Amount: 0.3, Side: accounting.Credit},
+			},
+		}
+
+		if err := accounting.ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+	})
+
+	t.Run("rejects an entry with fewer than two lines", func(t *testing.T) {
+		je := accounting.JournalEntry{
+			ID:    "1",
+			Lines: []accounting.JournalEntryLine{{AccountName: "1", Amount: 1, Side: accounting.Debit}},
+		}
+
+		if err := accounting.ValidateJournalEntry(je); !accounting.IsInvalidEntry(err) {
+			t.Fatalf("expected an InvalidEntry error, received %v", err)
+		}
+	})
+
+	t.Run("rejects an unbalanced entry", func(t *testing.T) {
+		je := accounting.JournalEntry{
+			ID: "1",
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "1", Amount: 100, Side: accounting.Debit},
+				{AccountName: "2", Amount: 99.99, Side: accounting.Credit},
+			},
+		}
+
+		if err := accounting.ValidateJournalEntry(je); !accounting.IsUnbalancedEntry(err) {
+			t.Fatalf("expected an UnbalancedEntry error, received %v", err)
+		}
+	})
+}
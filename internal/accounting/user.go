@@ -0,0 +1,204 @@
+package accounting
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// the shortest password accepted
+	MinPasswordLength = 12
+	// how long a login lasts before the user must sign in again
+	SessionLifetime = 12 * time.Hour
+)
+
+// argon2id parameters, per the second recommendation of RFC 9106 (64 MiB)
+const (
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	argonKeyLen  = 32
+	argonSaltLen = 16
+)
+
+// representation of someone who may sign in to the books
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	Role         Role      `json:"role"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// a signed-in user's session; the ID is a hash of the token held in the
+// user's cookie, so that the stored sessions cannot be replayed
+type Session struct {
+	ID        string    `json:"-"`
+	UserID    string    `json:"user_id"`
+	CSRFToken string    `json:"-"` // echoed by every form the session submits
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// constructor for a new User, hashing the password with argon2id
+func NewUser(username, password string, role Role) (*User, error) {
+	username = strings.TrimSpace(username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &User{
+		ID:           NewID(),
+		Username:     username,
+		Role:         role,
+		PasswordHash: hash,
+		CreatedAt:    time.Now().UTC(),
+	}, nil
+}
+
+func validateUsername(username string) error {
+	if username == "" {
+		return errors.New("User requires a username")
+	}
+	if len(username) > 64 {
+		return errors.New("username cannot be longer than 64 characters")
+	}
+	if strings.IndexFunc(username, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("username %q cannot contain spaces", username)
+	}
+	return nil
+}
+
+// CheckPassword reports whether the password is the user's
+func (u *User) CheckPassword(password string) bool {
+	return checkPassword(u.PasswordHash, password)
+}
+
+// HashPassword hashes the password with argon2id and a random salt, encoded
+// as $argon2id$v=19$m=...,t=...,p=...$salt$key
+func HashPassword(password string) (string, error) {
+	if len([]rune(password)) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// verifies the password against a hash from HashPassword, under the
+// parameters recorded in the hash
+func checkPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, iterations, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, candidate) == 1
+}
+
+// constructor for a new Session of the user, lasting SessionLifetime from
+// now; returns the session along with the token for the user's cookie
+func NewSession(userID string, now time.Time) (*Session, string, error) {
+	token, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	csrfToken, err := NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &Session{
+		ID:        SessionID(token),
+		UserID:    userID,
+		CSRFToken: csrfToken,
+		CreatedAt: now.UTC(),
+		ExpiresAt: now.UTC().Add(SessionLifetime),
+	}, token, nil
+}
+
+// Expired reports whether the session has lapsed at the given time
+func (s *Session) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// NewToken generates 32 random bytes, URL-safe base64 encoded
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SessionID derives the stored ID of the session from its cookie's token
+func SessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type userKey struct{}
+
+// WithUser marks the context as acting for the user
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user the context acts for, if any
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userKey{}).(*User)
+	return user, ok && user != nil
+}
@@ -0,0 +1,76 @@
+package accounting
+
+import "fmt"
+
+type ErrUserNotFound struct {
+	Username string
+}
+
+type ErrUserExists struct {
+	Username string
+}
+
+// a sign-in with an unknown username or the wrong password; which of the
+// two is deliberately not said
+type ErrInvalidCredentials struct{}
+
+// a session token which is unknown, signed out or expired
+type ErrSessionNotFound struct{}
+
+// an action attempted by someone whose role does not allow it; Username is
+// empty if no one is signed in
+type ErrPermissionDenied struct {
+	Username string
+	Role     Role
+	Action   string
+	Required Role
+}
+
+func (e *ErrUserNotFound) Error() string {
+	return fmt.Sprintf("user \"%s\" not found", e.Username)
+}
+
+func (e *ErrUserExists) Error() string {
+	return fmt.Sprintf("user \"%s\" already exists", e.Username)
+}
+
+func (e *ErrInvalidCredentials) Error() string {
+	return "invalid username or password"
+}
+
+func (e *ErrSessionNotFound) Error() string {
+	return "session not found or expired"
+}
+
+func (e *ErrPermissionDenied) Error() string {
+	if e.Username == "" {
+		return fmt.Sprintf("permission denied: signing in as a %s is required to %s", e.Required, e.Action)
+	}
+	return fmt.Sprintf("permission denied: %s (%s) cannot %s; the %s role is required", e.Username, e.Role, e.Action, e.Required)
+}
+
+// --------- helper utilities ------------
+func IsUserNotFound(err error) bool {
+	_, ok := err.(*ErrUserNotFound)
+	return ok
+}
+
+func IsUserExists(err error) bool {
+	_, ok := err.(*ErrUserExists)
+	return ok
+}
+
+func IsInvalidCredentials(err error) bool {
+	_, ok := err.(*ErrInvalidCredentials)
+	return ok
+}
+
+func IsSessionNotFound(err error) bool {
+	_, ok := err.(*ErrSessionNotFound)
+	return ok
+}
+
+func IsPermissionDenied(err error) bool {
+	_, ok := err.(*ErrPermissionDenied)
+	return ok
+}
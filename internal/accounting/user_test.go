@@ -0,0 +1,67 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Run("hashes the password with argon2id and a salt of its own", func(t *testing.T) {
+		alice, err := NewUser(" alice ", "correct horse battery", BookkeeperRole)
+		if err != nil {
+			t.Fatalf("failed to create user with error %v", err)
+		}
+		bob, _ := NewUser("bob", "correct horse battery", BookkeeperRole)
+
+		if alice.Username != "alice" {
+			t.Fatalf("expected the username to be trimmed, got %q", alice.Username)
+		}
+		if !strings.HasPrefix(alice.PasswordHash, "$argon2id$v=19$") || strings.Contains(alice.PasswordHash, "correct") {
+			t.Fatalf("expected an encoded argon2id hash, got %q", alice.PasswordHash)
+		}
+		if alice.PasswordHash == bob.PasswordHash {
+			t.Fatal("expected the same password to hash differently for each user")
+		}
+		if !alice.CheckPassword("correct horse battery") || alice.CheckPassword("correct horse batter") {
+			t.Fatal("expected only the password to check")
+		}
+	})
+
+	t.Run("rejects short passwords and invalid usernames", func(t *testing.T) {
+		if _, err := NewUser("alice", "too short", BookkeeperRole); err == nil {
+			t.Fatal("expected a short password to be rejected")
+		}
+		for _, username := range []string{"", "   ", "alice smith", strings.Repeat("a", 65)} {
+			if _, err := NewUser(username, "correct horse battery", BookkeeperRole); err == nil {
+				t.Fatalf("expected username %q to be rejected", username)
+			}
+		}
+	})
+
+	t.Run("fails malformed hashes closed", func(t *testing.T) {
+		user := &User{PasswordHash: "$argon2id$v=19$m=65536,t=1,p=4$$"}
+		if user.CheckPassword("") {
+			t.Fatal("expected a hash without a key to match nothing")
+		}
+	})
+}
+
+func TestNewSession(t *testing.T) {
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	session, token, err := NewSession("user-1", now)
+	if err != nil {
+		t.Fatalf("failed to create session with error %v", err)
+	}
+
+	if session.ID == token || session.ID != SessionID(token) {
+		t.Fatalf("expected the session to be stored under a hash of its token, got %q for %q", session.ID, token)
+	}
+	if session.CSRFToken == "" || session.CSRFToken == token {
+		t.Fatal("expected a CSRF token distinct from the session token")
+	}
+	if session.Expired(now.Add(SessionLifetime-time.Second)) || !session.Expired(now.Add(SessionLifetime)) {
+		t.Fatalf("expected the session to last %v from %v, expires %v", SessionLifetime, now, session.ExpiresAt)
+	}
+}
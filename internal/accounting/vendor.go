@@ -0,0 +1,29 @@
+package accounting
+
+import (
+	"errors"
+	"strings"
+)
+
+// representation of a vendor; a party from whom bills are received
+type Vendor struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	Email        string       `json:"email"`
+	Phone        string       `json:"phone"`
+	Address      string       `json:"address"`
+	DefaultTerms PaymentTerms `json:"default_terms"` // applied to new bills unless overridden
+}
+
+// constructor for a new Vendor
+func NewVendor(name string, defaultTerms PaymentTerms) (*Vendor, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("Vendor requires a non-empty string for its Name attribute")
+	}
+
+	return &Vendor{
+		ID:           NewID(),
+		Name:         strings.TrimSpace(name),
+		DefaultTerms: defaultTerms,
+	}, nil
+}
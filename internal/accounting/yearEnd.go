@@ -0,0 +1,171 @@
+package accounting
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// representation of the close of a fiscal year: its revenue and expense
+// accounts zeroed into retained earnings by a single closing entry, and the
+// year locked against new postings until it is reopened
+type YearEndClose struct {
+	ID                          string    `json:"id"`
+	YearStart                   time.Time `json:"year_start"`
+	YearEnd                     time.Time `json:"year_end"` // inclusive; the closing entry is dated on it
+	RetainedEarningsAccountName string    `json:"retained_earnings_account_name"`
+	IncomeSummaryAccountName    string    `json:"income_summary_account_name"` // empty to close directly to retained earnings
+	NetIncome                   float64   `json:"net_income"`                  // negative for a loss
+	ClosedAt                    time.Time `json:"closed_at"`
+	EntryID                     string    `json:"entry_id"`
+	ReopenedAt                  time.Time `json:"reopened_at"` // zero while the year is closed
+	ReopenReason                string    `json:"reopen_reason"`
+	ReversalEntryID             string    `json:"reversal_entry_id"`
+}
+
+type YearEndAction string
+
+const (
+	YearClosed   YearEndAction = "Close"
+	YearReopened YearEndAction = "Reopen"
+)
+
+// a record of a year being closed or reopened
+type YearEndAuditRecord struct {
+	ID         string        `json:"id"`
+	CloseID    string        `json:"close_id"`
+	Action     YearEndAction `json:"action"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Reason     string        `json:"reason"`
+}
+
+// constructor for a new YearEndClose of the fiscal year [yearStart, yearEnd]
+func NewYearEndClose(yearStart, yearEnd time.Time, retainedEarningsAccountName string, incomeSummaryAccountName string) (*YearEndClose, error) {
+	if yearStart.IsZero() || yearEnd.Before(yearStart) {
+		return nil, errors.New("YearEndClose requires a year end on or after the year start")
+	}
+
+	if strings.TrimSpace(retainedEarningsAccountName) == "" {
+		return nil, errors.New("YearEndClose requires a retained earnings account")
+	}
+
+	return &YearEndClose{
+		ID:                          NewID(),
+		YearStart:                   yearStart,
+		YearEnd:                     yearEnd,
+		RetainedEarningsAccountName: retainedEarningsAccountName,
+		IncomeSummaryAccountName:    incomeSummaryAccountName,
+	}, nil
+}
+
+// Closed reports whether the close is in effect, i.e. it has not been reopened
+func (c *YearEndClose) Closed() bool {
+	return c.ReopenedAt.IsZero()
+}
+
+// Covers reports whether the date falls within the closed year
+func (c *YearEndClose) Covers(date time.Time) bool {
+	return withinDays(date, c.YearStart, c.YearEnd)
+}
+
+// ClosingEntry produces the entry which zeroes each revenue and expense
+// account's balance for the year, given the year's totals per account, and
+// records the year's net income. Balances close to the income summary
+// account, which in turn closes to retained earnings, when one is named.
+func (c *YearEndClose) ClosingEntry(accounts []*Account, totals []AccountTotal) (JournalEntry, error) {
+	types := make(map[string]*Account, len(accounts))
+	for _, account := range accounts {
+		types[account.Name] = account
+	}
+
+	closeTo := c.RetainedEarningsAccountName
+	if c.IncomeSummaryAccountName != "" {
+		closeTo = c.IncomeSummaryAccountName
+	}
+
+	je := JournalEntry{
+		ID:          NewID(),
+		Timestamp:   c.YearEnd,
+		Description: fmt.Sprintf("Closing entry for the year ended %s", c.YearEnd.Format(time.DateOnly)),
+	}
+
+	sorted := append([]AccountTotal(nil), totals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].AccountName < sorted[j].AccountName })
+
+	var netIncome float64
+	for _, total := range sorted {
+		account, ok := types[total.AccountName]
+		if !ok || (account.AccountType != Revenue && account.AccountType != Expense) {
+			continue
+		}
+
+		// the debit balance of the account, negative for a credit balance
+		balance := total.Balance(DebitNormal)
+		switch {
+		case balance > 0:
+			je.Lines = append(je.Lines, JournalEntryLine{AccountName: total.AccountName, Amount: balance, Side: Credit})
+		case balance < 0:
+			je.Lines = append(je.Lines, JournalEntryLine{AccountName: total.AccountName, Amount: -balance, Side: Debit})
+		default:
+			continue
+		}
+		netIncome -= balance
+	}
+	netIncome = RoundCents(netIncome)
+
+	if len(je.Lines) == 0 {
+		return JournalEntry{}, fmt.Errorf("no revenue or expense balances to close for the year ended %s", c.YearEnd.Format(time.DateOnly))
+	}
+
+	// the net of the closing lines lands in closeTo
+	offset := func(account string, amount float64) {
+		switch {
+		case amount > 0:
+			je.Lines = append(je.Lines, JournalEntryLine{AccountName: account, Amount: amount, Side: Credit})
+		case amount < 0:
+			je.Lines = append(je.Lines, JournalEntryLine{AccountName: account, Amount: -amount, Side: Debit})
+		}
+	}
+	offset(closeTo, netIncome)
+	if closeTo != c.RetainedEarningsAccountName {
+		offset(closeTo, -netIncome)
+		offset(c.RetainedEarningsAccountName, netIncome)
+	}
+
+	c.NetIncome = netIncome
+	return je, nil
+}
+
+// Reopen lifts the close, returning the entry which reverses the closing
+// entry: each line on the opposite side, dated with it and cross-referencing it.
+func (c *YearEndClose) Reopen(closing JournalEntry, at time.Time, reason string) (JournalEntry, error) {
+	if !c.Closed() {
+		return JournalEntry{}, fmt.Errorf("the year ended %s was already reopened", c.YearEnd.Format(time.DateOnly))
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return JournalEntry{}, errors.New("reopening a year requires a reason")
+	}
+
+	reversal := JournalEntry{
+		ID:             NewID(),
+		Timestamp:      closing.Timestamp,
+		Description:    fmt.Sprintf("Reversal of the closing entry for the year ended %s", c.YearEnd.Format(time.DateOnly)),
+		CrossReference: sql.NullString{String: closing.ID, Valid: true},
+	}
+	for _, line := range closing.Lines {
+		if line.Side == Debit {
+			line.Side = Credit
+		} else {
+			line.Side = Debit
+		}
+		reversal.Lines = append(reversal.Lines, line)
+	}
+
+	c.ReopenedAt = at
+	c.ReopenReason = strings.TrimSpace(reason)
+	return reversal, nil
+}
@@ -0,0 +1,54 @@
+package accounting
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrYearCloseNotFound struct {
+	ID string
+}
+
+type ErrYearAlreadyClosed struct {
+	YearEnd time.Time
+}
+
+// an entry dated within a closed fiscal year
+type ErrYearClosed struct {
+	EntryID string
+	Date    time.Time
+	YearEnd time.Time
+}
+
+func (e *ErrYearCloseNotFound) Error() string {
+	return fmt.Sprintf("year-end close \"%s\" not found", e.ID)
+}
+
+func (e *ErrYearAlreadyClosed) Error() string {
+	return fmt.Sprintf("the year ended %s is already closed", e.YearEnd.Format(time.DateOnly))
+}
+
+func (e *ErrYearClosed) Error() string {
+	return fmt.Sprintf(
+		"journal entry \"%s\" is dated %s, within the year ended %s, which is closed",
+		e.EntryID,
+		e.Date.Format(time.DateOnly),
+		e.YearEnd.Format(time.DateOnly),
+	)
+}
+
+// --------- helper utilities ------------
+func IsYearCloseNotFound(err error) bool {
+	_, ok := err.(*ErrYearCloseNotFound)
+	return ok
+}
+
+func IsYearAlreadyClosed(err error) bool {
+	_, ok := err.(*ErrYearAlreadyClosed)
+	return ok
+}
+
+func IsYearClosed(err error) bool {
+	_, ok := err.(*ErrYearClosed)
+	return ok
+}
@@ -0,0 +1,115 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYearEndClose(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	accounts := []*Account{
+		{Name: "Cash", AccountType: Asset},
+		{Name: "Sales", AccountType: Revenue},
+		{Name: "Rent", AccountType: Expense},
+		{Name: "Supplies", AccountType: Expense},
+	}
+	totals := []AccountTotal{
+		{AccountName: "Cash", Debits: 10000, Credits: 4000},
+		{AccountName: "Sales", Debits: 200, Credits: 10200},
+		{AccountName: "Rent", Debits: 3000},
+		{AccountName: "Supplies", Debits: 1000, Credits: 1000},
+	}
+
+	t.Run("closes revenue and expenses directly to retained earnings", func(t *testing.T) {
+		closing, err := NewYearEndClose(start, end, "Retained Earnings", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		je, err := closing.ClosingEntry(accounts, totals)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+		if closing.NetIncome != 7000 || !je.Timestamp.Equal(end) {
+			t.Fatalf("expected net income of 7000 closed on the year end, got %v on %v", closing.NetIncome, je.Timestamp)
+		}
+
+		expected := []JournalEntryLine{
+			{AccountName: "Rent", Amount: 3000, Side: Credit},
+			{AccountName: "Sales", Amount: 10000, Side: Debit},
+			{AccountName: "Retained Earnings", Amount: 7000, Side: Credit},
+		}
+		if len(je.Lines) != len(expected) {
+			t.Fatalf("expected %+v, got %+v", expected, je.Lines)
+		}
+		for i := range expected {
+			if je.Lines[i] != expected[i] {
+				t.Fatalf("expected line %d to be %+v, got %+v", i, expected[i], je.Lines[i])
+			}
+		}
+	})
+
+	t.Run("closes a loss through income summary", func(t *testing.T) {
+		closing, _ := NewYearEndClose(start, end, "Retained Earnings", "Income Summary")
+
+		je, err := closing.ClosingEntry(accounts, []AccountTotal{
+			{AccountName: "Sales", Credits: 500},
+			{AccountName: "Rent", Debits: 800},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ValidateJournalEntry(je); err != nil {
+			t.Fatalf("expected a valid entry, got %v", err)
+		}
+
+		var summary, retained float64
+		for _, line := range je.Lines {
+			amount := line.Amount
+			if line.Side == Credit {
+				amount = -amount
+			}
+			switch line.AccountName {
+			case "Income Summary":
+				summary += amount
+			case "Retained Earnings":
+				retained += amount
+			}
+		}
+		if closing.NetIncome != -300 || summary != 0 || retained != 300 {
+			t.Fatalf("expected a 300 loss debited to retained earnings through a zeroed summary, got %v, %v, %v",
+				closing.NetIncome, summary, retained)
+		}
+	})
+
+	t.Run("reopening reverses the closing entry", func(t *testing.T) {
+		closing, _ := NewYearEndClose(start, end, "Retained Earnings", "")
+		je, _ := closing.ClosingEntry(accounts, totals)
+
+		if _, err := closing.Reopen(je, time.Now(), " "); err == nil {
+			t.Fatalf("expected a reason to be required")
+		}
+
+		reversal, err := closing.Reopen(je, time.Now(), "Late vendor bill")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if closing.Closed() || reversal.CrossReference.String != je.ID {
+			t.Fatalf("expected the close lifted and the reversal to reference the closing entry")
+		}
+		for i, line := range reversal.Lines {
+			if line.AccountName != je.Lines[i].AccountName || line.Amount != je.Lines[i].Amount || line.Side == je.Lines[i].Side {
+				t.Fatalf("expected line %d reversed, got %+v against %+v", i, line, je.Lines[i])
+			}
+		}
+
+		if _, err := closing.Reopen(je, time.Now(), "again"); err == nil {
+			t.Fatalf("expected an error reopening twice")
+		}
+	})
+}
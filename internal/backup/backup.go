@@ -0,0 +1,291 @@
+// Package backup takes, lists, prunes and restores timestamped snapshots of
+// the books' database.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+)
+
+// backups are named for when they were taken, e.g.
+// ghoam-20250102T150405.000Z.db, so that they sort oldest first
+const (
+	namePrefix  = "ghoam-"
+	nameSuffix  = ".db"
+	stampLayout = "20060102T150405.000Z"
+)
+
+// a verified snapshot of the database
+type Backup struct {
+	Name    string
+	Path    string
+	TakenAt time.Time
+	Size    int64
+}
+
+// which backups are kept when pruning: the newest Keep, and any taken within
+// MaxAge
+type Retention struct {
+	Keep   int
+	MaxAge time.Duration
+}
+
+// the retention used when nothing overrides it
+var DefaultRetention = Retention{Keep: 10, MaxAge: 30 * 24 * time.Hour}
+
+// copies the live database consistently; satisfied by *sqlite.Repositories
+type Snapshotter interface {
+	Snapshot(ctx context.Context, path string) error
+}
+
+// Name names a backup taken at takenAt
+func Name(takenAt time.Time) string {
+	return namePrefix + takenAt.UTC().Format(stampLayout) + nameSuffix
+}
+
+// reports when the backup named name was taken, if it is named as a backup
+func parseName(name string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(name, namePrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	if stamp, ok = strings.CutSuffix(stamp, nameSuffix); !ok {
+		return time.Time{}, false
+	}
+
+	takenAt, err := time.Parse(stampLayout, stamp)
+	return takenAt, err == nil
+}
+
+// Create snapshots the database into dir, which is created if need be, and
+// verifies the snapshot. One which fails verification is discarded rather
+// than kept among the backups.
+func Create(ctx context.Context, db Snapshotter, dir string, now time.Time) (Backup, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Backup{}, fmt.Errorf("failed to create the backup directory: %w", err)
+	}
+
+	takenAt := now.UTC()
+	path := filepath.Join(dir, Name(takenAt))
+	if _, err := os.Stat(path); err == nil {
+		return Backup{}, fmt.Errorf("a backup named %s already exists", path)
+	}
+
+	// the snapshot is named as a backup only once verified
+	partial := path + ".partial"
+	if err := db.Snapshot(ctx, partial); err != nil {
+		os.Remove(partial)
+		return Backup{}, err
+	}
+	if err := sqlite.VerifyFile(ctx, partial); err != nil {
+		os.Remove(partial)
+		return Backup{}, fmt.Errorf("the backup failed verification and was discarded: %w", err)
+	}
+	if err := os.Rename(partial, path); err != nil {
+		os.Remove(partial)
+		return Backup{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	return Backup{Name: info.Name(), Path: path, TakenAt: takenAt, Size: info.Size()}, nil
+}
+
+// List retrieves the backups in dir, newest first; none if dir does not exist
+func List(dir string) ([]Backup, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, file := range files {
+		takenAt, ok := parseName(file.Name())
+		if !ok || !file.Type().IsRegular() {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, Backup{
+			Name:    file.Name(),
+			Path:    filepath.Join(dir, file.Name()),
+			TakenAt: takenAt,
+			Size:    info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].TakenAt.After(backups[j].TakenAt) })
+
+	return backups, nil
+}
+
+// Prune deletes the backups in dir which the retention does not keep as of
+// now, returning those deleted. The newest backup is always kept.
+func Prune(dir string, retention Retention, now time.Time) ([]Backup, error) {
+	backups, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []Backup
+	for i, b := range backups {
+		if i == 0 || i < retention.Keep || now.Sub(b.TakenAt) < retention.MaxAge {
+			continue
+		}
+
+		if err := os.Remove(b.Path); err != nil {
+			return pruned, fmt.Errorf("failed to prune backup %s: %w", b.Name, err)
+		}
+		pruned = append(pruned, b)
+	}
+
+	return pruned, nil
+}
+
+// Restore replaces the database at dst with the backup at src, once the
+// backup passes verification. It refuses to overwrite a database which
+// records a change the backup lacks unless forced. Nothing may have the
+// database open meanwhile; stop the server first.
+func Restore(ctx context.Context, src, dst string, force bool) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("failed to find the backup: %w", err)
+	}
+	if err := sqlite.VerifyFile(ctx, src); err != nil {
+		return fmt.Errorf("refusing to restore a backup which fails verification: %w", err)
+	}
+
+	_, err := os.Stat(dst)
+	switch {
+	case err == nil:
+		if !force {
+			if err := refuseNewer(ctx, src, dst); err != nil {
+				return err
+			}
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("failed to create the database directory: %w", err)
+		}
+	default:
+		return err
+	}
+
+	// copied alongside, then renamed over the database, so that a failure
+	// part way leaves the database as it was
+	restoring := dst + ".restoring"
+	if err := copyFile(src, restoring); err != nil {
+		os.Remove(restoring)
+		return fmt.Errorf("failed to copy the backup: %w", err)
+	}
+	if err := os.Rename(restoring, dst); err != nil {
+		os.Remove(restoring)
+		return err
+	}
+
+	// a journal left by the replaced database would be replayed into the
+	// restored one
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		if err := os.Remove(dst + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fails with ErrDatabaseNewer if the latest change in the audit log of the
+// database at dst is missing from that of the backup at src. The books' own
+// record is compared rather than file times, which copying, syncing and
+// merely opening the database disturb.
+func refuseNewer(ctx context.Context, src, dst string) error {
+	last, ok, err := sqlite.LastChange(ctx, dst)
+	if err != nil {
+		return fmt.Errorf("cannot tell whether %s has changed since the backup; use -force to overwrite it: %w", dst, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	held, err := sqlite.HasChange(ctx, src, last.ID)
+	if err != nil || held {
+		return err
+	}
+
+	backupLast, _, err := sqlite.LastChange(ctx, src)
+	if err != nil {
+		return err
+	}
+	return &ErrDatabaseNewer{Database: dst, ChangedAt: last.OccurredAt, BackupChangedAt: backupLast.OccurredAt}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// returned on restoring over a database which records a change the backup lacks
+type ErrDatabaseNewer struct {
+	Database  string
+	ChangedAt time.Time
+	// the latest change the backup records; zero if none
+	BackupChangedAt time.Time
+}
+
+func (e *ErrDatabaseNewer) Error() string {
+	backup := "the backup records no changes"
+	if !e.BackupChangedAt.IsZero() {
+		backup = "the backup's latest is from " + e.BackupChangedAt.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf(
+		"%s records a change at %s which the backup lacks, and %s; use -force to overwrite it",
+		e.Database,
+		e.ChangedAt.UTC().Format(time.RFC3339),
+		backup,
+	)
+}
+
+// --------- helper utilities ------------
+
+func IsDatabaseNewer(err error) bool {
+	_, ok := err.(*ErrDatabaseNewer)
+	return ok
+}
@@ -0,0 +1,178 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+
+	newBooks := func(t *testing.T, path string) *sqlite.Repositories {
+		t.Helper()
+		repos, err := sqlite.New(path)
+		if err != nil {
+			t.Fatalf("failed to open database with error %v", err)
+		}
+		t.Cleanup(func() { repos.Close() })
+		return repos
+	}
+
+	t.Run("takes and lists timestamped backups, newest first", func(t *testing.T) {
+		repos := newBooks(t, ":memory:")
+		dir := filepath.Join(t.TempDir(), "backups")
+
+		first, err := Create(ctx, repos, dir, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to back up with error %v", err)
+		}
+		if first.Name != "ghoam-20250102T030405.000Z.db" || first.Size == 0 {
+			t.Fatalf("unexpected backup %+v", first)
+		}
+		if _, err := Create(ctx, repos, dir, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("failed to back up with error %v", err)
+		}
+		if _, err := Create(ctx, repos, dir, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)); err == nil {
+			t.Fatal("expected an error taking two backups at once")
+		}
+
+		backups, err := List(dir)
+		if err != nil {
+			t.Fatalf("failed to list backups with error %v", err)
+		}
+		if len(backups) != 2 || !backups[0].TakenAt.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)) || backups[1].Path != first.Path {
+			t.Fatalf("expected both backups, newest first, got %+v", backups)
+		}
+
+		if backups, err := List(filepath.Join(t.TempDir(), "missing")); err != nil || backups != nil {
+			t.Fatalf("expected no backups in a missing directory, got %+v and %v", backups, err)
+		}
+	})
+
+	t.Run("prunes backups neither among the newest nor recent", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		for _, daysAgo := range []int{1, 5, 20, 40, 60, 90} {
+			path := filepath.Join(dir, Name(now.AddDate(0, 0, -daysAgo)))
+			if err := os.WriteFile(path, nil, 0o644); err != nil {
+				t.Fatalf("failed to write backup with error %v", err)
+			}
+		}
+		// files not named as backups are left alone
+		if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644); err != nil {
+			t.Fatalf("failed to write file with error %v", err)
+		}
+
+		pruned, err := Prune(dir, Retention{Keep: 4, MaxAge: 30 * 24 * time.Hour}, now)
+		if err != nil {
+			t.Fatalf("failed to prune with error %v", err)
+		}
+		if len(pruned) != 2 || !pruned[0].TakenAt.Equal(now.AddDate(0, 0, -60)) || !pruned[1].TakenAt.Equal(now.AddDate(0, 0, -90)) {
+			t.Fatalf("expected the two oldest to be pruned, got %+v", pruned)
+		}
+
+		remaining, err := List(dir)
+		if err != nil {
+			t.Fatalf("failed to list backups with error %v", err)
+		}
+		if len(remaining) != 4 {
+			t.Fatalf("expected 4 backups to remain, got %+v", remaining)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+			t.Fatalf("expected other files to remain, got %v", err)
+		}
+
+		// the newest is kept however old
+		if pruned, err := Prune(dir, Retention{}, now.AddDate(1, 0, 0)); err != nil || len(pruned) != 3 {
+			t.Fatalf("expected all but the newest to be pruned, got %+v and %v", pruned, err)
+		}
+	})
+
+	t.Run("restores a backup, refusing to overwrite newer books unless forced", func(t *testing.T) {
+		dir := t.TempDir()
+		dbPath := filepath.Join(dir, "ghoam.db")
+		repos := newBooks(t, dbPath)
+
+		taken, err := Create(ctx, repos, filepath.Join(dir, "backups"), time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("failed to back up with error %v", err)
+		}
+
+		// a change after the backup
+		if err := repos.Accounts.Save(ctx, &accounting.Account{Name: "Cash", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal}); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+		if err := repos.Close(); err != nil {
+			t.Fatalf("failed to close database with error %v", err)
+		}
+
+		if err := Restore(ctx, taken.Path, dbPath, false); !IsDatabaseNewer(err) {
+			t.Fatalf("expected ErrDatabaseNewer, got %v", err)
+		}
+		if err := Restore(ctx, taken.Path, dbPath, true); err != nil {
+			t.Fatalf("failed to restore with error %v", err)
+		}
+
+		restored := newBooks(t, dbPath)
+		if _, err := restored.Accounts.ByName(ctx, "Cash"); !accounting.IsAccountNotFound(err) {
+			t.Fatalf("expected the books as backed up, without Cash, got %v", err)
+		}
+	})
+
+	t.Run("restores over books merely touched since the backup", func(t *testing.T) {
+		dir := t.TempDir()
+		dbPath := filepath.Join(dir, "ghoam.db")
+		repos := newBooks(t, dbPath)
+		if err := repos.Accounts.Save(ctx, &accounting.Account{Name: "Cash", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal}); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+
+		taken, err := Create(ctx, repos, filepath.Join(dir, "backups"), time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("failed to back up with error %v", err)
+		}
+		if err := repos.Close(); err != nil {
+			t.Fatalf("failed to close database with error %v", err)
+		}
+
+		// copied or synced later, but unchanged
+		later := time.Now().Add(time.Hour)
+		if err := os.Chtimes(dbPath, later, later); err != nil {
+			t.Fatalf("failed to touch database with error %v", err)
+		}
+
+		if err := Restore(ctx, taken.Path, dbPath, false); err != nil {
+			t.Fatalf("expected an unchanged database to be restored over, got %v", err)
+		}
+	})
+
+	t.Run("restores into a missing database, but not from a bad backup", func(t *testing.T) {
+		dir := t.TempDir()
+		taken, err := Create(ctx, newBooks(t, ":memory:"), dir, time.Now())
+		if err != nil {
+			t.Fatalf("failed to back up with error %v", err)
+		}
+
+		dbPath := filepath.Join(dir, "data", "ghoam.db")
+		if err := Restore(ctx, taken.Path, dbPath, false); err != nil {
+			t.Fatalf("failed to restore with error %v", err)
+		}
+
+		garbage := filepath.Join(dir, "garbage.db")
+		if err := os.WriteFile(garbage, make([]byte, 4096), 0o644); err != nil {
+			t.Fatalf("failed to write file with error %v", err)
+		}
+		if err := Restore(ctx, garbage, dbPath, true); err == nil {
+			t.Fatal("expected a bad backup to be refused")
+		}
+		if err := sqlite.VerifyFile(ctx, dbPath); err != nil {
+			t.Fatalf("expected the restored books to remain, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Package config resolves the server's settings from, in increasing order of
+// precedence: defaults, an optional JSON config file, GHOAM_* environment
+// variables, and command-line flags.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/backup"
+)
+
+type Config struct {
+	DBPath      string `json:"db_path"`
+	ListenAddr  string `json:"listen_addr"`
+	BaseURL     string `json:"base_url"`  // the externally visible URL; empty -> derived from ListenAddr
+	LogLevel    string `json:"log_level"` // debug, info, warn or error
+	TLSCertFile string `json:"tls_cert_file"`
+	TLSKeyFile  string `json:"tls_key_file"`
+	BackupDir   string `json:"backup_dir"`  // empty -> a backups directory beside the database
+	BackupKeep  string `json:"backup_keep"` // the number of newest backups always kept
+	BackupDays  string `json:"backup_days"` // the days for which any backup is kept
+}
+
+// the settings used when nothing overrides them
+func Default() Config {
+	return Config{
+		DBPath:     "data/ghoam.db",
+		ListenAddr: ":8080",
+		LogLevel:   "info",
+		BackupKeep: strconv.Itoa(backup.DefaultRetention.Keep),
+		BackupDays: strconv.Itoa(int(backup.DefaultRetention.MaxAge / (24 * time.Hour))),
+	}
+}
+
+// a setting's name in each source
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	field func(*Config) *string
+}
+
+var settings = []setting{
+	{"db", "GHOAM_DB_PATH", "path to the SQLite database file", func(c *Config) *string { return &c.DBPath }},
+	{"addr", "GHOAM_LISTEN_ADDR", "address to listen on, e.g. :8080", func(c *Config) *string { return &c.ListenAddr }},
+	{"base-url", "GHOAM_BASE_URL", "externally visible URL of the server", func(c *Config) *string { return &c.BaseURL }},
+	{"log-level", "GHOAM_LOG_LEVEL", "log level: debug, info, warn or error", func(c *Config) *string { return &c.LogLevel }},
+	{"tls-cert", "GHOAM_TLS_CERT", "TLS certificate file; serves HTTPS along with -tls-key", func(c *Config) *string { return &c.TLSCertFile }},
+	{"tls-key", "GHOAM_TLS_KEY", "TLS private key file", func(c *Config) *string { return &c.TLSKeyFile }},
+	{"backup-dir", "GHOAM_BACKUP_DIR", "directory for backups; defaults to backups beside the database", func(c *Config) *string { return &c.BackupDir }},
+	{"backup-keep", "GHOAM_BACKUP_KEEP", "number of newest backups always kept", func(c *Config) *string { return &c.BackupKeep }},
+	{"backup-days", "GHOAM_BACKUP_DAYS", "days for which every backup is kept", func(c *Config) *string { return &c.BackupDays }},
+}
+
+// Load resolves the settings from the command-line arguments (without the
+// program name) and the environment, read with getenv. The config file is
+// named by the -config flag or GHOAM_CONFIG; a missing file is an error only
+// if one is named.
+//
+// Returns flag.ErrHelp if help was requested, after printing usage to output.
+func Load(args []string, getenv func(string) string, output io.Writer) (Config, error) {
+	fs := flag.NewFlagSet("ghoam", flag.ContinueOnError)
+	fs.SetOutput(output)
+	resolve := Flags(fs, getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	return resolve()
+}
+
+// Flags defines the settings' flags on fs, for commands with flags of their
+// own, and returns a func which resolves the settings as Load does once fs
+// has been parsed
+func Flags(fs *flag.FlagSet, getenv func(string) string) func() (Config, error) {
+	configPath := fs.String("config", getenv("GHOAM_CONFIG"), "path to a JSON config file (env GHOAM_CONFIG)")
+	flags := make([]string, len(settings))
+	for i, s := range settings {
+		fs.StringVar(&flags[i], s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+
+	return func() (Config, error) {
+		cfg := Default()
+
+		if *configPath != "" {
+			if err := readFile(*configPath, &cfg); err != nil {
+				return Config{}, err
+			}
+		}
+
+		for _, s := range settings {
+			if value := getenv(s.env); value != "" {
+				*s.field(&cfg) = value
+			}
+		}
+
+		// only flags given explicitly override, so an empty flag can clear a setting
+		fs.Visit(func(f *flag.Flag) {
+			for i, s := range settings {
+				if s.flag == f.Name {
+					*s.field(&cfg) = flags[i]
+				}
+			}
+		})
+
+		if err := cfg.Validate(); err != nil {
+			return Config{}, err
+		}
+
+		return cfg, nil
+	}
+}
+
+// overlays the settings present in the JSON file onto cfg
+func readFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks that the settings are complete and consistent
+func (c Config) Validate() error {
+	if strings.TrimSpace(c.DBPath) == "" {
+		return errors.New("a database path is required")
+	}
+
+	if strings.TrimSpace(c.ListenAddr) == "" {
+		return errors.New("a listen address is required")
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("base URL %q must be an absolute http or https URL", c.BaseURL)
+		}
+	}
+
+	if _, err := c.Level(); err != nil {
+		return err
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("a TLS certificate and key must be given together")
+	}
+
+	if _, err := c.Retention(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reports whether the server is to serve HTTPS
+func (c Config) TLS() bool {
+	return c.TLSCertFile != ""
+}
+
+// reports whether cookies are to be sent over HTTPS only: when serving TLS,
+// or behind a proxy which does
+func (c Config) SecureCookies() bool {
+	return strings.HasPrefix(c.URL(), "https://")
+}
+
+// Level parses the log level
+func (c Config) Level() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: use debug, info, warn or error", c.LogLevel)
+	}
+	return level, nil
+}
+
+// the externally visible URL: BaseURL if set, otherwise derived from the
+// listen address and whether TLS is on
+func (c Config) URL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+
+	scheme := "http"
+	if c.TLS() {
+		scheme = "https"
+	}
+
+	host := c.ListenAddr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+
+	return scheme + "://" + host
+}
+
+// the directory backups are taken into: BackupDir if set, otherwise backups
+// beside the database
+func (c Config) BackupDirectory() string {
+	if c.BackupDir != "" {
+		return c.BackupDir
+	}
+	return filepath.Join(filepath.Dir(c.DBPath), "backups")
+}
+
+// Retention parses which backups are kept when pruning
+func (c Config) Retention() (backup.Retention, error) {
+	keep, err := strconv.Atoi(c.BackupKeep)
+	if err != nil || keep < 1 {
+		return backup.Retention{}, fmt.Errorf("invalid backup count %q: keep at least 1", c.BackupKeep)
+	}
+
+	days, err := strconv.Atoi(c.BackupDays)
+	if err != nil || days < 0 {
+		return backup.Retention{}, fmt.Errorf("invalid backup days %q: use a whole number of days", c.BackupDays)
+	}
+
+	return backup.Retention{Keep: keep, MaxAge: time.Duration(days) * 24 * time.Hour}, nil
+}
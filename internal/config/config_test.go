@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	t.Run("defaults without any overrides", func(t *testing.T) {
+		cfg, err := Load(nil, env(nil), io.Discard)
+		if err != nil {
+			t.Fatalf("failed to load config with error %v", err)
+		}
+		if cfg != Default() {
+			t.Fatalf("expected the defaults, got %+v", cfg)
+		}
+		if cfg.URL() != "http://localhost:8080" {
+			t.Fatalf("expected a URL derived from the listen address, got %s", cfg.URL())
+		}
+	})
+
+	t.Run("file, then environment, then flags take precedence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ghoam.json")
+		if err := os.WriteFile(path, []byte(`{"db_path": "/var/lib/ghoam/books.db", "listen_addr": ":9000", "log_level": "debug"}`), 0o600); err != nil {
+			t.Fatalf("failed to write config file with error %v", err)
+		}
+
+		cfg, err := Load(
+			[]string{"-addr", "127.0.0.1:9443", "-tls-cert", "cert.pem", "-tls-key", "key.pem"},
+			env(map[string]string{"GHOAM_CONFIG": path, "GHOAM_LISTEN_ADDR": ":9100", "GHOAM_LOG_LEVEL": "warn"}),
+			io.Discard,
+		)
+		if err != nil {
+			t.Fatalf("failed to load config with error %v", err)
+		}
+
+		expected := Config{
+			DBPath:      "/var/lib/ghoam/books.db",
+			ListenAddr:  "127.0.0.1:9443",
+			LogLevel:    "warn",
+			TLSCertFile: "cert.pem",
+			TLSKeyFile:  "key.pem",
+			BackupKeep:  "10",
+			BackupDays:  "30",
+		}
+		if cfg != expected {
+			t.Fatalf("expected %+v, got %+v", expected, cfg)
+		}
+		if cfg.BackupDirectory() != "/var/lib/ghoam/backups" {
+			t.Fatalf("expected backups beside the database, got %s", cfg.BackupDirectory())
+		}
+		if cfg.URL() != "https://127.0.0.1:9443" {
+			t.Fatalf("expected an https URL, got %s", cfg.URL())
+		}
+		if !cfg.SecureCookies() {
+			t.Fatal("expected secure cookies when serving TLS")
+		}
+	})
+
+	t.Run("shares the settings flags with commands of their own", func(t *testing.T) {
+		fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		username := fs.String("username", "", "")
+		resolve := Flags(fs, env(map[string]string{"GHOAM_BASE_URL": "https://books.example.com"}))
+
+		if err := fs.Parse([]string{"-username", "alice", "-db", "books.db"}); err != nil {
+			t.Fatalf("failed to parse flags with error %v", err)
+		}
+		cfg, err := resolve()
+		if err != nil {
+			t.Fatalf("failed to resolve config with error %v", err)
+		}
+
+		if *username != "alice" || cfg.DBPath != "books.db" {
+			t.Fatalf("expected both the command's and the settings' flags, got %q and %+v", *username, cfg)
+		}
+		if !cfg.SecureCookies() {
+			t.Fatal("expected secure cookies behind an https base URL")
+		}
+	})
+
+	t.Run("rejects inconsistent settings", func(t *testing.T) {
+		for name, args := range map[string][]string{
+			"a certificate without a key": {"-tls-cert", "cert.pem"},
+			"an unknown log level":        {"-log-level", "loud"},
+			"a relative base URL":         {"-base-url", "books.example.com"},
+			"an empty database path":      {"-db", ""},
+			"keeping no backups":          {"-backup-keep", "0"},
+			"a fractional backup age":     {"-backup-days", "1.5"},
+		} {
+			if _, err := Load(args, env(nil), io.Discard); err == nil {
+				t.Errorf("expected %s to be rejected", name)
+			}
+		}
+
+		if _, err := Load([]string{"-config", filepath.Join(t.TempDir(), "missing.json")}, env(nil), io.Discard); err == nil {
+			t.Error("expected a missing config file to be rejected")
+		}
+		if _, err := Load([]string{"-h"}, env(nil), io.Discard); !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("expected flag.ErrHelp, received %v", err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type AgingHandler struct {
+	AgingService *services.AgingService
+	Views        *view.Views
+}
+
+// serves the A/R aging report
+func (h *AgingHandler) GetReceivables(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.AgingService.GetReceivablesAging)
+}
+
+// serves the A/P aging report
+func (h *AgingHandler) GetPayables(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.AgingService.GetPayablesAging)
+}
+
+// renders a report as of the `as_of` query parameter (default today),
+// in the `format` query parameter: html (default), csv or json
+func (h *AgingHandler) serve(
+	w http.ResponseWriter,
+	r *http.Request,
+	build func(ctx context.Context, asOf time.Time) (*accounting.AgingReport, error),
+) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, "invalid as_of date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := build(r.Context(), asOf)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to build aging report", "error", err)
+		http.Error(w, "failed to build aging report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode aging report", "error", err)
+		}
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"aging.csv\"")
+		if err := writeAgingCSV(w, report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write aging report", "error", err)
+		}
+	default:
+		h.Views.Render(w, r, "aging", report)
+	}
+}
+
+// writes one row per open document, followed by the report totals
+func writeAgingCSV(w http.ResponseWriter, report *accounting.AgingReport) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"Party", "Document", "Date", "Due Date", "Days Past Due"}
+	labels := report.BucketLabels()
+	header = append(header, labels[:]...)
+	header = append(header, "Open Balance")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		for _, doc := range row.Documents {
+			record := []string{
+				row.PartyName,
+				doc.Number,
+				doc.Date.Format(time.DateOnly),
+				doc.DueDate.Format(time.DateOnly),
+				strconv.Itoa(doc.DaysPastDue),
+			}
+			for bucket := range accounting.AgingBucketCount {
+				amount := 0.0
+				if accounting.AgingBucket(bucket) == doc.Bucket {
+					amount = doc.OpenBalance
+				}
+				record = append(record, formatAmount(amount))
+			}
+			record = append(record, formatAmount(doc.OpenBalance))
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	totals := []string{"Total", "", "", "", ""}
+	for _, amount := range report.Buckets {
+		totals = append(totals, formatAmount(amount))
+	}
+	totals = append(totals, formatAmount(report.Total))
+	if err := cw.Write(totals); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// reads the `as_of` query parameter, defaulting to today
+func parseAsOf(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("as_of")
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
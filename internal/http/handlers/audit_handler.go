@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type AuditHandler struct {
+	AuditService *services.AuditService
+	Views        *view.Views
+}
+
+// renders the audit log, filtered by the `user`, `entity_type`, `entity_id`,
+// `from` and `to` query parameters (dates inclusive, each optional)
+func (h *AuditHandler) GetLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := accounting.AuditFilter{
+		Actor:      query.Get("user"),
+		EntityType: accounting.EntityType(query.Get("entity_type")),
+		EntityID:   query.Get("entity_id"),
+	}
+
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			http.Error(w, "invalid from date: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.From = from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			http.Error(w, "invalid to date: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	records, err := h.AuditService.GetRecords(r.Context(), filter)
+	if err != nil {
+		writeAuditError(w, r, "failed to get audit log", err)
+		return
+	}
+
+	data := map[string]any{
+		"Records":     records,
+		"User":        query.Get("user"),
+		"EntityType":  query.Get("entity_type"),
+		"EntityID":    query.Get("entity_id"),
+		"From":        query.Get("from"),
+		"To":          query.Get("to"),
+		"EntityTypes": []accounting.EntityType{accounting.AccountGroupEntity, accounting.AccountEntity, accounting.JournalEntryEntity},
+	}
+
+	h.Views.Render(w, r, "auditLog", data)
+}
+
+func writeAuditError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsAccountNotFound(err) || accounting.IsEntryNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+func TestAuditHandler(t *testing.T) {
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	admin, err := accounting.NewUser("root", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	viewer, err := accounting.NewUser("carol", "correct horse battery", accounting.ViewerRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+
+	for _, name := range []string{"Petty Cash", "Savings"} {
+		if err := repos.Accounts.Save(accounting.WithUser(context.Background(), admin), &accounting.Account{
+			Name:            name,
+			ParentGroupName: "Assets",
+			AccountType:     accounting.Asset,
+			NormalBalance:   accounting.DebitNormal,
+		}); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+	}
+
+	audit := &AuditHandler{AuditService: &services.AuditService{AuditRepo: repos.Audit}, Views: views}
+	journalEntries := &JournalEntriesHandler{
+		JournalEntryService: &services.JournalEntryService{JournalEntryRepo: repos.JournalEntries},
+		AuditService:        audit.AuditService,
+		Views:               views,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /audit", audit.GetLog)
+	mux.HandleFunc("GET /journal-entries/chain", journalEntries.GetChain)
+
+	as := func(user *accounting.User, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req.WithContext(accounting.WithUser(req.Context(), user)))
+		return rec
+	}
+
+	t.Run("lists changes for admins", func(t *testing.T) {
+		rec := as(admin, "/audit?user=root&entity_type=account")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, `<a href="/accounts/Petty%20Cash">Petty Cash</a>`) || !strings.Contains(body, "Savings") {
+			t.Fatalf("expected both accounts' creation, got:\n%s", body)
+		}
+	})
+
+	t.Run("filters by entity", func(t *testing.T) {
+		rec := as(admin, "/audit?entity_type=account&entity_id=Savings&from=2000-01-01&to=2999-12-31")
+		if body := rec.Body.String(); rec.Code != http.StatusOK || strings.Contains(body, "Petty Cash") || !strings.Contains(body, "Savings") {
+			t.Fatalf("expected only Savings, got %d:\n%s", rec.Code, body)
+		}
+	})
+
+	t.Run("forbids viewers", func(t *testing.T) {
+		if rec := as(viewer, "/audit"); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("verifies the ledger for admins", func(t *testing.T) {
+		rec := as(admin, "/journal-entries/chain")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "All 0 entries match their hashes.") {
+			t.Fatalf("expected an intact ledger, got %d:\n%s", rec.Code, rec.Body.String())
+		}
+		if rec := as(viewer, "/journal-entries/chain"); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 for a viewer, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects invalid dates", func(t *testing.T) {
+		if rec := as(admin, "/audit?from=yesterday"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+}
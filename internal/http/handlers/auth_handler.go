@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/middleware"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type AuthHandler struct {
+	AuthService *services.AuthService
+	Views       *view.Views
+	// whether cookies are restricted to HTTPS
+	SecureCookies bool
+}
+
+// renders the login form, which returns to the `next` query parameter once
+// signed in; someone already signed in goes straight there
+func (h *AuthHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
+	next := localPath(r.URL.Query().Get("next"))
+	if _, ok := accounting.UserFromContext(r.Context()); ok {
+		http.Redirect(w, r, next, http.StatusSeeOther)
+		return
+	}
+
+	h.Views.Render(w, r, "login", map[string]any{"Next": next})
+}
+
+// signs in per the `username` and `password` form values, and redirects to
+// the `next` form value; a failed attempt re-renders the form with a 401
+func (h *AuthHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
+	username := strings.TrimSpace(r.PostFormValue("username"))
+	next := localPath(r.PostFormValue("next"))
+
+	session, token, err := h.AuthService.Login(r.Context(), username, r.PostFormValue("password"))
+	if accounting.IsInvalidCredentials(err) {
+		slog.WarnContext(r.Context(), "failed login", "username", username)
+		h.Views.RenderStatus(w, r, http.StatusUnauthorized, "login", map[string]any{
+			"Next":     next,
+			"Username": username,
+			"Error":    err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to sign in", "error", err)
+		http.Error(w, "failed to sign in: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "signed in", "username", username)
+	middleware.SetSessionCookie(w, token, session.ExpiresAt, h.SecureCookies)
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+// ends the current session and redirects to the login form
+func (h *AuthHandler) PostLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(middleware.SessionCookie); err == nil {
+		if err := h.AuthService.Logout(r.Context(), cookie.Value); err != nil {
+			slog.ErrorContext(r.Context(), "failed to sign out", "error", err)
+			http.Error(w, "failed to sign out: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	middleware.ClearSessionCookie(w, h.SecureCookies)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// the path to return to after signing in: a path on this site, so that the
+// login form cannot be used to send people elsewhere
+func localPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
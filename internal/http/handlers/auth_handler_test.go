@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/middleware"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+var csrfInput = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+// a browser-like client, keeping cookies and stopping at redirects
+type testBrowser struct {
+	t      *testing.T
+	client *http.Client
+	base   string
+}
+
+func (b *testBrowser) do(method, path string, form url.Values, headers map[string]string) (*http.Response, string) {
+	b.t.Helper()
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, b.base+path, body)
+	if err != nil {
+		b.t.Fatalf("failed to build request with error %v", err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		b.t.Fatalf("failed to %s %s with error %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(resp.Body)
+
+	return resp, string(content)
+}
+
+// the CSRF token of the last page's forms
+func csrfTokenIn(t *testing.T, page string) string {
+	t.Helper()
+
+	match := csrfInput.FindStringSubmatch(page)
+	if match == nil {
+		t.Fatalf("expected a CSRF field in the page, got:\n%s", page)
+	}
+	return match[1]
+}
+
+func TestAuthHandler(t *testing.T) {
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	auth := &services.AuthService{UserRepo: repos.Users, SessionRepo: repos.Sessions}
+	if _, err := auth.CreateUser(accounting.AsSystem(context.Background()), "alice", "correct horse battery", accounting.ViewerRole); err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+
+	authHandler := &AuthHandler{AuthService: auth, Views: views}
+	chart := &ChartOfAccountsHandler{
+		ChartOfAccountsService: &services.ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups},
+		Views:                  views,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login", authHandler.GetLogin)
+	mux.HandleFunc("POST /login", authHandler.PostLogin)
+	mux.HandleFunc("POST /logout", authHandler.PostLogout)
+	mux.HandleFunc("GET /chart", chart.GetChart)
+
+	server := httptest.NewServer(middleware.Chain(mux,
+		middleware.Sessions(auth, false),
+		middleware.CSRF(false),
+		middleware.RequireLogin("/login", "/static/"),
+	))
+	t.Cleanup(server.Close)
+
+	jar, _ := cookiejar.New(nil)
+	browser := &testBrowser{
+		t:    t,
+		base: server.URL,
+		client: &http.Client{
+			Jar:           jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+
+	t.Run("sends visitors who are not signed in to the login page", func(t *testing.T) {
+		resp, _ := browser.do(http.MethodGet, "/chart", nil, nil)
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/login?next=%2Fchart" {
+			t.Fatalf("expected a redirect to the login page, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+
+		resp, _ = browser.do(http.MethodGet, "/chart", nil, map[string]string{"HX-Request": "true"})
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("HX-Redirect") != "/login" {
+			t.Fatalf("expected HTMX swaps to redirect the page, got %d with %v", resp.StatusCode, resp.Header)
+		}
+	})
+
+	var loginToken string
+
+	t.Run("rejects sign ins without the login form's CSRF token", func(t *testing.T) {
+		resp, page := browser.do(http.MethodGet, "/login?next=/chart", nil, nil)
+		if resp.StatusCode != http.StatusOK || strings.Contains(page, "<nav>") {
+			t.Fatalf("expected the login page without the nav, got %d:\n%s", resp.StatusCode, page)
+		}
+		loginToken = csrfTokenIn(t, page)
+
+		resp, _ = browser.do(http.MethodPost, "/login", url.Values{
+			"username": {"alice"},
+			"password": {"correct horse battery"},
+		}, nil)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected a 403, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("rejects a wrong password", func(t *testing.T) {
+		resp, page := browser.do(http.MethodPost, "/login", url.Values{
+			"csrf_token": {loginToken},
+			"username":   {"alice"},
+			"password":   {"wrong horse battery"},
+		}, nil)
+		if resp.StatusCode != http.StatusUnauthorized || !strings.Contains(page, "invalid username or password") {
+			t.Fatalf("expected a 401 with the form again, got %d:\n%s", resp.StatusCode, page)
+		}
+	})
+
+	var sessionToken string
+
+	t.Run("signs in and returns to the page asked for", func(t *testing.T) {
+		resp, _ := browser.do(http.MethodPost, "/login", url.Values{
+			"csrf_token": {loginToken},
+			"username":   {"Alice"},
+			"password":   {"correct horse battery"},
+			"next":       {"/chart"},
+		}, nil)
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/chart" {
+			t.Fatalf("expected a redirect to the chart, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+
+		resp, page := browser.do(http.MethodGet, "/chart", nil, nil)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(page, "<span>alice</span>") {
+			t.Fatalf("expected the chart for alice, got %d:\n%s", resp.StatusCode, page)
+		}
+
+		sessionToken = csrfTokenIn(t, page)
+		if sessionToken == loginToken {
+			t.Fatal("expected the session to have a CSRF token of its own")
+		}
+	})
+
+	t.Run("does not redirect off the site after signing in", func(t *testing.T) {
+		resp, _ := browser.do(http.MethodGet, "/login?next=//evil.example", nil, nil)
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/" {
+			t.Fatalf("expected a redirect home, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+	})
+
+	t.Run("signs out with the session's CSRF token", func(t *testing.T) {
+		resp, _ := browser.do(http.MethodPost, "/logout", url.Values{"csrf_token": {loginToken}}, nil)
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected the login form's token to be refused once signed in, got %d", resp.StatusCode)
+		}
+
+		resp, _ = browser.do(http.MethodPost, "/logout", nil, map[string]string{middleware.CSRFHeader: sessionToken})
+		if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/login" {
+			t.Fatalf("expected a redirect to the login page, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+
+		resp, _ = browser.do(http.MethodGet, "/chart", nil, nil)
+		if resp.StatusCode != http.StatusSeeOther {
+			t.Fatalf("expected to be signed out, got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type BackupsHandler struct {
+	BackupService *services.BackupService
+	Views         *view.Views
+}
+
+// renders the backups taken, newest first, with a button to take another
+func (h *BackupsHandler) GetBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.BackupService.GetBackups(r.Context())
+	if err != nil {
+		writeBackupError(w, r, "failed to get backups", err)
+		return
+	}
+
+	data := map[string]any{"Backups": backups, "Dir": h.BackupService.Dir}
+	h.Views.Render(w, r, "backups", data)
+}
+
+// takes a backup of the live books, and redirects to the backups, or to the
+// integrity check if the books checked beforehand had problems
+func (h *BackupsHandler) PostBackup(w http.ResponseWriter, r *http.Request) {
+	result, err := h.BackupService.CreateBackup(r.Context())
+	if err != nil {
+		writeBackupError(w, r, "failed to back up the books", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "backup taken", "path", result.Backup.Path, "size", result.Backup.Size, "pruned", len(result.Pruned))
+	if !result.Check.OK() {
+		slog.WarnContext(r.Context(), "books backed up with integrity problems", "problems", len(result.Check.Problems))
+		http.Redirect(w, r, "/integrity", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/backups", http.StatusSeeOther)
+}
+
+func writeBackupError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/backup"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+func TestBackupsHandler(t *testing.T) {
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	admin, err := accounting.NewUser("root", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	bookkeeper, err := accounting.NewUser("bob", "correct horse battery", accounting.BookkeeperRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+
+	backups := &BackupsHandler{
+		BackupService: &services.BackupService{Snapshotter: repos, IntegrityRepo: repos.Integrity, Dir: t.TempDir(), Retention: backup.DefaultRetention},
+		Views:         views,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /backups", backups.GetBackups)
+	mux.HandleFunc("POST /backups", backups.PostBackup)
+
+	as := func(user *accounting.User, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req.WithContext(accounting.WithUser(req.Context(), user)))
+		return rec
+	}
+
+	t.Run("forbids all but admins", func(t *testing.T) {
+		if rec := as(bookkeeper, http.MethodPost, "/backups"); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := as(bookkeeper, http.MethodGet, "/backups"); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("takes a backup and lists it", func(t *testing.T) {
+		if rec := as(admin, http.MethodGet, "/backups"); !strings.Contains(rec.Body.String(), "No backups yet.") {
+			t.Fatalf("expected no backups, got %d:\n%s", rec.Code, rec.Body.String())
+		}
+
+		rec := as(admin, http.MethodPost, "/backups")
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected 303, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = as(admin, http.MethodGet, "/backups")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<td>ghoam-") {
+			t.Fatalf("expected the backup listed, got %d:\n%s", rec.Code, rec.Body.String())
+		}
+	})
+}
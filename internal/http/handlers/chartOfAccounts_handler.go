@@ -1,42 +1,129 @@
 package handlers
 
 import (
-	"html/template"
-	"log"
+	"database/sql"
+	"log/slog"
 	"net/http"
+	"net/url"
 
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
 	"github.com/hoodnoah/ghoam/internal/services"
 )
 
 type ChartOfAccountsHandler struct {
-	ChartOfAccountsService  *services.ChartOfAccountsService
-	ChartOfAccountsTemplate *template.Template
+	ChartOfAccountsService *services.ChartOfAccountsService
+	AuditService           *services.AuditService
+	Views                  *view.Views
 }
 
+// renders the chart of accounts as a tree of groups and their accounts, with
+// forms to add accounts and groups and to move groups
 func (h *ChartOfAccountsHandler) GetChart(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	w.Header().Set("Content-Type", "text/html")
 
 	// fetch chart of accounts
 	chart, err := h.ChartOfAccountsService.GetChartOfAccounts(ctx)
 	if err != nil {
-		log.Printf("failed to get chart of accounts with error %v", err)
+		slog.ErrorContext(r.Context(), "failed to get chart of accounts", "error", err)
 		http.Error(w, "failed to get chart of accounts: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// render full page
-	if err := h.ChartOfAccountsTemplate.ExecuteTemplate(w, "chart", chart); err != nil {
-		log.Printf("template error: %v", err)
-		http.Error(w, "render error: "+err.Error(), http.StatusInternalServerError)
+	groups, err := h.ChartOfAccountsService.GetGroups(ctx)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get account groups", "error", err)
+		http.Error(w, "failed to get account groups: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	// if it's an hx-swap, render only the fragment
-	if r.Header.Get("HX-Request") == "true" {
-		if err := h.ChartOfAccountsTemplate.ExecuteTemplate(w, "chartFragment", chart); err != nil {
-			log.Printf("failed to render chart of accounts fragment with error :%v", err)
-			http.Error(w, "Failed to render chart of accounts fragment:"+err.Error(), http.StatusInternalServerError)
-		}
+	data := map[string]any{"Chart": chart, "Groups": groups, "AccountTypes": accountTypes}
+	h.Views.Render(w, r, "chart", data)
+}
+
+// renders the account in the path along with the history of its changes
+func (h *ChartOfAccountsHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	account, err := h.ChartOfAccountsService.GetAccount(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeAuditError(w, r, "failed to get account", err)
+		return
+	}
+
+	history, err := h.AuditService.GetHistory(r.Context(), accounting.AccountEntity, account.Name)
+	if err != nil {
+		writeAuditError(w, r, "failed to get account history", err)
+		return
+	}
+
+	data := map[string]any{"Account": account, "History": history, "AccountTypes": accountTypes}
+	h.Views.Render(w, r, "account", data)
+}
+
+// the account types offered by the chart's forms
+var accountTypes = []accounting.AccountType{
+	accounting.Asset,
+	accounting.ContraAsset,
+	accounting.Liability,
+	accounting.Equity,
+	accounting.Revenue,
+	accounting.Expense,
+}
+
+// adds or changes the account named by the `name` form value per the
+// `group`, `type`, `normal_balance` and `display_after` (default none) form
+// values, and redirects to it
+func (h *ChartOfAccountsHandler) PostAccount(w http.ResponseWriter, r *http.Request) {
+	account, err := h.ChartOfAccountsService.SaveAccount(r.Context(), accounting.Account{
+		Name:            r.FormValue("name"),
+		ParentGroupName: r.FormValue("group"),
+		AccountType:     accounting.AccountType(r.FormValue("type")),
+		NormalBalance:   accounting.NormalBalance(r.FormValue("normal_balance")),
+		DisplayAfter:    formNullString(r, "display_after"),
+	})
+	if err != nil {
+		writeChartError(w, r, "failed to save account", err)
 		return
 	}
+
+	http.Redirect(w, r, "/accounts/"+url.PathEscape(account.Name), http.StatusSeeOther)
+}
+
+// adds a group per the `name`, `parent` and `display_after` (default none)
+// form values, and redirects to the chart
+func (h *ChartOfAccountsHandler) PostGroup(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.ChartOfAccountsService.CreateGroup(r.Context(), r.FormValue("name"), r.FormValue("parent"), formNullString(r, "display_after")); err != nil {
+		writeChartError(w, r, "failed to create account group", err)
+		return
+	}
+
+	http.Redirect(w, r, "/chart", http.StatusSeeOther)
+}
+
+// moves the group in the path per the `parent` and `display_after` (default
+// none) form values, and redirects to the chart
+func (h *ChartOfAccountsHandler) PostGroupMove(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.ChartOfAccountsService.MoveGroup(r.Context(), r.PathValue("name"), r.FormValue("parent"), formNullString(r, "display_after")); err != nil {
+		writeChartError(w, r, "failed to move account group", err)
+		return
+	}
+
+	http.Redirect(w, r, "/chart", http.StatusSeeOther)
+}
+
+// the named form value, null when blank
+func formNullString(r *http.Request, name string) sql.NullString {
+	value := r.FormValue(name)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func writeChartError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsAccountNotFound(err) || accounting.IsGroupNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type DocumentsHandler struct {
+	DocumentService *services.DocumentService
+}
+
+// generates a PDF of the invoice in the path, attaches it, and returns it
+func (h *DocumentsHandler) PostInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	attachment, err := h.DocumentService.GenerateInvoicePDF(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeDocumentError(w, r, "failed to generate invoice", err)
+		return
+	}
+
+	writeAttachment(w, r, attachment)
+}
+
+// generates a PDF statement for the customer in the path and the `month`
+// query parameter (YYYY-MM, default this month), attaches it, and returns it
+func (h *DocumentsHandler) PostStatementPDF(w http.ResponseWriter, r *http.Request) {
+	month := time.Now().UTC()
+	if value := r.URL.Query().Get("month"); value != "" {
+		parsed, err := time.Parse("2006-01", value)
+		if err != nil {
+			http.Error(w, "invalid month: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		month = parsed
+	}
+
+	attachment, err := h.DocumentService.GenerateStatementPDF(r.Context(), r.PathValue("id"), month)
+	if err != nil {
+		writeDocumentError(w, r, "failed to generate statement", err)
+		return
+	}
+
+	writeAttachment(w, r, attachment)
+}
+
+// returns a stored attachment
+func (h *DocumentsHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, err := h.DocumentService.GetAttachment(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeDocumentError(w, r, "failed to get attachment", err)
+		return
+	}
+
+	writeAttachment(w, r, &attachment)
+}
+
+func writeAttachment(w http.ResponseWriter, r *http.Request, attachment *accounting.Attachment) {
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", attachment.FileName))
+	if _, err := w.Write(attachment.Data); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write attachment", "error", err)
+	}
+}
+
+// maps missing records to 404, and everything else to 500
+func writeDocumentError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if accounting.IsPermissionDenied(err) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if accounting.IsInvoiceNotFound(err) || accounting.IsCustomerNotFound(err) || accounting.IsAttachmentNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	slog.ErrorContext(r.Context(), message, "error", err)
+	http.Error(w, message+": "+err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type FixedAssetsHandler struct {
+	FixedAssetService *services.FixedAssetService
+	Views             *view.Views
+}
+
+// renders the fixed asset register as of the `as_of` query parameter (default today)
+func (h *FixedAssetsHandler) GetRegister(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, "invalid as_of date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	register, err := h.FixedAssetService.GetRegister(r.Context(), asOf)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get fixed asset register", "error", err)
+		http.Error(w, "failed to get fixed asset register: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.Views.Render(w, r, "fixedAssetRegister", register)
+}
+
+// renders the depreciation schedule of the asset in the path
+func (h *FixedAssetsHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	asset, schedule, err := h.FixedAssetService.GetSchedule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if accounting.IsFixedAssetNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to get depreciation schedule", "error", err)
+		http.Error(w, "failed to get depreciation schedule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{"Asset": asset, "Schedule": schedule}
+	h.Views.Render(w, r, "depreciationSchedule", data)
+}
+
+// posts depreciation for every unposted period through the `through` form
+// value (YYYY-MM-DD, default today), and returns the postings made as JSON
+func (h *FixedAssetsHandler) PostDepreciation(w http.ResponseWriter, r *http.Request) {
+	through, err := parseFormDate(r, "through")
+	if err != nil {
+		http.Error(w, "invalid through date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	postings, err := h.FixedAssetService.RunDepreciation(r.Context(), through)
+	if err != nil {
+		if accounting.IsPermissionDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to run depreciation", "error", err)
+		http.Error(w, "failed to run depreciation: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, postings)
+}
+
+// disposes of the asset in the path per the `date`, `proceeds`,
+// `proceeds_account` and `gain_loss_account` form values, and returns the disposal as JSON
+func (h *FixedAssetsHandler) PostDisposal(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid disposal date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proceeds, err := parseFormAmount(r, "proceeds")
+	if err != nil {
+		http.Error(w, "invalid proceeds: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	disposal, err := h.FixedAssetService.DisposeAsset(r.Context(), services.DisposeAssetRequest{
+		AssetID:             r.PathValue("id"),
+		DisposalDate:        date,
+		Proceeds:            proceeds,
+		ProceedsAccountName: r.FormValue("proceeds_account"),
+		GainLossAccountName: r.FormValue("gain_loss_account"),
+	})
+	if err != nil {
+		if accounting.IsPermissionDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if accounting.IsFixedAssetNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to dispose of fixed asset", "error", err)
+		http.Error(w, "failed to dispose of fixed asset: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, disposal)
+}
+
+// parses the named form value as a date, defaulting to today
+func parseFormDate(r *http.Request, name string) (time.Time, error) {
+	value := r.FormValue(name)
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
+// parses the named form value as an amount, defaulting to zero
+func parseFormAmount(r *http.Request, name string) (float64, error) {
+	value := r.FormValue(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
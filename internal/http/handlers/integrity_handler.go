@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type IntegrityHandler struct {
+	IntegrityService *services.IntegrityService
+	Views            *view.Views
+}
+
+// checks the books whole, and renders each problem found with its fix
+func (h *IntegrityHandler) GetCheck(w http.ResponseWriter, r *http.Request) {
+	report, err := h.IntegrityService.Check(r.Context())
+	if err != nil {
+		writeIntegrityError(w, r, "failed to check the books", err)
+		return
+	}
+
+	h.Views.Render(w, r, "integrity", report)
+}
+
+func writeIntegrityError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
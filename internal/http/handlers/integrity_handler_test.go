@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+func TestIntegrityHandler(t *testing.T) {
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	admin, err := accounting.NewUser("root", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	bookkeeper, err := accounting.NewUser("bob", "correct horse battery", accounting.BookkeeperRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+
+	integrity := &IntegrityHandler{IntegrityService: &services.IntegrityService{IntegrityRepo: repos.Integrity}, Views: views}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /integrity", integrity.GetCheck)
+
+	as := func(user *accounting.User) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/integrity", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req.WithContext(accounting.WithUser(req.Context(), user)))
+		return rec
+	}
+
+	t.Run("forbids all but admins", func(t *testing.T) {
+		if rec := as(bookkeeper); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("reports sound books", func(t *testing.T) {
+		rec := as(admin)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "No problems found.") {
+			t.Fatalf("expected no problems, got %d:\n%s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("lists each problem with its fix", func(t *testing.T) {
+		if err := repos.Accounts.Save(accounting.WithUser(context.Background(), admin), &accounting.Account{
+			Name:            "Petty Cash",
+			ParentGroupName: "Assets",
+			AccountType:     accounting.Asset,
+			NormalBalance:   accounting.CreditNormal,
+		}); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+
+		rec := as(admin)
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || strings.Contains(body, "No problems found.") || !strings.Contains(body, "<td>Petty Cash</td>") {
+			t.Fatalf("expected the account's problem listed, got %d:\n%s", rec.Code, body)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type InventoryHandler struct {
+	InventoryService *services.InventoryService
+	Views            *view.Views
+}
+
+// renders the units on hand as of the `as_of` query parameter (default today),
+// as html or, with `format=json`, json
+func (h *InventoryHandler) GetOnHand(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, "invalid as_of date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.InventoryService.GetOnHand(r.Context(), asOf)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to build on-hand report", "error", err)
+		http.Error(w, "failed to build on-hand report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode on-hand report", "error", err)
+		}
+		return
+	}
+
+	h.Views.Render(w, r, "inventoryOnHand", report)
+}
+
+// acquires a unit per the `serial_number`, `description`, `cost`, `date`,
+// `location`, `inventory_account` and `payment_account` form values
+func (h *InventoryHandler) PostUnit(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid acquisition date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cost, err := parseFormAmount(r, "cost")
+	if err != nil {
+		http.Error(w, "invalid cost: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unit, err := h.InventoryService.AcquireUnit(r.Context(), services.AcquireUnitRequest{
+		SerialNumber:         r.FormValue("serial_number"),
+		Description:          r.FormValue("description"),
+		Cost:                 cost,
+		AcquisitionDate:      date,
+		Location:             r.FormValue("location"),
+		InventoryAccountName: r.FormValue("inventory_account"),
+		PaymentAccountName:   r.FormValue("payment_account"),
+	})
+	if err != nil {
+		writeInventoryError(w, r, "failed to acquire unit", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, unit)
+}
+
+// moves the unit in the path to the `location` form value
+func (h *InventoryHandler) PostMove(w http.ResponseWriter, r *http.Request) {
+	if err := h.InventoryService.MoveUnit(r.Context(), r.PathValue("id"), r.FormValue("location")); err != nil {
+		writeInventoryError(w, r, "failed to move unit", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invoices the unit in the path per the `customer_id`, `number`, `date`,
+// `price`, `receivable_account`, `revenue_account`, `project_id` and `memo`
+// form values
+func (h *InventoryHandler) PostSale(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid sale date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	price, err := parseFormAmount(r, "price")
+	if err != nil {
+		http.Error(w, "invalid price: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sale, err := h.InventoryService.SellUnit(r.Context(), services.SellUnitRequest{
+		UnitID:                r.PathValue("id"),
+		CustomerID:            r.FormValue("customer_id"),
+		InvoiceNumber:         r.FormValue("number"),
+		SaleDate:              date,
+		Price:                 price,
+		ReceivableAccountName: r.FormValue("receivable_account"),
+		RevenueAccountName:    r.FormValue("revenue_account"),
+		ProjectID:             r.FormValue("project_id"),
+		Memo:                  r.FormValue("memo"),
+	})
+	if err != nil {
+		writeInventoryError(w, r, "failed to sell unit", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, sale)
+}
+
+// creates a stock item per the `sku`, `name`, `method`, `inventory_account`
+// and `cogs_account` form values
+func (h *InventoryHandler) PostStockItem(w http.ResponseWriter, r *http.Request) {
+	item, err := h.InventoryService.CreateStockItem(r.Context(), services.CreateStockItemRequest{
+		SKU:                  r.FormValue("sku"),
+		Name:                 r.FormValue("name"),
+		Method:               accounting.CostingMethod(r.FormValue("method")),
+		InventoryAccountName: r.FormValue("inventory_account"),
+		COGSAccountName:      r.FormValue("cogs_account"),
+	})
+	if err != nil {
+		writeInventoryError(w, r, "failed to create stock item", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, item)
+}
+
+// renders the movements and cost layers of the stock item in the path as of
+// the `as_of` query parameter (default today), as html or, with `format=json`, json
+func (h *InventoryHandler) GetStockItem(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, "invalid as_of date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	item, movements, valuation, err := h.InventoryService.GetStockValuation(r.Context(), r.PathValue("id"), asOf)
+	if err != nil {
+		if accounting.IsStockItemNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to value stock item", "error", err)
+		http.Error(w, "failed to value stock item: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{"Item": item, "Movements": movements, "Valuation": valuation, "AsOf": asOf}
+	if r.URL.Query().Get("format") == "json" {
+		writeJSON(w, r, http.StatusOK, data)
+		return
+	}
+
+	h.Views.Render(w, r, "stockItem", data)
+}
+
+// receives stock into the item in the path per the `date`, `quantity`,
+// `unit_cost`, `credit_account` and `memo` form values; the response lists
+// any later issues recosted by a backdated receipt
+func (h *InventoryHandler) PostReceipt(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid receipt date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	quantity, err := parseFormAmount(r, "quantity")
+	if err != nil {
+		http.Error(w, "invalid quantity: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unitCost, err := parseFormAmount(r, "unit_cost")
+	if err != nil {
+		http.Error(w, "invalid unit cost: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.InventoryService.ReceiveStock(r.Context(), services.ReceiveStockRequest{
+		ItemID:            r.PathValue("id"),
+		Date:              date,
+		Quantity:          quantity,
+		UnitCost:          unitCost,
+		CreditAccountName: r.FormValue("credit_account"),
+		Memo:              r.FormValue("memo"),
+	})
+	if err != nil {
+		writeInventoryError(w, r, "failed to receive stock", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, result)
+}
+
+// issues stock from the item in the path per the `date`, `quantity`,
+// `debit_account`, `project_id` and `memo` form values; the response lists any later
+// issues recosted by a backdated issue
+func (h *InventoryHandler) PostIssue(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid issue date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	quantity, err := parseFormAmount(r, "quantity")
+	if err != nil {
+		http.Error(w, "invalid quantity: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.InventoryService.IssueStock(r.Context(), services.IssueStockRequest{
+		ItemID:           r.PathValue("id"),
+		Date:             date,
+		Quantity:         quantity,
+		DebitAccountName: r.FormValue("debit_account"),
+		ProjectID:        r.FormValue("project_id"),
+		Memo:             r.FormValue("memo"),
+	})
+	if err != nil {
+		writeInventoryError(w, r, "failed to issue stock", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, result)
+}
+
+// renders the history of counts
+func (h *InventoryHandler) GetCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.InventoryService.GetCounts(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get inventory counts", "error", err)
+		http.Error(w, "failed to get inventory counts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.Views.Render(w, r, "inventoryCounts", counts)
+}
+
+// generates a count sheet per the `date` and `shrinkage_account` form values,
+// and redirects to it
+func (h *InventoryHandler) PostCount(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid count date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.InventoryService.StartCount(r.Context(), date, r.FormValue("shrinkage_account"))
+	if err != nil {
+		writeInventoryError(w, r, "failed to start inventory count", err)
+		return
+	}
+
+	http.Redirect(w, r, "/inventory/counts/"+count.ID, http.StatusSeeOther)
+}
+
+// renders the count sheet in the path, with its variances
+func (h *InventoryHandler) GetCount(w http.ResponseWriter, r *http.Request) {
+	count, err := h.InventoryService.GetCount(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeInventoryError(w, r, "failed to get inventory count", err)
+		return
+	}
+
+	h.Views.Render(w, r, "inventoryCount", count)
+}
+
+// records the counted quantities in the `counted_<item id>` form values on
+// the count in the path, and redirects back to it for review
+func (h *InventoryHandler) PostCountedQuantities(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	counted := make(map[string]float64)
+	for key := range r.PostForm {
+		itemID, ok := strings.CutPrefix(key, "counted_")
+		if !ok || r.PostForm.Get(key) == "" {
+			continue
+		}
+		quantity, err := parseFormAmount(r, key)
+		if err != nil {
+			http.Error(w, "invalid counted quantity: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		counted[itemID] = quantity
+	}
+
+	id := r.PathValue("id")
+	if _, err := h.InventoryService.RecordCounts(r.Context(), id, counted); err != nil {
+		writeInventoryError(w, r, "failed to record counted quantities", err)
+		return
+	}
+
+	http.Redirect(w, r, "/inventory/counts/"+id, http.StatusSeeOther)
+}
+
+// posts the variances of the count in the path, and redirects back to it
+func (h *InventoryHandler) PostCountPosting(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := h.InventoryService.PostCount(r.Context(), id); err != nil {
+		writeInventoryError(w, r, "failed to post inventory count", err)
+		return
+	}
+
+	http.Redirect(w, r, "/inventory/counts/"+id, http.StatusSeeOther)
+}
+
+// maps missing records to 404, a repeated sale or a shortfall to 409, and everything else to 422
+func writeInventoryError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsInventoryUnitNotFound(err) || accounting.IsStockItemNotFound(err) ||
+		accounting.IsInventoryCountNotFound(err) || accounting.IsCustomerNotFound(err) ||
+		accounting.IsProjectNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accounting.IsUnitAlreadySold(err) || accounting.IsInsufficientStock(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type JournalEntriesHandler struct {
+	JournalEntryService *services.JournalEntryService
+	AuditService        *services.AuditService
+	Views               *view.Views
+}
+
+// renders the journal entry in the path along with the history of its changes
+func (h *JournalEntriesHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.JournalEntryService.GetEntry(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeAuditError(w, r, "failed to get journal entry", err)
+		return
+	}
+
+	history, err := h.AuditService.GetHistory(r.Context(), accounting.JournalEntryEntity, entry.ID)
+	if err != nil {
+		writeAuditError(w, r, "failed to get journal entry history", err)
+		return
+	}
+
+	data := map[string]any{"Entry": entry, "History": history}
+	h.Views.Render(w, r, "journalEntry", data)
+}
+
+// renders the result of verifying the hash chain over every posted entry
+func (h *JournalEntriesHandler) GetChain(w http.ResponseWriter, r *http.Request) {
+	report, err := h.JournalEntryService.VerifyChain(r.Context())
+	if err != nil {
+		writeAuditError(w, r, "failed to verify the ledger", err)
+		return
+	}
+
+	h.Views.Render(w, r, "entryChain", report)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type PayablesHandler struct {
+	PayablesService *services.PayablesService
+	Views           *view.Views
+}
+
+// renders the vendors and bills, with forms to add a vendor and enter a bill
+func (h *PayablesHandler) GetPayables(w http.ResponseWriter, r *http.Request) {
+	vendors, err := h.PayablesService.GetVendors(r.Context())
+	if err != nil {
+		writePayablesError(w, r, "failed to get vendors", err)
+		return
+	}
+
+	bills, err := h.PayablesService.GetBills(r.Context())
+	if err != nil {
+		writePayablesError(w, r, "failed to get bills", err)
+		return
+	}
+
+	data := map[string]any{"Vendors": vendors, "Bills": bills}
+	h.Views.Render(w, r, "payables", data)
+}
+
+// creates a vendor per the `name` and `terms` form values (e.g. "2/10 Net
+// 30", default Net 30), and redirects to the payables
+func (h *PayablesHandler) PostVendor(w http.ResponseWriter, r *http.Request) {
+	terms, err := accounting.ParsePaymentTerms(r.FormValue("terms"))
+	if err != nil {
+		http.Error(w, "invalid terms: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.PayablesService.CreateVendor(r.Context(), r.FormValue("name"), terms); err != nil {
+		writePayablesError(w, r, "failed to create vendor", err)
+		return
+	}
+
+	http.Redirect(w, r, "/payables", http.StatusSeeOther)
+}
+
+// enters a bill per the `vendor_id`, `number`, `bill_date`, `terms` (default
+// the vendor's), `payable_account` and `memo` form values, and the repeated
+// `line_account`, `line_description`, `line_amount` and `line_project`
+// values, and redirects to it
+func (h *PayablesHandler) PostBill(w http.ResponseWriter, r *http.Request) {
+	billDate, err := parseFormDate(r, "bill_date")
+	if err != nil {
+		http.Error(w, "invalid bill date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	terms, err := parseFormTerms(r, "terms")
+	if err != nil {
+		http.Error(w, "invalid terms: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lines, err := parseFormLines(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	billLines := make([]accounting.BillLine, len(lines))
+	for i, line := range lines {
+		billLines[i] = accounting.BillLine{
+			AccountName: line.AccountName,
+			Description: line.Description,
+			Amount:      line.Amount,
+			ProjectID:   line.ProjectID,
+		}
+	}
+
+	bill, err := h.PayablesService.EnterBill(r.Context(), services.EnterBillRequest{
+		VendorID:           r.FormValue("vendor_id"),
+		Number:             r.FormValue("number"),
+		BillDate:           billDate,
+		Terms:              terms,
+		PayableAccountName: r.FormValue("payable_account"),
+		Memo:               r.FormValue("memo"),
+		Lines:              billLines,
+	})
+	if err != nil {
+		writePayablesError(w, r, "failed to enter bill", err)
+		return
+	}
+
+	http.Redirect(w, r, "/bills/"+bill.ID, http.StatusSeeOther)
+}
+
+// renders the bill in the path with the payments made against it
+func (h *PayablesHandler) GetBill(w http.ResponseWriter, r *http.Request) {
+	bill, vendor, payments, err := h.PayablesService.GetBill(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePayablesError(w, r, "failed to get bill", err)
+		return
+	}
+
+	data := map[string]any{
+		"Bill":        &bill,
+		"Vendor":      vendor,
+		"Payments":    payments,
+		"OpenBalance": bill.OpenBalance(payments),
+	}
+	h.Views.Render(w, r, "bill", data)
+}
+
+// pays the bill in the path per the `payment_date`, `cash_account` and
+// `amount` (default the open balance, net of any discount earned) form
+// values, and redirects back to it
+func (h *PayablesHandler) PostPayment(w http.ResponseWriter, r *http.Request) {
+	paymentDate, err := parseFormDate(r, "payment_date")
+	if err != nil {
+		http.Error(w, "invalid payment date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount, err := parseFormAmount(r, "amount")
+	if err != nil {
+		http.Error(w, "invalid amount: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.PayablesService.PayBill(r.Context(), services.PayBillRequest{
+		BillID:          r.PathValue("id"),
+		PaymentDate:     paymentDate,
+		CashAccountName: r.FormValue("cash_account"),
+		Amount:          amount,
+	})
+	if err != nil {
+		writePayablesError(w, r, "failed to pay bill", err)
+		return
+	}
+
+	http.Redirect(w, r, "/bills/"+payment.BillID, http.StatusSeeOther)
+}
+
+// a document line as entered on a form
+type formLine struct {
+	AccountName string
+	Description string
+	Amount      float64
+	ProjectID   string
+	TaxCode     string
+}
+
+// parses the repeated `line_account`, `line_description`, `line_amount`,
+// `line_project` and `line_tax_code` form values into lines, skipping rows
+// left blank
+func parseFormLines(r *http.Request) ([]formLine, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	value := func(name string, i int) string {
+		if values := r.Form[name]; i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+
+	var lines []formLine
+	for i := range r.Form["line_account"] {
+		line := formLine{
+			AccountName: value("line_account", i),
+			Description: value("line_description", i),
+			ProjectID:   value("line_project", i),
+			TaxCode:     value("line_tax_code", i),
+		}
+		amount := value("line_amount", i)
+		if line.AccountName == "" && amount == "" {
+			continue
+		}
+
+		var err error
+		if line.Amount, err = strconv.ParseFloat(amount, 64); err != nil {
+			return nil, fmt.Errorf("invalid amount on line %d: %w", i+1, err)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// parses the named form value as payment terms; nil if left blank
+func parseFormTerms(r *http.Request, name string) (*accounting.PaymentTerms, error) {
+	if strings.TrimSpace(r.FormValue(name)) == "" {
+		return nil, nil
+	}
+
+	terms, err := accounting.ParsePaymentTerms(r.FormValue(name))
+	if err != nil {
+		return nil, err
+	}
+	return &terms, nil
+}
+
+func writePayablesError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsVendorNotFound(err) || accounting.IsBillNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
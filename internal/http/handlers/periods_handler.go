@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type PeriodsHandler struct {
+	PeriodService *services.PeriodService
+	Views         *view.Views
+}
+
+// renders the fiscal calendar and the periods of the fiscal year in the
+// `year` query parameter (default the current fiscal year), with their statuses
+func (h *PeriodsHandler) GetPeriods(w http.ResponseWriter, r *http.Request) {
+	calendar, err := h.PeriodService.GetCalendar(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get fiscal calendar", "error", err)
+		http.Error(w, "failed to get fiscal calendar: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	year := calendar.FiscalYearOf(time.Now())
+	if value := r.URL.Query().Get("year"); value != "" {
+		if year, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "invalid year: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	periods, err := h.PeriodService.GetPeriods(r.Context(), year)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get accounting periods", "error", err)
+		http.Error(w, "failed to get accounting periods: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"Calendar": calendar,
+		"Year":     year,
+		"PrevYear": year - 1,
+		"NextYear": year + 1,
+		"Periods":  periods,
+		"Months":   months(),
+		"Patterns": []accounting.PeriodPattern{accounting.MonthlyPeriods, accounting.FourFourFivePeriods},
+		"Statuses": []accounting.PeriodStatus{accounting.PeriodOpen, accounting.PeriodSoftClosed, accounting.PeriodClosed},
+	}
+
+	h.Views.Render(w, r, "periods", data)
+}
+
+// replaces the fiscal calendar per the `start_month` and `pattern` form
+// values, and redirects to the periods
+func (h *PeriodsHandler) PostCalendar(w http.ResponseWriter, r *http.Request) {
+	startMonth, err := strconv.Atoi(r.FormValue("start_month"))
+	if err != nil {
+		http.Error(w, "invalid start month: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	calendar := accounting.FiscalCalendar{
+		StartMonth: time.Month(startMonth),
+		Pattern:    accounting.PeriodPattern(r.FormValue("pattern")),
+	}
+	if err := h.PeriodService.SaveCalendar(r.Context(), calendar); err != nil {
+		if accounting.IsPermissionDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to save fiscal calendar", "error", err)
+		http.Error(w, "failed to save fiscal calendar: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	http.Redirect(w, r, "/periods", http.StatusSeeOther)
+}
+
+// opens the fiscal year in the `year` form value, and redirects to it
+func (h *PeriodsHandler) PostOpenYear(w http.ResponseWriter, r *http.Request) {
+	year, err := strconv.Atoi(r.FormValue("year"))
+	if err != nil {
+		http.Error(w, "invalid year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.PeriodService.OpenYear(r.Context(), year); err != nil {
+		if accounting.IsPermissionDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to open fiscal year", "error", err)
+		http.Error(w, "failed to open fiscal year: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/periods?year=%d", year), http.StatusSeeOther)
+}
+
+// sets the status of the period in the path per the `status` and `reason`
+// form values, and redirects to its fiscal year
+func (h *PeriodsHandler) PostStatus(w http.ResponseWriter, r *http.Request) {
+	period, err := h.PeriodService.SetPeriodStatus(
+		r.Context(),
+		r.PathValue("id"),
+		accounting.PeriodStatus(r.FormValue("status")),
+		r.FormValue("reason"),
+	)
+	if err != nil {
+		if accounting.IsPermissionDenied(err) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if accounting.IsPeriodNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "failed to set period status", "error", err)
+		http.Error(w, "failed to set period status: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/periods?year=%d", period.FiscalYear), http.StatusSeeOther)
+}
+
+// the months of the year, for choosing when fiscal years begin
+func months() []time.Month {
+	months := make([]time.Month, 12)
+	for i := range months {
+		months[i] = time.Month(i + 1)
+	}
+	return months
+}
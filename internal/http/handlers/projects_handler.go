@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type ProjectsHandler struct {
+	ProjectService *services.ProjectService
+	Views          *view.Views
+}
+
+// renders the list of projects
+func (h *ProjectsHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.ProjectService.GetProjects(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get projects", "error", err)
+		http.Error(w, "failed to get projects: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.Views.Render(w, r, "projects", projects)
+}
+
+// creates a project per the `name`, `customer_id`, `start_date` and
+// `description` form values, and redirects to it
+func (h *ProjectsHandler) PostProject(w http.ResponseWriter, r *http.Request) {
+	startDate, err := parseFormDate(r, "start_date")
+	if err != nil {
+		http.Error(w, "invalid start date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.ProjectService.CreateProject(r.Context(), services.CreateProjectRequest{
+		Name:        r.FormValue("name"),
+		CustomerID:  r.FormValue("customer_id"),
+		StartDate:   startDate,
+		Description: r.FormValue("description"),
+	})
+	if err != nil {
+		writeProjectError(w, r, "failed to create project", err)
+		return
+	}
+
+	http.Redirect(w, r, "/projects/"+project.ID, http.StatusSeeOther)
+}
+
+// renders the project in the path with the hours recorded against it
+func (h *ProjectsHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	project, entries, err := h.ProjectService.GetProject(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeProjectError(w, r, "failed to get project", err)
+		return
+	}
+
+	var hours, cost float64
+	for _, entry := range entries {
+		hours += entry.Hours
+		cost += entry.Cost()
+	}
+
+	data := map[string]any{
+		"Project":     project,
+		"TimeEntries": entries,
+		"Hours":       hours,
+		"LaborCost":   accounting.RoundCents(cost),
+	}
+
+	h.Views.Render(w, r, "project", data)
+}
+
+// records hours against the project in the path per the `person`, `date`,
+// `hours`, `rate`, `debit_account`, `credit_account` and `memo` form values,
+// and redirects back to it
+func (h *ProjectsHandler) PostTime(w http.ResponseWriter, r *http.Request) {
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid work date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hours, err := parseFormAmount(r, "hours")
+	if err != nil {
+		http.Error(w, "invalid hours: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rate, err := parseFormAmount(r, "rate")
+	if err != nil {
+		http.Error(w, "invalid rate: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.ProjectService.RecordTime(r.Context(), services.RecordTimeRequest{
+		ProjectID:         r.PathValue("id"),
+		Person:            r.FormValue("person"),
+		Date:              date,
+		Hours:             hours,
+		Rate:              rate,
+		DebitAccountName:  r.FormValue("debit_account"),
+		CreditAccountName: r.FormValue("credit_account"),
+		Memo:              r.FormValue("memo"),
+	})
+	if err != nil {
+		writeProjectError(w, r, "failed to record time", err)
+		return
+	}
+
+	http.Redirect(w, r, "/projects/"+entry.ProjectID, http.StatusSeeOther)
+}
+
+// closes the project in the path to further time, and redirects back to it
+func (h *ProjectsHandler) PostClose(w http.ResponseWriter, r *http.Request) {
+	project, err := h.ProjectService.CloseProject(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeProjectError(w, r, "failed to close project", err)
+		return
+	}
+
+	http.Redirect(w, r, "/projects/"+project.ID, http.StatusSeeOther)
+}
+
+// renders the comparison of project margins over the `from` and `to` query
+// parameters (default the year to date), as html or, with `format=json`, json
+func (h *ProjectsHandler) GetMargins(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, "invalid date range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.ProjectService.GetMarginReport(r.Context(), from, to)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to build project margin report", "error", err)
+		http.Error(w, "failed to build project margin report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode project margin report", "error", err)
+		}
+		return
+	}
+
+	h.Views.Render(w, r, "projectMargins", report)
+}
+
+// renders the margin of the project in the path over the `from` and `to`
+// query parameters, drilled down to its journal lines and time entries
+func (h *ProjectsHandler) GetMargin(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, "invalid date range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	margin, err := h.ProjectService.GetProjectMargin(r.Context(), r.PathValue("id"), from, to)
+	if err != nil {
+		writeProjectError(w, r, "failed to build project margin", err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(margin); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode project margin", "error", err)
+		}
+		return
+	}
+
+	data := map[string]any{"From": from, "To": to, "Margin": margin}
+	h.Views.Render(w, r, "projectMargin", data)
+}
+
+// parses the `from` and `to` query parameters, which default to the start
+// of the current year and today
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	return from, to, nil
+}
+
+func writeProjectError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsProjectNotFound(err) || accounting.IsCustomerNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type ReceivablesHandler struct {
+	ReceivablesService *services.ReceivablesService
+	Views              *view.Views
+}
+
+// renders the customers and invoices, with forms to add a customer and issue
+// an invoice whose lines may carry a sales tax code
+func (h *ReceivablesHandler) GetReceivables(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.ReceivablesService.GetCustomers(r.Context())
+	if err != nil {
+		writeReceivablesError(w, r, "failed to get customers", err)
+		return
+	}
+
+	invoices, err := h.ReceivablesService.GetInvoices(r.Context())
+	if err != nil {
+		writeReceivablesError(w, r, "failed to get invoices", err)
+		return
+	}
+
+	taxCodes, err := h.ReceivablesService.GetTaxCodes(r.Context())
+	if err != nil {
+		writeReceivablesError(w, r, "failed to get sales tax codes", err)
+		return
+	}
+
+	data := map[string]any{"Customers": customers, "Invoices": invoices, "TaxCodes": taxCodes}
+	h.Views.Render(w, r, "receivables", data)
+}
+
+// creates a customer per the `name` and `terms` form values (e.g. "Net 15",
+// default Net 30), and redirects to the receivables
+func (h *ReceivablesHandler) PostCustomer(w http.ResponseWriter, r *http.Request) {
+	terms, err := accounting.ParsePaymentTerms(r.FormValue("terms"))
+	if err != nil {
+		http.Error(w, "invalid terms: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.ReceivablesService.CreateCustomer(r.Context(), r.FormValue("name"), terms); err != nil {
+		writeReceivablesError(w, r, "failed to create customer", err)
+		return
+	}
+
+	http.Redirect(w, r, "/receivables", http.StatusSeeOther)
+}
+
+// issues an invoice per the `customer_id`, `number`, `invoice_date`, `terms`
+// (default the customer's), `receivable_account` and `memo` form values, and
+// the repeated `line_account`, `line_description`, `line_amount`,
+// `line_project` and `line_tax_code` values, and redirects to it
+func (h *ReceivablesHandler) PostInvoice(w http.ResponseWriter, r *http.Request) {
+	invoiceDate, err := parseFormDate(r, "invoice_date")
+	if err != nil {
+		http.Error(w, "invalid invoice date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	terms, err := parseFormTerms(r, "terms")
+	if err != nil {
+		http.Error(w, "invalid terms: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lines, err := parseFormLines(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	invoiceLines := make([]accounting.InvoiceLine, len(lines))
+	for i, line := range lines {
+		invoiceLines[i] = accounting.InvoiceLine{
+			AccountName: line.AccountName,
+			Description: line.Description,
+			Amount:      line.Amount,
+			ProjectID:   line.ProjectID,
+			TaxCode:     line.TaxCode,
+		}
+	}
+
+	invoice, err := h.ReceivablesService.IssueInvoice(r.Context(), services.IssueInvoiceRequest{
+		CustomerID:            r.FormValue("customer_id"),
+		Number:                r.FormValue("number"),
+		InvoiceDate:           invoiceDate,
+		Terms:                 terms,
+		ReceivableAccountName: r.FormValue("receivable_account"),
+		Memo:                  r.FormValue("memo"),
+		Lines:                 invoiceLines,
+	})
+	if err != nil {
+		writeReceivablesError(w, r, "failed to issue invoice", err)
+		return
+	}
+
+	http.Redirect(w, r, "/invoices/"+invoice.ID, http.StatusSeeOther)
+}
+
+// renders the invoice in the path with the payments received against it
+func (h *ReceivablesHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	invoice, customer, payments, err := h.ReceivablesService.GetInvoice(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReceivablesError(w, r, "failed to get invoice", err)
+		return
+	}
+
+	data := map[string]any{
+		"Invoice":     &invoice,
+		"Customer":    customer,
+		"Payments":    payments,
+		"OpenBalance": invoice.OpenBalance(payments),
+	}
+	h.Views.Render(w, r, "invoice", data)
+}
+
+// receives a payment against the invoice in the path per the
+// `payment_date`, `cash_account` and `amount` (default the open balance)
+// form values, and redirects back to it
+func (h *ReceivablesHandler) PostPayment(w http.ResponseWriter, r *http.Request) {
+	paymentDate, err := parseFormDate(r, "payment_date")
+	if err != nil {
+		http.Error(w, "invalid payment date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount, err := parseFormAmount(r, "amount")
+	if err != nil {
+		http.Error(w, "invalid amount: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.ReceivablesService.ReceivePayment(r.Context(), services.ReceivePaymentRequest{
+		InvoiceID:       r.PathValue("id"),
+		PaymentDate:     paymentDate,
+		CashAccountName: r.FormValue("cash_account"),
+		Amount:          amount,
+	})
+	if err != nil {
+		writeReceivablesError(w, r, "failed to receive payment", err)
+		return
+	}
+
+	http.Redirect(w, r, "/invoices/"+payment.InvoiceID, http.StatusSeeOther)
+}
+
+func writeReceivablesError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsCustomerNotFound(err) || accounting.IsInvoiceNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type SalesTaxHandler struct {
+	SalesTaxService *services.SalesTaxService
+	Views           *view.Views
+}
+
+// renders the sales tax return for the `from` and `to` query parameters
+// (default the year to date), in the `format` query parameter: html
+// (default, with the jurisdictions, rates, codes and remittances for
+// editing), csv or json
+func (h *SalesTaxHandler) GetReturn(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		http.Error(w, "invalid date range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.SalesTaxService.Return(r.Context(), from, to)
+	if err != nil {
+		writeSalesTaxError(w, r, "failed to build sales tax return", err)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode sales tax return", "error", err)
+		}
+		return
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=\"sales-tax-%s-%s.csv\"", from.Format(time.DateOnly), to.Format(time.DateOnly),
+		))
+		if err := writeSalesTaxReturnCSV(w, report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write sales tax return", "error", err)
+		}
+		return
+	}
+
+	schedule, err := h.SalesTaxService.GetSchedule(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get sales tax schedule", "error", err)
+		http.Error(w, "failed to get sales tax schedule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	remittances, err := h.SalesTaxService.GetRemittances(r.Context(), from, to)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get sales tax remittances", "error", err)
+		http.Error(w, "failed to get sales tax remittances: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jurisdictionNames := make(map[string]string, len(schedule.Jurisdictions))
+	for _, jurisdiction := range schedule.Jurisdictions {
+		jurisdictionNames[jurisdiction.ID] = jurisdiction.Name
+	}
+	rateNames := make(map[string]string, len(schedule.Rates))
+	for _, rate := range schedule.Rates {
+		rateNames[rate.ID] = jurisdictionNames[rate.JurisdictionID] + " " + rate.Name
+	}
+
+	data := map[string]any{
+		"From":              from,
+		"To":                to,
+		"Return":            report,
+		"Schedule":          schedule,
+		"Remittances":       remittances,
+		"JurisdictionNames": jurisdictionNames,
+		"RateNames":         rateNames,
+	}
+
+	h.Views.Render(w, r, "salesTax", data)
+}
+
+// creates a jurisdiction per the `name` and `liability_account` form values,
+// and redirects to the return
+func (h *SalesTaxHandler) PostJurisdiction(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.SalesTaxService.CreateJurisdiction(r.Context(), r.FormValue("name"), r.FormValue("liability_account")); err != nil {
+		writeSalesTaxError(w, r, "failed to create sales tax jurisdiction", err)
+		return
+	}
+
+	http.Redirect(w, r, "/sales-tax", http.StatusSeeOther)
+}
+
+// saves the rate `id` (or a new rate, if empty) of the `jurisdiction_id`
+// per the `name` and `rate` form values, the rate given as a fraction, and
+// redirects to the return
+func (h *SalesTaxHandler) PostRate(w http.ResponseWriter, r *http.Request) {
+	rate, err := parseFormAmount(r, "rate")
+	if err != nil {
+		http.Error(w, "invalid rate: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.SalesTaxService.SaveRate(r.Context(), accounting.SalesTaxRate{
+		ID:             r.FormValue("id"),
+		JurisdictionID: r.FormValue("jurisdiction_id"),
+		Name:           r.FormValue("name"),
+		Rate:           rate,
+	}); err != nil {
+		writeSalesTaxError(w, r, "failed to save sales tax rate", err)
+		return
+	}
+
+	http.Redirect(w, r, "/sales-tax", http.StatusSeeOther)
+}
+
+// saves the tax code `code` per the `description` form value and the rates
+// checked as `rate_id`, and redirects to the return
+func (h *SalesTaxHandler) PostCode(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.SalesTaxService.SaveCode(r.Context(), accounting.SalesTaxCode{
+		Code:        r.PostFormValue("code"),
+		Description: r.PostFormValue("description"),
+		RateIDs:     r.PostForm["rate_id"],
+	}); err != nil {
+		writeSalesTaxError(w, r, "failed to save sales tax code", err)
+		return
+	}
+
+	http.Redirect(w, r, "/sales-tax", http.StatusSeeOther)
+}
+
+// records a remittance per the `jurisdiction_id`, `period_start`,
+// `period_end`, `paid_on`, `cash_account`, `amount` (default the liability
+// outstanding for the period) and `memo` form values, and redirects to the
+// return for the period
+func (h *SalesTaxHandler) PostRemittance(w http.ResponseWriter, r *http.Request) {
+	periodStart, err := parseFormDate(r, "period_start")
+	if err != nil {
+		http.Error(w, "invalid period start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	periodEnd, err := parseFormDate(r, "period_end")
+	if err != nil {
+		http.Error(w, "invalid period end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paidOn, err := parseFormDate(r, "paid_on")
+	if err != nil {
+		http.Error(w, "invalid payment date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount, err := parseFormAmount(r, "amount")
+	if err != nil {
+		http.Error(w, "invalid amount: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.SalesTaxService.RecordRemittance(r.Context(), services.RecordRemittanceRequest{
+		JurisdictionID:  r.FormValue("jurisdiction_id"),
+		PeriodStart:     periodStart,
+		PeriodEnd:       periodEnd,
+		PaidOn:          paidOn,
+		CashAccountName: r.FormValue("cash_account"),
+		Amount:          amount,
+		Memo:            r.FormValue("memo"),
+	}); err != nil {
+		writeSalesTaxError(w, r, "failed to record sales tax remittance", err)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf(
+		"/sales-tax?from=%s&to=%s", periodStart.Format(time.DateOnly), periodEnd.Format(time.DateOnly),
+	), http.StatusSeeOther)
+}
+
+// writes one row per jurisdiction followed by the return's totals
+func writeSalesTaxReturnCSV(w http.ResponseWriter, report *accounting.SalesTaxReturn) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"Jurisdiction", "Gross Sales", "Exempt Sales", "Taxable Sales", "Tax Collected", "Tax Remitted", "Due"}); err != nil {
+		return err
+	}
+
+	for _, line := range report.Jurisdictions {
+		if err := cw.Write([]string{
+			line.Jurisdiction,
+			"",
+			"",
+			formatAmount(line.TaxableSales),
+			formatAmount(line.TaxCollected),
+			formatAmount(line.TaxRemitted),
+			formatAmount(line.Due),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := cw.Write([]string{
+		"Total",
+		formatAmount(report.GrossSales),
+		formatAmount(report.ExemptSales),
+		formatAmount(report.TaxableSales),
+		formatAmount(report.TaxCollected),
+		formatAmount(report.TaxRemitted),
+		formatAmount(accounting.RoundCents(report.TaxCollected - report.TaxRemitted)),
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeSalesTaxError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsAccountNotFound(err) ||
+		accounting.IsSalesTaxJurisdictionNotFound(err) ||
+		accounting.IsSalesTaxRateNotFound(err) ||
+		accounting.IsSalesTaxCodeNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accounting.IsYearClosed(err) || accounting.IsPeriodClosed(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
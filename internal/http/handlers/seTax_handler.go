@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type SETaxHandler struct {
+	SETaxService *services.SETaxService
+	Views        *view.Views
+}
+
+// renders the self-employment tax estimate as of the `as_of` query parameter
+// (default today), with the year's rate versions, reserve transfers and
+// estimated payments; `format=json` returns the estimate alone
+func (h *SETaxHandler) GetEstimate(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		http.Error(w, "invalid as_of date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// without any rates there is nothing to estimate, but rates may be entered
+	estimate, err := h.SETaxService.Estimate(r.Context(), asOf)
+	if err != nil && !accounting.IsSETaxRatesNotFound(err) {
+		slog.ErrorContext(r.Context(), "failed to estimate self-employment tax", "error", err)
+		http.Error(w, "failed to estimate self-employment tax: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		if estimate == nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(estimate); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode self-employment tax estimate", "error", err)
+		}
+		return
+	}
+
+	versions, err := h.SETaxService.GetRateVersions(r.Context(), asOf.Year())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get self-employment tax rates", "error", err)
+		http.Error(w, "failed to get self-employment tax rates: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	transfers, payments, err := h.SETaxService.GetActivity(r.Context(), asOf.Year())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get self-employment tax activity", "error", err)
+		http.Error(w, "failed to get self-employment tax activity: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"AsOf":         asOf,
+		"TaxYear":      asOf.Year(),
+		"Estimate":     estimate,
+		"RateVersions": versions,
+		"Transfers":    transfers,
+		"Payments":     payments,
+	}
+
+	h.Views.Render(w, r, "seTax", data)
+}
+
+// saves a new version of the rates for the `tax_year` form value, and
+// redirects to the estimate
+func (h *SETaxHandler) PostRates(w http.ResponseWriter, r *http.Request) {
+	taxYear, err := strconv.Atoi(r.FormValue("tax_year"))
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rates := accounting.SETaxRates{TaxYear: taxYear, Note: r.FormValue("note")}
+	for name, field := range map[string]*float64{
+		"net_earnings_factor":           &rates.NetEarningsFactor,
+		"social_security_rate":          &rates.SocialSecurityRate,
+		"social_security_wage_base":     &rates.SocialSecurityWageBase,
+		"medicare_rate":                 &rates.MedicareRate,
+		"additional_medicare_rate":      &rates.AdditionalMedicareRate,
+		"additional_medicare_threshold": &rates.AdditionalMedicareThreshold,
+		"minimum_net_earnings":          &rates.MinimumNetEarnings,
+	} {
+		if *field, err = parseFormAmount(r, name); err != nil {
+			http.Error(w, "invalid "+name+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := h.SETaxService.SaveRates(r.Context(), rates); err != nil {
+		writeSETaxError(w, r, "failed to save self-employment tax rates", err)
+		return
+	}
+
+	http.Redirect(w, r, "/se-tax", http.StatusSeeOther)
+}
+
+// transfers the `amount` form value from the `from_account` into the tax
+// reserve for the `tax_year`, dated `date`, and redirects to the estimate
+func (h *SETaxHandler) PostReserve(w http.ResponseWriter, r *http.Request) {
+	taxYear, err := strconv.Atoi(r.FormValue("tax_year"))
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	date, err := parseFormDate(r, "date")
+	if err != nil {
+		http.Error(w, "invalid date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount, err := parseFormAmount(r, "amount")
+	if err != nil {
+		http.Error(w, "invalid amount: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.SETaxService.PostReserve(r.Context(), services.PostReserveRequest{
+		TaxYear:         taxYear,
+		Date:            date,
+		Amount:          amount,
+		FromAccountName: r.FormValue("from_account"),
+	}); err != nil {
+		writeSETaxError(w, r, "failed to post tax reserve", err)
+		return
+	}
+
+	http.Redirect(w, r, "/se-tax", http.StatusSeeOther)
+}
+
+// records an estimated payment per the `tax_year`, `quarter`, `paid_on`,
+// `amount`, `from_account` and `memo` form values, and redirects to the estimate
+func (h *SETaxHandler) PostPayment(w http.ResponseWriter, r *http.Request) {
+	taxYear, err := strconv.Atoi(r.FormValue("tax_year"))
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	quarter, err := strconv.Atoi(r.FormValue("quarter"))
+	if err != nil {
+		http.Error(w, "invalid quarter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	paidOn, err := parseFormDate(r, "paid_on")
+	if err != nil {
+		http.Error(w, "invalid payment date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	amount, err := parseFormAmount(r, "amount")
+	if err != nil {
+		http.Error(w, "invalid amount: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.SETaxService.RecordPayment(r.Context(), services.RecordEstimatedPaymentRequest{
+		TaxYear:         taxYear,
+		Quarter:         quarter,
+		PaidOn:          paidOn,
+		Amount:          amount,
+		FromAccountName: r.FormValue("from_account"),
+		Memo:            r.FormValue("memo"),
+	}); err != nil {
+		writeSETaxError(w, r, "failed to record estimated payment", err)
+		return
+	}
+
+	http.Redirect(w, r, "/se-tax", http.StatusSeeOther)
+}
+
+func writeSETaxError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsAccountNotFound(err) || accounting.IsSETaxRatesNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accounting.IsYearClosed(err) || accounting.IsPeriodClosed(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
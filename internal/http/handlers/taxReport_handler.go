@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type TaxReportHandler struct {
+	TaxReportService *services.TaxReportService
+	Views            *view.Views
+}
+
+// renders the tax report for the `year` (default the current year) and
+// `form` (default Schedule C) query parameters, in the `format` query
+// parameter: html (default, with the year's mappings for editing), csv or json
+func (h *TaxReportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	taxYear, form, err := parseTaxForm(r.URL.Query().Get)
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.TaxReportService.Report(r.Context(), taxYear, form)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to build tax report", "error", err)
+		http.Error(w, "failed to build tax report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to encode tax report", "error", err)
+		}
+		return
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"tax-%d.csv\"", taxYear))
+		if err := writeTaxReportCSV(w, report); err != nil {
+			slog.ErrorContext(r.Context(), "failed to write tax report", "error", err)
+		}
+		return
+	}
+
+	forms, err := h.TaxReportService.GetForms(r.Context(), taxYear)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get tax forms", "error", err)
+		http.Error(w, "failed to get tax forms: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mappings, err := h.TaxReportService.GetMappings(r.Context(), taxYear, form)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get tax line mappings", "error", err)
+		http.Error(w, "failed to get tax line mappings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	mapped := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		mapped[mapping.AccountName] = mapping.LineCode
+	}
+
+	accounts, err := h.TaxReportService.GetAccounts(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get accounts", "error", err)
+		http.Error(w, "failed to get accounts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]any{
+		"Report":   report,
+		"Forms":    forms,
+		"Accounts": accounts,
+		"Mapped":   mapped,
+	}
+
+	h.Views.Render(w, r, "taxReport", data)
+}
+
+// maps the `account` form value to the `line` of the `form` for the
+// `year`, or unmaps it if `line` is empty, and redirects to the report
+func (h *TaxReportHandler) PostMapping(w http.ResponseWriter, r *http.Request) {
+	taxYear, form, err := parseTaxForm(r.FormValue)
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.TaxReportService.MapAccount(r.Context(), accounting.TaxLineMapping{
+		TaxYear:     taxYear,
+		Form:        form,
+		AccountName: r.FormValue("account"),
+		LineCode:    r.FormValue("line"),
+	}); err != nil {
+		writeTaxError(w, r, "failed to map account", err)
+		return
+	}
+
+	http.Redirect(w, r, taxReportURL(taxYear, form), http.StatusSeeOther)
+}
+
+// saves the line of the `form` for the `year` per the `code`,
+// `description` and `display_order` form values, and redirects to the report
+func (h *TaxReportHandler) PostLine(w http.ResponseWriter, r *http.Request) {
+	taxYear, form, err := parseTaxForm(r.FormValue)
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	order := 0
+	if value := r.FormValue("display_order"); value != "" {
+		if order, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "invalid display order: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.TaxReportService.SaveLine(r.Context(), accounting.TaxLine{
+		TaxYear:      taxYear,
+		Form:         form,
+		Code:         r.FormValue("code"),
+		Description:  r.FormValue("description"),
+		DisplayOrder: order,
+	}); err != nil {
+		writeTaxError(w, r, "failed to save tax line", err)
+		return
+	}
+
+	http.Redirect(w, r, taxReportURL(taxYear, form), http.StatusSeeOther)
+}
+
+// deletes the line `code` of the `form` for the `year`, and redirects to the report
+func (h *TaxReportHandler) PostDeleteLine(w http.ResponseWriter, r *http.Request) {
+	taxYear, form, err := parseTaxForm(r.FormValue)
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.TaxReportService.DeleteLine(r.Context(), taxYear, form, r.FormValue("code")); err != nil {
+		writeTaxError(w, r, "failed to delete tax line", err)
+		return
+	}
+
+	http.Redirect(w, r, taxReportURL(taxYear, form), http.StatusSeeOther)
+}
+
+// copies the lines and mappings of the `from_year` form value to the
+// `year`, and redirects to that year's report
+func (h *TaxReportHandler) PostCopy(w http.ResponseWriter, r *http.Request) {
+	taxYear, form, err := parseTaxForm(r.FormValue)
+	if err != nil {
+		http.Error(w, "invalid tax year: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fromYear, err := strconv.Atoi(r.FormValue("from_year"))
+	if err != nil {
+		http.Error(w, "invalid year to copy from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.TaxReportService.CopyYear(r.Context(), fromYear, taxYear); err != nil {
+		writeTaxError(w, r, "failed to copy tax lines", err)
+		return
+	}
+
+	http.Redirect(w, r, taxReportURL(taxYear, form), http.StatusSeeOther)
+}
+
+// writes one row per account on each line, followed by the line's total,
+// then the unmapped accounts
+func writeTaxReportCSV(w http.ResponseWriter, report *accounting.TaxReport) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"Form", "Line", "Description", "Account", "Amount"}); err != nil {
+		return err
+	}
+
+	for _, line := range report.Lines {
+		for _, account := range line.Accounts {
+			if err := cw.Write([]string{report.Form, line.Line.Code, line.Line.Description, account.AccountName, formatAmount(account.Amount)}); err != nil {
+				return err
+			}
+		}
+		if err := cw.Write([]string{report.Form, line.Line.Code, line.Line.Description, "Total", formatAmount(line.Amount)}); err != nil {
+			return err
+		}
+	}
+
+	for _, account := range report.Unmapped {
+		if err := cw.Write([]string{report.Form, "", "Unmapped", account.AccountName, formatAmount(account.Amount)}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// reads the `year` and `form` values with get, defaulting to the current
+// year and Schedule C
+func parseTaxForm(get func(string) string) (int, string, error) {
+	taxYear := time.Now().Year()
+	if value := get("year"); value != "" {
+		var err error
+		if taxYear, err = strconv.Atoi(value); err != nil {
+			return 0, "", err
+		}
+	}
+
+	form := get("form")
+	if form == "" {
+		form = accounting.ScheduleC
+	}
+
+	return taxYear, form, nil
+}
+
+func taxReportURL(taxYear int, form string) string {
+	return fmt.Sprintf("/tax?year=%d&form=%s", taxYear, url.QueryEscape(form))
+}
+
+func writeTaxError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsAccountNotFound(err) || accounting.IsTaxLineNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type UsersHandler struct {
+	AuthService *services.AuthService
+	Views       *view.Views
+}
+
+// renders the users and their roles, for admins to manage
+func (h *UsersHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.AuthService.GetUsers(r.Context())
+	if err != nil {
+		writeUserError(w, r, "failed to get users", err)
+		return
+	}
+
+	data := map[string]any{"Users": users, "Roles": accounting.Roles}
+	h.Views.Render(w, r, "users", data)
+}
+
+// creates a user per the `username`, `password` and `role` form values, and
+// redirects to the users
+func (h *UsersHandler) PostUser(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.AuthService.CreateUser(
+		r.Context(),
+		r.FormValue("username"),
+		r.FormValue("password"),
+		accounting.Role(r.FormValue("role")),
+	); err != nil {
+		writeUserError(w, r, "failed to create user", err)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// changes the role of the user in the path per the `role` form value, and
+// redirects to the users
+func (h *UsersHandler) PostRole(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.AuthService.SetRole(r.Context(), r.PathValue("id"), accounting.Role(r.FormValue("role"))); err != nil {
+		writeUserError(w, r, "failed to change role", err)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+func writeUserError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsUserNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accounting.IsUserExists(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
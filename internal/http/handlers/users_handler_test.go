@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+func TestUsersHandler(t *testing.T) {
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	auth := &services.AuthService{UserRepo: repos.Users, SessionRepo: repos.Sessions}
+	system := accounting.AsSystem(context.Background())
+	admin, err := auth.CreateUser(system, "root", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create admin with error %v", err)
+	}
+	viewer, err := auth.CreateUser(system, "carol", "correct horse battery", accounting.ViewerRole)
+	if err != nil {
+		t.Fatalf("failed to create viewer with error %v", err)
+	}
+
+	users := &UsersHandler{AuthService: auth, Views: views}
+	periods := &PeriodsHandler{PeriodService: &services.PeriodService{PeriodRepo: repos.Periods}, Views: views}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", users.GetUsers)
+	mux.HandleFunc("POST /users", users.PostUser)
+	mux.HandleFunc("POST /users/{id}/role", users.PostRole)
+	mux.HandleFunc("POST /periods/calendar", periods.PostCalendar)
+
+	as := func(user *accounting.User, method, path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req.WithContext(accounting.WithUser(req.Context(), user)))
+		return rec
+	}
+
+	t.Run("forbids viewers from managing users or closing the books", func(t *testing.T) {
+		if rec := as(viewer, http.MethodGet, "/users", nil); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 listing users, got %d: %s", rec.Code, rec.Body.String())
+		}
+		rec := as(viewer, http.MethodPost, "/periods/calendar", url.Values{"start_month": {"7"}, "pattern": {"Monthly"}})
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("expected 403 changing the calendar, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), "the admin role is required") {
+			t.Fatalf("expected the denial to be explained, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("lets admins add users and change their roles", func(t *testing.T) {
+		rec := as(admin, http.MethodPost, "/users", url.Values{"username": {"bob"}, "password": {"correct horse battery"}, "role": {"bookkeeper"}})
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected a redirect after adding a user, got %d: %s", rec.Code, rec.Body.String())
+		}
+		bob, err := repos.Users.ByUsername(context.Background(), "bob")
+		if err != nil || bob.Role != accounting.BookkeeperRole {
+			t.Fatalf("expected bob to be a bookkeeper, got %+v, %v", bob, err)
+		}
+
+		rec = as(admin, http.MethodPost, "/users/"+viewer.ID+"/role", url.Values{"role": {"bookkeeper"}})
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected a redirect after changing a role, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = as(admin, http.MethodGet, "/users", nil)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<td>bob</td>") || !strings.Contains(rec.Body.String(), `<a href="/users">Users</a>`) {
+			t.Fatalf("expected the users page, got %d:\n%s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("refuses to demote the last admin", func(t *testing.T) {
+		rec := as(admin, http.MethodPost, "/users/"+admin.ID+"/role", url.Values{"role": {"viewer"}})
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422 demoting the only admin, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
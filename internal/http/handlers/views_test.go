@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+	"github.com/hoodnoah/ghoam/internal/services"
+	"github.com/hoodnoah/ghoam/templates"
+)
+
+// the ids of the records the detail pages render
+type viewFixtures struct {
+	assetID, itemID, countID, projectID, entryID, billID, invoiceID string
+}
+
+// routes every page handler over an in-memory book holding one of each record
+// the detail pages need; see signedInAs
+func newViewsTestMux(t *testing.T) (http.Handler, viewFixtures) {
+	t.Helper()
+	ctx := accounting.AsSystem(context.Background())
+
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	views, err := view.ParseFS(templates.FS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	if err := repos.Accounts.Save(ctx, &accounting.Account{
+		Name:            "Equipment",
+		ParentGroupName: "Assets",
+		AccountType:     accounting.Asset,
+		NormalBalance:   accounting.DebitNormal,
+		DisplayAfter:    sql.NullString{},
+	}); err != nil {
+		t.Fatalf("failed to save account with error %v", err)
+	}
+
+	chartService := &services.ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}
+	agingService := &services.AgingService{
+		CustomerRepo:          repos.Customers,
+		InvoiceRepo:           repos.Invoices,
+		VendorRepo:            repos.Vendors,
+		BillRepo:              repos.Bills,
+		AccountRepo:           repos.Accounts,
+		JournalEntryRepo:      repos.JournalEntries,
+		ReceivableAccountName: "Accounts Receivable",
+		PayableAccountName:    "Accounts Payable",
+	}
+	payablesService := &services.PayablesService{
+		VendorRepo:          repos.Vendors,
+		BillRepo:            repos.Bills,
+		AccountRepo:         repos.Accounts,
+		DiscountAccountName: "Purchase Discounts",
+	}
+	receivablesService := &services.ReceivablesService{
+		CustomerRepo: repos.Customers,
+		InvoiceRepo:  repos.Invoices,
+		AccountRepo:  repos.Accounts,
+		SalesTaxRepo: repos.SalesTax,
+	}
+	fixedAssetService := &services.FixedAssetService{FixedAssetRepo: repos.FixedAssets, AccountRepo: repos.Accounts}
+	inventoryService := &services.InventoryService{
+		UnitRepo:             repos.InventoryUnits,
+		StockRepo:            repos.Stock,
+		CountRepo:            repos.Counts,
+		ProjectRepo:          repos.Projects,
+		CustomerRepo:         repos.Customers,
+		AccountRepo:          repos.Accounts,
+		JournalEntryRepo:     repos.JournalEntries,
+		COGSAccountName:      "Cost of Goods Sold",
+		ShrinkageAccountName: "Inventory Shrinkage",
+	}
+	projectService := &services.ProjectService{
+		ProjectRepo:      repos.Projects,
+		CustomerRepo:     repos.Customers,
+		AccountRepo:      repos.Accounts,
+		JournalEntryRepo: repos.JournalEntries,
+	}
+	yearEndService := &services.YearEndService{
+		YearEndRepo:                 repos.YearEnds,
+		AccountRepo:                 repos.Accounts,
+		JournalEntryRepo:            repos.JournalEntries,
+		RetainedEarningsAccountName: "Retained Earnings",
+		IncomeSummaryAccountName:    "Income Summary",
+	}
+	seTaxService := &services.SETaxService{
+		SETaxRepo:          repos.SETax,
+		AccountRepo:        repos.Accounts,
+		JournalEntryRepo:   repos.JournalEntries,
+		YearEndRepo:        repos.YearEnds,
+		ReserveAccountName: "Tax Reserve",
+		DrawAccountName:    "Owner Draws",
+	}
+	taxReportService := &services.TaxReportService{
+		TaxLineRepo:      repos.TaxLines,
+		AccountRepo:      repos.Accounts,
+		JournalEntryRepo: repos.JournalEntries,
+		YearEndRepo:      repos.YearEnds,
+	}
+	salesTaxService := &services.SalesTaxService{
+		SalesTaxRepo:     repos.SalesTax,
+		InvoiceRepo:      repos.Invoices,
+		AccountRepo:      repos.Accounts,
+		JournalEntryRepo: repos.JournalEntries,
+	}
+
+	var fixtures viewFixtures
+
+	asset, err := fixedAssetService.RegisterAsset(ctx, services.RegisterAssetRequest{
+		Name:                               "Truck",
+		Cost:                               36000,
+		SalvageValue:                       6000,
+		InServiceDate:                      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UsefulLife:                         5,
+		Method:                             accounting.StraightLine,
+		AssetAccountName:                   "Equipment",
+		AccumulatedDepreciationAccountName: "Accumulated Depreciation",
+	})
+	if err != nil {
+		t.Fatalf("failed to register asset with error %v", err)
+	}
+	fixtures.assetID = asset.ID
+
+	item, err := inventoryService.CreateStockItem(ctx, services.CreateStockItemRequest{
+		SKU:                  "BOLT",
+		Name:                 "Bolt",
+		Method:               accounting.FIFO,
+		InventoryAccountName: "Inventory",
+	})
+	if err != nil {
+		t.Fatalf("failed to create stock item with error %v", err)
+	}
+	fixtures.itemID = item.ID
+
+	count, err := inventoryService.StartCount(ctx, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), "")
+	if err != nil {
+		t.Fatalf("failed to start count with error %v", err)
+	}
+	fixtures.countID = count.ID
+
+	project, err := projectService.CreateProject(ctx, services.CreateProjectRequest{
+		Name:      "Kitchen",
+		StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("failed to create project with error %v", err)
+	}
+	fixtures.projectID = project.ID
+
+	entry := accounting.JournalEntry{
+		ID:          accounting.NewID(),
+		Timestamp:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Owner contribution",
+		Lines: []accounting.JournalEntryLine{
+			{AccountName: "Equipment", Amount: 500, Side: accounting.Debit},
+			{AccountName: "Retained Earnings", Amount: 500, Side: accounting.Credit},
+		},
+	}
+	if err := repos.JournalEntries.Save(ctx, entry); err != nil {
+		t.Fatalf("failed to save journal entry with error %v", err)
+	}
+	fixtures.entryID = entry.ID
+
+	vendor, err := payablesService.CreateVendor(ctx, "Paper Co.", accounting.DefaultPaymentTerms)
+	if err != nil {
+		t.Fatalf("failed to create vendor with error %v", err)
+	}
+	bill, err := payablesService.EnterBill(ctx, services.EnterBillRequest{
+		VendorID:           vendor.ID,
+		Number:             "PC-100",
+		BillDate:           time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		PayableAccountName: "Accounts Payable",
+		Lines:              []accounting.BillLine{{AccountName: "Equipment", Amount: 250}},
+	})
+	if err != nil {
+		t.Fatalf("failed to enter bill with error %v", err)
+	}
+	fixtures.billID = bill.ID
+
+	if err := repos.Accounts.Save(ctx, &accounting.Account{
+		Name:            "Consulting Revenue",
+		ParentGroupName: "Revenues",
+		AccountType:     accounting.Revenue,
+		NormalBalance:   accounting.CreditNormal,
+		DisplayAfter:    sql.NullString{},
+	}); err != nil {
+		t.Fatalf("failed to save account with error %v", err)
+	}
+	customer, err := receivablesService.CreateCustomer(ctx, "Acme Corp.", accounting.DefaultPaymentTerms)
+	if err != nil {
+		t.Fatalf("failed to create customer with error %v", err)
+	}
+	invoice, err := receivablesService.IssueInvoice(ctx, services.IssueInvoiceRequest{
+		CustomerID:            customer.ID,
+		Number:                "1001",
+		InvoiceDate:           time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		ReceivableAccountName: "Accounts Receivable",
+		Lines:                 []accounting.InvoiceLine{{AccountName: "Consulting Revenue", Amount: 1200}},
+	})
+	if err != nil {
+		t.Fatalf("failed to issue invoice with error %v", err)
+	}
+	fixtures.invoiceID = invoice.ID
+
+	auditService := &services.AuditService{AuditRepo: repos.Audit}
+
+	chart := &ChartOfAccountsHandler{ChartOfAccountsService: chartService, AuditService: auditService, Views: views}
+	journalEntries := &JournalEntriesHandler{
+		JournalEntryService: &services.JournalEntryService{JournalEntryRepo: repos.JournalEntries},
+		AuditService:        auditService,
+		Views:               views,
+	}
+	aging := &AgingHandler{AgingService: agingService, Views: views}
+	receivables := &ReceivablesHandler{ReceivablesService: receivablesService, Views: views}
+	payables := &PayablesHandler{PayablesService: payablesService, Views: views}
+	fixedAssets := &FixedAssetsHandler{FixedAssetService: fixedAssetService, Views: views}
+	inventory := &InventoryHandler{InventoryService: inventoryService, Views: views}
+	projects := &ProjectsHandler{ProjectService: projectService, Views: views}
+	yearEnd := &YearEndHandler{YearEndService: yearEndService, Views: views}
+	periods := &PeriodsHandler{PeriodService: &services.PeriodService{PeriodRepo: repos.Periods}, Views: views}
+	seTax := &SETaxHandler{SETaxService: seTaxService, Views: views}
+	taxReport := &TaxReportHandler{TaxReportService: taxReportService, Views: views}
+	salesTax := &SalesTaxHandler{SalesTaxService: salesTaxService, Views: views}
+	integrity := &IntegrityHandler{IntegrityService: &services.IntegrityService{IntegrityRepo: repos.Integrity}, Views: views}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		views.Render(w, r, "index", nil)
+	})
+	mux.HandleFunc("GET /integrity", integrity.GetCheck)
+	mux.HandleFunc("GET /chart", chart.GetChart)
+	mux.HandleFunc("GET /accounts/{name}", chart.GetAccount)
+	mux.HandleFunc("GET /journal-entries/chain", journalEntries.GetChain)
+	mux.HandleFunc("GET /journal-entries/{id}", journalEntries.GetEntry)
+	mux.HandleFunc("GET /aging/receivables", aging.GetReceivables)
+	mux.HandleFunc("GET /aging/payables", aging.GetPayables)
+	mux.HandleFunc("GET /receivables", receivables.GetReceivables)
+	mux.HandleFunc("GET /invoices/{id}", receivables.GetInvoice)
+	mux.HandleFunc("GET /payables", payables.GetPayables)
+	mux.HandleFunc("GET /bills/{id}", payables.GetBill)
+	mux.HandleFunc("GET /fixed-assets", fixedAssets.GetRegister)
+	mux.HandleFunc("GET /fixed-assets/{id}/schedule", fixedAssets.GetSchedule)
+	mux.HandleFunc("GET /inventory/units", inventory.GetOnHand)
+	mux.HandleFunc("GET /inventory/items/{id}", inventory.GetStockItem)
+	mux.HandleFunc("GET /inventory/counts", inventory.GetCounts)
+	mux.HandleFunc("GET /inventory/counts/{id}", inventory.GetCount)
+	mux.HandleFunc("GET /projects", projects.GetProjects)
+	mux.HandleFunc("GET /projects/margins", projects.GetMargins)
+	mux.HandleFunc("GET /projects/{id}", projects.GetProject)
+	mux.HandleFunc("GET /projects/{id}/margin", projects.GetMargin)
+	mux.HandleFunc("GET /year-end", yearEnd.GetCloses)
+	mux.HandleFunc("GET /periods", periods.GetPeriods)
+	mux.HandleFunc("GET /se-tax", seTax.GetEstimate)
+	mux.HandleFunc("GET /tax", taxReport.GetReport)
+	mux.HandleFunc("GET /sales-tax", salesTax.GetReturn)
+
+	return mux, fixtures
+}
+
+// serves the mux as alice, signed in with the role
+func signedInAs(t *testing.T, mux http.Handler, role accounting.Role) http.Handler {
+	t.Helper()
+	user, err := accounting.NewUser("alice", "correct horse battery", role)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(accounting.WithUser(r.Context(), user)))
+	})
+}
+
+func TestPageHandlers_RenderPageOrFragment(t *testing.T) {
+	mux, fixtures := newViewsTestMux(t)
+	viewer := signedInAs(t, mux, accounting.ViewerRole)
+	admin := signedInAs(t, mux, accounting.AdminRole)
+
+	type page struct {
+		path    string
+		heading string
+	}
+	pages := []page{
+		{"/", "<h1>GHOAM - Business Accounting for Humans</h1>"},
+		{"/chart", "<h1>Chart of Accounts</h1>"},
+		{"/accounts/Equipment", "<h1>Account: Equipment</h1>"},
+		{"/journal-entries/" + fixtures.entryID, "<h1>Journal Entry " + fixtures.entryID + "</h1>"},
+		{"/aging/receivables?as_of=2025-06-30", "<h1>Receivables Aging as of 2025-06-30</h1>"},
+		{"/aging/payables?as_of=2025-06-30", "<h1>Payables Aging as of 2025-06-30</h1>"},
+		{"/receivables", "<h1>Receivables</h1>"},
+		{"/invoices/" + fixtures.invoiceID, "<h1>Invoice 1001 to Acme Corp.</h1>"},
+		{"/payables", "<h1>Payables</h1>"},
+		{"/bills/" + fixtures.billID, "<h1>Bill PC-100 from Paper Co.</h1>"},
+		{"/fixed-assets?as_of=2025-06-30", "<h1>Fixed Asset Register as of 2025-06-30</h1>"},
+		{"/fixed-assets/" + fixtures.assetID + "/schedule", "<h1>Depreciation Schedule: Truck</h1>"},
+		{"/inventory/units?as_of=2025-06-30", "<h1>Inventory on Hand as of 2025-06-30</h1>"},
+		{"/inventory/items/" + fixtures.itemID + "?as_of=2025-06-30", "<h1>BOLT Bolt as of 2025-06-30</h1>"},
+		{"/inventory/counts", "<h1>Inventory Counts</h1>"},
+		{"/inventory/counts/" + fixtures.countID, "<h1>Inventory Count of 2025-06-30"},
+		{"/projects", "<h1>Projects</h1>"},
+		{"/projects/margins?from=2025-01-01&to=2025-06-30", "<h1>Project Margins, 2025-01-01 to 2025-06-30</h1>"},
+		{"/projects/" + fixtures.projectID, "<h1>Project: Kitchen"},
+		{"/projects/" + fixtures.projectID + "/margin", "<h1>Project Margin: Kitchen</h1>"},
+		{"/year-end", "<h1>Year-End Close</h1>"},
+		{"/periods?year=2025", "<h1>Accounting Periods: Fiscal 2025</h1>"},
+		{"/se-tax?as_of=2025-06-30", "<h1>Self-Employment Tax: 2025</h1>"},
+		{"/tax?year=2025", ": 2025</h1>"},
+		{"/sales-tax?from=2025-01-01&to=2025-06-30", "<h1>Sales Tax Return: 2025-01-01 to 2025-06-30</h1>"},
+	}
+
+	// pages only admins may see
+	adminPages := []page{
+		{"/integrity", "<h1>Check Books</h1>"},
+		{"/journal-entries/chain", "<h1>Ledger Verification</h1>"},
+	}
+
+	for _, signedIn := range []struct {
+		mux   http.Handler
+		pages []page
+	}{
+		{viewer, pages},
+		{admin, adminPages},
+	} {
+		mux := signedIn.mux
+		for _, page := range signedIn.pages {
+			t.Run(page.path, func(t *testing.T) {
+				full := httptest.NewRecorder()
+				mux.ServeHTTP(full, httptest.NewRequest(http.MethodGet, page.path, nil))
+
+				if full.Code != http.StatusOK {
+					t.Fatalf("expected 200 for the full page, got %d: %s", full.Code, full.Body.String())
+				}
+				if body := full.Body.String(); !strings.Contains(body, "<!DOCTYPE html>") || !strings.Contains(body, "<nav>") || !strings.Contains(body, "<span>alice</span>") || !strings.Contains(body, page.heading) {
+					t.Fatalf("expected the page within the layout, got:\n%s", body)
+				}
+
+				req := httptest.NewRequest(http.MethodGet, page.path, nil)
+				req.Header.Set("HX-Request", "true")
+				fragment := httptest.NewRecorder()
+				mux.ServeHTTP(fragment, req)
+
+				if fragment.Code != http.StatusOK {
+					t.Fatalf("expected 200 for the fragment, got %d: %s", fragment.Code, fragment.Body.String())
+				}
+				body := fragment.Body.String()
+				if strings.Contains(body, "<html") || strings.Contains(body, "<nav>") {
+					t.Fatalf("expected only the fragment, got:\n%s", body)
+				}
+				if strings.Count(body, page.heading) != 1 {
+					t.Fatalf("expected the fragment to render once, got:\n%s", body)
+				}
+				if !strings.HasPrefix(fragment.Header().Get("Content-Type"), "text/html") {
+					t.Fatalf("expected an html content type, got %q", fragment.Header().Get("Content-Type"))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/view"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+type YearEndHandler struct {
+	YearEndService *services.YearEndService
+	Views          *view.Views
+}
+
+// renders the history of year-end closes
+func (h *YearEndHandler) GetCloses(w http.ResponseWriter, r *http.Request) {
+	closes, err := h.YearEndService.GetCloses(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get year-end closes", "error", err)
+		http.Error(w, "failed to get year-end closes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.Views.Render(w, r, "yearEnd", closes)
+}
+
+// closes the year per the `year_start`, `year_end`, `use_income_summary` and
+// `reason` form values, and redirects to the history of closes
+func (h *YearEndHandler) PostClose(w http.ResponseWriter, r *http.Request) {
+	yearStart, err := parseFormDate(r, "year_start")
+	if err != nil {
+		http.Error(w, "invalid year start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	yearEnd, err := parseFormDate(r, "year_end")
+	if err != nil {
+		http.Error(w, "invalid year end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.YearEndService.CloseYear(r.Context(), services.CloseYearRequest{
+		YearStart:        yearStart,
+		YearEnd:          yearEnd,
+		UseIncomeSummary: r.FormValue("use_income_summary") != "",
+		Reason:           r.FormValue("reason"),
+	}); err != nil {
+		writeYearEndError(w, r, "failed to close year", err)
+		return
+	}
+
+	http.Redirect(w, r, "/year-end", http.StatusSeeOther)
+}
+
+// reopens the close in the path per the `reason` form value, and redirects
+// to the history of closes
+func (h *YearEndHandler) PostReopen(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.YearEndService.ReopenYear(r.Context(), r.PathValue("id"), r.FormValue("reason")); err != nil {
+		writeYearEndError(w, r, "failed to reopen year", err)
+		return
+	}
+
+	http.Redirect(w, r, "/year-end", http.StatusSeeOther)
+}
+
+func writeYearEndError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case accounting.IsPermissionDenied(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case accounting.IsYearCloseNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case accounting.IsYearAlreadyClosed(err) || accounting.IsYearClosed(err) || accounting.IsPeriodClosed(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		http.Error(w, message+": "+err.Error(), http.StatusUnprocessableEntity)
+	}
+}
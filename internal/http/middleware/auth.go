@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/services"
+)
+
+const (
+	// holds a signed-in user's session token
+	SessionCookie = "ghoam_session"
+	// holds the CSRF token of a visitor not yet signed in, e.g. on the login form
+	csrfCookie = "ghoam_csrf"
+
+	// the form field and header by which unsafe requests echo the CSRF token
+	CSRFField  = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+type sessionKey struct{}
+
+type csrfKey struct{}
+
+// Sessions resolves the session cookie, if any, to its user and session,
+// which it marks the request's context with; a cookie whose session has
+// lapsed is cleared
+func Sessions(auth *services.AuthService, secure bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(SessionCookie)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, session, err := auth.Authenticate(r.Context(), cookie.Value)
+			if accounting.IsSessionNotFound(err) {
+				ClearSessionCookie(w, secure)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to authenticate session", "error", err)
+				http.Error(w, "failed to authenticate session", http.StatusInternalServerError)
+				return
+			}
+
+			ctx := accounting.WithUser(r.Context(), user)
+			ctx = context.WithValue(ctx, sessionKey{}, session)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SessionFromContext returns the signed-in session the context carries, if any
+func SessionFromContext(ctx context.Context) (*accounting.Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(*accounting.Session)
+	return session, ok && session != nil
+}
+
+// CSRF rejects unsafe requests which do not echo the CSRF token, in the
+// csrf_token form field or the X-CSRF-Token header, with a 403. The token is
+// the session's once signed in, and otherwise one kept in a cookie of its
+// own; it is carried by the context for forms to include.
+func CSRF(secure bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var token string
+			if session, ok := SessionFromContext(r.Context()); ok {
+				token = session.CSRFToken
+			} else if cookie, err := r.Cookie(csrfCookie); err == nil && cookie.Value != "" {
+				token = cookie.Value
+			} else {
+				if token, err = accounting.NewToken(); err != nil {
+					slog.ErrorContext(r.Context(), "failed to generate CSRF token", "error", err)
+					http.Error(w, "failed to generate CSRF token", http.StatusInternalServerError)
+					return
+				}
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookie,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   secure,
+					SameSite: http.SameSiteStrictMode,
+				})
+			}
+
+			if !safeMethod(r.Method) {
+				submitted := r.Header.Get(CSRFHeader)
+				if submitted == "" {
+					submitted = r.PostFormValue(CSRFField)
+				}
+				if subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+					slog.WarnContext(r.Context(), "rejected request without a valid CSRF token", "method", r.Method, "path", r.URL.Path)
+					http.Error(w, "invalid or missing CSRF token; reload the page and try again", http.StatusForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, token)))
+		})
+	}
+}
+
+// CSRFToken returns the token forms submitted in the context must echo
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey{}).(string)
+	return token
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// RequireLogin sends requests without a signed-in user to the login page,
+// except for those to the login page itself and paths under the public
+// prefixes. Page views are redirected back once signed in; HTMX swaps
+// redirect the whole page; anything else is refused with a 401.
+func RequireLogin(loginPath string, publicPrefixes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := accounting.UserFromContext(r.Context()); ok || r.URL.Path == loginPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, prefix := range publicPrefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			switch {
+			case r.Header.Get("HX-Request") == "true":
+				w.Header().Set("HX-Redirect", loginPath)
+				http.Error(w, "sign in required", http.StatusUnauthorized)
+			case r.Method == http.MethodGet || r.Method == http.MethodHead:
+				http.Redirect(w, r, loginPath+"?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			default:
+				http.Error(w, "sign in required", http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+// SetSessionCookie hands the session's token to the browser until it expires
+func SetSessionCookie(w http.ResponseWriter, token string, expires time.Time, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearSessionCookie removes the session cookie from the browser
+func ClearSessionCookie(w http.ResponseWriter, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
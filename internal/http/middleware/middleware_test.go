@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/logging"
+)
+
+func TestChain(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logging.New(&logs, slog.LevelInfo)
+
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/panic":
+			panic("boom")
+		case "/redirect":
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+		default:
+			logger.InfoContext(r.Context(), "handled")
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, strings.Repeat("ledger ", 100))
+		}
+	}), RequestID, AccessLog(logger), Recover(logger), Gzip)
+
+	t.Run("tags logs with the request ID and compresses for clients which accept it", func(t *testing.T) {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		req.Header.Set(RequestIDHeader, "from-proxy-1")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Header().Get(RequestIDHeader) != "from-proxy-1" {
+			t.Fatalf("expected the proxy's request ID to be echoed, got %q", w.Header().Get(RequestIDHeader))
+		}
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected a gzip response, got headers %v", w.Header())
+		}
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("failed to read gzip body with error %v", err)
+		}
+		body, _ := io.ReadAll(gz)
+		if len(body) != 700 {
+			t.Fatalf("expected 700 bytes uncompressed, got %d", len(body))
+		}
+
+		if got := strings.Count(logs.String(), "request_id=from-proxy-1"); got != 2 {
+			t.Fatalf("expected the handler's log and the access log to carry the request ID, got:\n%s", logs.String())
+		}
+		if !strings.Contains(logs.String(), "status=200") {
+			t.Fatalf("expected the access log to record the status, got:\n%s", logs.String())
+		}
+	})
+
+	t.Run("generates a request ID and leaves uncompressed clients alone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "not a valid id!")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if id := w.Header().Get(RequestIDHeader); id == "" || id == "not a valid id!" {
+			t.Fatalf("expected a generated request ID, got %q", id)
+		}
+		if w.Header().Get("Content-Encoding") != "" || w.Body.Len() != 700 {
+			t.Fatalf("expected a plain 700 byte body, got %d bytes with headers %v", w.Body.Len(), w.Header())
+		}
+	})
+
+	t.Run("recovers a panicking handler as a 500", func(t *testing.T) {
+		logs.Reset()
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected a 500, got %d", w.Code)
+		}
+		if !strings.Contains(logs.String(), "handler panicked") || !strings.Contains(logs.String(), "status=500") {
+			t.Fatalf("expected the panic and its 500 to be logged, got:\n%s", logs.String())
+		}
+	})
+
+	t.Run("passes redirects through", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/redirect", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+			t.Fatalf("expected a redirect to /, got %d %v", w.Code, w.Header())
+		}
+	})
+}
@@ -0,0 +1,216 @@
+// Package view renders the application's pages, either whole within the
+// layout or, for HTMX requests, as the bare fragment to be swapped in.
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"text/template/parse"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/http/middleware"
+)
+
+const (
+	// the template the layout file defines, wrapping every full page
+	layoutTemplate = "layout"
+	// the template the layout calls for a page's body
+	contentTemplate = "content"
+)
+
+// Views holds one template set per view, so that the helper templates one
+// page defines cannot collide with, or silently replace, another page's.
+type Views struct {
+	sets map[string]*template.Template
+}
+
+// the functions every template may call, bound to the request being rendered
+var requestFuncs = template.FuncMap{
+	// the CSRF token to be echoed, e.g. in an hx-headers attribute
+	"csrfToken": func() string { return "" },
+	// a hidden input echoing the CSRF token, for every form which posts
+	"csrfField": func() template.HTML { return "" },
+	// the signed-in user, or nil
+	"currentUser": func() *accounting.User { return nil },
+}
+
+func bindRequestFuncs(r *http.Request) template.FuncMap {
+	token := middleware.CSRFToken(r.Context())
+	user, _ := accounting.UserFromContext(r.Context())
+
+	return template.FuncMap{
+		"csrfToken": func() string { return token },
+		"csrfField": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="` + middleware.CSRFField + `" value="` + template.HTMLEscapeString(token) + `">`)
+		},
+		"currentUser": func() *accounting.User { return user },
+	}
+}
+
+// ParseFS parses the layout file and, separately against it, each page file
+// matching the patterns. Every template a page file defines which no other
+// template in that file calls becomes a view of that name; the rest are the
+// page's helpers, private to its set.
+func ParseFS(fsys fs.FS, layout string, patterns ...string) (*Views, error) {
+	base, err := template.New(path.Base(layout)).Funcs(requestFuncs).ParseFS(fsys, layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse layout %s: %w", layout, err)
+	}
+	if base.Lookup(layoutTemplate) == nil {
+		return nil, fmt.Errorf("layout %s does not define %q", layout, layoutTemplate)
+	}
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if match != layout && !slices.Contains(files, match) {
+				files = append(files, match)
+			}
+		}
+	}
+
+	views := &Views{sets: make(map[string]*template.Template)}
+	for _, file := range files {
+		if err := views.parsePage(fsys, base, file); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
+
+// parses the page file into a set of its own for each view it defines,
+// pointing the layout's content at that view
+func (v *Views) parsePage(fsys fs.FS, base *template.Template, file string) error {
+	page, err := template.New(path.Base(file)).Funcs(requestFuncs).ParseFS(fsys, file)
+	if err != nil {
+		return fmt.Errorf("failed to parse page %s: %w", file, err)
+	}
+
+	helpers := make(map[string]bool)
+	for _, defined := range page.Templates() {
+		if defined.Tree != nil {
+			calledFrom(defined.Name(), defined.Tree.Root, helpers)
+		}
+	}
+
+	for _, defined := range page.Templates() {
+		name := defined.Name()
+		if name == path.Base(file) || defined.Tree == nil || helpers[name] {
+			continue // the file itself, outside any define, or a helper
+		}
+		if _, exists := v.sets[name]; exists {
+			return fmt.Errorf("view %q in %s is already defined by another page", name, file)
+		}
+		if base.Lookup(name) != nil {
+			return fmt.Errorf("view %q in %s collides with a layout template", name, file)
+		}
+
+		set, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if set, err = set.ParseFS(fsys, file); err != nil {
+			return fmt.Errorf("failed to parse page %s: %w", file, err)
+		}
+		if _, err = set.New(contentTemplate).Parse(fmt.Sprintf("{{ template %q . }}", name)); err != nil {
+			return err
+		}
+
+		v.sets[name] = set
+	}
+
+	return nil
+}
+
+// records the templates the node calls, other than the caller itself
+func calledFrom(caller string, node parse.Node, called map[string]bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			calledFrom(caller, child, called)
+		}
+	case *parse.TemplateNode:
+		if node.Name != caller {
+			called[node.Name] = true
+		}
+	case *parse.IfNode:
+		calledFrom(caller, node.List, called)
+		calledFrom(caller, node.ElseList, called)
+	case *parse.RangeNode:
+		calledFrom(caller, node.List, called)
+		calledFrom(caller, node.ElseList, called)
+	case *parse.WithNode:
+		calledFrom(caller, node.List, called)
+		calledFrom(caller, node.ElseList, called)
+	}
+}
+
+// IsFragment reports whether the request is an HTMX swap, wanting only the
+// view's markup rather than the whole page. Boosted links and history
+// restores are HTMX requests too, but replace the whole body.
+func IsFragment(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true" &&
+		r.Header.Get("HX-Boosted") != "true" &&
+		r.Header.Get("HX-History-Restore-Request") != "true"
+}
+
+// Render writes the named view with the data: as a fragment for HTMX
+// requests, and within the layout otherwise. The markup is rendered in full
+// before anything is written, so a template error yields a clean 500.
+func (v *Views) Render(w http.ResponseWriter, r *http.Request, name string, data any) {
+	v.RenderStatus(w, r, http.StatusOK, name, data)
+}
+
+// RenderStatus renders the view as Render does, with the given status
+func (v *Views) RenderStatus(w http.ResponseWriter, r *http.Request, status int, name string, data any) {
+	parsed, ok := v.sets[name]
+	if !ok {
+		slog.ErrorContext(r.Context(), "unknown view", "view", name)
+		http.Error(w, "render error: unknown view "+name, http.StatusInternalServerError)
+		return
+	}
+
+	// the parsed sets are never executed themselves, so that each request's
+	// copy can be bound to its user and CSRF token
+	set, err := parsed.Clone()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "template error", "view", name, "error", err)
+		http.Error(w, "render error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	set.Funcs(bindRequestFuncs(r))
+
+	entry := layoutTemplate
+	if IsFragment(r) {
+		entry = name
+	}
+
+	var markup bytes.Buffer
+	if err := set.ExecuteTemplate(&markup, entry, data); err != nil {
+		slog.ErrorContext(r.Context(), "template error", "view", name, "error", err)
+		http.Error(w, "render error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// fragments and full pages of the same URL must be cached apart
+	w.Header().Add("Vary", "HX-Request, HX-Boosted, HX-History-Restore-Request")
+	w.WriteHeader(status)
+	if _, err := markup.WriteTo(w); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write view", "view", name, "error", err)
+	}
+}
@@ -0,0 +1,108 @@
+package view
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testFS = fstest.MapFS{
+	"layout.gohtml": {Data: []byte(`{{ define "layout" }}<html><nav>menu</nav><main>{{ block "content" . }}{{ end }}</main></html>{{ end }}`)},
+	"tree.gohtml":   {Data: []byte(`{{ define "tree" }}<ul>{{ template "node" . }}</ul>{{ end }}{{ define "node" }}<li>tree {{ . }}</li>{{ end }}`)},
+	"list.gohtml":   {Data: []byte(`{{ define "list" }}<ol>{{ template "node" . }}</ol>{{ end }}{{ define "node" }}<li>list {{ . }}</li>{{ end }}`)},
+}
+
+func render(t *testing.T, views *Views, name string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	views.Render(w, req, name, "x")
+
+	return w
+}
+
+func TestViews_Render(t *testing.T) {
+	views, err := ParseFS(testFS, "layout.gohtml", "*.gohtml")
+	if err != nil {
+		t.Fatalf("failed to parse views with error %v", err)
+	}
+
+	t.Run("renders a full page within the layout", func(t *testing.T) {
+		w := render(t, views, "tree", nil)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		if got, want := w.Body.String(), "<html><nav>menu</nav><main><ul><li>tree x</li></ul></main></html>"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+			t.Fatalf("expected an html content type, got %q", w.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("renders only the fragment for an HTMX request", func(t *testing.T) {
+		w := render(t, views, "list", map[string]string{"HX-Request": "true"})
+
+		if got, want := w.Body.String(), "<ol><li>list x</li></ol>"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("keeps each page's helper templates to itself", func(t *testing.T) {
+		tree := render(t, views, "tree", map[string]string{"HX-Request": "true"}).Body.String()
+		list := render(t, views, "list", map[string]string{"HX-Request": "true"}).Body.String()
+
+		if tree != "<ul><li>tree x</li></ul>" || list != "<ol><li>list x</li></ol>" {
+			t.Fatalf("expected each page to use its own node, got %q and %q", tree, list)
+		}
+	})
+
+	t.Run("renders full pages for boosted and history restore requests", func(t *testing.T) {
+		for _, header := range []string{"HX-Boosted", "HX-History-Restore-Request"} {
+			w := render(t, views, "tree", map[string]string{"HX-Request": "true", header: "true"})
+			if !strings.Contains(w.Body.String(), "<nav>menu</nav>") {
+				t.Fatalf("expected a full page with %s, got %q", header, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("fails an unknown view with a 500", func(t *testing.T) {
+		w := render(t, views, "missing", nil)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", w.Code)
+		}
+	})
+}
+
+func TestParseFS(t *testing.T) {
+	t.Run("rejects a view defined by two pages", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"layout.gohtml": testFS["layout.gohtml"],
+			"a.gohtml":      {Data: []byte(`{{ define "page" }}a{{ end }}`)},
+			"b.gohtml":      {Data: []byte(`{{ define "page" }}b{{ end }}`)},
+		}
+
+		if _, err := ParseFS(fsys, "layout.gohtml", "*.gohtml"); err == nil {
+			t.Fatal("expected an error for the duplicate view")
+		}
+	})
+
+	t.Run("rejects a page which redefines the layout's content", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"layout.gohtml": testFS["layout.gohtml"],
+			"a.gohtml":      {Data: []byte(`{{ define "content" }}a{{ end }}`)},
+		}
+
+		if _, err := ParseFS(fsys, "layout.gohtml", "*.gohtml"); err == nil {
+			t.Fatal("expected an error for the collision with the layout")
+		}
+	})
+}
@@ -0,0 +1,249 @@
+// Package pdf writes simple, single-column PDF documents using only the
+// standard library: text in the standard base-14 fonts, rules, and JPEG images.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// US Letter, in points
+const (
+	PageWidth  = 612.0
+	PageHeight = 792.0
+)
+
+// enumeration of the fonts available to a document; the standard
+// base-14 fonts need not be embedded
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// resource names, indexed by Font
+var fontResources = []string{"F1", "F2", "F3"}
+
+// representation of a PDF document under construction
+type Document struct {
+	pages  []*bytes.Buffer
+	images []*JPEG
+}
+
+// constructor for an empty Document
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page; subsequent drawing goes to it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// the number of pages in the document
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Text draws s with its baseline starting at (x, y), measured from the bottom-left corner
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		fontResources[font], num(size), num(x), num(y), escape(s))
+}
+
+// Line strokes a line from (x1, y1) to (x2, y2)
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Image draws img scaled into the w×h box whose bottom-left corner is (x, y)
+func (d *Document) Image(img *JPEG, x, y, w, h float64) {
+	index := -1
+	for i, existing := range d.images {
+		if existing == img {
+			index = i
+		}
+	}
+	if index < 0 {
+		index = len(d.images)
+		d.images = append(d.images, img)
+	}
+
+	fmt.Fprintf(d.current(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(w), num(h), num(x), num(y), index+1)
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo serializes the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// object numbers: catalog, page tree, fonts, images, then a (page, content) pair per page
+	const catalogObj, pagesObj, firstFontObj = 1, 2, 3
+	firstImageObj := firstFontObj + len(fontNames)
+	firstPageObj := firstImageObj + len(d.images)
+
+	pw := &objectWriter{}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	pw.begin(catalogObj)
+	pw.printf("<< /Type /Catalog /Pages %d 0 R >>\n", pagesObj)
+	pw.end()
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+	pw.begin(pagesObj)
+	pw.printf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>\n",
+		strings.Join(kids, " "), len(d.pages), num(PageWidth), num(PageHeight))
+	pw.end()
+
+	for i, name := range fontNames {
+		pw.begin(firstFontObj + i)
+		pw.printf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", name)
+		pw.end()
+	}
+
+	for i, img := range d.images {
+		pw.begin(firstImageObj + i)
+		pw.printf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n",
+			img.Width, img.Height, img.colorSpace, len(img.data))
+		pw.write(img.data)
+		pw.printf("\nendstream\n")
+		pw.end()
+	}
+
+	var resources strings.Builder
+	resources.WriteString("<< /Font <<")
+	for i := range fontNames {
+		fmt.Fprintf(&resources, " /%s %d 0 R", fontResources[i], firstFontObj+i)
+	}
+	resources.WriteString(" >>")
+	if len(d.images) > 0 {
+		resources.WriteString(" /XObject <<")
+		for i := range d.images {
+			fmt.Fprintf(&resources, " /Im%d %d 0 R", i+1, firstImageObj+i)
+		}
+		resources.WriteString(" >>")
+	}
+	resources.WriteString(" >>")
+
+	for i, content := range d.pages {
+		pageObj := firstPageObj + 2*i
+
+		pw.begin(pageObj)
+		pw.printf("<< /Type /Page /Parent %d 0 R /Resources %s /Contents %d 0 R >>\n", pagesObj, resources.String(), pageObj+1)
+		pw.end()
+
+		pw.begin(pageObj + 1)
+		pw.printf("<< /Length %d >>\nstream\n", content.Len())
+		pw.write(content.Bytes())
+		pw.printf("endstream\n")
+		pw.end()
+	}
+
+	pw.finish(catalogObj)
+
+	n, err := w.Write(pw.buf.Bytes())
+	return int64(n), err
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// accumulates numbered objects, recording their offsets for the cross-reference table
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (pw *objectWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&pw.buf, format, args...)
+}
+
+func (pw *objectWriter) write(b []byte) {
+	pw.buf.Write(b)
+}
+
+func (pw *objectWriter) begin(obj int) {
+	if pw.offsets == nil {
+		pw.offsets = make(map[int]int)
+	}
+	pw.offsets[obj] = pw.buf.Len()
+	pw.printf("%d 0 obj\n", obj)
+}
+
+func (pw *objectWriter) end() {
+	pw.printf("endobj\n")
+}
+
+// writes the cross-reference table and trailer
+func (pw *objectWriter) finish(rootObj int) {
+	xref := pw.buf.Len()
+	count := len(pw.offsets) + 1
+
+	pw.printf("xref\n0 %d\n", count)
+	pw.printf("0000000000 65535 f \n")
+	for obj := 1; obj < count; obj++ {
+		pw.printf("%010d 00000 n \n", pw.offsets[obj])
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", count, rootObj, xref)
+}
+
+// formats a number compactly, without exponents
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// the WinAnsi code points of common punctuation outside Latin-1
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// escapes s as the body of a PDF literal string in WinAnsiEncoding
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20:
+			// control characters are not drawn
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
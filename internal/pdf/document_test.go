@@ -0,0 +1,79 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	t.Run("writes a cross-reference table matching its objects", func(t *testing.T) {
+		doc := New()
+		doc.AddPage()
+		doc.Text(72, 720, Helvetica, 12, "Hello (world) \\ – café")
+		doc.AddPage()
+		doc.Line(72, 700, 540, 700, 1)
+
+		out := doc.Bytes()
+		if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+			t.Fatalf("expected a PDF header and trailer")
+		}
+
+		// every xref offset must point at the start of its object
+		xrefAt := bytes.LastIndex(out, []byte("\nxref\n")) + 1
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xrefAt:], -1)
+		if len(entries) != 2+len(fontNames)+2*2 {
+			t.Fatalf("unexpected number of xref entries: %d", len(entries))
+		}
+		for i, entry := range entries {
+			offset, _ := strconv.Atoi(string(entry[1]))
+			want := strconv.Itoa(i+1) + " 0 obj"
+			if !bytes.HasPrefix(out[offset:], []byte(want)) {
+				t.Fatalf("xref entry %d points at %q, expected %q", i+1, out[offset:offset+10], want)
+			}
+		}
+
+		if !bytes.Contains(out, []byte(`(Hello \(world\) \\ \226 caf\351) Tj`)) {
+			t.Fatalf("expected escaped WinAnsi text in the content stream")
+		}
+	})
+
+	t.Run("embeds JPEG images", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+			t.Fatalf("failed to encode test image with error %v", err)
+		}
+
+		img, err := LoadJPEG(buf.Bytes())
+		if err != nil {
+			t.Fatalf("failed to load JPEG with error %v", err)
+		}
+		if w, h := img.Fit(100, 100); w != 100 || h != 50 {
+			t.Fatalf("expected the image to fit at 100x50, got %vx%v", w, h)
+		}
+
+		out := Layout("# Invoice", img).Bytes()
+		if !bytes.Contains(out, []byte("/Filter /DCTDecode")) || !bytes.Contains(out, []byte("/Im1 Do")) {
+			t.Fatalf("expected the image to be embedded and drawn")
+		}
+	})
+}
+
+func TestLayout(t *testing.T) {
+	t.Run("paginates long markup", func(t *testing.T) {
+		markup := strings.Repeat("line\n", 100)
+		if pages := Layout(markup, nil).PageCount(); pages != 2 {
+			t.Fatalf("expected 2 pages, got %d", pages)
+		}
+	})
+
+	t.Run("breaks pages explicitly", func(t *testing.T) {
+		if pages := Layout("# One\n@page\n# Two", nil).PageCount(); pages != 2 {
+			t.Fatalf("expected 2 pages, got %d", pages)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+)
+
+// a JPEG image, embedded as-is in the documents which draw it
+type JPEG struct {
+	Width, Height int
+	colorSpace    string
+	data          []byte
+}
+
+// LoadJPEG reads the dimensions and color space of a JPEG image
+func LoadJPEG(data []byte) (*JPEG, error) {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JPEG image: %w", err)
+	}
+
+	img := JPEG{Width: config.Width, Height: config.Height, data: data}
+	switch config.ColorModel {
+	case color.GrayModel:
+		img.colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		img.colorSpace = "DeviceCMYK"
+	default:
+		img.colorSpace = "DeviceRGB"
+	}
+
+	return &img, nil
+}
+
+// Fit scales the image to fit within a w×h box, preserving its aspect ratio
+func (img *JPEG) Fit(w, h float64) (float64, float64) {
+	scale := min(w/float64(img.Width), h/float64(img.Height))
+	return float64(img.Width) * scale, float64(img.Height) * scale
+}
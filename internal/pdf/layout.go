@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// page geometry used by Layout
+const (
+	margin      = 54.0
+	logoWidth   = 144.0
+	logoHeight  = 72.0
+	bodySize    = 10.0
+	bodyLeading = 12.0
+)
+
+// Layout renders line-oriented markup, such as the output of a document
+// template, into a paginated document. Each line of the markup is one of:
+//
+//	# Heading          a large bold heading
+//	## Subheading      a smaller bold heading
+//	---                a horizontal rule
+//	@page              a page break
+//	anything else      monospaced body text; columns may be aligned with spaces
+//
+// If logo is non-nil, it is drawn in the top-right corner of the first page.
+func Layout(markup string, logo *JPEG) *Document {
+	doc := New()
+	doc.AddPage()
+
+	y := PageHeight - margin
+	if logo != nil {
+		w, h := logo.Fit(logoWidth, logoHeight)
+		doc.Image(logo, PageWidth-margin-w, PageHeight-margin-h, w, h)
+	}
+
+	// moves the cursor down by `height`, starting a new page when it would run off the bottom
+	advance := func(height float64) {
+		if y-height < margin {
+			doc.AddPage()
+			y = PageHeight - margin
+		}
+		y -= height
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markup, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "@page":
+			doc.AddPage()
+			y = PageHeight - margin
+		case strings.HasPrefix(trimmed, "## "):
+			advance(18)
+			doc.Text(margin, y, HelveticaBold, 12, strings.TrimPrefix(trimmed, "## "))
+		case strings.HasPrefix(trimmed, "# "):
+			advance(24)
+			doc.Text(margin, y, HelveticaBold, 18, strings.TrimPrefix(trimmed, "# "))
+		case trimmed == "---":
+			advance(bodyLeading / 2)
+			doc.Line(margin, y, PageWidth-margin, y, 0.5)
+			advance(bodyLeading / 2)
+		case trimmed == "":
+			advance(bodyLeading)
+		default:
+			advance(bodyLeading)
+			doc.Text(margin, y, Courier, bodySize, strings.TrimRight(line, " \t"))
+		}
+	}
+
+	return doc
+}
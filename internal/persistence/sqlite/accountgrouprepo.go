@@ -23,6 +23,10 @@ type validateFn func(context.Context, *accounting.AccountGroup) error
 //
 // Returns ErrGroupNotFound if the account does not exist.
 func (r *accountGroupRepo) GetByName(ctx context.Context, name string) (accounting.AccountGroup, error) {
+	return groupByName(ctx, r.db, name)
+}
+
+func groupByName(ctx context.Context, q rowQuerier, name string) (accounting.AccountGroup, error) {
 	const query = `
 		SELECT name, parent_name, display_after, is_immutable
 	  FROM account_groups
@@ -30,7 +34,7 @@ func (r *accountGroupRepo) GetByName(ctx context.Context, name string) (accounti
 	`
 
 	var group accounting.AccountGroup
-	err := r.db.QueryRowContext(
+	err := q.QueryRowContext(
 		ctx,
 		query,
 		name,
@@ -52,7 +56,7 @@ func (r *accountGroupRepo) GetByName(ctx context.Context, name string) (accounti
 }
 
 // Upsert inserts or updates an AccountGroup in the database
-// More or less an upsert
+// More or less an upsert, audited as a create, update or reorder
 func (r *accountGroupRepo) Upsert(ctx context.Context, group *accounting.AccountGroup) error {
 	const query = `
 		INSERT INTO account_groups
@@ -65,28 +69,47 @@ func (r *accountGroupRepo) Upsert(ctx context.Context, group *accounting.Account
 			is_immutable = excluded.is_immutable;
 	`
 
-	// check if the group exists already
-	extantGroup, err := r.GetByName(ctx, group.Name)
-	if err != nil && !accounting.IsGroupNotFound(err) {
-		return err
-	}
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// check if the group exists already
+		extantGroup, err := groupByName(ctx, tx, group.Name)
+		if err != nil && !accounting.IsGroupNotFound(err) {
+			return err
+		}
+		exists := err == nil
 
-	// if the group is immutable, it cannot be updated.
-	if extantGroup.IsImmutable {
-		return &accounting.ErrGroupImmutable{Name: group.Name}
-	}
+		// if the group is immutable, it cannot be updated.
+		if extantGroup.IsImmutable {
+			return &accounting.ErrGroupImmutable{Name: group.Name}
+		}
+		if exists && extantGroup == *group {
+			return nil
+		}
 
-	// insert/update if the record either doesn't exist, or is mutable
-	_, err = r.db.ExecContext(
-		ctx,
-		query,
-		group.Name,
-		group.ParentName,
-		group.DisplayAfter,
-		group.IsImmutable,
-	)
+		// insert/update if the record either doesn't exist, or is mutable
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			group.Name,
+			group.ParentName,
+			group.DisplayAfter,
+			group.IsImmutable,
+		); err != nil {
+			return err
+		}
+
+		if !exists {
+			return insertAuditRecord(ctx, tx, accounting.AuditCreate, accounting.AccountGroupEntity, group.Name, nil, group)
+		}
 
-	return err
+		// a reorder, if moving the group is all that changed
+		op := accounting.AuditUpdate
+		moved := extantGroup
+		moved.DisplayAfter = group.DisplayAfter
+		if moved == *group {
+			op = accounting.AuditReorder
+		}
+		return insertAuditRecord(ctx, tx, op, accounting.AccountGroupEntity, group.Name, extantGroup, group)
+	})
 }
 
 // Gets all account groups
@@ -100,6 +123,7 @@ func (r *accountGroupRepo) GetAll(ctx context.Context) ([]*accounting.AccountGro
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	var groups []*accounting.AccountGroup
 
@@ -141,14 +165,20 @@ func (r *accountGroupRepo) Insert(ctx context.Context, group *accounting.Account
 	}
 
 	// we know the error is ErrAccountNotFound - we can insert
-	_, err = r.db.ExecContext(ctx, query,
-		group.Name,
-		group.ParentName,
-		group.DisplayAfter,
-		false,
-	)
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, query,
+			group.Name,
+			group.ParentName,
+			group.DisplayAfter,
+			false,
+		); err != nil {
+			return err
+		}
 
-	return err
+		inserted := *group
+		inserted.IsImmutable = false
+		return insertAuditRecord(ctx, tx, accounting.AuditCreate, accounting.AccountGroupEntity, group.Name, nil, inserted)
+	})
 }
 
 // utility method for determining if a name exists
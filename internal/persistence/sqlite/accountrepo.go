@@ -17,7 +17,7 @@ type accountRepo struct {
 }
 
 // Save inserts or updates an account in the database.
-// More or less an upsert.
+// More or less an upsert, audited as a create, update or reorder.
 func (r *accountRepo) Save(ctx context.Context, account *accounting.Account) error {
 	const query = `
 		INSERT INTO accounts
@@ -31,17 +31,41 @@ func (r *accountRepo) Save(ctx context.Context, account *accounting.Account) err
 			normal_balance = excluded.normal_balance;
 	`
 
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		account.Name,
-		account.ParentGroupName,
-		account.AccountType,
-		account.DisplayAfter,
-		account.NormalBalance,
-	)
-
-	return err
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		extant, err := accountByName(ctx, tx, account.Name)
+		if err != nil && !accounting.IsAccountNotFound(err) {
+			return err
+		}
+		exists := err == nil
+		if exists && extant == *account {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			account.Name,
+			account.ParentGroupName,
+			account.AccountType,
+			account.DisplayAfter,
+			account.NormalBalance,
+		); err != nil {
+			return err
+		}
+
+		if !exists {
+			return insertAuditRecord(ctx, tx, accounting.AuditCreate, accounting.AccountEntity, account.Name, nil, account)
+		}
+
+		// a reorder, if moving the account is all that changed
+		op := accounting.AuditUpdate
+		moved := extant
+		moved.DisplayAfter = account.DisplayAfter
+		if moved == *account {
+			op = accounting.AuditReorder
+		}
+		return insertAuditRecord(ctx, tx, op, accounting.AccountEntity, account.Name, extant, account)
+	})
 }
 
 // Retrieves all accounts
@@ -87,6 +111,15 @@ func (r *accountRepo) GetAll(ctx context.Context) ([]*accounting.Account, error)
 //
 // Returns ErrAccountNotFound if the account does not exist.
 func (r *accountRepo) ByName(ctx context.Context, name string) (accounting.Account, error) {
+	return accountByName(ctx, r.db, name)
+}
+
+// utility type satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func accountByName(ctx context.Context, q rowQuerier, name string) (accounting.Account, error) {
 	const query = `
 		SELECT name, parent_group_name, account_type, display_after, normal_balance
 		FROM accounts
@@ -94,7 +127,7 @@ func (r *accountRepo) ByName(ctx context.Context, name string) (accounting.Accou
 	`
 
 	var account accounting.Account
-	err := r.db.QueryRowContext(ctx, query, name).Scan(&account.Name, &account.ParentGroupName, &account.AccountType, &account.DisplayAfter, &account.NormalBalance)
+	err := q.QueryRowContext(ctx, query, name).Scan(&account.Name, &account.ParentGroupName, &account.AccountType, &account.DisplayAfter, &account.NormalBalance)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return accounting.Account{}, &accounting.ErrAccountNotFound{Name: name}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type attachmentRepo struct {
+	db *sql.DB
+}
+
+// Save inserts an attachment
+func (r *attachmentRepo) Save(ctx context.Context, attachment *accounting.Attachment) error {
+	const query = `
+		INSERT INTO attachments
+			(id, entity_type, entity_id, file_name, content_type, data, created_at)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?);
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		attachment.ID,
+		attachment.EntityType,
+		attachment.EntityID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Data,
+		formatTimestamp(attachment.CreatedAt),
+	)
+
+	return err
+}
+
+// Retrieves an attachment, with its data, by ID
+//
+// Returns ErrAttachmentNotFound if the attachment does not exist.
+func (r *attachmentRepo) ByID(ctx context.Context, id string) (accounting.Attachment, error) {
+	const query = `
+		SELECT id, entity_type, entity_id, file_name, content_type, created_at, data
+		FROM attachments
+		WHERE id = ?;
+	`
+
+	var (
+		attachment accounting.Attachment
+		createdAt  string
+	)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&attachment.ID,
+		&attachment.EntityType,
+		&attachment.EntityID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&createdAt,
+		&attachment.Data,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Attachment{}, &accounting.ErrAttachmentNotFound{ID: id}
+		}
+		return accounting.Attachment{}, err
+	}
+
+	if attachment.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return accounting.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// Lists the attachments of a record, newest first, without their data
+func (r *attachmentRepo) ListFor(ctx context.Context, entityType accounting.EntityType, entityID string) ([]accounting.Attachment, error) {
+	const query = `
+		SELECT id, entity_type, entity_id, file_name, content_type, created_at
+		FROM attachments
+		WHERE entity_type = ? AND entity_id = ?
+		ORDER BY id DESC;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []accounting.Attachment
+	for rows.Next() {
+		var (
+			attachment accounting.Attachment
+			createdAt  string
+		)
+		if err := rows.Scan(
+			&attachment.ID,
+			&attachment.EntityType,
+			&attachment.EntityID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&createdAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if attachment.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	// std
+	"bytes"
+	"context"
+	"testing"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestAttachmentRepo(t *testing.T) {
+	t.Run("saves and lists attachments for a record", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		attachment, err := accounting.NewAttachment(accounting.InvoiceEntity, "inv-1", "invoice-1001.pdf", "application/pdf", []byte("%PDF-1.4"))
+		if err != nil {
+			t.Fatalf("failed to create attachment with error %v", err)
+		}
+
+		if err := repos.Attachments.Save(ctx, attachment); err != nil {
+			t.Fatalf("failed to save attachment with error %v", err)
+		}
+
+		saved, err := repos.Attachments.ByID(ctx, attachment.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve attachment with error %v", err)
+		}
+		if !bytes.Equal(saved.Data, attachment.Data) || saved.FileName != attachment.FileName {
+			t.Fatalf("expected to retrieve %+v, received %+v", attachment, saved)
+		}
+
+		listed, err := repos.Attachments.ListFor(ctx, accounting.InvoiceEntity, "inv-1")
+		if err != nil {
+			t.Fatalf("failed to list attachments with error %v", err)
+		}
+		if len(listed) != 1 || listed[0].ID != attachment.ID {
+			t.Fatalf("expected to list the saved attachment, received %+v", listed)
+		}
+	})
+
+	t.Run("returns a specific error if the attachment is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.Attachments.ByID(context.Background(), "missing"); !accounting.IsAttachmentNotFound(err) {
+			t.Fatalf("expected an AttachmentNotFound error, received %v", err)
+		}
+	})
+}
+
+func TestBookSettingsRepo(t *testing.T) {
+	t.Run("saves and retrieves the book settings", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		settings := accounting.BookSettings{BusinessName: "Hood Woodworks", Address: "1 Main St\nSpringfield"}
+		if err := repos.BookSettings.Save(ctx, &settings); err != nil {
+			t.Fatalf("failed to save book settings with error %v", err)
+		}
+
+		saved, err := repos.BookSettings.Get(ctx)
+		if err != nil {
+			t.Fatalf("failed to retrieve book settings with error %v", err)
+		}
+		if saved.BusinessName != settings.BusinessName || saved.Address != settings.Address || saved.Logo != nil {
+			t.Fatalf("expected to retrieve %+v, received %+v", settings, saved)
+		}
+	})
+}
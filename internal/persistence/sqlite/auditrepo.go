@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"strings"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/logging"
+)
+
+type auditRepo struct {
+	db *sql.DB
+}
+
+const auditColumns = `id, occurred_at, actor, request_id, operation, entity_type, entity_id, before_json, after_json`
+
+// Retrieves the audit records matching the filter, newest first
+func (r *auditRepo) Records(ctx context.Context, filter accounting.AuditFilter) ([]accounting.AuditRecord, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, formatTimestamp(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, formatTimestamp(filter.To))
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_log"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY occurred_at DESC, rowid DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return r.query(ctx, query, args...)
+}
+
+// Retrieves the audit records of an entity, in the order they occurred
+func (r *auditRepo) History(ctx context.Context, entityType accounting.EntityType, entityID string) ([]accounting.AuditRecord, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE entity_type = ? AND entity_id = ?
+		ORDER BY occurred_at, rowid;
+	`
+
+	return r.query(ctx, query, entityType, entityID)
+}
+
+func (r *auditRepo) query(ctx context.Context, query string, args ...any) ([]accounting.AuditRecord, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []accounting.AuditRecord
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// insertAuditRecord records a change within the transaction making it, on
+// behalf of the context's actor and request; before is nil for a new record
+func insertAuditRecord(ctx context.Context, tx *sql.Tx, op accounting.AuditOperation, entityType accounting.EntityType, entityID string, before, after any) error {
+	const query = `
+		INSERT INTO audit_log
+			(` + auditColumns + `)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	record, err := accounting.NewAuditRecord(accounting.Actor(ctx), logging.RequestID(ctx), op, entityType, entityID, before, after)
+	if err != nil {
+		return err
+	}
+
+	var beforeJSON sql.NullString
+	if record.Before != nil {
+		beforeJSON = sql.NullString{String: string(record.Before), Valid: true}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		record.ID,
+		formatTimestamp(record.OccurredAt),
+		record.Actor,
+		record.RequestID,
+		record.Operation,
+		record.EntityType,
+		record.EntityID,
+		beforeJSON,
+		string(record.After),
+	)
+	return err
+}
+
+func scanAuditRecord(s scanner) (accounting.AuditRecord, error) {
+	var (
+		record     accounting.AuditRecord
+		occurredAt string
+		before     sql.NullString
+		after      string
+	)
+
+	if err := s.Scan(
+		&record.ID,
+		&occurredAt,
+		&record.Actor,
+		&record.RequestID,
+		&record.Operation,
+		&record.EntityType,
+		&record.EntityID,
+		&before,
+		&after,
+	); err != nil {
+		return accounting.AuditRecord{}, err
+	}
+
+	var err error
+	if record.OccurredAt, err = parseTimestamp(occurredAt); err != nil {
+		return accounting.AuditRecord{}, err
+	}
+	if before.Valid {
+		record.Before = []byte(before.String)
+	}
+	record.After = []byte(after)
+
+	return record, nil
+}
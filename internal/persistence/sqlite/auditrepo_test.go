@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/logging"
+)
+
+func TestAuditRepo(t *testing.T) {
+	newRepos := func(t *testing.T) *Repositories {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		t.Cleanup(func() { repos.Close() })
+		return repos
+	}
+
+	alice, err := accounting.NewUser("alice", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	ctx := logging.WithRequestID(accounting.WithUser(context.Background(), alice), "req-1")
+
+	t.Run("records each change to an account with its actor and request", func(t *testing.T) {
+		repos := newRepos(t)
+
+		account := &accounting.Account{
+			Name:            "Operating Bank",
+			ParentGroupName: "Assets",
+			AccountType:     accounting.Asset,
+			NormalBalance:   accounting.DebitNormal,
+		}
+		if err := repos.Accounts.Save(ctx, account); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+
+		account.DisplayAfter = sql.NullString{String: "Retained Earnings", Valid: true}
+		if err := repos.Accounts.Save(ctx, account); err != nil {
+			t.Fatalf("failed to reorder account with error %v", err)
+		}
+
+		account.ParentGroupName = "Liabilities"
+		if err := repos.Accounts.Save(context.Background(), account); err != nil {
+			t.Fatalf("failed to update account with error %v", err)
+		}
+
+		// saving it unchanged is not a change
+		if err := repos.Accounts.Save(ctx, account); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+
+		history, err := repos.Audit.History(ctx, accounting.AccountEntity, "Operating Bank")
+		if err != nil {
+			t.Fatalf("failed to get history with error %v", err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("expected 3 records, got %d", len(history))
+		}
+
+		ops := []accounting.AuditOperation{history[0].Operation, history[1].Operation, history[2].Operation}
+		if ops[0] != accounting.AuditCreate || ops[1] != accounting.AuditReorder || ops[2] != accounting.AuditUpdate {
+			t.Fatalf("expected a create, reorder and update, got %v", ops)
+		}
+		if history[0].Actor != "alice" || history[0].RequestID != "req-1" || history[0].Before != nil {
+			t.Fatalf("unexpected create record %+v", history[0])
+		}
+		if history[2].Actor != accounting.SystemActor || history[2].RequestID != "" {
+			t.Fatalf("expected the system to have made the update, got %+v", history[2])
+		}
+
+		var before, after accounting.Account
+		if err := json.Unmarshal(history[2].Before, &before); err != nil {
+			t.Fatalf("failed to decode before with error %v", err)
+		}
+		if err := json.Unmarshal(history[2].After, &after); err != nil {
+			t.Fatalf("failed to decode after with error %v", err)
+		}
+		if before.ParentGroupName != "Assets" || after.ParentGroupName != "Liabilities" {
+			t.Fatalf("expected the move between groups, got %+v to %+v", before, after)
+		}
+	})
+
+	t.Run("records groups created and updated", func(t *testing.T) {
+		repos := newRepos(t)
+
+		group := &accounting.AccountGroup{Name: "Current Assets", ParentName: sql.NullString{String: "Assets", Valid: true}}
+		if err := repos.AccountGroups.Insert(ctx, group); err != nil {
+			t.Fatalf("failed to insert group with error %v", err)
+		}
+		group.ParentName = sql.NullString{String: "Liabilities", Valid: true}
+		if err := repos.AccountGroups.Upsert(ctx, group); err != nil {
+			t.Fatalf("failed to update group with error %v", err)
+		}
+
+		history, err := repos.Audit.History(ctx, accounting.AccountGroupEntity, "Current Assets")
+		if err != nil {
+			t.Fatalf("failed to get history with error %v", err)
+		}
+		if len(history) != 2 || history[0].Operation != accounting.AuditCreate || history[1].Operation != accounting.AuditUpdate {
+			t.Fatalf("expected a create then an update, got %+v", history)
+		}
+	})
+
+	t.Run("records postings in the entry's transaction", func(t *testing.T) {
+		repos := newRepos(t)
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		posted := accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 100, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: 100, Side: accounting.Credit},
+			},
+		}
+		if err := repos.JournalEntries.Save(ctx, posted); err != nil {
+			t.Fatalf("failed to save entry with error %v", err)
+		}
+
+		failed := posted
+		failed.ID = accounting.NewID()
+		failed.Lines = []accounting.JournalEntryLine{
+			{AccountName: "Cash", Amount: 100, Side: accounting.Debit},
+			{AccountName: "Nonexistent Account", Amount: 100, Side: accounting.Credit},
+		}
+		if err := repos.JournalEntries.Save(ctx, failed); err == nil {
+			t.Fatal("expected an error saving an entry to a missing account")
+		}
+
+		records, err := repos.Audit.Records(ctx, accounting.AuditFilter{EntityType: accounting.JournalEntryEntity})
+		if err != nil {
+			t.Fatalf("failed to get records with error %v", err)
+		}
+		if len(records) != 1 || records[0].EntityID != posted.ID || records[0].Operation != accounting.AuditPost {
+			t.Fatalf("expected only the posted entry to be recorded, got %+v", records)
+		}
+	})
+
+	t.Run("filters records by actor, entity and date", func(t *testing.T) {
+		repos := newRepos(t)
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		if err := repos.Accounts.Save(ctx, &accounting.Account{Name: "Savings", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal}); err != nil {
+			t.Fatalf("failed to save account with error %v", err)
+		}
+
+		byAlice, err := repos.Audit.Records(ctx, accounting.AuditFilter{Actor: "alice"})
+		if err != nil {
+			t.Fatalf("failed to get records with error %v", err)
+		}
+		if len(byAlice) != 1 || byAlice[0].EntityID != "Savings" {
+			t.Fatalf("expected alice's one change, got %+v", byAlice)
+		}
+
+		all, err := repos.Audit.Records(ctx, accounting.AuditFilter{EntityType: accounting.AccountEntity})
+		if err != nil {
+			t.Fatalf("failed to get records with error %v", err)
+		}
+		if len(all) != 2 || all[0].EntityID != "Savings" {
+			t.Fatalf("expected both changes, newest first, got %+v", all)
+		}
+
+		tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+		later, err := repos.Audit.Records(ctx, accounting.AuditFilter{From: tomorrow})
+		if err != nil {
+			t.Fatalf("failed to get records with error %v", err)
+		}
+		if len(later) != 0 {
+			t.Fatalf("expected no records from tomorrow, got %+v", later)
+		}
+	})
+
+	t.Run("refuses to alter the log", func(t *testing.T) {
+		repos := newRepos(t)
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		db := repos.Audit.(*auditRepo).db
+		if _, err := db.Exec(`UPDATE audit_log SET actor = 'mallory'`); err == nil {
+			t.Fatal("expected updating the log to fail")
+		}
+		if _, err := db.Exec(`DELETE FROM audit_log`); err == nil {
+			t.Fatal("expected deleting from the log to fail")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+// Snapshot writes a consistent copy of the database to path, which must not
+// exist, while the repositories remain in use. The copy waits for the
+// transaction under way, if any, and so holds only whole postings.
+func (r *Repositories) Snapshot(ctx context.Context, path string) error {
+	return SnapshotDB(ctx, r.db, path)
+}
+
+// SnapshotDB is Snapshot for a database opened with Open, e.g. before it is
+// migrated
+func SnapshotDB(ctx context.Context, db *sql.DB, path string) error {
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?;", path); err != nil {
+		return fmt.Errorf("failed to snapshot the database to %s: %w", path, err)
+	}
+	return nil
+}
+
+// VerifyFile checks the database file at path without altering it: that
+// sqlite finds it intact, and that its trial balance nets to zero
+func VerifyFile(ctx context.Context, path string) error {
+	db, err := OpenReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check;")
+	if err != nil {
+		return fmt.Errorf("failed to check the integrity of %s: %w", path, err)
+	}
+	var problems []string
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			rows.Close()
+			return err
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check the integrity of %s: %w", path, err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s failed its integrity check: %s", path, strings.Join(problems, "; "))
+	}
+
+	const trialBalance = `
+		SELECT
+			COALESCE(SUM(CASE WHEN side = 'Debit' THEN amount END), 0),
+			COALESCE(SUM(CASE WHEN side = 'Credit' THEN amount END), 0)
+		FROM journal_lines;
+	`
+
+	var debits, credits float64
+	if err := db.QueryRowContext(ctx, trialBalance).Scan(&debits, &credits); err != nil {
+		return fmt.Errorf("failed to total the trial balance of %s: %w", path, err)
+	}
+	if debits, credits = accounting.RoundCents(debits), accounting.RoundCents(credits); debits != credits {
+		return fmt.Errorf("the trial balance of %s does not net to zero: debits %.2f, credits %.2f", path, debits, credits)
+	}
+
+	return nil
+}
+
+// LastChange reads the latest record of the audit log of the database file
+// at path without altering it; false if nothing has been recorded, including
+// in books which predate the audit log
+func LastChange(ctx context.Context, path string) (accounting.AuditRecord, bool, error) {
+	db, err := OpenReadOnly(path)
+	if err != nil {
+		return accounting.AuditRecord{}, false, err
+	}
+	defer db.Close()
+
+	if logged, err := hasAuditLog(ctx, db); err != nil || !logged {
+		return accounting.AuditRecord{}, false, err
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		ORDER BY occurred_at DESC, rowid DESC
+		LIMIT 1;
+	`
+	record, err := scanAuditRecord(db.QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return accounting.AuditRecord{}, false, nil
+	}
+	if err != nil {
+		return accounting.AuditRecord{}, false, fmt.Errorf("failed to read the audit log of %s: %w", path, err)
+	}
+
+	return record, true, nil
+}
+
+// HasChange reports whether the audit log of the database file at path holds
+// the record with the id, without altering the file
+func HasChange(ctx context.Context, path, id string) (bool, error) {
+	db, err := OpenReadOnly(path)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	if logged, err := hasAuditLog(ctx, db); err != nil || !logged {
+		return false, err
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log WHERE id = ?;", id).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read the audit log of %s: %w", path, err)
+	}
+
+	return count > 0, nil
+}
+
+// OpenReadOnly opens the database file at path read-only, so that a missing
+// file is not created and no migrations are run against it
+func OpenReadOnly(path string) (*sql.DB, error) {
+	return sql.Open("sqlite3", "file:"+(&url.URL{Path: filepath.ToSlash(path)}).EscapedPath()+"?mode=ro")
+}
+
+// reports whether the database has been migrated as far as the audit log
+func hasAuditLog(ctx context.Context, db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'audit_log';").Scan(&count)
+	return count > 0, err
+}
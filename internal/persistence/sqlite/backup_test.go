@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	newBooks := func(t *testing.T) *Repositories {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		t.Cleanup(func() { repos.Close() })
+
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		if err := repos.JournalEntries.Save(ctx, accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 250.10, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: 250.10, Side: accounting.Credit},
+			},
+		}); err != nil {
+			t.Fatalf("failed to save entry with error %v", err)
+		}
+		return repos
+	}
+
+	t.Run("copies the live books into a file which verifies", func(t *testing.T) {
+		repos := newBooks(t)
+		path := filepath.Join(t.TempDir(), "snapshot.db")
+
+		if err := repos.Snapshot(ctx, path); err != nil {
+			t.Fatalf("failed to snapshot with error %v", err)
+		}
+		if err := VerifyFile(ctx, path); err != nil {
+			t.Fatalf("expected the snapshot to verify, got %v", err)
+		}
+
+		copied, err := New(path)
+		if err != nil {
+			t.Fatalf("failed to open snapshot with error %v", err)
+		}
+		defer copied.Close()
+		totals, err := copied.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to total snapshot with error %v", err)
+		}
+		if len(totals) != 2 || totals[0].AccountName != "Cash" || totals[0].Debits != 250.10 {
+			t.Fatalf("expected the entry in the snapshot, got %+v", totals)
+		}
+	})
+
+	t.Run("refuses to overwrite a file", func(t *testing.T) {
+		repos := newBooks(t)
+		path := filepath.Join(t.TempDir(), "snapshot.db")
+		if err := os.WriteFile(path, []byte("taken"), 0o644); err != nil {
+			t.Fatalf("failed to write file with error %v", err)
+		}
+
+		if err := repos.Snapshot(ctx, path); err == nil {
+			t.Fatal("expected an error snapshotting over an existing file")
+		}
+	})
+
+	t.Run("rejects books whose trial balance does not net to zero", func(t *testing.T) {
+		repos := newBooks(t)
+		if _, err := repos.db.Exec(`UPDATE journal_lines SET amount = 250.11 WHERE side = 'Debit'`); err != nil {
+			t.Fatalf("failed to unbalance entry with error %v", err)
+		}
+
+		path := filepath.Join(t.TempDir(), "snapshot.db")
+		if err := repos.Snapshot(ctx, path); err != nil {
+			t.Fatalf("failed to snapshot with error %v", err)
+		}
+		if err := VerifyFile(ctx, path); err == nil || !strings.Contains(err.Error(), "does not net to zero") {
+			t.Fatalf("expected an unbalanced trial balance, got %v", err)
+		}
+	})
+
+	t.Run("rejects a file which is not a database", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "garbage.db")
+		if err := os.WriteFile(path, []byte(strings.Repeat("not a database ", 512)), 0o644); err != nil {
+			t.Fatalf("failed to write file with error %v", err)
+		}
+
+		if err := VerifyFile(ctx, path); err == nil {
+			t.Fatal("expected a garbage file to fail verification")
+		}
+		if err := VerifyFile(ctx, filepath.Join(t.TempDir(), "missing.db")); err == nil {
+			t.Fatal("expected a missing file to fail verification")
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type billRepo struct {
+	db *sql.DB
+}
+
+// Post saves a bill, its lines, and the journal entry which posts it in a single transaction.
+func (r *billRepo) Post(ctx context.Context, bill *accounting.Bill, je accounting.JournalEntry) error {
+	const billQuery = `
+		INSERT INTO bills
+			(id, vendor_id, number, bill_date, due_date, terms, payable_account_name, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	const lineQuery = `
+		INSERT INTO bill_lines
+			(bill_id, line_no, account_name, description, amount, project_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			billQuery,
+			bill.ID,
+			bill.VendorID,
+			bill.Number,
+			formatDate(bill.BillDate),
+			formatDate(bill.DueDate),
+			bill.Terms.String(),
+			bill.PayableAccountName,
+			bill.Memo,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		for i, line := range bill.Lines {
+			if _, err := tx.ExecContext(
+				ctx,
+				lineQuery,
+				bill.ID,
+				i+1,
+				line.AccountName,
+				line.Description,
+				line.Amount,
+				sql.NullString{String: line.ProjectID, Valid: line.ProjectID != ""},
+			); err != nil {
+				return err
+			}
+		}
+
+		bill.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves a bill, with its lines, by ID
+//
+// Returns ErrBillNotFound if the bill does not exist.
+func (r *billRepo) ByID(ctx context.Context, id string) (accounting.Bill, error) {
+	return billByID(ctx, r.db, id)
+}
+
+// Retrieves all bills, with their lines, ordered by bill date
+func (r *billRepo) GetAll(ctx context.Context) ([]*accounting.Bill, error) {
+	const query = `
+		SELECT id, vendor_id, number, bill_date, due_date, terms, payable_account_name, memo, journal_entry_id
+		FROM bills
+		ORDER BY bill_date, id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var bills []*accounting.Bill
+	for rows.Next() {
+		bill, err := scanBill(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		bills = append(bills, &bill)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// lines are fetched once the bill cursor is released
+	for _, bill := range bills {
+		if bill.Lines, err = billLines(ctx, r.db, bill.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bills, nil
+}
+
+// RecordPayment saves a bill payment and the journal entry which posts it in a single transaction.
+//
+// Returns ErrOverpayment if the payment, with any discount taken, exceeds the
+// bill's open balance as it stands within the transaction.
+func (r *billRepo) RecordPayment(ctx context.Context, payment *accounting.BillPayment, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO bill_payments
+			(id, bill_id, payment_date, cash_account_name, amount, discount_taken, discount_account_name, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// re-read, lest a payment recorded since the caller's read be overpaid
+		bill, err := billByID(ctx, tx, payment.BillID)
+		if err != nil {
+			return err
+		}
+		payments, err := billPayments(ctx, tx, bill.ID)
+		if err != nil {
+			return err
+		}
+		if open := bill.OpenBalance(payments); accounting.RoundCents(payment.Amount+payment.DiscountTaken) > open {
+			return &accounting.ErrOverpayment{DocumentID: bill.ID, OpenBalance: open, Amount: payment.Amount}
+		}
+
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			payment.ID,
+			payment.BillID,
+			formatDate(payment.PaymentDate),
+			payment.CashAccountName,
+			payment.Amount,
+			payment.DiscountTaken,
+			sql.NullString{String: payment.DiscountAccountName, Valid: payment.DiscountAccountName != ""},
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		payment.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves the payments made against a bill, ordered by payment date
+func (r *billRepo) Payments(ctx context.Context, billID string) ([]accounting.BillPayment, error) {
+	return billPayments(ctx, r.db, billID)
+}
+
+// retrieves a bill, with its lines, by ID
+func billByID(ctx context.Context, q querier, id string) (accounting.Bill, error) {
+	const query = `
+		SELECT id, vendor_id, number, bill_date, due_date, terms, payable_account_name, memo, journal_entry_id
+		FROM bills
+		WHERE id = ?;
+	`
+
+	bill, err := scanBill(q.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Bill{}, &accounting.ErrBillNotFound{ID: id}
+		}
+		return accounting.Bill{}, err
+	}
+
+	if bill.Lines, err = billLines(ctx, q, id); err != nil {
+		return accounting.Bill{}, err
+	}
+
+	return bill, nil
+}
+
+// retrieves the payments made against a bill, ordered by payment date
+func billPayments(ctx context.Context, q querier, billID string) ([]accounting.BillPayment, error) {
+	const query = `
+		SELECT id, bill_id, payment_date, cash_account_name, amount, discount_taken, discount_account_name, journal_entry_id
+		FROM bill_payments
+		WHERE bill_id = ?
+		ORDER BY payment_date, id;
+	`
+
+	rows, err := q.QueryContext(ctx, query, billID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []accounting.BillPayment
+	for rows.Next() {
+		var (
+			payment         accounting.BillPayment
+			paymentDate     string
+			discountAccount sql.NullString
+		)
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.BillID,
+			&paymentDate,
+			&payment.CashAccountName,
+			&payment.Amount,
+			&payment.DiscountTaken,
+			&discountAccount,
+			&payment.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if payment.PaymentDate, err = parseDate(paymentDate); err != nil {
+			return nil, err
+		}
+		payment.DiscountAccountName = discountAccount.String
+
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// retrieves the lines of a bill in order
+func billLines(ctx context.Context, q querier, billID string) ([]accounting.BillLine, error) {
+	const query = `
+		SELECT account_name, description, amount, project_id
+		FROM bill_lines
+		WHERE bill_id = ?
+		ORDER BY line_no;
+	`
+
+	rows, err := q.QueryContext(ctx, query, billID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.BillLine
+	for rows.Next() {
+		var line accounting.BillLine
+		var projectID sql.NullString
+		if err := rows.Scan(&line.AccountName, &line.Description, &line.Amount, &projectID); err != nil {
+			return nil, err
+		}
+		line.ProjectID = projectID.String
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func scanBill(s scanner) (accounting.Bill, error) {
+	var (
+		bill              accounting.Bill
+		billDate, dueDate string
+		terms             string
+	)
+
+	if err := s.Scan(
+		&bill.ID,
+		&bill.VendorID,
+		&bill.Number,
+		&billDate,
+		&dueDate,
+		&terms,
+		&bill.PayableAccountName,
+		&bill.Memo,
+		&bill.EntryID,
+	); err != nil {
+		return accounting.Bill{}, err
+	}
+
+	var err error
+	if bill.BillDate, err = parseDate(billDate); err != nil {
+		return accounting.Bill{}, err
+	}
+	if bill.DueDate, err = parseDate(dueDate); err != nil {
+		return accounting.Bill{}, err
+	}
+	if bill.Terms, err = accounting.ParsePaymentTerms(terms); err != nil {
+		return accounting.Bill{}, err
+	}
+
+	return bill, nil
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+// creates a vendor and a posted bill for use in tests
+func postTestBill(t *testing.T, repos *Repositories, amount float64) *accounting.Bill {
+	t.Helper()
+	ctx := context.Background()
+
+	saveTestAccount(t, repos, "Office Supplies", "Expenses", accounting.Expense, accounting.DebitNormal)
+
+	vendor, err := accounting.NewVendor("Paper Co.", accounting.DefaultPaymentTerms)
+	if err != nil {
+		t.Fatalf("failed to create vendor with error %v", err)
+	}
+	if err := repos.Vendors.Save(ctx, vendor); err != nil {
+		t.Fatalf("failed to save vendor with error %v", err)
+	}
+
+	bill, err := accounting.NewBill(
+		vendor.ID,
+		"PC-100",
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		accounting.PaymentTerms{DiscountPercent: 2, DiscountDays: 10, NetDays: 30},
+		"Accounts Payable",
+		[]accounting.BillLine{{AccountName: "Office Supplies", Description: "Paper", Amount: amount}},
+	)
+	if err != nil {
+		t.Fatalf("failed to create bill with error %v", err)
+	}
+
+	if err := repos.Bills.Post(ctx, bill, bill.JournalEntry()); err != nil {
+		t.Fatalf("failed to post bill with error %v", err)
+	}
+
+	return bill
+}
+
+func TestBillRepo_Post(t *testing.T) {
+	t.Run("saves the bill with its journal entry", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		bill := postTestBill(t, repos, 250)
+
+		saved, err := repos.Bills.ByID(ctx, bill.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve bill with error %v", err)
+		}
+
+		if saved.Total() != 250 || saved.Terms != bill.Terms || !saved.DueDate.Equal(bill.DueDate) {
+			t.Fatalf("expected to retrieve %+v, received %+v", bill, saved)
+		}
+
+		if _, err := repos.JournalEntries.ByID(ctx, saved.EntryID); err != nil {
+			t.Fatalf("expected the bill's journal entry to exist, received %v", err)
+		}
+	})
+
+	t.Run("returns a specific error if the bill is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.Bills.ByID(context.Background(), "missing"); !accounting.IsBillNotFound(err) {
+			t.Fatalf("expected a BillNotFound error, received %v", err)
+		}
+	})
+}
+
+func TestBillRepo_RecordPayment(t *testing.T) {
+	t.Run("records a discounted payment", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Checking", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		bill := postTestBill(t, repos, 250)
+
+		payment, err := accounting.NewBillPayment(bill, 250, bill.BillDate.AddDate(0, 0, 5), "Checking", 245, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+
+		if err := repos.Bills.RecordPayment(ctx, payment, payment.JournalEntry(bill)); err != nil {
+			t.Fatalf("failed to record payment with error %v", err)
+		}
+
+		payments, err := repos.Bills.Payments(ctx, bill.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve payments with error %v", err)
+		}
+
+		if open := bill.OpenBalance(payments); open != 0 {
+			t.Fatalf("expected the bill to be settled, found an open balance of %v", open)
+		}
+	})
+
+	t.Run("rejects a payment made against a stale open balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Checking", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		bill := postTestBill(t, repos, 250)
+		paidAt := bill.BillDate.AddDate(0, 0, 20)
+
+		// both read the open balance before either is recorded
+		first, err := accounting.NewBillPayment(bill, 250, paidAt, "Checking", 200, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+		second, err := accounting.NewBillPayment(bill, 250, paidAt, "Checking", 200, "Purchase Discounts")
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+
+		if err := repos.Bills.RecordPayment(ctx, first, first.JournalEntry(bill)); err != nil {
+			t.Fatalf("failed to record payment with error %v", err)
+		}
+		if err := repos.Bills.RecordPayment(ctx, second, second.JournalEntry(bill)); !accounting.IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error, received %v", err)
+		}
+
+		payments, err := repos.Bills.Payments(ctx, bill.ID)
+		if err != nil || len(payments) != 1 {
+			t.Fatalf("expected only the first payment recorded, received %v and %v", payments, err)
+		}
+	})
+}
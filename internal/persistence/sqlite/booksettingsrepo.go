@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type bookSettingsRepo struct {
+	db *sql.DB
+}
+
+// Retrieves the book settings; there is exactly one row, seeded by migration
+func (r *bookSettingsRepo) Get(ctx context.Context) (accounting.BookSettings, error) {
+	const query = `
+		SELECT business_name, address, email, phone, logo
+		FROM book_settings
+		WHERE id = 1;
+	`
+
+	var settings accounting.BookSettings
+	err := r.db.QueryRowContext(ctx, query).Scan(
+		&settings.BusinessName,
+		&settings.Address,
+		&settings.Email,
+		&settings.Phone,
+		&settings.Logo,
+	)
+	if err != nil {
+		return accounting.BookSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// Save replaces the book settings
+func (r *bookSettingsRepo) Save(ctx context.Context, settings *accounting.BookSettings) error {
+	const query = `
+		UPDATE book_settings SET
+			business_name = ?,
+			address = ?,
+			email = ?,
+			phone = ?,
+			logo = ?
+		WHERE id = 1;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		settings.BusinessName,
+		settings.Address,
+		settings.Email,
+		settings.Phone,
+		settings.Logo,
+	)
+
+	return err
+}
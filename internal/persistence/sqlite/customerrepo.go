@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type customerRepo struct {
+	db *sql.DB
+}
+
+// Save inserts or updates a customer in the database.
+func (r *customerRepo) Save(ctx context.Context, customer *accounting.Customer) error {
+	const query = `
+		INSERT INTO customers
+			(id, name, email, phone, address, default_terms)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			email = excluded.email,
+			phone = excluded.phone,
+			address = excluded.address,
+			default_terms = excluded.default_terms;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		customer.ID,
+		customer.Name,
+		customer.Email,
+		customer.Phone,
+		customer.Address,
+		customer.DefaultTerms.String(),
+	)
+
+	return err
+}
+
+// Retrieves a customer by ID
+//
+// Returns ErrCustomerNotFound if the customer does not exist.
+func (r *customerRepo) ByID(ctx context.Context, id string) (accounting.Customer, error) {
+	const query = `
+		SELECT id, name, email, phone, address, default_terms
+		FROM customers
+		WHERE id = ?;
+	`
+
+	customer, err := scanCustomer(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Customer{}, &accounting.ErrCustomerNotFound{ID: id}
+		}
+		return accounting.Customer{}, err
+	}
+
+	return customer, nil
+}
+
+// Retrieves all customers, ordered by name
+func (r *customerRepo) GetAll(ctx context.Context) ([]*accounting.Customer, error) {
+	const query = `
+		SELECT id, name, email, phone, address, default_terms
+		FROM customers
+		ORDER BY name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []*accounting.Customer
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, &customer)
+	}
+
+	return customers, rows.Err()
+}
+
+func scanCustomer(s scanner) (accounting.Customer, error) {
+	var (
+		customer accounting.Customer
+		terms    string
+	)
+
+	if err := s.Scan(&customer.ID, &customer.Name, &customer.Email, &customer.Phone, &customer.Address, &terms); err != nil {
+		return accounting.Customer{}, err
+	}
+
+	parsed, err := accounting.ParsePaymentTerms(terms)
+	if err != nil {
+		return accounting.Customer{}, err
+	}
+	customer.DefaultTerms = parsed
+
+	return customer, nil
+}
@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type fixedAssetRepo struct {
+	db *sql.DB
+}
+
+// Save inserts or updates a fixed asset in the database.
+func (r *fixedAssetRepo) Save(ctx context.Context, asset *accounting.FixedAsset) error {
+	const query = `
+		INSERT INTO fixed_assets
+			(id, name, cost, salvage_value, in_service_date, useful_life, method, declining_balance_factor,
+			 asset_account_name, accumulated_depreciation_account_name, depreciation_expense_account_name)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			cost = excluded.cost,
+			salvage_value = excluded.salvage_value,
+			in_service_date = excluded.in_service_date,
+			useful_life = excluded.useful_life,
+			method = excluded.method,
+			declining_balance_factor = excluded.declining_balance_factor,
+			asset_account_name = excluded.asset_account_name,
+			accumulated_depreciation_account_name = excluded.accumulated_depreciation_account_name,
+			depreciation_expense_account_name = excluded.depreciation_expense_account_name;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		asset.ID,
+		asset.Name,
+		asset.Cost,
+		asset.SalvageValue,
+		formatDate(asset.InServiceDate),
+		asset.UsefulLife,
+		asset.Method,
+		asset.DecliningBalanceFactor,
+		asset.AssetAccountName,
+		asset.AccumulatedDepreciationAccountName,
+		sql.NullString{String: asset.DepreciationExpenseAccountName, Valid: asset.DepreciationExpenseAccountName != ""},
+	)
+
+	return err
+}
+
+// Retrieves a fixed asset by ID
+//
+// Returns ErrFixedAssetNotFound if the asset does not exist.
+func (r *fixedAssetRepo) ByID(ctx context.Context, id string) (accounting.FixedAsset, error) {
+	query := fixedAssetSelect + `
+		WHERE a.id = ?;
+	`
+
+	asset, err := scanFixedAsset(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.FixedAsset{}, &accounting.ErrFixedAssetNotFound{ID: id}
+		}
+		return accounting.FixedAsset{}, err
+	}
+
+	return asset, nil
+}
+
+// Retrieves all fixed assets, ordered by in-service date
+func (r *fixedAssetRepo) GetAll(ctx context.Context) ([]*accounting.FixedAsset, error) {
+	query := fixedAssetSelect + `
+		ORDER BY a.in_service_date, a.name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []*accounting.FixedAsset
+	for rows.Next() {
+		asset, err := scanFixedAsset(rows)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, &asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// Retrieves every depreciation posting, ordered by period
+func (r *fixedAssetRepo) DepreciationPostings(ctx context.Context) ([]accounting.DepreciationPosting, error) {
+	const query = `
+		SELECT asset_id, period_end, amount, journal_entry_id
+		FROM depreciation_postings
+		ORDER BY period_end, asset_id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var postings []accounting.DepreciationPosting
+	for rows.Next() {
+		var (
+			posting   accounting.DepreciationPosting
+			periodEnd string
+		)
+		if err := rows.Scan(&posting.AssetID, &periodEnd, &posting.Amount, &posting.EntryID); err != nil {
+			return nil, err
+		}
+		if posting.PeriodEnd, err = parseDate(periodEnd); err != nil {
+			return nil, err
+		}
+		postings = append(postings, posting)
+	}
+
+	return postings, rows.Err()
+}
+
+// PostDepreciation saves a period's postings with the journal entry they share in a single
+// transaction. The (asset, period) key makes posting idempotent: a repeat fails and is rolled back.
+func (r *fixedAssetRepo) PostDepreciation(ctx context.Context, postings []accounting.DepreciationPosting, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO depreciation_postings
+			(asset_id, period_end, amount, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		for i, posting := range postings {
+			if _, err := tx.ExecContext(ctx, query, posting.AssetID, formatDate(posting.PeriodEnd), posting.Amount, je.ID); err != nil {
+				return err
+			}
+			postings[i].EntryID = je.ID
+		}
+
+		return nil
+	})
+}
+
+// PostDisposal saves an asset's disposal and the journal entry which posts it in a single transaction
+func (r *fixedAssetRepo) PostDisposal(ctx context.Context, disposal *accounting.FixedAssetDisposal, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO fixed_asset_disposals
+			(asset_id, disposal_date, proceeds, proceeds_account_name, gain_loss_account_name,
+			 accumulated_depreciation, gain_or_loss, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			disposal.AssetID,
+			formatDate(disposal.DisposalDate),
+			disposal.Proceeds,
+			sql.NullString{String: disposal.ProceedsAccountName, Valid: disposal.ProceedsAccountName != ""},
+			disposal.GainLossAccountName,
+			disposal.AccumulatedDepreciation,
+			disposal.GainOrLoss,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		disposal.EntryID = je.ID
+		return nil
+	})
+}
+
+const fixedAssetSelect = `
+	SELECT a.id, a.name, a.cost, a.salvage_value, a.in_service_date, a.useful_life, a.method, a.declining_balance_factor,
+		a.asset_account_name, a.accumulated_depreciation_account_name, a.depreciation_expense_account_name,
+		d.disposal_date, d.proceeds, d.proceeds_account_name, d.gain_loss_account_name,
+		d.accumulated_depreciation, d.gain_or_loss, d.journal_entry_id
+	FROM fixed_assets a
+	LEFT JOIN fixed_asset_disposals d ON d.asset_id = a.id`
+
+func scanFixedAsset(s scanner) (accounting.FixedAsset, error) {
+	var (
+		asset           accounting.FixedAsset
+		inServiceDate   string
+		expenseAccount  sql.NullString
+		disposalDate    sql.NullString
+		proceeds        sql.NullFloat64
+		proceedsAccount sql.NullString
+		gainLossAccount sql.NullString
+		accumulated     sql.NullFloat64
+		gainOrLoss      sql.NullFloat64
+		disposalEntryID sql.NullString
+	)
+
+	if err := s.Scan(
+		&asset.ID,
+		&asset.Name,
+		&asset.Cost,
+		&asset.SalvageValue,
+		&inServiceDate,
+		&asset.UsefulLife,
+		&asset.Method,
+		&asset.DecliningBalanceFactor,
+		&asset.AssetAccountName,
+		&asset.AccumulatedDepreciationAccountName,
+		&expenseAccount,
+		&disposalDate,
+		&proceeds,
+		&proceedsAccount,
+		&gainLossAccount,
+		&accumulated,
+		&gainOrLoss,
+		&disposalEntryID,
+	); err != nil {
+		return accounting.FixedAsset{}, err
+	}
+
+	var err error
+	if asset.InServiceDate, err = parseDate(inServiceDate); err != nil {
+		return accounting.FixedAsset{}, err
+	}
+	asset.DepreciationExpenseAccountName = expenseAccount.String
+
+	if disposalDate.Valid {
+		disposal := accounting.FixedAssetDisposal{
+			AssetID:                 asset.ID,
+			Proceeds:                proceeds.Float64,
+			ProceedsAccountName:     proceedsAccount.String,
+			GainLossAccountName:     gainLossAccount.String,
+			AccumulatedDepreciation: accumulated.Float64,
+			GainOrLoss:              gainOrLoss.Float64,
+			EntryID:                 disposalEntryID.String,
+		}
+		if disposal.DisposalDate, err = parseDate(disposalDate.String); err != nil {
+			return accounting.FixedAsset{}, err
+		}
+		asset.Disposal = &disposal
+	}
+
+	return asset, nil
+}
@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestFixedAssetRepo(t *testing.T) {
+	t.Run("saves and retrieves a fixed asset", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Equipment", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Accumulated Depreciation", "Assets", accounting.ContraAsset, accounting.CreditNormal)
+
+		asset, err := accounting.NewFixedAsset(
+			"Table Saw",
+			3200,
+			200,
+			time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			7,
+			accounting.DecliningBalance,
+			"Equipment",
+			"Accumulated Depreciation",
+		)
+		if err != nil {
+			t.Fatalf("failed to create fixed asset with error %v", err)
+		}
+
+		if err := repos.FixedAssets.Save(ctx, asset); err != nil {
+			t.Fatalf("failed to save fixed asset with error %v", err)
+		}
+
+		saved, err := repos.FixedAssets.ByID(ctx, asset.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve fixed asset with error %v", err)
+		}
+
+		if saved != *asset {
+			t.Fatalf("expected to retrieve %+v, received %+v", *asset, saved)
+		}
+	})
+
+	t.Run("returns a specific error if the asset is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.FixedAssets.ByID(context.Background(), "missing"); !accounting.IsFixedAssetNotFound(err) {
+			t.Fatalf("expected a FixedAssetNotFound error, received %v", err)
+		}
+	})
+
+	t.Run("posts depreciation once per asset and period", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Equipment", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		asset, err := accounting.NewFixedAsset(
+			"Table Saw",
+			1200,
+			0,
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			1,
+			accounting.StraightLine,
+			"Equipment",
+			"Accumulated Depreciation",
+		)
+		if err != nil {
+			t.Fatalf("failed to create fixed asset with error %v", err)
+		}
+		asset.DepreciationExpenseAccountName = "Depreciation Expense"
+		if err := repos.FixedAssets.Save(ctx, asset); err != nil {
+			t.Fatalf("failed to save fixed asset with error %v", err)
+		}
+
+		periodEnd := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+		post := func() error {
+			postings := []accounting.DepreciationPosting{{AssetID: asset.ID, PeriodEnd: periodEnd, Amount: 100}}
+			je := accounting.DepreciationEntry(periodEnd, []*accounting.FixedAsset{asset}, []float64{100})
+			return repos.FixedAssets.PostDepreciation(ctx, postings, je)
+		}
+
+		if err := post(); err != nil {
+			t.Fatalf("failed to post depreciation with error %v", err)
+		}
+		if err := post(); err == nil {
+			t.Fatalf("expected posting the same period twice to fail")
+		}
+
+		postings, err := repos.FixedAssets.DepreciationPostings(ctx)
+		if err != nil {
+			t.Fatalf("failed to retrieve postings with error %v", err)
+		}
+		if len(postings) != 1 || !postings[0].PeriodEnd.Equal(periodEnd) || postings[0].EntryID == "" {
+			t.Fatalf("expected a single posting for January, received %+v", postings)
+		}
+
+		totals, err := repos.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to retrieve account totals with error %v", err)
+		}
+		for _, total := range totals {
+			if total.AccountName == "Depreciation Expense" && total.Debits != 100 {
+				t.Fatalf("expected the rejected repeat to be rolled back, received %+v", total)
+			}
+		}
+	})
+
+	t.Run("saves a disposal with its entry", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Equipment", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		asset, _ := accounting.NewFixedAsset(
+			"Table Saw",
+			1200,
+			0,
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			1,
+			accounting.StraightLine,
+			"Equipment",
+			"Accumulated Depreciation",
+		)
+		if err := repos.FixedAssets.Save(ctx, asset); err != nil {
+			t.Fatalf("failed to save fixed asset with error %v", err)
+		}
+
+		disposal, err := accounting.NewFixedAssetDisposal(asset, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), 500, "Cash", "Gain or Loss on Disposal of Assets", 1200)
+		if err != nil {
+			t.Fatalf("failed to create disposal with error %v", err)
+		}
+		if err := repos.FixedAssets.PostDisposal(ctx, disposal, disposal.JournalEntry(asset)); err != nil {
+			t.Fatalf("failed to post disposal with error %v", err)
+		}
+
+		saved, err := repos.FixedAssets.ByID(ctx, asset.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve fixed asset with error %v", err)
+		}
+		if saved.Disposal == nil || *saved.Disposal != *disposal {
+			t.Fatalf("expected to retrieve disposal %+v, received %+v", *disposal, saved.Disposal)
+		}
+	})
+}
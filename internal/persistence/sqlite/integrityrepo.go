@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type integrityRepo struct {
+	db *sql.DB
+}
+
+// NewIntegrityRepo reads the books of a database opened with Open, which
+// may not yet be migrated; periods are left unchecked by schemas which
+// predate their recorded balances
+func NewIntegrityRepo(db *sql.DB) accounting.IntegrityRepository {
+	return &integrityRepo{db: db}
+}
+
+// Reads the accounts, groups, entries and their lines, stray lines, and the
+// totals of closed periods within a single transaction
+func (r *integrityRepo) Load(ctx context.Context) (accounting.IntegrityData, error) {
+	var data accounting.IntegrityData
+
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		if data.Accounts, err = integrityAccounts(ctx, tx); err != nil {
+			return err
+		}
+		if data.Groups, err = integrityGroups(ctx, tx); err != nil {
+			return err
+		}
+		if data.Entries, data.StrayLines, err = integrityEntries(ctx, tx); err != nil {
+			return err
+		}
+
+		if exists, err := tableExists(ctx, tx, "period_balances"); err != nil || !exists {
+			return err
+		}
+		data.ClosedPeriods, err = integrityClosedPeriods(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return accounting.IntegrityData{}, err
+	}
+
+	return data, nil
+}
+
+func integrityAccounts(ctx context.Context, tx *sql.Tx) ([]accounting.Account, error) {
+	const query = `
+		SELECT name, parent_group_name, account_type, display_after, normal_balance
+		FROM accounts
+		ORDER BY name;
+	`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []accounting.Account
+	for rows.Next() {
+		var account accounting.Account
+		if err := rows.Scan(&account.Name, &account.ParentGroupName, &account.AccountType, &account.DisplayAfter, &account.NormalBalance); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func integrityGroups(ctx context.Context, tx *sql.Tx) ([]accounting.AccountGroup, error) {
+	const query = `
+		SELECT name, parent_name, display_after
+		FROM account_groups
+		ORDER BY name;
+	`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []accounting.AccountGroup
+	for rows.Next() {
+		var group accounting.AccountGroup
+		if err := rows.Scan(&group.Name, &group.ParentName, &group.DisplayAfter); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+// reads every entry with its lines, and the lines whose entry does not exist
+func integrityEntries(ctx context.Context, tx *sql.Tx) ([]accounting.JournalEntry, []accounting.StrayLine, error) {
+	const entryQuery = `
+		SELECT id
+		FROM journal_entries
+		ORDER BY id;
+	`
+	const lineQuery = `
+		SELECT id, journal_entry_id, account_name, amount, side
+		FROM journal_lines
+		ORDER BY journal_entry_id, rowid;
+	`
+
+	rows, err := tx.QueryContext(ctx, entryQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		entries []accounting.JournalEntry
+		index   = make(map[string]int)
+	)
+	for rows.Next() {
+		var je accounting.JournalEntry
+		if err := rows.Scan(&je.ID); err != nil {
+			return nil, nil, err
+		}
+		index[je.ID] = len(entries)
+		entries = append(entries, je)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	lineRows, err := tx.QueryContext(ctx, lineQuery)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer lineRows.Close()
+
+	var strays []accounting.StrayLine
+	for lineRows.Next() {
+		var stray accounting.StrayLine
+		if err := lineRows.Scan(&stray.ID, &stray.EntryID, &stray.Line.AccountName, &stray.Line.Amount, &stray.Line.Side); err != nil {
+			return nil, nil, err
+		}
+
+		if i, ok := index[stray.EntryID]; ok {
+			entries[i].Lines = append(entries[i].Lines, stray.Line)
+		} else {
+			strays = append(strays, stray)
+		}
+	}
+
+	return entries, strays, lineRows.Err()
+}
+
+// reads the totals of each closed period as recorded on closing it and as
+// they stand now
+func integrityClosedPeriods(ctx context.Context, tx *sql.Tx) ([]accounting.ClosedPeriodTotals, error) {
+	const periodQuery = `
+		SELECT id, fiscal_year, number, start_date, end_date, status
+		FROM accounting_periods
+		WHERE status = ?
+		ORDER BY start_date;
+	`
+	const balancesQuery = `
+		SELECT account_name, debits, credits
+		FROM period_balances
+		WHERE period_id = ?
+		ORDER BY account_name;
+	`
+
+	rows, err := tx.QueryContext(ctx, periodQuery, accounting.PeriodClosed)
+	if err != nil {
+		return nil, err
+	}
+	var closed []accounting.ClosedPeriodTotals
+	for rows.Next() {
+		period, err := scanPeriod(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		closed = append(closed, accounting.ClosedPeriodTotals{Period: period})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, c := range closed {
+		balances, err := tx.QueryContext(ctx, balancesQuery, c.Period.ID)
+		if err != nil {
+			return nil, err
+		}
+		for balances.Next() {
+			var total accounting.AccountTotal
+			if err := balances.Scan(&total.AccountName, &total.Debits, &total.Credits); err != nil {
+				balances.Close()
+				return nil, err
+			}
+			closed[i].AtClose = append(closed[i].AtClose, total)
+		}
+		balances.Close()
+		if err := balances.Err(); err != nil {
+			return nil, err
+		}
+
+		if closed[i].Now, err = accountTotals(ctx, tx, c.Period.Start, c.Period.End.AddDate(0, 0, 1)); err != nil {
+			return nil, err
+		}
+	}
+
+	return closed, nil
+}
+
+func tableExists(ctx context.Context, q rowQuerier, name string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?);`, name).Scan(&exists)
+	return exists, err
+}
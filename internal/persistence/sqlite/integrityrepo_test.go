@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestIntegrityRepo(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*Repositories, []accounting.AccountingPeriod) {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		t.Cleanup(func() { repos.Close() })
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		periods := accounting.DefaultFiscalCalendar.Periods(2025)
+		if err := repos.Periods.Insert(ctx, periods); err != nil {
+			t.Fatalf("failed to insert periods with error %v", err)
+		}
+
+		err = repos.JournalEntries.Save(ctx, accounting.JournalEntry{
+			ID:        "je-1",
+			Timestamp: time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 100, Side: accounting.Debit},
+				{AccountName: "Sales", Amount: 100, Side: accounting.Credit},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to save entry with error %v", err)
+		}
+
+		return repos, periods
+	}
+	check := func(t *testing.T, repos *Repositories) accounting.IntegrityReport {
+		t.Helper()
+		data, err := repos.Integrity.Load(ctx)
+		if err != nil {
+			t.Fatalf("failed to load the books with error %v", err)
+		}
+		return accounting.CheckIntegrity(data)
+	}
+
+	t.Run("reads sound books", func(t *testing.T) {
+		repos, _ := setup(t)
+
+		report := check(t, repos)
+		if !report.OK() || report.Entries != 1 || report.Accounts < 2 {
+			t.Fatalf("expected sound books, got %+v", report)
+		}
+	})
+
+	t.Run("reads lines whose entry does not exist", func(t *testing.T) {
+		repos, _ := setup(t)
+		if _, err := repos.db.Exec(`PRAGMA foreign_keys = OFF;`); err != nil {
+			t.Fatalf("failed to disable foreign keys with error %v", err)
+		}
+		if _, err := repos.db.Exec(`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('line-9', 'Cash', 5, 'Debit', 'je-9');`); err != nil {
+			t.Fatalf("failed to insert stray line with error %v", err)
+		}
+
+		report := check(t, repos)
+		if len(report.Problems) != 2 || report.Problems[0].EntityID != "line-9" {
+			t.Fatalf("expected the stray line and the trial balance reported, got %+v", report.Problems)
+		}
+	})
+
+	t.Run("records a closed period's balances and reports changes to them", func(t *testing.T) {
+		repos, periods := setup(t)
+		change, err := periods[0].SetStatus(accounting.PeriodClosed, "month end", time.Now())
+		if err != nil {
+			t.Fatalf("failed to set status with error %v", err)
+		}
+		if err := repos.Periods.SetStatus(ctx, &periods[0], change); err != nil {
+			t.Fatalf("failed to save status with error %v", err)
+		}
+
+		data, err := repos.Integrity.Load(ctx)
+		if err != nil {
+			t.Fatalf("failed to load the books with error %v", err)
+		}
+		if len(data.ClosedPeriods) != 1 || len(data.ClosedPeriods[0].AtClose) != 2 || data.ClosedPeriods[0].AtClose[0].Debits != 100 {
+			t.Fatalf("expected the period's totals recorded on closing, got %+v", data.ClosedPeriods)
+		}
+		if report := accounting.CheckIntegrity(data); !report.OK() {
+			t.Fatalf("expected an unchanged period to pass, got %+v", report.Problems)
+		}
+
+		// edited directly, past the repository's guard on closed periods
+		if _, err := repos.db.Exec(`UPDATE journal_lines SET amount = 120 WHERE journal_entry_id = 'je-1';`); err != nil {
+			t.Fatalf("failed to edit the entry with error %v", err)
+		}
+		report := check(t, repos)
+		if len(report.Problems) != 2 || report.Problems[0].EntityID != periods[0].ID {
+			t.Fatalf("expected both accounts reported against the period, got %+v", report.Problems)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type inventoryCountRepo struct {
+	db *sql.DB
+}
+
+// Save inserts or updates a count and replaces its lines in a single transaction.
+func (r *inventoryCountRepo) Save(ctx context.Context, count *accounting.InventoryCount) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return saveInventoryCount(ctx, tx, count)
+	})
+}
+
+// Retrieves a count, with its lines, by ID
+//
+// Returns ErrInventoryCountNotFound if the count does not exist.
+func (r *inventoryCountRepo) ByID(ctx context.Context, id string) (accounting.InventoryCount, error) {
+	const query = `
+		SELECT id, count_date, status, shrinkage_account_name, journal_entry_id
+		FROM inventory_counts
+		WHERE id = ?;
+	`
+
+	count, err := scanInventoryCount(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.InventoryCount{}, &accounting.ErrInventoryCountNotFound{ID: id}
+		}
+		return accounting.InventoryCount{}, err
+	}
+
+	if count.Lines, err = r.lines(ctx, id); err != nil {
+		return accounting.InventoryCount{}, err
+	}
+
+	return count, nil
+}
+
+// Retrieves all counts, with their lines, most recent first
+func (r *inventoryCountRepo) GetAll(ctx context.Context) ([]*accounting.InventoryCount, error) {
+	const query = `
+		SELECT id, count_date, status, shrinkage_account_name, journal_entry_id
+		FROM inventory_counts
+		ORDER BY count_date DESC, id DESC;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []*accounting.InventoryCount
+	for rows.Next() {
+		count, err := scanInventoryCount(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// lines are fetched once the count cursor is released
+	for _, count := range counts {
+		if count.Lines, err = r.lines(ctx, count.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+// Post saves a posted count along with its variance movements, their shared
+// journal entry, and any adjustments to issues the movements recosted, in a
+// single transaction.
+func (r *inventoryCountRepo) Post(
+	ctx context.Context,
+	count *accounting.InventoryCount,
+	movements []*accounting.StockMovement,
+	je accounting.JournalEntry,
+	adjustments []accounting.StockCostAdjustment,
+	adjustmentEntries []accounting.JournalEntry,
+) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if len(movements) > 0 {
+			if err := insertJournalEntry(ctx, tx, je); err != nil {
+				return err
+			}
+			for _, movement := range movements {
+				if err := insertStockMovement(ctx, tx, movement, je.ID); err != nil {
+					return err
+				}
+			}
+			count.EntryID = je.ID
+		}
+
+		if err := insertStockCostAdjustments(ctx, tx, adjustments, adjustmentEntries); err != nil {
+			return err
+		}
+
+		return saveInventoryCount(ctx, tx, count)
+	})
+}
+
+// retrieves the lines of a count in order
+func (r *inventoryCountRepo) lines(ctx context.Context, countID string) ([]accounting.InventoryCountLine, error) {
+	const query = `
+		SELECT l.item_id, i.sku, l.expected_quantity, l.counted_quantity, l.variance_cost, l.movement_id
+		FROM inventory_count_lines l
+		JOIN stock_items i ON i.id = l.item_id
+		WHERE l.count_id = ?
+		ORDER BY l.line_no;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, countID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.InventoryCountLine
+	for rows.Next() {
+		var (
+			line       accounting.InventoryCountLine
+			counted    sql.NullFloat64
+			movementID sql.NullString
+		)
+		if err := rows.Scan(&line.ItemID, &line.SKU, &line.ExpectedQuantity, &counted, &line.VarianceCost, &movementID); err != nil {
+			return nil, err
+		}
+		line.Counted = counted.Valid
+		line.CountedQuantity = counted.Float64
+		line.MovementID = movementID.String
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// writes a count's header and lines within tx
+func saveInventoryCount(ctx context.Context, tx *sql.Tx, count *accounting.InventoryCount) error {
+	const countQuery = `
+		INSERT INTO inventory_counts
+			(id, count_date, status, shrinkage_account_name, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			count_date = excluded.count_date,
+			status = excluded.status,
+			shrinkage_account_name = excluded.shrinkage_account_name,
+			journal_entry_id = excluded.journal_entry_id;
+	`
+	const deleteLinesQuery = `
+		DELETE FROM inventory_count_lines
+		WHERE count_id = ?;
+	`
+	const lineQuery = `
+		INSERT INTO inventory_count_lines
+			(count_id, line_no, item_id, expected_quantity, counted_quantity, variance_cost, movement_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?);
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		countQuery,
+		count.ID,
+		formatDate(count.CountDate),
+		count.Status,
+		count.ShrinkageAccountName,
+		sql.NullString{String: count.EntryID, Valid: count.EntryID != ""},
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteLinesQuery, count.ID); err != nil {
+		return err
+	}
+
+	for i, line := range count.Lines {
+		if _, err := tx.ExecContext(
+			ctx,
+			lineQuery,
+			count.ID,
+			i+1,
+			line.ItemID,
+			line.ExpectedQuantity,
+			sql.NullFloat64{Float64: line.CountedQuantity, Valid: line.Counted},
+			line.VarianceCost,
+			sql.NullString{String: line.MovementID, Valid: line.MovementID != ""},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanInventoryCount(s scanner) (accounting.InventoryCount, error) {
+	var (
+		count     accounting.InventoryCount
+		countDate string
+		entryID   sql.NullString
+	)
+
+	if err := s.Scan(&count.ID, &countDate, &count.Status, &count.ShrinkageAccountName, &entryID); err != nil {
+		return accounting.InventoryCount{}, err
+	}
+
+	var err error
+	if count.CountDate, err = parseDate(countDate); err != nil {
+		return accounting.InventoryCount{}, err
+	}
+	count.EntryID = entryID.String
+
+	return count, nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestInventoryCountRepo(t *testing.T) {
+	t.Run("saves a count and posts its variances", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		item, _ := accounting.NewStockItem("BOLT", "Bolt", accounting.FIFO, "Inventory", "Cost of Goods Sold")
+		if err := repos.Stock.SaveItem(ctx, item); err != nil {
+			t.Fatalf("failed to save stock item with error %v", err)
+		}
+		receipt, _ := accounting.NewStockReceipt(item, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), 10, 2, "Cash")
+		receipt.Sequence = 1
+		if err := repos.Stock.Record(ctx, receipt, receipt.JournalEntry(item), nil, nil); err != nil {
+			t.Fatalf("failed to record receipt with error %v", err)
+		}
+
+		count, err := accounting.NewInventoryCount(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), "Inventory Shrinkage", []*accounting.StockItem{item}, []float64{10})
+		if err != nil {
+			t.Fatalf("failed to create count with error %v", err)
+		}
+		if err := repos.Counts.Save(ctx, count); err != nil {
+			t.Fatalf("failed to save count with error %v", err)
+		}
+
+		if err := count.Record(item.ID, 7); err != nil {
+			t.Fatalf("failed to record count with error %v", err)
+		}
+		movement, _ := count.VarianceMovement(item, 0)
+		recorded, _ := repos.Stock.Movements(ctx, item.ID)
+		if _, err := accounting.ApplyStockMovement(item, recorded, movement); err != nil {
+			t.Fatalf("failed to apply variance with error %v", err)
+		}
+		count.Lines[0].MovementID, count.Lines[0].VarianceCost = movement.ID, -movement.Cost
+		count.Status = accounting.CountPosted
+
+		je := count.JournalEntry([]*accounting.StockItem{item}, []*accounting.StockMovement{movement})
+		if err := repos.Counts.Post(ctx, count, []*accounting.StockMovement{movement}, je, nil, nil); err != nil {
+			t.Fatalf("failed to post count with error %v", err)
+		}
+
+		saved, err := repos.Counts.ByID(ctx, count.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve count with error %v", err)
+		}
+		if saved.Status != accounting.CountPosted || saved.EntryID != je.ID || len(saved.Lines) != 1 {
+			t.Fatalf("expected the posted count with its entry, received %+v", saved)
+		}
+		if line := saved.Lines[0]; line.SKU != "BOLT" || !line.Counted || line.CountedQuantity != 7 || line.VarianceCost != -6 || line.MovementID != movement.ID {
+			t.Fatalf("expected the shortage of 3 costing 6 to be kept, received %+v", line)
+		}
+
+		movements, _ := repos.Stock.Movements(ctx, item.ID)
+		if len(movements) != 2 || movements[1].EntryID != je.ID {
+			t.Fatalf("expected the variance movement to share the count's entry, received %+v", movements)
+		}
+	})
+
+	t.Run("returns a specific error if the count is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.Counts.ByID(context.Background(), "missing"); !accounting.IsInventoryCountNotFound(err) {
+			t.Fatalf("expected an InventoryCountNotFound error, received %v", err)
+		}
+	})
+}
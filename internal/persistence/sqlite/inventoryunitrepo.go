@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type inventoryUnitRepo struct {
+	db *sql.DB
+}
+
+// Acquire saves a new unit and the journal entry which brings it onto the books in a single transaction.
+func (r *inventoryUnitRepo) Acquire(ctx context.Context, unit *accounting.InventoryUnit, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO inventory_units
+			(id, serial_number, description, acquisition_cost, acquisition_date, acquisition_entry_id,
+			 location, inventory_account_name)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			unit.ID,
+			unit.SerialNumber,
+			unit.Description,
+			unit.AcquisitionCost,
+			formatDate(unit.AcquisitionDate),
+			je.ID,
+			unit.Location,
+			unit.InventoryAccountName,
+		); err != nil {
+			return err
+		}
+
+		unit.AcquisitionEntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves a unit, with its sale if sold, by ID
+//
+// Returns ErrInventoryUnitNotFound if the unit does not exist.
+func (r *inventoryUnitRepo) ByID(ctx context.Context, id string) (accounting.InventoryUnit, error) {
+	query := inventoryUnitSelect + `
+		WHERE u.id = ?;
+	`
+
+	unit, err := scanInventoryUnit(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.InventoryUnit{}, &accounting.ErrInventoryUnitNotFound{ID: id}
+		}
+		return accounting.InventoryUnit{}, err
+	}
+
+	return unit, nil
+}
+
+// Retrieves all units, ordered by serial number
+func (r *inventoryUnitRepo) GetAll(ctx context.Context) ([]*accounting.InventoryUnit, error) {
+	query := inventoryUnitSelect + `
+		ORDER BY u.serial_number;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var units []*accounting.InventoryUnit
+	for rows.Next() {
+		unit, err := scanInventoryUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, &unit)
+	}
+
+	return units, rows.Err()
+}
+
+// Move records a unit's new location
+//
+// Returns ErrInventoryUnitNotFound if the unit does not exist.
+func (r *inventoryUnitRepo) Move(ctx context.Context, id string, location string) error {
+	const query = `
+		UPDATE inventory_units
+		SET location = ?
+		WHERE id = ?;
+	`
+
+	result, err := r.db.ExecContext(ctx, query, location, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return &accounting.ErrInventoryUnitNotFound{ID: id}
+	}
+
+	return nil
+}
+
+// Sell saves the invoice a unit is sold on, the sale, and the journal entry
+// they share in a single transaction.
+func (r *inventoryUnitRepo) Sell(ctx context.Context, sale *accounting.UnitSale, invoice *accounting.Invoice, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO unit_sales
+			(unit_id, invoice_id, sale_date, price, cogs_account_name, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if err := insertInvoice(ctx, tx, invoice, je.ID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			sale.UnitID,
+			sale.InvoiceID,
+			formatDate(sale.SaleDate),
+			sale.Price,
+			sale.COGSAccountName,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		sale.EntryID = je.ID
+		return nil
+	})
+}
+
+const inventoryUnitSelect = `
+	SELECT u.id, u.serial_number, u.description, u.acquisition_cost, u.acquisition_date, u.acquisition_entry_id,
+		u.location, u.inventory_account_name,
+		s.invoice_id, s.sale_date, s.price, s.cogs_account_name, s.journal_entry_id
+	FROM inventory_units u
+	LEFT JOIN unit_sales s ON s.unit_id = u.id`
+
+func scanInventoryUnit(s scanner) (accounting.InventoryUnit, error) {
+	var (
+		unit            accounting.InventoryUnit
+		acquisitionDate string
+		invoiceID       sql.NullString
+		saleDate        sql.NullString
+		price           sql.NullFloat64
+		cogsAccount     sql.NullString
+		saleEntryID     sql.NullString
+	)
+
+	if err := s.Scan(
+		&unit.ID,
+		&unit.SerialNumber,
+		&unit.Description,
+		&unit.AcquisitionCost,
+		&acquisitionDate,
+		&unit.AcquisitionEntryID,
+		&unit.Location,
+		&unit.InventoryAccountName,
+		&invoiceID,
+		&saleDate,
+		&price,
+		&cogsAccount,
+		&saleEntryID,
+	); err != nil {
+		return accounting.InventoryUnit{}, err
+	}
+
+	var err error
+	if unit.AcquisitionDate, err = parseDate(acquisitionDate); err != nil {
+		return accounting.InventoryUnit{}, err
+	}
+
+	if invoiceID.Valid {
+		sale := accounting.UnitSale{
+			UnitID:          unit.ID,
+			InvoiceID:       invoiceID.String,
+			Price:           price.Float64,
+			COGSAccountName: cogsAccount.String,
+			EntryID:         saleEntryID.String,
+		}
+		if sale.SaleDate, err = parseDate(saleDate.String); err != nil {
+			return accounting.InventoryUnit{}, err
+		}
+		unit.Sale = &sale
+	}
+
+	return unit, nil
+}
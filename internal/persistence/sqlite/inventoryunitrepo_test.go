@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestInventoryUnitRepo(t *testing.T) {
+	t.Run("acquires, moves and sells a unit", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		unit, err := accounting.NewInventoryUnit("SN-1001", "Walnut Desk", 450, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "Warehouse", "Inventory")
+		if err != nil {
+			t.Fatalf("failed to create unit with error %v", err)
+		}
+		if err := repos.InventoryUnits.Acquire(ctx, unit, unit.AcquisitionEntry("Cash")); err != nil {
+			t.Fatalf("failed to acquire unit with error %v", err)
+		}
+		if err := repos.InventoryUnits.Move(ctx, unit.ID, "Showroom"); err != nil {
+			t.Fatalf("failed to move unit with error %v", err)
+		}
+
+		customer, _ := accounting.NewCustomer("Acme Corp.", accounting.DefaultPaymentTerms)
+		if err := repos.Customers.Save(ctx, customer); err != nil {
+			t.Fatalf("failed to save customer with error %v", err)
+		}
+		invoice, _ := accounting.NewInvoice(customer.ID, "2001", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), customer.DefaultTerms, "Accounts Receivable",
+			[]accounting.InvoiceLine{{AccountName: "Sales", Amount: 1200}})
+		sale, err := accounting.NewUnitSale(unit, invoice, "Cost of Goods Sold")
+		if err != nil {
+			t.Fatalf("failed to create sale with error %v", err)
+		}
+		if err := repos.InventoryUnits.Sell(ctx, sale, invoice, sale.JournalEntry(unit, invoice)); err != nil {
+			t.Fatalf("failed to sell unit with error %v", err)
+		}
+
+		saved, err := repos.InventoryUnits.ByID(ctx, unit.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve unit with error %v", err)
+		}
+		if saved.Location != "Showroom" || saved.AcquisitionEntryID == "" {
+			t.Fatalf("expected the moved unit with its acquisition entry, received %+v", saved)
+		}
+		if saved.Sale == nil || *saved.Sale != *sale {
+			t.Fatalf("expected to retrieve sale %+v, received %+v", *sale, saved.Sale)
+		}
+
+		if _, err := repos.Invoices.ByID(ctx, invoice.ID); err != nil {
+			t.Fatalf("expected the sale's invoice to be saved, received %v", err)
+		}
+
+		totals, err := repos.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to retrieve account totals with error %v", err)
+		}
+		for _, total := range totals {
+			if total.AccountName == "Inventory" && total.Balance(accounting.DebitNormal) != 0 {
+				t.Fatalf("expected inventory to be relieved, received %+v", total)
+			}
+		}
+	})
+
+	t.Run("rejects a duplicate serial number", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		for i := range 2 {
+			unit, _ := accounting.NewInventoryUnit("SN-1001", "Walnut Desk", 450, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "", "Inventory")
+			err := repos.InventoryUnits.Acquire(ctx, unit, unit.AcquisitionEntry("Cash"))
+			if i == 1 && err == nil {
+				t.Fatalf("expected the second unit with the same serial number to be rejected")
+			}
+		}
+
+		units, err := repos.InventoryUnits.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("failed to retrieve units with error %v", err)
+		}
+		if len(units) != 1 {
+			t.Fatalf("expected the rejected acquisition to be rolled back, received %d units", len(units))
+		}
+	})
+
+	t.Run("returns a specific error if the unit is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if err := repos.InventoryUnits.Move(context.Background(), "missing", "Showroom"); !accounting.IsInventoryUnitNotFound(err) {
+			t.Fatalf("expected an InventoryUnitNotFound error, received %v", err)
+		}
+	})
+}
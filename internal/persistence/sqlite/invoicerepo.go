@@ -0,0 +1,371 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type invoiceRepo struct {
+	db *sql.DB
+}
+
+// Post saves an invoice, its lines, and the journal entry which posts it in a single transaction.
+func (r *invoiceRepo) Post(ctx context.Context, invoice *accounting.Invoice, je accounting.JournalEntry) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		return insertInvoice(ctx, tx, invoice, je.ID)
+	})
+}
+
+// Retrieves an invoice, with its lines, by ID
+//
+// Returns ErrInvoiceNotFound if the invoice does not exist.
+func (r *invoiceRepo) ByID(ctx context.Context, id string) (accounting.Invoice, error) {
+	return invoiceByID(ctx, r.db, id)
+}
+
+// Retrieves all invoices, with their lines, ordered by invoice date
+func (r *invoiceRepo) GetAll(ctx context.Context) ([]*accounting.Invoice, error) {
+	const query = `
+		SELECT id, customer_id, number, invoice_date, due_date, terms, receivable_account_name, memo, journal_entry_id
+		FROM invoices
+		ORDER BY invoice_date, id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoices []*accounting.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		invoices = append(invoices, &invoice)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// lines are fetched once the invoice cursor is released
+	for _, invoice := range invoices {
+		if invoice.Lines, err = invoiceLines(ctx, r.db, invoice.ID); err != nil {
+			return nil, err
+		}
+		if invoice.Taxes, err = invoiceTaxes(ctx, r.db, invoice.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return invoices, nil
+}
+
+// RecordPayment saves an invoice payment and the journal entry which posts it in a single transaction.
+//
+// Returns ErrOverpayment if the payment exceeds the invoice's open balance as
+// it stands within the transaction.
+func (r *invoiceRepo) RecordPayment(ctx context.Context, payment *accounting.InvoicePayment, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO invoice_payments
+			(id, invoice_id, payment_date, cash_account_name, amount, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// re-read, lest a payment recorded since the caller's read be overpaid
+		invoice, err := invoiceByID(ctx, tx, payment.InvoiceID)
+		if err != nil {
+			return err
+		}
+		payments, err := invoicePayments(ctx, tx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		if open := invoice.OpenBalance(payments); accounting.RoundCents(payment.Amount) > open {
+			return &accounting.ErrOverpayment{DocumentID: invoice.ID, OpenBalance: open, Amount: payment.Amount}
+		}
+
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			payment.ID,
+			payment.InvoiceID,
+			formatDate(payment.PaymentDate),
+			payment.CashAccountName,
+			payment.Amount,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		payment.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves the payments made against an invoice, ordered by payment date
+func (r *invoiceRepo) Payments(ctx context.Context, invoiceID string) ([]accounting.InvoicePayment, error) {
+	return invoicePayments(ctx, r.db, invoiceID)
+}
+
+// retrieves an invoice, with its lines and tax, by ID
+func invoiceByID(ctx context.Context, q querier, id string) (accounting.Invoice, error) {
+	const query = `
+		SELECT id, customer_id, number, invoice_date, due_date, terms, receivable_account_name, memo, journal_entry_id
+		FROM invoices
+		WHERE id = ?;
+	`
+
+	invoice, err := scanInvoice(q.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Invoice{}, &accounting.ErrInvoiceNotFound{ID: id}
+		}
+		return accounting.Invoice{}, err
+	}
+
+	if invoice.Lines, err = invoiceLines(ctx, q, id); err != nil {
+		return accounting.Invoice{}, err
+	}
+	if invoice.Taxes, err = invoiceTaxes(ctx, q, id); err != nil {
+		return accounting.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// retrieves the payments made against an invoice, ordered by payment date
+func invoicePayments(ctx context.Context, q querier, invoiceID string) ([]accounting.InvoicePayment, error) {
+	const query = `
+		SELECT id, invoice_id, payment_date, cash_account_name, amount, journal_entry_id
+		FROM invoice_payments
+		WHERE invoice_id = ?
+		ORDER BY payment_date, id;
+	`
+
+	rows, err := q.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []accounting.InvoicePayment
+	for rows.Next() {
+		var (
+			payment     accounting.InvoicePayment
+			paymentDate string
+		)
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.InvoiceID,
+			&paymentDate,
+			&payment.CashAccountName,
+			&payment.Amount,
+			&payment.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if payment.PaymentDate, err = parseDate(paymentDate); err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// retrieves the lines of an invoice in order
+func invoiceLines(ctx context.Context, q querier, invoiceID string) ([]accounting.InvoiceLine, error) {
+	const query = `
+		SELECT account_name, description, amount, project_id, tax_code
+		FROM invoice_lines
+		WHERE invoice_id = ?
+		ORDER BY line_no;
+	`
+
+	rows, err := q.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.InvoiceLine
+	for rows.Next() {
+		var line accounting.InvoiceLine
+		var projectID, taxCode sql.NullString
+		if err := rows.Scan(&line.AccountName, &line.Description, &line.Amount, &projectID, &taxCode); err != nil {
+			return nil, err
+		}
+		line.ProjectID = projectID.String
+		line.TaxCode = taxCode.String
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// retrieves the tax charged on an invoice, in line order
+func invoiceTaxes(ctx context.Context, q querier, invoiceID string) ([]accounting.InvoiceTax, error) {
+	const query = `
+		SELECT line_no, rate_id, jurisdiction_id, name, liability_account_name, taxable_amount, amount
+		FROM invoice_taxes
+		WHERE invoice_id = ?
+		ORDER BY line_no, rowid;
+	`
+
+	rows, err := q.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []accounting.InvoiceTax
+	for rows.Next() {
+		var tax accounting.InvoiceTax
+		if err := rows.Scan(
+			&tax.LineNo,
+			&tax.RateID,
+			&tax.JurisdictionID,
+			&tax.Name,
+			&tax.LiabilityAccountName,
+			&tax.TaxableAmount,
+			&tax.Amount,
+		); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, tax)
+	}
+
+	return taxes, rows.Err()
+}
+
+func scanInvoice(s scanner) (accounting.Invoice, error) {
+	var (
+		invoice              accounting.Invoice
+		invoiceDate, dueDate string
+		terms                string
+	)
+
+	if err := s.Scan(
+		&invoice.ID,
+		&invoice.CustomerID,
+		&invoice.Number,
+		&invoiceDate,
+		&dueDate,
+		&terms,
+		&invoice.ReceivableAccountName,
+		&invoice.Memo,
+		&invoice.EntryID,
+	); err != nil {
+		return accounting.Invoice{}, err
+	}
+
+	var err error
+	if invoice.InvoiceDate, err = parseDate(invoiceDate); err != nil {
+		return accounting.Invoice{}, err
+	}
+	if invoice.DueDate, err = parseDate(dueDate); err != nil {
+		return accounting.Invoice{}, err
+	}
+	if invoice.Terms, err = accounting.ParsePaymentTerms(terms); err != nil {
+		return accounting.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// insertInvoice writes an invoice, its lines and their tax within tx, recording the
+// already-inserted journal entry which posts it
+func insertInvoice(ctx context.Context, tx *sql.Tx, invoice *accounting.Invoice, entryID string) error {
+	const invoiceQuery = `
+		INSERT INTO invoices
+			(id, customer_id, number, invoice_date, due_date, terms, receivable_account_name, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	const lineQuery = `
+		INSERT INTO invoice_lines
+			(invoice_id, line_no, account_name, description, amount, project_id, tax_code)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?);
+	`
+	const taxQuery = `
+		INSERT INTO invoice_taxes
+			(invoice_id, line_no, rate_id, jurisdiction_id, name, liability_account_name, taxable_amount, amount)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		invoiceQuery,
+		invoice.ID,
+		invoice.CustomerID,
+		invoice.Number,
+		formatDate(invoice.InvoiceDate),
+		formatDate(invoice.DueDate),
+		invoice.Terms.String(),
+		invoice.ReceivableAccountName,
+		invoice.Memo,
+		entryID,
+	); err != nil {
+		return err
+	}
+
+	for i, line := range invoice.Lines {
+		if _, err := tx.ExecContext(
+			ctx,
+			lineQuery,
+			invoice.ID,
+			i+1,
+			line.AccountName,
+			line.Description,
+			line.Amount,
+			sql.NullString{String: line.ProjectID, Valid: line.ProjectID != ""},
+			sql.NullString{String: line.TaxCode, Valid: line.TaxCode != ""},
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, tax := range invoice.Taxes {
+		if _, err := tx.ExecContext(
+			ctx,
+			taxQuery,
+			invoice.ID,
+			tax.LineNo,
+			tax.RateID,
+			tax.JurisdictionID,
+			tax.Name,
+			tax.LiabilityAccountName,
+			tax.TaxableAmount,
+			tax.Amount,
+		); err != nil {
+			return err
+		}
+	}
+
+	invoice.EntryID = entryID
+	return nil
+}
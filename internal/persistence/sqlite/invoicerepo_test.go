@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+// creates a customer and a posted invoice for use in tests
+func postTestInvoice(t *testing.T, repos *Repositories, amount float64) *accounting.Invoice {
+	t.Helper()
+	ctx := context.Background()
+
+	saveTestAccount(t, repos, "Consulting Revenue", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+	customer, err := accounting.NewCustomer("Acme Corp.", accounting.DefaultPaymentTerms)
+	if err != nil {
+		t.Fatalf("failed to create customer with error %v", err)
+	}
+	if err := repos.Customers.Save(ctx, customer); err != nil {
+		t.Fatalf("failed to save customer with error %v", err)
+	}
+
+	invoice, err := accounting.NewInvoice(
+		customer.ID,
+		"1001",
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		customer.DefaultTerms,
+		"Accounts Receivable",
+		[]accounting.InvoiceLine{{AccountName: "Consulting Revenue", Description: "Advisory", Amount: amount}},
+	)
+	if err != nil {
+		t.Fatalf("failed to create invoice with error %v", err)
+	}
+
+	if err := repos.Invoices.Post(ctx, invoice, invoice.JournalEntry()); err != nil {
+		t.Fatalf("failed to post invoice with error %v", err)
+	}
+
+	return invoice
+}
+
+func TestInvoiceRepo_Post(t *testing.T) {
+	t.Run("saves the invoice with its journal entry", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		invoice := postTestInvoice(t, repos, 1200)
+
+		saved, err := repos.Invoices.ByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve invoice with error %v", err)
+		}
+
+		if saved.Total() != 1200 || saved.CustomerID != invoice.CustomerID || !saved.DueDate.Equal(invoice.DueDate) {
+			t.Fatalf("expected to retrieve %+v, received %+v", invoice, saved)
+		}
+
+		if _, err := repos.JournalEntries.ByID(ctx, saved.EntryID); err != nil {
+			t.Fatalf("expected the invoice's journal entry to exist, received %v", err)
+		}
+	})
+}
+
+func TestInvoiceRepo_RecordPayment(t *testing.T) {
+	t.Run("records a partial payment", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Checking", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		invoice := postTestInvoice(t, repos, 1200)
+
+		payment, err := accounting.NewInvoicePayment(invoice, 1200, invoice.InvoiceDate.AddDate(0, 0, 20), "Checking", 700)
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+
+		if err := repos.Invoices.RecordPayment(ctx, payment, payment.JournalEntry(invoice)); err != nil {
+			t.Fatalf("failed to record payment with error %v", err)
+		}
+
+		payments, err := repos.Invoices.Payments(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve payments with error %v", err)
+		}
+
+		if open := invoice.OpenBalance(payments); open != 500 {
+			t.Fatalf("expected an open balance of 500, found %v", open)
+		}
+	})
+
+	t.Run("rejects a payment made against a stale open balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Checking", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		invoice := postTestInvoice(t, repos, 1200)
+		paidAt := invoice.InvoiceDate.AddDate(0, 0, 20)
+
+		// both read the open balance before either is recorded
+		first, err := accounting.NewInvoicePayment(invoice, 1200, paidAt, "Checking", 700)
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+		second, err := accounting.NewInvoicePayment(invoice, 1200, paidAt, "Checking", 700)
+		if err != nil {
+			t.Fatalf("failed to create payment with error %v", err)
+		}
+
+		if err := repos.Invoices.RecordPayment(ctx, first, first.JournalEntry(invoice)); err != nil {
+			t.Fatalf("failed to record payment with error %v", err)
+		}
+		if err := repos.Invoices.RecordPayment(ctx, second, second.JournalEntry(invoice)); !accounting.IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error, received %v", err)
+		}
+
+		payments, err := repos.Invoices.Payments(ctx, invoice.ID)
+		if err != nil || len(payments) != 1 {
+			t.Fatalf("expected only the first payment recorded, received %v and %v", payments, err)
+		}
+	})
+}
@@ -0,0 +1,556 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"time"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type journalEntryRepo struct {
+	db *sql.DB
+}
+
+// NewJournalEntryRepo reads the entries of a database opened with Open or
+// OpenReadOnly, as it stands, without chaining any left unhashed
+func NewJournalEntryRepo(db *sql.DB) accounting.JournalEntryRepository {
+	return &journalEntryRepo{db: db}
+}
+
+// Save validates and posts a journal entry and its lines in a single transaction.
+//
+// Returns ErrUnbalancedEntry or ErrInvalidEntry if the entry cannot be posted.
+func (r *journalEntryRepo) Save(ctx context.Context, je accounting.JournalEntry) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		return insertJournalEntry(ctx, tx, je)
+	})
+}
+
+// Retrieves a journal entry, with its lines, by ID
+//
+// Returns ErrEntryNotFound if the entry does not exist.
+func (r *journalEntryRepo) ByID(ctx context.Context, id string) (accounting.JournalEntry, error) {
+	link, err := chainLinkByID(ctx, r.db, id)
+	return link.Entry, err
+}
+
+// Retrieves every posted entry with its stored hashes, in ID order
+func (r *journalEntryRepo) Chain(ctx context.Context) ([]accounting.ChainLink, error) {
+	const entryQuery = `
+		SELECT id, timestamp, description, cross_reference, prev_hash, hash
+		FROM journal_entries
+		ORDER BY id;
+	`
+	const lineQuery = `
+		SELECT journal_entry_id, account_name, amount, side, project_id
+		FROM journal_lines
+		ORDER BY journal_entry_id, rowid;
+	`
+
+	rows, err := r.db.QueryContext(ctx, entryQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		links []accounting.ChainLink
+		index = make(map[string]int)
+	)
+	for rows.Next() {
+		link, err := scanChainLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[link.Entry.ID] = len(links)
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lineRows, err := r.db.QueryContext(ctx, lineQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var (
+			entryID   string
+			line      accounting.JournalEntryLine
+			projectID sql.NullString
+		)
+		if err := lineRows.Scan(&entryID, &line.AccountName, &line.Amount, &line.Side, &projectID); err != nil {
+			return nil, err
+		}
+		line.ProjectID = projectID.String
+
+		if i, ok := index[entryID]; ok {
+			links[i].Entry.Lines = append(links[i].Entry.Lines, line)
+		}
+	}
+
+	return links, lineRows.Err()
+}
+
+// Sums debits and credits per account for entries dated within [from, to)
+func (r *journalEntryRepo) AccountTotals(ctx context.Context, from, to time.Time) ([]accounting.AccountTotal, error) {
+	return accountTotals(ctx, r.db, from, to)
+}
+
+func accountTotals(ctx context.Context, q querier, from, to time.Time) ([]accounting.AccountTotal, error) {
+	const query = `
+		SELECT
+			l.account_name,
+			COALESCE(SUM(CASE WHEN l.side = 'Debit' THEN l.amount END), 0),
+			COALESCE(SUM(CASE WHEN l.side = 'Credit' THEN l.amount END), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.journal_entry_id
+		WHERE e.timestamp >= ? AND e.timestamp < ?
+		GROUP BY l.account_name
+		ORDER BY l.account_name;
+	`
+
+	rows, err := q.QueryContext(ctx, query, formatTimestamp(from), formatTimestamp(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []accounting.AccountTotal
+	for rows.Next() {
+		var total accounting.AccountTotal
+		if err := rows.Scan(&total.AccountName, &total.Debits, &total.Credits); err != nil {
+			return nil, err
+		}
+		total.Debits = accounting.RoundCents(total.Debits)
+		total.Credits = accounting.RoundCents(total.Credits)
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// Retrieves the lines tagged with a project on entries dated within [from, to),
+// each with the kind of document which posted it
+func (r *journalEntryRepo) ProjectLines(ctx context.Context, from, to time.Time) ([]accounting.ProjectLine, error) {
+	const query = `
+		SELECT
+			e.id,
+			e.timestamp,
+			e.description,
+			l.account_name,
+			a.account_type,
+			l.amount,
+			l.side,
+			l.project_id,
+			CASE
+				WHEN EXISTS (SELECT 1 FROM time_entries t WHERE t.journal_entry_id = e.id) THEN 'Time Entry'
+				WHEN EXISTS (SELECT 1 FROM unit_sales s WHERE s.journal_entry_id = e.id) THEN 'Unit Sale'
+				WHEN EXISTS (SELECT 1 FROM stock_movements m WHERE m.journal_entry_id = e.id) THEN 'Stock Movement'
+				WHEN EXISTS (SELECT 1 FROM stock_cost_adjustments c WHERE c.journal_entry_id = e.id) THEN 'Stock Cost Adjustment'
+				WHEN EXISTS (SELECT 1 FROM invoices i WHERE i.journal_entry_id = e.id) THEN 'Invoice'
+				WHEN EXISTS (SELECT 1 FROM bills b WHERE b.journal_entry_id = e.id) THEN 'Bill'
+				ELSE 'Journal Entry'
+			END
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.journal_entry_id
+		JOIN accounts a ON a.name = l.account_name
+		WHERE l.project_id IS NOT NULL AND e.timestamp >= ? AND e.timestamp < ?
+		ORDER BY e.timestamp, e.id, l.rowid;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, formatTimestamp(from), formatTimestamp(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.ProjectLine
+	for rows.Next() {
+		var (
+			line        accounting.ProjectLine
+			timestamp   string
+			description sql.NullString
+		)
+		if err := rows.Scan(
+			&line.EntryID,
+			&timestamp,
+			&description,
+			&line.AccountName,
+			&line.AccountType,
+			&line.Amount,
+			&line.Side,
+			&line.ProjectID,
+			&line.Source,
+		); err != nil {
+			return nil, err
+		}
+
+		if line.Date, err = parseTimestamp(timestamp); err != nil {
+			return nil, err
+		}
+		line.Description = description.String
+
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// utility type satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	rowQuerier
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// retrieves a journal entry, with its lines and stored hashes, by ID
+func chainLinkByID(ctx context.Context, q querier, id string) (accounting.ChainLink, error) {
+	const query = `
+		SELECT id, timestamp, description, cross_reference, prev_hash, hash
+		FROM journal_entries
+		WHERE id = ?;
+	`
+
+	link, err := scanChainLink(q.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.ChainLink{}, &accounting.ErrEntryNotFound{ID: id}
+		}
+		return accounting.ChainLink{}, err
+	}
+
+	if link.Entry.Lines, err = entryLines(ctx, q, id); err != nil {
+		return accounting.ChainLink{}, err
+	}
+
+	return link, nil
+}
+
+func scanChainLink(s scanner) (accounting.ChainLink, error) {
+	var (
+		link        accounting.ChainLink
+		timestamp   string
+		description sql.NullString
+	)
+
+	if err := s.Scan(&link.Entry.ID, &timestamp, &description, &link.Entry.CrossReference, &link.PrevHash, &link.Hash); err != nil {
+		return accounting.ChainLink{}, err
+	}
+
+	var err error
+	if link.Entry.Timestamp, err = parseTimestamp(timestamp); err != nil {
+		return accounting.ChainLink{}, err
+	}
+	link.Entry.Description = description.String
+
+	return link, nil
+}
+
+// retrieves the lines of a journal entry in the order they were written
+func entryLines(ctx context.Context, q querier, entryID string) ([]accounting.JournalEntryLine, error) {
+	const query = `
+		SELECT account_name, amount, side, project_id
+		FROM journal_lines
+		WHERE journal_entry_id = ?
+		ORDER BY rowid;
+	`
+
+	rows, err := q.QueryContext(ctx, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.JournalEntryLine
+	for rows.Next() {
+		var (
+			line      accounting.JournalEntryLine
+			projectID sql.NullString
+		)
+		if err := rows.Scan(&line.AccountName, &line.Amount, &line.Side, &projectID); err != nil {
+			return nil, err
+		}
+		line.ProjectID = projectID.String
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// insertJournalEntry validates and writes an entry within an open transaction,
+// so that documents which post entries can be saved atomically with them,
+// and records its posting in the audit trail.
+func insertJournalEntry(ctx context.Context, tx *sql.Tx, je accounting.JournalEntry) error {
+	const entryQuery = `
+		INSERT INTO journal_entries
+			(id, timestamp, description, cross_reference)
+		VALUES
+			(?, ?, ?, ?);
+	`
+	const lineQuery = `
+		INSERT INTO journal_lines
+			(id, account_name, amount, side, journal_entry_id, project_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+
+	if err := accounting.ValidateJournalEntry(je); err != nil {
+		return err
+	}
+
+	if err := checkYearOpen(ctx, tx, je); err != nil {
+		return err
+	}
+
+	if err := checkPeriodOpen(ctx, tx, je); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, entryQuery, je.ID, formatTimestamp(je.Timestamp), je.Description, je.CrossReference); err != nil {
+		return err
+	}
+
+	for _, line := range je.Lines {
+		if _, err := tx.ExecContext(
+			ctx,
+			lineQuery,
+			accounting.NewID(),
+			line.AccountName,
+			accounting.RoundCents(line.Amount),
+			line.Side,
+			je.ID,
+			sql.NullString{String: line.ProjectID, Valid: line.ProjectID != ""},
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := chainEntry(ctx, tx, je.ID); err != nil {
+		return err
+	}
+
+	return insertAuditRecord(ctx, tx, accounting.AuditPost, accounting.JournalEntryEntity, je.ID, nil, je)
+}
+
+// chainEntry links a newly written entry into the hash chain at its place in
+// ID order. Entries are nearly always posted in that order; any posted since
+// this one's ID was generated are checked, then relinked after it.
+//
+// Returns ErrChainBroken if an entry to be relinked no longer matches its hashes.
+func chainEntry(ctx context.Context, tx *sql.Tx, id string) error {
+	prevHash, err := hashBefore(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	later, err := idsAfter(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	// the hash the first later entry was linked to before this one arrived
+	oldPrevHash := prevHash
+	for i, linkID := range append([]string{id}, later...) {
+		link, err := chainLinkByID(ctx, tx, linkID)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if broken := link.Verify(oldPrevHash); broken != nil {
+				return broken
+			}
+			oldPrevHash = link.Hash
+		}
+
+		if prevHash, err = setEntryHash(ctx, tx, link.Entry, prevHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkYearOpen returns ErrYearClosed if the entry is dated within a closed fiscal year
+func checkYearOpen(ctx context.Context, tx *sql.Tx, je accounting.JournalEntry) error {
+	const query = `
+		SELECT year_end
+		FROM year_end_closes
+		WHERE reopened_at IS NULL AND ? BETWEEN year_start AND year_end
+		LIMIT 1;
+	`
+
+	var yearEnd string
+	err := tx.QueryRowContext(ctx, query, formatDate(je.Timestamp.UTC())).Scan(&yearEnd)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	closedYearEnd, err := parseDate(yearEnd)
+	if err != nil {
+		return err
+	}
+
+	return &accounting.ErrYearClosed{EntryID: je.ID, Date: je.Timestamp, YearEnd: closedYearEnd}
+}
+
+// checkPeriodOpen returns ErrPeriodClosed if the entry is dated within a
+// closed period, or within a soft-closed one and ctx may not post there
+func checkPeriodOpen(ctx context.Context, tx *sql.Tx, je accounting.JournalEntry) error {
+	const query = `
+		SELECT start_date, end_date, status
+		FROM accounting_periods
+		WHERE ? BETWEEN start_date AND end_date
+		LIMIT 1;
+	`
+
+	var (
+		start, end string
+		status     accounting.PeriodStatus
+	)
+	err := tx.QueryRowContext(ctx, query, formatDate(je.Timestamp.UTC())).Scan(&start, &end, &status)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if status == accounting.PeriodOpen || (status == accounting.PeriodSoftClosed && accounting.SoftClosedPostingsAllowed(ctx)) {
+		return nil
+	}
+
+	closed := &accounting.ErrPeriodClosed{EntryID: je.ID, Date: je.Timestamp, Status: status}
+	if closed.PeriodStart, err = parseDate(start); err != nil {
+		return err
+	}
+	if closed.PeriodEnd, err = parseDate(end); err != nil {
+		return err
+	}
+
+	return closed
+}
+
+// runs fn within a transaction, committing if it succeeds and rolling back otherwise
+func withTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// the hash of the entry before the given ID, or empty if it would be the first
+func hashBefore(ctx context.Context, tx *sql.Tx, id string) (string, error) {
+	const query = `
+		SELECT hash
+		FROM journal_entries
+		WHERE id < ?
+		ORDER BY id DESC
+		LIMIT 1;
+	`
+
+	var hash string
+	err := tx.QueryRowContext(ctx, query, id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+// the IDs of the entries after the given ID, in order
+func idsAfter(ctx context.Context, tx *sql.Tx, id string) ([]string, error) {
+	const query = `
+		SELECT id
+		FROM journal_entries
+		WHERE id > ?
+		ORDER BY id;
+	`
+
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// links the entry, as stored, to the previous entry's hash, returning its own
+func setEntryHash(ctx context.Context, tx *sql.Tx, je accounting.JournalEntry, prevHash string) (string, error) {
+	const query = `
+		UPDATE journal_entries
+		SET prev_hash = ?, hash = ?
+		WHERE id = ?;
+	`
+
+	hash := accounting.EntryHash(je, prevHash)
+	if _, err := tx.ExecContext(ctx, query, prevHash, hash, je.ID); err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// chainUnhashedEntries links every entry into the hash chain for books with
+// entries posted before entries were hashed, then marks the books chained so
+// that it is done only once. Entries found unhashed in books marked chained
+// are left for VerifyChain to report, never rechained over.
+func chainUnhashedEntries(ctx context.Context, db *sql.DB) error {
+	return withTx(ctx, db, func(tx *sql.Tx) error {
+		var chained bool
+		const chainedQuery = `SELECT EXISTS (SELECT 1 FROM entry_chain_state);`
+		if err := tx.QueryRowContext(ctx, chainedQuery).Scan(&chained); err != nil {
+			return err
+		}
+		if chained {
+			return nil
+		}
+
+		ids, err := idsAfter(ctx, tx, "")
+		if err != nil {
+			return err
+		}
+
+		prevHash := ""
+		for _, id := range ids {
+			link, err := chainLinkByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if prevHash, err = setEntryHash(ctx, tx, link.Entry, prevHash); err != nil {
+				return err
+			}
+		}
+
+		const markQuery = `INSERT INTO entry_chain_state (id, chained_at) VALUES (1, ?);`
+		_, err = tx.ExecContext(ctx, markQuery, formatTimestamp(time.Now()))
+		return err
+	})
+}
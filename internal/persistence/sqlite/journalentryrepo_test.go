@@ -0,0 +1,284 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite/migrate"
+)
+
+// saves an account for use in tests
+func saveTestAccount(t *testing.T, repos *Repositories, name string, group string, accountType accounting.AccountType, normal accounting.NormalBalance) {
+	t.Helper()
+
+	err := repos.Accounts.Save(context.Background(), &accounting.Account{
+		Name:            name,
+		ParentGroupName: group,
+		AccountType:     accountType,
+		NormalBalance:   normal,
+		DisplayAfter:    sql.NullString{},
+	})
+	if err != nil {
+		t.Fatalf("failed to save account %s with error %v", name, err)
+	}
+}
+
+func TestJournalEntryRepo_Save(t *testing.T) {
+	t.Run("saves and retrieves a balanced entry", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		je := accounting.JournalEntry{
+			ID:          accounting.NewID(),
+			Timestamp:   time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Description: "Owner contribution",
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 1000, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: 1000, Side: accounting.Credit},
+			},
+		}
+
+		if err := repos.JournalEntries.Save(ctx, je); err != nil {
+			t.Fatalf("failed to save journal entry with error %v", err)
+		}
+
+		saved, err := repos.JournalEntries.ByID(ctx, je.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve journal entry with error %v", err)
+		}
+
+		if !saved.Timestamp.Equal(je.Timestamp) || saved.Description != je.Description || len(saved.Lines) != 2 {
+			t.Fatalf("expected to retrieve %+v, received %+v", je, saved)
+		}
+	})
+
+	t.Run("rejects an unbalanced entry without writing it", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		je := accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: time.Now(),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 1000, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: 900, Side: accounting.Credit},
+			},
+		}
+
+		if err := repos.JournalEntries.Save(ctx, je); !accounting.IsUnbalancedEntry(err) {
+			t.Fatalf("expected an UnbalancedEntry error, received %v", err)
+		}
+
+		if _, err := repos.JournalEntries.ByID(ctx, je.ID); !accounting.IsEntryNotFound(err) {
+			t.Fatalf("expected an EntryNotFound error, received %v", err)
+		}
+	})
+
+	t.Run("rolls back the entry when a line references a missing account", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		je := accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: time.Now(),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Retained Earnings", Amount: 10, Side: accounting.Debit},
+				{AccountName: "Nonexistent Account", Amount: 10, Side: accounting.Credit},
+			},
+		}
+
+		if err := repos.JournalEntries.Save(ctx, je); err == nil {
+			t.Fatalf("expected an error, didn't receive one")
+		}
+
+		if _, err := repos.JournalEntries.ByID(ctx, je.ID); !accounting.IsEntryNotFound(err) {
+			t.Fatalf("expected an EntryNotFound error, received %v", err)
+		}
+	})
+}
+
+func TestJournalEntryRepo_AccountTotals(t *testing.T) {
+	t.Run("totals only entries within the range", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		for _, day := range []int{1, 15, 31} {
+			je := accounting.JournalEntry{
+				ID:        accounting.NewID(),
+				Timestamp: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+				Lines: []accounting.JournalEntryLine{
+					{AccountName: "Cash", Amount: 100, Side: accounting.Debit},
+					{AccountName: "Retained Earnings", Amount: 100, Side: accounting.Credit},
+				},
+			}
+			if err := repos.JournalEntries.Save(ctx, je); err != nil {
+				t.Fatalf("failed to save journal entry with error %v", err)
+			}
+		}
+
+		totals, err := repos.JournalEntries.AccountTotals(ctx,
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		)
+		if err != nil {
+			t.Fatalf("failed to total accounts with error %v", err)
+		}
+
+		for _, total := range totals {
+			if total.AccountName == "Cash" && total.Balance(accounting.DebitNormal) != 200 {
+				t.Fatalf("expected a Cash balance of 200, got %v", total.Balance(accounting.DebitNormal))
+			}
+		}
+	})
+}
+
+func TestJournalEntryRepo_Chain(t *testing.T) {
+	ctx := context.Background()
+	entry := func(id string, amount float64) accounting.JournalEntry {
+		return accounting.JournalEntry{
+			ID:        id,
+			Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: amount, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: amount, Side: accounting.Credit},
+			},
+		}
+	}
+	verify := func(t *testing.T, repos *Repositories) accounting.ChainReport {
+		t.Helper()
+		links, err := repos.JournalEntries.Chain(ctx)
+		if err != nil {
+			t.Fatalf("failed to get chain with error %v", err)
+		}
+		return accounting.VerifyChain(links)
+	}
+
+	t.Run("chains entries in ID order, whatever order they are posted in", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		first, second, third := accounting.NewID(), accounting.NewID(), accounting.NewID()
+		for _, je := range []accounting.JournalEntry{entry(first, 10), entry(third, 30), entry(second, 20)} {
+			if err := repos.JournalEntries.Save(ctx, je); err != nil {
+				t.Fatalf("failed to save journal entry with error %v", err)
+			}
+		}
+
+		links, err := repos.JournalEntries.Chain(ctx)
+		if err != nil {
+			t.Fatalf("failed to get chain with error %v", err)
+		}
+		if len(links) != 3 || links[0].Entry.ID != first || links[1].Entry.ID != second || links[2].Entry.ID != third {
+			t.Fatalf("expected the entries in ID order, got %+v", links)
+		}
+		if report := accounting.VerifyChain(links); !report.Intact() || report.Entries != 3 {
+			t.Fatalf("expected an intact chain of 3, got %+v", report)
+		}
+	})
+
+	t.Run("reports an entry edited directly in the database", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		ids := []string{accounting.NewID(), accounting.NewID(), accounting.NewID()}
+		for _, id := range ids {
+			if err := repos.JournalEntries.Save(ctx, entry(id, 10)); err != nil {
+				t.Fatalf("failed to save journal entry with error %v", err)
+			}
+		}
+
+		db := repos.JournalEntries.(*journalEntryRepo).db
+		if _, err := db.Exec(`UPDATE journal_lines SET amount = 1 WHERE journal_entry_id = ?`, ids[1]); err != nil {
+			t.Fatalf("failed to tamper with entry with error %v", err)
+		}
+
+		report := verify(t, repos)
+		if report.Intact() || report.Break.EntryID != ids[1] {
+			t.Fatalf("expected the edited entry to break the chain, got %+v", report)
+		}
+
+		// nor will posting before it relink over the edit
+		if err := repos.JournalEntries.Save(ctx, entry("00000000000000000000000001", 10)); !accounting.IsChainBroken(err) {
+			t.Fatalf("expected ErrChainBroken, received %v", err)
+		}
+	})
+
+	t.Run("chains books which predate hashing once, and never again", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "books.db")
+		db, err := Open(path)
+		if err != nil {
+			t.Fatalf("failed to open database with error %v", err)
+		}
+		if err := migrate.UpTo(db, 20); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+		for _, query := range []string{
+			`INSERT INTO journal_entries (id, timestamp, description) VALUES ('01JA0000000000000000000001', '2025-01-02T00:00:00Z', 'Sale')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('l-1', 'Retained Earnings', 10, 'Debit', '01JA0000000000000000000001')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('l-2', 'Accounts Payable', 10, 'Credit', '01JA0000000000000000000001')`,
+			`INSERT INTO journal_entries (id, timestamp, description) VALUES ('01JA0000000000000000000002', '2025-01-03T00:00:00Z', 'Sale')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('l-3', 'Retained Earnings', 20, 'Debit', '01JA0000000000000000000002')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('l-4', 'Accounts Payable', 20, 'Credit', '01JA0000000000000000000002')`,
+		} {
+			if _, err := db.Exec(query); err != nil {
+				t.Fatalf("failed to post with error %v", err)
+			}
+		}
+		db.Close()
+
+		repos, err := New(path)
+		if err != nil {
+			t.Fatalf("failed to open books with error %v", err)
+		}
+		if report := verify(t, repos); !report.Intact() || report.Entries != 2 {
+			t.Fatalf("expected an intact chain of 2, got %+v", report)
+		}
+
+		// hashes cleared once chained are a break, not books to chain afresh
+		db = repos.JournalEntries.(*journalEntryRepo).db
+		if _, err := db.Exec(`UPDATE journal_entries SET prev_hash = '', hash = ''`); err != nil {
+			t.Fatalf("failed to clear hashes with error %v", err)
+		}
+		repos.Close()
+
+		repos, err = New(path)
+		if err != nil {
+			t.Fatalf("failed to reopen books with error %v", err)
+		}
+		t.Cleanup(func() { repos.Close() })
+		if report := verify(t, repos); report.Intact() || report.Break.EntryID != "01JA0000000000000000000001" {
+			t.Fatalf("expected the cleared hashes to break the chain, got %+v", report)
+		}
+	})
+}
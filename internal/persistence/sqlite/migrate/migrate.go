@@ -3,35 +3,286 @@ package migrate
 import (
 	"database/sql"
 	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	msqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 //go:embed sql/*.sql
 var migrations embed.FS
 
+// what undoing a migration destroys of the posted books
+type journalRisk struct {
+	// FROM clauses selecting the rows the down migration drops or strips:
+	// entries, their lines, the records which post them or link to them, and
+	// the history of closing and auditing them
+	Rows []string
+	// the seeded accounts the down migration deletes, which entries may post to
+	Accounts []string
+}
+
+// journalRisks declares what each migration's down migration destroys of the
+// posted books. Every migration is declared, even if it destroys nothing, so
+// that each new one is weighed as it is written; the tests enforce this.
+var journalRisks = map[uint]journalRisk{
+	1: {Rows: []string{"journal_entries"}},
+	// every account hangs from the base groups
+	2: {Rows: []string{"journal_lines"}},
+	3: {Accounts: []string{"Retained Earnings"}},
+	4: {Rows: []string{"bills", "bill_payments"}, Accounts: []string{"Accounts Payable", "Purchase Discounts"}},
+	5: {Rows: []string{"invoices", "invoice_payments"}, Accounts: []string{"Accounts Receivable"}},
+	6: {Rows: []string{"attachments"}},
+	7: {Rows: []string{"journal_lines WHERE account_name IN (SELECT name FROM accounts WHERE account_type = 'Contra Asset')"}},
+	8: {
+		Rows:     []string{"depreciation_postings", "fixed_asset_disposals"},
+		Accounts: []string{"Accumulated Depreciation", "Depreciation Expense", "Gain or Loss on Disposal of Assets"},
+	},
+	9:  {Rows: []string{"inventory_units", "unit_sales"}, Accounts: []string{"Inventory", "Cost of Goods Sold"}},
+	10: {Rows: []string{"stock_movements", "stock_cost_adjustments"}},
+	11: {Rows: []string{"inventory_counts WHERE journal_entry_id IS NOT NULL"}, Accounts: []string{"Inventory Shrinkage"}},
+	12: {
+		Rows:     []string{"time_entries WHERE journal_entry_id IS NOT NULL", "journal_lines WHERE project_id IS NOT NULL"},
+		Accounts: []string{"Work in Process", "Direct Labor", "Accrued Payroll"},
+	},
+	13: {Rows: []string{"year_end_closes", "year_end_audit"}, Accounts: []string{"Income Summary"}},
+	14: {Rows: []string{"period_status_changes"}},
+	15: {Rows: []string{"se_tax_reserve_transfers", "estimated_tax_payments"}, Accounts: []string{"Tax Reserve", "Owner Draws"}},
+	16: {},
+	17: {Rows: []string{"invoice_taxes", "sales_tax_remittances"}},
+	18: {},
+	19: {},
+	20: {Rows: []string{"audit_log"}},
+	21: {Rows: []string{"journal_entries WHERE hash <> ''"}},
+	22: {Rows: []string{"period_balances"}},
+	// unmarked books holding entries would be rechained on opening
+	23: {Rows: []string{"entry_chain_state WHERE EXISTS (SELECT 1 FROM journal_entries)"}},
+}
+
 func Up(db *sql.DB) error {
-	drv, err := msqlite.WithInstance(db, &msqlite.Config{})
+	m, _, err := newMigrate(db)
 	if err != nil {
 		return err
 	}
 
-	src, err := iofs.New(migrations, "sql")
+	// idempotent: if already at latest, this is a no-op
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// the schema version of a database and the migrations not yet applied to it
+type Status struct {
+	Version uint // 0 if no migration has been applied
+	Dirty   bool // a migration failed part way; see Force
+	Latest  uint
+	Pending []uint
+}
+
+// GetStatus reports the database's schema version against the migrations
+func GetStatus(db *sql.DB) (Status, error) {
+	m, src, err := newMigrate(db)
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	status.Version, status.Dirty, err = m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return Status{}, err
+	}
+
+	versions, err := sourceVersions(src)
+	if err != nil {
+		return Status{}, err
+	}
+	for _, v := range versions {
+		if v > status.Version {
+			status.Pending = append(status.Pending, v)
+		}
+	}
+	status.Latest = versions[len(versions)-1]
+
+	return status, nil
+}
+
+// UpTo applies the pending migrations up to and including version
+func UpTo(db *sql.DB, version uint) error {
+	status, err := GetStatus(db)
 	if err != nil {
 		return err
 	}
+	if version <= status.Version {
+		return fmt.Errorf("the database is already at version %d; migrate down to reach %d", status.Version, version)
+	}
 
-	m, err := migrate.NewWithInstance("iofs", src, "sqlite3", drv)
+	m, _, err := newMigrate(db)
 	if err != nil {
 		return err
 	}
 
-	// idempotent: if already at latest, this is a no-op
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+	return m.Migrate(version)
+}
+
+// DownTo undoes the migrations applied after version; to 0 undoes them all.
+// Undoing a migration which would destroy posted records the books hold, per
+// journalRisks, is refused with ErrJournalAtRisk unless destroyJournal.
+func DownTo(db *sql.DB, version uint, destroyJournal bool) error {
+	if err := CheckDown(db, version, destroyJournal); err != nil {
 		return err
 	}
 
-	return nil
+	m, _, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	if version == 0 {
+		return m.Down()
+	}
+	return m.Migrate(version)
+}
+
+// CheckDown reports why DownTo would refuse to migrate down to version, if it would
+func CheckDown(db *sql.DB, version uint, destroyJournal bool) error {
+	status, err := GetStatus(db)
+	if err != nil {
+		return err
+	}
+	if version >= status.Version {
+		return fmt.Errorf("the database is at version %d; migrate up to reach %d", status.Version, version)
+	}
+	if destroyJournal {
+		return nil
+	}
+
+	return checkJournalUndone(db, version, status.Version)
+}
+
+// Force records the database as at version and clean, without running any
+// migration, once a failed migration has been repaired by hand; -1 records
+// that none has been applied
+func Force(db *sql.DB, version int) error {
+	m, _, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	return m.Force(version)
+}
+
+// reports ErrJournalAtRisk if the books hold posted records which undoing
+// the migrations in (to, from] would destroy
+func checkJournalUndone(db *sql.DB, to, from uint) error {
+	var atRisk []uint
+	for v := to + 1; v <= from; v++ {
+		risk, ok := journalRisks[v]
+		if !ok {
+			return fmt.Errorf("migration %d does not declare what undoing it destroys of the posted books", v)
+		}
+
+		held, err := risk.held(db)
+		if err != nil {
+			return fmt.Errorf("failed to check what undoing migration %d destroys: %w", v, err)
+		}
+		if held {
+			atRisk = append(atRisk, v)
+		}
+	}
+	if len(atRisk) == 0 {
+		return nil
+	}
+
+	return &ErrJournalAtRisk{Versions: atRisk}
+}
+
+// reports whether the books hold any of the rows, or lines posted to any of
+// the accounts, at risk
+func (r journalRisk) held(db *sql.DB) (bool, error) {
+	queries := make([]string, 0, len(r.Rows)+1)
+	var args []any
+	for _, from := range r.Rows {
+		queries = append(queries, "SELECT EXISTS (SELECT 1 FROM "+from+")")
+	}
+	if len(r.Accounts) > 0 {
+		queries = append(queries, "SELECT EXISTS (SELECT 1 FROM journal_lines WHERE account_name IN (?"+strings.Repeat(", ?", len(r.Accounts)-1)+"))")
+		for _, name := range r.Accounts {
+			args = append(args, name)
+		}
+	}
+
+	for _, query := range queries {
+		var held bool
+		if err := db.QueryRow(query, args...).Scan(&held); err != nil {
+			return false, err
+		}
+		if held {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// lists the versions of the migrations, in order
+func sourceVersions(src source.Driver) ([]uint, error) {
+	v, err := src.First()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{v}
+	for {
+		v, err = src.Next(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+}
+
+func newMigrate(db *sql.DB) (*migrate.Migrate, source.Driver, error) {
+	drv, err := msqlite.WithInstance(db, &msqlite.Config{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create sqlite migration driver: %w", err)
+	}
+
+	src, err := iofs.New(migrations, "sql")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create source driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite3", drv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return m, src, nil
+}
+
+// returned on migrating down past migrations which would destroy posted
+// journal entries or the records linked to them
+type ErrJournalAtRisk struct {
+	Versions []uint
+}
+
+func (e *ErrJournalAtRisk) Error() string {
+	return fmt.Sprintf("undoing migrations %v would destroy posted journal entries or the records linked to them; confirm with -destroy-journal", e.Versions)
+}
+
+// --------- helper utilities ------------
+
+func IsJournalAtRisk(err error) bool {
+	_, ok := err.(*ErrJournalAtRisk)
+	return ok
 }
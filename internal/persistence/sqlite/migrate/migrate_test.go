@@ -0,0 +1,160 @@
+package migrate
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+)
+
+func TestMigrate(t *testing.T) {
+	newDB := func(t *testing.T) *sql.DB {
+		t.Helper()
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatalf("failed to open database with error %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+
+	t.Run("reports the version and pending migrations", func(t *testing.T) {
+		db := newDB(t)
+
+		status, err := GetStatus(db)
+		if err != nil {
+			t.Fatalf("failed to get status with error %v", err)
+		}
+		if status.Version != 0 || status.Dirty || len(status.Pending) != int(status.Latest) || status.Pending[0] != 1 {
+			t.Fatalf("expected every migration pending, got %+v", status)
+		}
+
+		if err := UpTo(db, 3); err != nil {
+			t.Fatalf("failed to migrate up with error %v", err)
+		}
+		status, err = GetStatus(db)
+		if err != nil {
+			t.Fatalf("failed to get status with error %v", err)
+		}
+		if status.Version != 3 || len(status.Pending) != int(status.Latest)-3 || status.Pending[0] != 4 {
+			t.Fatalf("expected version 3 with the rest pending, got %+v", status)
+		}
+
+		if err := UpTo(db, 2); err == nil {
+			t.Fatal("expected an error migrating up to an earlier version")
+		}
+	})
+
+	t.Run("migrates down, refusing to destroy posted records unless confirmed", func(t *testing.T) {
+		db := newDB(t)
+		if err := Up(db); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+
+		// undoing the audit log of books with nothing audited destroys nothing
+		if err := DownTo(db, 19, false); err != nil {
+			t.Fatalf("failed to migrate down with error %v", err)
+		}
+		if err := Up(db); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+
+		for _, query := range []string{
+			`INSERT INTO journal_entries (id, timestamp, description, hash) VALUES ('je-1', '2025-01-02T00:00:00Z', 'posted', 'abc')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('jl-1', 'Retained Earnings', 100, 'Debit', 'je-1')`,
+			`INSERT INTO journal_lines (id, account_name, amount, side, journal_entry_id) VALUES ('jl-2', 'Accounts Payable', 100, 'Credit', 'je-1')`,
+			`INSERT INTO vendors (id, name) VALUES ('v-1', 'Paper Co.')`,
+			`INSERT INTO bills (id, vendor_id, bill_date, due_date, terms, payable_account_name, journal_entry_id) VALUES ('b-1', 'v-1', '2025-01-02', '2025-02-01', 'Net 30', 'Accounts Payable', 'je-1')`,
+		} {
+			if _, err := db.Exec(query); err != nil {
+				t.Fatalf("failed to post with error %v", err)
+			}
+		}
+
+		err := DownTo(db, 11, false)
+		if !IsJournalAtRisk(err) {
+			t.Fatalf("expected ErrJournalAtRisk, got %v", err)
+		}
+		if versions := err.(*ErrJournalAtRisk).Versions; len(versions) != 2 || versions[0] != 21 || versions[1] != 23 {
+			t.Fatalf("expected migrations 21 and 23 at risk, got %v", versions)
+		}
+
+		// the bill, and the accounts its entry posts to, are at risk too
+		err = DownTo(db, 2, false)
+		if !IsJournalAtRisk(err) {
+			t.Fatalf("expected ErrJournalAtRisk, got %v", err)
+		}
+		if versions := err.(*ErrJournalAtRisk).Versions; len(versions) != 4 || versions[0] != 3 || versions[1] != 4 || versions[2] != 21 || versions[3] != 23 {
+			t.Fatalf("expected migrations 3, 4, 21 and 23 at risk, got %v", versions)
+		}
+
+		if err := DownTo(db, 11, true); err != nil {
+			t.Fatalf("failed to migrate down with error %v", err)
+		}
+		status, err := GetStatus(db)
+		if err != nil {
+			t.Fatalf("failed to get status with error %v", err)
+		}
+		if status.Version != 11 || status.Dirty {
+			t.Fatalf("expected a clean version 11, got %+v", status)
+		}
+
+		if err := DownTo(db, 11, true); err == nil {
+			t.Fatal("expected an error migrating down to the current version")
+		}
+	})
+
+	t.Run("declares what undoing each migration destroys", func(t *testing.T) {
+		db := newDB(t)
+		if err := Up(db); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+
+		status, err := GetStatus(db)
+		if err != nil {
+			t.Fatalf("failed to get status with error %v", err)
+		}
+		for v := uint(1); v <= status.Latest; v++ {
+			risk, ok := journalRisks[v]
+			if !ok {
+				t.Fatalf("expected migration %d to declare what undoing it destroys", v)
+			}
+			if _, err := risk.held(db); err != nil {
+				t.Fatalf("failed to check migration %d's declaration with error %v", v, err)
+			}
+		}
+	})
+
+	t.Run("undoes every migration", func(t *testing.T) {
+		db := newDB(t)
+		if err := Up(db); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+
+		if err := DownTo(db, 0, false); err != nil {
+			t.Fatalf("failed to migrate down with error %v", err)
+		}
+		var tables int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name != 'schema_migrations'`).Scan(&tables); err != nil {
+			t.Fatalf("failed to count tables with error %v", err)
+		}
+		if tables != 0 {
+			t.Fatalf("expected no tables to remain, got %d", tables)
+		}
+	})
+
+	t.Run("forces a version", func(t *testing.T) {
+		db := newDB(t)
+		if err := UpTo(db, 5); err != nil {
+			t.Fatalf("failed to migrate with error %v", err)
+		}
+
+		if err := Force(db, 4); err != nil {
+			t.Fatalf("failed to force with error %v", err)
+		}
+		if status, err := GetStatus(db); err != nil || status.Version != 4 || status.Pending[0] != 5 {
+			t.Fatalf("expected version 4, got %+v and %v", status, err)
+		}
+	})
+}
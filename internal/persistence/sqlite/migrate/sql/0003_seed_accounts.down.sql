@@ -2,5 +2,5 @@ DELETE FROM accounts
   WHERE name = "Retained Earnings"
   AND parent_group_name = "Equity"
   AND account_type = "Equity"
-  AND display_after = NULL
+  AND display_after IS NULL
   AND normal_balance = "Credit";
\ No newline at end of file
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS bill_payments_bill_id;
+DROP INDEX IF EXISTS bills_vendor_id;
+DROP TABLE IF EXISTS bill_payments;
+DROP TABLE IF EXISTS bill_lines;
+DROP TABLE IF EXISTS bills;
+DROP TABLE IF EXISTS vendors;
+
+DELETE FROM accounts
+  WHERE name IN ('Accounts Payable', 'Purchase Discounts');
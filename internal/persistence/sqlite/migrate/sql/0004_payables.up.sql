@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS vendors (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  email TEXT NOT NULL DEFAULT '',
+  phone TEXT NOT NULL DEFAULT '',
+  address TEXT NOT NULL DEFAULT '',
+  default_terms TEXT NOT NULL DEFAULT 'Net 30'
+);
+
+CREATE TABLE IF NOT EXISTS bills (
+  id TEXT PRIMARY KEY,
+  vendor_id TEXT NOT NULL REFERENCES vendors(id),
+  number TEXT NOT NULL DEFAULT '',
+  bill_date TEXT NOT NULL,
+  due_date TEXT NOT NULL,
+  terms TEXT NOT NULL,
+  payable_account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE TABLE IF NOT EXISTS bill_lines (
+  bill_id TEXT NOT NULL REFERENCES bills(id),
+  line_no INTEGER NOT NULL,
+  account_name TEXT NOT NULL REFERENCES accounts(name),
+  description TEXT NOT NULL DEFAULT '',
+  amount REAL NOT NULL CHECK (amount > 0),
+  PRIMARY KEY (bill_id, line_no)
+);
+
+CREATE TABLE IF NOT EXISTS bill_payments (
+  id TEXT PRIMARY KEY,
+  bill_id TEXT NOT NULL REFERENCES bills(id),
+  payment_date TEXT NOT NULL,
+  cash_account_name TEXT NOT NULL REFERENCES accounts(name),
+  amount REAL NOT NULL CHECK (amount > 0),
+  discount_taken REAL NOT NULL DEFAULT 0,
+  discount_account_name TEXT REFERENCES accounts(name),
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE INDEX IF NOT EXISTS bills_vendor_id ON bills(vendor_id);
+CREATE INDEX IF NOT EXISTS bill_payments_bill_id ON bill_payments(bill_id);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Accounts Payable', 'Liabilities', 'Liability', NULL, 'Credit'),
+  ('Purchase Discounts', 'Revenues', 'Revenue', NULL, 'Credit');
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS invoice_payments_invoice_id;
+DROP INDEX IF EXISTS invoices_customer_id;
+DROP TABLE IF EXISTS invoice_payments;
+DROP TABLE IF EXISTS invoice_lines;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS customers;
+
+DELETE FROM accounts
+  WHERE name = 'Accounts Receivable';
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS customers (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  email TEXT NOT NULL DEFAULT '',
+  phone TEXT NOT NULL DEFAULT '',
+  address TEXT NOT NULL DEFAULT '',
+  default_terms TEXT NOT NULL DEFAULT 'Net 30'
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+  id TEXT PRIMARY KEY,
+  customer_id TEXT NOT NULL REFERENCES customers(id),
+  number TEXT NOT NULL DEFAULT '',
+  invoice_date TEXT NOT NULL,
+  due_date TEXT NOT NULL,
+  terms TEXT NOT NULL,
+  receivable_account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE TABLE IF NOT EXISTS invoice_lines (
+  invoice_id TEXT NOT NULL REFERENCES invoices(id),
+  line_no INTEGER NOT NULL,
+  account_name TEXT NOT NULL REFERENCES accounts(name),
+  description TEXT NOT NULL DEFAULT '',
+  amount REAL NOT NULL CHECK (amount > 0),
+  PRIMARY KEY (invoice_id, line_no)
+);
+
+CREATE TABLE IF NOT EXISTS invoice_payments (
+  id TEXT PRIMARY KEY,
+  invoice_id TEXT NOT NULL REFERENCES invoices(id),
+  payment_date TEXT NOT NULL,
+  cash_account_name TEXT NOT NULL REFERENCES accounts(name),
+  amount REAL NOT NULL CHECK (amount > 0),
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE INDEX IF NOT EXISTS invoices_customer_id ON invoices(customer_id);
+CREATE INDEX IF NOT EXISTS invoice_payments_invoice_id ON invoice_payments(invoice_id);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Accounts Receivable', 'Assets', 'Asset', NULL, 'Debit');
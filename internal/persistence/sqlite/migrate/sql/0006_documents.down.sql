@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS attachments_entity;
+DROP TABLE IF EXISTS attachments;
+DROP TABLE IF EXISTS book_settings;
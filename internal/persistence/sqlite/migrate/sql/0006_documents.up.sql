@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS book_settings (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  business_name TEXT NOT NULL DEFAULT '',
+  address TEXT NOT NULL DEFAULT '',
+  email TEXT NOT NULL DEFAULT '',
+  phone TEXT NOT NULL DEFAULT '',
+  logo BLOB
+);
+
+INSERT INTO book_settings (id) VALUES (1);
+
+CREATE TABLE IF NOT EXISTS attachments (
+  id TEXT PRIMARY KEY,
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  file_name TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  data BLOB NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachments_entity ON attachments(entity_type, entity_id);
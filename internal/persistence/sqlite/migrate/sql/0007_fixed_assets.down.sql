@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS fixed_assets;
+
+DELETE FROM account_types
+  WHERE name = 'Contra Asset';
@@ -0,0 +1,15 @@
+INSERT INTO account_types (name, display_after) VALUES
+  ('Contra Asset', 'Asset');
+
+CREATE TABLE IF NOT EXISTS fixed_assets (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  cost REAL NOT NULL CHECK (cost > 0),
+  salvage_value REAL NOT NULL DEFAULT 0,
+  in_service_date TEXT NOT NULL,
+  useful_life INTEGER NOT NULL,
+  method TEXT NOT NULL CHECK (method IN ('Straight Line', 'Declining Balance', 'MACRS')),
+  declining_balance_factor REAL NOT NULL DEFAULT 0,
+  asset_account_name TEXT NOT NULL REFERENCES accounts(name),
+  accumulated_depreciation_account_name TEXT NOT NULL REFERENCES accounts(name)
+);
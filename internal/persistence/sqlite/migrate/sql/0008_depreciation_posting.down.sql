@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS fixed_asset_disposals;
+DROP TABLE IF EXISTS depreciation_postings;
+
+ALTER TABLE fixed_assets DROP COLUMN depreciation_expense_account_name;
+
+DELETE FROM accounts
+  WHERE name IN ('Accumulated Depreciation', 'Depreciation Expense', 'Gain or Loss on Disposal of Assets');
@@ -0,0 +1,25 @@
+ALTER TABLE fixed_assets ADD COLUMN depreciation_expense_account_name TEXT REFERENCES accounts(name);
+
+CREATE TABLE IF NOT EXISTS depreciation_postings (
+  asset_id TEXT NOT NULL REFERENCES fixed_assets(id),
+  period_end TEXT NOT NULL,
+  amount REAL NOT NULL,
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id),
+  PRIMARY KEY (asset_id, period_end)
+);
+
+CREATE TABLE IF NOT EXISTS fixed_asset_disposals (
+  asset_id TEXT PRIMARY KEY REFERENCES fixed_assets(id),
+  disposal_date TEXT NOT NULL,
+  proceeds REAL NOT NULL DEFAULT 0,
+  proceeds_account_name TEXT REFERENCES accounts(name),
+  gain_loss_account_name TEXT NOT NULL REFERENCES accounts(name),
+  accumulated_depreciation REAL NOT NULL,
+  gain_or_loss REAL NOT NULL,
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Accumulated Depreciation', 'Assets', 'Contra Asset', NULL, 'Credit'),
+  ('Depreciation Expense', 'Expenses', 'Expense', NULL, 'Debit'),
+  ('Gain or Loss on Disposal of Assets', 'Revenues', 'Revenue', NULL, 'Credit');
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS unit_sales;
+DROP TABLE IF EXISTS inventory_units;
+
+DELETE FROM accounts
+  WHERE name IN ('Inventory', 'Cost of Goods Sold');
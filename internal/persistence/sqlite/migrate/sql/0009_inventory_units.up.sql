@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS inventory_units (
+  id TEXT PRIMARY KEY,
+  serial_number TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL DEFAULT '',
+  acquisition_cost REAL NOT NULL CHECK (acquisition_cost > 0),
+  acquisition_date TEXT NOT NULL,
+  acquisition_entry_id TEXT NOT NULL REFERENCES journal_entries(id),
+  location TEXT NOT NULL DEFAULT '',
+  inventory_account_name TEXT NOT NULL REFERENCES accounts(name)
+);
+
+CREATE TABLE IF NOT EXISTS unit_sales (
+  unit_id TEXT PRIMARY KEY REFERENCES inventory_units(id),
+  invoice_id TEXT NOT NULL REFERENCES invoices(id),
+  sale_date TEXT NOT NULL,
+  price REAL NOT NULL,
+  cogs_account_name TEXT NOT NULL REFERENCES accounts(name),
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Inventory', 'Assets', 'Asset', NULL, 'Debit'),
+  ('Cost of Goods Sold', 'Expenses', 'Expense', NULL, 'Debit');
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS stock_cost_adjustments_movement_id;
+DROP INDEX IF EXISTS stock_movements_item_id;
+DROP TABLE IF EXISTS stock_cost_adjustments;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_items;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS stock_items (
+  id TEXT PRIMARY KEY,
+  sku TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL DEFAULT '',
+  costing_method TEXT NOT NULL CHECK (costing_method IN ('FIFO', 'LIFO', 'Weighted Average')),
+  inventory_account_name TEXT NOT NULL REFERENCES accounts(name),
+  cogs_account_name TEXT NOT NULL REFERENCES accounts(name)
+);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+  id TEXT PRIMARY KEY,
+  item_id TEXT NOT NULL REFERENCES stock_items(id),
+  kind TEXT NOT NULL CHECK (kind IN ('Receipt', 'Issue')),
+  movement_date TEXT NOT NULL,
+  sequence INTEGER NOT NULL,
+  quantity REAL NOT NULL CHECK (quantity > 0),
+  cost REAL NOT NULL,
+  account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id),
+  UNIQUE (item_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS stock_cost_adjustments (
+  id TEXT PRIMARY KEY,
+  movement_id TEXT NOT NULL REFERENCES stock_movements(id),
+  previous_cost REAL NOT NULL,
+  new_cost REAL NOT NULL,
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_item_id ON stock_movements(item_id, movement_date, sequence);
+CREATE INDEX IF NOT EXISTS stock_cost_adjustments_movement_id ON stock_cost_adjustments(movement_id);
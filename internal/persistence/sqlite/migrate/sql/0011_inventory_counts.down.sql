@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS inventory_count_lines;
+DROP TABLE IF EXISTS inventory_counts;
+
+DELETE FROM accounts
+  WHERE name = 'Inventory Shrinkage';
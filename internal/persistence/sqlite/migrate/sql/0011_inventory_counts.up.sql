@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS inventory_counts (
+  id TEXT PRIMARY KEY,
+  count_date TEXT NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('Open', 'Posted')),
+  shrinkage_account_name TEXT NOT NULL REFERENCES accounts(name),
+  journal_entry_id TEXT REFERENCES journal_entries(id)
+);
+
+CREATE TABLE IF NOT EXISTS inventory_count_lines (
+  count_id TEXT NOT NULL REFERENCES inventory_counts(id),
+  line_no INTEGER NOT NULL,
+  item_id TEXT NOT NULL REFERENCES stock_items(id),
+  expected_quantity REAL NOT NULL,
+  counted_quantity REAL,
+  variance_cost REAL NOT NULL DEFAULT 0,
+  movement_id TEXT REFERENCES stock_movements(id),
+  PRIMARY KEY (count_id, line_no),
+  UNIQUE (count_id, item_id)
+);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Inventory Shrinkage', 'Expenses', 'Expense', NULL, 'Debit');
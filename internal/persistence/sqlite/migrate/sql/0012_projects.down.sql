@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS time_entries_project_id;
+DROP INDEX IF EXISTS journal_lines_project_id;
+
+ALTER TABLE stock_movements DROP COLUMN project_id;
+ALTER TABLE bill_lines DROP COLUMN project_id;
+ALTER TABLE invoice_lines DROP COLUMN project_id;
+ALTER TABLE journal_lines DROP COLUMN project_id;
+
+DROP TABLE IF EXISTS time_entries;
+DROP TABLE IF EXISTS projects;
+
+DELETE FROM accounts
+  WHERE name IN ('Work in Process', 'Direct Labor', 'Accrued Payroll');
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS projects (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  customer_id TEXT REFERENCES customers(id),
+  start_date TEXT NOT NULL,
+  status TEXT NOT NULL CHECK (status IN ('Active', 'Closed')),
+  description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS time_entries (
+  id TEXT PRIMARY KEY,
+  project_id TEXT NOT NULL REFERENCES projects(id),
+  person TEXT NOT NULL,
+  work_date TEXT NOT NULL,
+  hours REAL NOT NULL CHECK (hours > 0),
+  rate REAL NOT NULL CHECK (rate >= 0),
+  debit_account_name TEXT NOT NULL REFERENCES accounts(name),
+  credit_account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT REFERENCES journal_entries(id)
+);
+
+-- the project dimension: any journal line, and the document lines which post them, may be tagged
+ALTER TABLE journal_lines ADD COLUMN project_id TEXT REFERENCES projects(id);
+ALTER TABLE invoice_lines ADD COLUMN project_id TEXT REFERENCES projects(id);
+ALTER TABLE bill_lines ADD COLUMN project_id TEXT REFERENCES projects(id);
+ALTER TABLE stock_movements ADD COLUMN project_id TEXT REFERENCES projects(id);
+
+CREATE INDEX IF NOT EXISTS journal_lines_project_id ON journal_lines(project_id);
+CREATE INDEX IF NOT EXISTS time_entries_project_id ON time_entries(project_id, work_date);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Work in Process', 'Assets', 'Asset', NULL, 'Debit'),
+  ('Direct Labor', 'Expenses', 'Expense', NULL, 'Debit'),
+  ('Accrued Payroll', 'Liabilities', 'Liability', NULL, 'Credit');
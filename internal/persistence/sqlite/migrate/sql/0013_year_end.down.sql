@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS year_end_audit;
+DROP INDEX IF EXISTS year_end_closes_in_effect;
+DROP TABLE IF EXISTS year_end_closes;
+
+DELETE FROM accounts
+  WHERE name = 'Income Summary';
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS year_end_closes (
+  id TEXT PRIMARY KEY,
+  year_start TEXT NOT NULL,
+  year_end TEXT NOT NULL,
+  retained_earnings_account_name TEXT NOT NULL REFERENCES accounts(name),
+  income_summary_account_name TEXT REFERENCES accounts(name),
+  net_income REAL NOT NULL,
+  closed_at TEXT NOT NULL,
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id),
+  reopened_at TEXT,
+  reopen_reason TEXT NOT NULL DEFAULT '',
+  reversal_entry_id TEXT REFERENCES journal_entries(id),
+  CHECK (year_end >= year_start)
+);
+
+-- a year may be closed again once reopened, but is closed at most once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS year_end_closes_in_effect
+  ON year_end_closes(year_end) WHERE reopened_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS year_end_audit (
+  id TEXT PRIMARY KEY,
+  close_id TEXT NOT NULL REFERENCES year_end_closes(id),
+  action TEXT NOT NULL CHECK (action IN ('Close', 'Reopen')),
+  occurred_at TEXT NOT NULL,
+  reason TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Income Summary', 'Equity', 'Equity', NULL, 'Credit');
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS period_status_changes;
+DROP INDEX IF EXISTS accounting_periods_dates;
+DROP TABLE IF EXISTS accounting_periods;
+DROP TABLE IF EXISTS fiscal_calendar;
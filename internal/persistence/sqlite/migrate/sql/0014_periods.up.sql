@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS fiscal_calendar (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  start_month INTEGER NOT NULL DEFAULT 1 CHECK (start_month BETWEEN 1 AND 12),
+  period_pattern TEXT NOT NULL DEFAULT 'Monthly' CHECK (period_pattern IN ('Monthly', '4-4-5'))
+);
+
+INSERT INTO fiscal_calendar (id) VALUES (1);
+
+CREATE TABLE IF NOT EXISTS accounting_periods (
+  id TEXT PRIMARY KEY,
+  fiscal_year INTEGER NOT NULL,
+  number INTEGER NOT NULL CHECK (number BETWEEN 1 AND 12),
+  start_date TEXT NOT NULL,
+  end_date TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'Open' CHECK (status IN ('Open', 'Soft Closed', 'Closed')),
+  UNIQUE (fiscal_year, number),
+  CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS accounting_periods_dates ON accounting_periods(start_date, end_date);
+
+CREATE TABLE IF NOT EXISTS period_status_changes (
+  id TEXT PRIMARY KEY,
+  period_id TEXT NOT NULL REFERENCES accounting_periods(id) ON DELETE CASCADE,
+  from_status TEXT NOT NULL,
+  to_status TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  changed_at TEXT NOT NULL
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS estimated_tax_payments;
+DROP TABLE IF EXISTS se_tax_reserve_transfers;
+DROP TABLE IF EXISTS se_tax_rates;
+
+DELETE FROM accounts
+  WHERE name IN ('Tax Reserve', 'Owner Draws');
@@ -0,0 +1,49 @@
+-- versioned: an edit inserts the next version, and the latest applies
+CREATE TABLE IF NOT EXISTS se_tax_rates (
+  tax_year INTEGER NOT NULL,
+  version INTEGER NOT NULL,
+  net_earnings_factor REAL NOT NULL,
+  social_security_rate REAL NOT NULL,
+  social_security_wage_base REAL NOT NULL CHECK (social_security_wage_base > 0),
+  medicare_rate REAL NOT NULL,
+  additional_medicare_rate REAL NOT NULL,
+  additional_medicare_threshold REAL NOT NULL,
+  minimum_net_earnings REAL NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  entered_at TEXT NOT NULL,
+  PRIMARY KEY (tax_year, version)
+);
+
+CREATE TABLE IF NOT EXISTS se_tax_reserve_transfers (
+  id TEXT PRIMARY KEY,
+  tax_year INTEGER NOT NULL,
+  transfer_date TEXT NOT NULL,
+  amount REAL NOT NULL CHECK (amount > 0),
+  from_account_name TEXT NOT NULL REFERENCES accounts(name),
+  reserve_account_name TEXT NOT NULL REFERENCES accounts(name),
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+CREATE TABLE IF NOT EXISTS estimated_tax_payments (
+  id TEXT PRIMARY KEY,
+  tax_year INTEGER NOT NULL,
+  quarter INTEGER NOT NULL CHECK (quarter BETWEEN 1 AND 4),
+  paid_on TEXT NOT NULL,
+  amount REAL NOT NULL CHECK (amount > 0),
+  from_account_name TEXT NOT NULL REFERENCES accounts(name),
+  draw_account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id)
+);
+
+INSERT INTO se_tax_rates
+  (tax_year, version, net_earnings_factor, social_security_rate, social_security_wage_base,
+   medicare_rate, additional_medicare_rate, additional_medicare_threshold, minimum_net_earnings, note, entered_at)
+VALUES
+  (2024, 1, 0.9235, 0.124, 168600, 0.029, 0.009, 200000, 400, 'Published rates; single filer threshold', '2024-01-01T00:00:00Z'),
+  (2025, 1, 0.9235, 0.124, 176100, 0.029, 0.009, 200000, 400, 'Published rates; single filer threshold', '2025-01-01T00:00:00Z'),
+  (2026, 1, 0.9235, 0.124, 184500, 0.029, 0.009, 200000, 400, 'Published rates; single filer threshold', '2026-01-01T00:00:00Z');
+
+INSERT INTO accounts (name, parent_group_name, account_type, display_after, normal_balance) VALUES
+  ('Tax Reserve', 'Assets', 'Asset', NULL, 'Debit'),
+  ('Owner Draws', 'Equity', 'Equity', NULL, 'Debit');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tax_line_mappings;
+DROP TABLE IF EXISTS tax_lines;
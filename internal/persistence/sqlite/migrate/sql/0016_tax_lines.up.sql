@@ -0,0 +1,67 @@
+-- lines are numbered per tax year, since forms renumber them
+CREATE TABLE IF NOT EXISTS tax_lines (
+  tax_year INTEGER NOT NULL,
+  form TEXT NOT NULL,
+  code TEXT NOT NULL,
+  description TEXT NOT NULL,
+  display_order INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (tax_year, form, code)
+);
+
+-- an account reports on at most one line of a form each year
+CREATE TABLE IF NOT EXISTS tax_line_mappings (
+  tax_year INTEGER NOT NULL,
+  form TEXT NOT NULL,
+  account_name TEXT NOT NULL REFERENCES accounts(name),
+  line_code TEXT NOT NULL,
+  PRIMARY KEY (tax_year, form, account_name),
+  FOREIGN KEY (tax_year, form, line_code) REFERENCES tax_lines(tax_year, form, code)
+);
+
+WITH
+  years(tax_year) AS (VALUES (2024), (2025), (2026)),
+  schedule_c(code, description, display_order) AS (VALUES
+    ('1', 'Gross receipts or sales', 10),
+    ('2', 'Returns and allowances', 20),
+    ('4', 'Cost of goods sold', 40),
+    ('6', 'Other income', 60),
+    ('8', 'Advertising', 80),
+    ('9', 'Car and truck expenses', 90),
+    ('10', 'Commissions and fees', 100),
+    ('11', 'Contract labor', 110),
+    ('12', 'Depletion', 120),
+    ('13', 'Depreciation and section 179 expense deduction', 130),
+    ('14', 'Employee benefit programs', 140),
+    ('15', 'Insurance (other than health)', 150),
+    ('16a', 'Interest: mortgage', 160),
+    ('16b', 'Interest: other', 165),
+    ('17', 'Legal and professional services', 170),
+    ('18', 'Office expense', 180),
+    ('19', 'Pension and profit-sharing plans', 190),
+    ('20a', 'Rent or lease: vehicles, machinery, and equipment', 200),
+    ('20b', 'Rent or lease: other business property', 205),
+    ('21', 'Repairs and maintenance', 210),
+    ('22', 'Supplies', 220),
+    ('23', 'Taxes and licenses', 230),
+    ('24a', 'Travel', 240),
+    ('24b', 'Deductible meals', 245),
+    ('25', 'Utilities', 250),
+    ('26', 'Wages', 260),
+    ('27a', 'Other expenses', 270)
+  )
+INSERT INTO tax_lines (tax_year, form, code, description, display_order)
+  SELECT years.tax_year, 'Schedule C', schedule_c.code, schedule_c.description, schedule_c.display_order
+  FROM years CROSS JOIN schedule_c;
+
+WITH
+  years(tax_year) AS (VALUES (2024), (2025), (2026)),
+  seeded(account_name, line_code) AS (VALUES
+    ('Cost of Goods Sold', '4'),
+    ('Inventory Shrinkage', '4'),
+    ('Depreciation Expense', '13'),
+    ('Direct Labor', '26')
+  )
+INSERT INTO tax_line_mappings (tax_year, form, account_name, line_code)
+  SELECT years.tax_year, 'Schedule C', seeded.account_name, seeded.line_code
+  FROM years CROSS JOIN seeded
+  WHERE seeded.account_name IN (SELECT name FROM accounts);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS sales_tax_remittances_period_end;
+
+DROP TABLE IF EXISTS sales_tax_remittances;
+DROP TABLE IF EXISTS invoice_taxes;
+
+ALTER TABLE invoice_lines DROP COLUMN tax_code;
+
+DROP TABLE IF EXISTS sales_tax_code_rates;
+DROP TABLE IF EXISTS sales_tax_codes;
+DROP TABLE IF EXISTS sales_tax_rates;
+DROP TABLE IF EXISTS sales_tax_jurisdictions;
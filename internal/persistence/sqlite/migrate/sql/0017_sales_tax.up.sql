@@ -0,0 +1,61 @@
+-- each jurisdiction's collected tax is owed on its own liability account
+CREATE TABLE IF NOT EXISTS sales_tax_jurisdictions (
+  id TEXT PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  liability_account_name TEXT NOT NULL UNIQUE REFERENCES accounts(name)
+);
+
+CREATE TABLE IF NOT EXISTS sales_tax_rates (
+  id TEXT PRIMARY KEY,
+  jurisdiction_id TEXT NOT NULL REFERENCES sales_tax_jurisdictions(id),
+  name TEXT NOT NULL,
+  rate REAL NOT NULL CHECK (rate >= 0 AND rate < 1),
+  UNIQUE (jurisdiction_id, name)
+);
+
+-- tax groups: a code without rates marks a line exempt
+CREATE TABLE IF NOT EXISTS sales_tax_codes (
+  code TEXT PRIMARY KEY,
+  description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS sales_tax_code_rates (
+  code TEXT NOT NULL REFERENCES sales_tax_codes(code),
+  rate_id TEXT NOT NULL REFERENCES sales_tax_rates(id),
+  position INTEGER NOT NULL,
+  PRIMARY KEY (code, rate_id)
+);
+
+ALTER TABLE invoice_lines ADD COLUMN tax_code TEXT REFERENCES sales_tax_codes(code);
+
+-- the tax an invoice charged, kept as charged so later rate edits leave it be
+CREATE TABLE IF NOT EXISTS invoice_taxes (
+  invoice_id TEXT NOT NULL REFERENCES invoices(id),
+  line_no INTEGER NOT NULL,
+  rate_id TEXT NOT NULL REFERENCES sales_tax_rates(id),
+  jurisdiction_id TEXT NOT NULL REFERENCES sales_tax_jurisdictions(id),
+  name TEXT NOT NULL,
+  liability_account_name TEXT NOT NULL REFERENCES accounts(name),
+  taxable_amount REAL NOT NULL,
+  amount REAL NOT NULL CHECK (amount >= 0),
+  PRIMARY KEY (invoice_id, line_no, rate_id)
+);
+
+CREATE TABLE IF NOT EXISTS sales_tax_remittances (
+  id TEXT PRIMARY KEY,
+  jurisdiction_id TEXT NOT NULL REFERENCES sales_tax_jurisdictions(id),
+  period_start TEXT NOT NULL,
+  period_end TEXT NOT NULL,
+  paid_on TEXT NOT NULL,
+  amount REAL NOT NULL CHECK (amount > 0),
+  liability_account_name TEXT NOT NULL REFERENCES accounts(name),
+  cash_account_name TEXT NOT NULL REFERENCES accounts(name),
+  memo TEXT NOT NULL DEFAULT '',
+  journal_entry_id TEXT NOT NULL REFERENCES journal_entries(id),
+  CHECK (period_end >= period_start)
+);
+
+CREATE INDEX IF NOT EXISTS sales_tax_remittances_period_end ON sales_tax_remittances(period_end);
+
+INSERT INTO sales_tax_codes (code, description) VALUES
+  ('EXEMPT', 'Exempt sale');
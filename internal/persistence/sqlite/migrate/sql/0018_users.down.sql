@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS sessions_expires_at;
+
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,6 @@
+-- what each user may do; the users created before roles existed were all
+-- created as admins from the command line
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'viewer'
+  CHECK (role IN ('viewer', 'bookkeeper', 'admin'));
+
+UPDATE users SET role = 'admin';
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+DROP TRIGGER IF EXISTS audit_log_no_update;
+
+DROP INDEX IF EXISTS audit_log_occurred_at;
+DROP INDEX IF EXISTS audit_log_entity;
+
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,29 @@
+-- who changed what in the books and when, written alongside each change to
+-- groups, accounts and journal entries; before and after hold the record as JSON
+CREATE TABLE IF NOT EXISTS audit_log (
+  id TEXT PRIMARY KEY,
+  occurred_at TEXT NOT NULL,
+  actor TEXT NOT NULL,
+  request_id TEXT NOT NULL DEFAULT '',
+  operation TEXT NOT NULL CHECK (operation IN ('create', 'update', 'reorder', 'post')),
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  before_json TEXT,
+  after_json TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_occurred_at ON audit_log(occurred_at);
+
+-- the log is append-only
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update
+BEFORE UPDATE ON audit_log
+BEGIN
+  SELECT RAISE(ABORT, 'the audit log is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete
+BEFORE DELETE ON audit_log
+BEGIN
+  SELECT RAISE(ABORT, 'the audit log is append-only');
+END;
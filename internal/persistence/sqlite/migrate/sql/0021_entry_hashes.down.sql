@@ -0,0 +1,2 @@
+ALTER TABLE journal_entries DROP COLUMN hash;
+ALTER TABLE journal_entries DROP COLUMN prev_hash;
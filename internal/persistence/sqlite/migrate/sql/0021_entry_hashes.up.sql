@@ -0,0 +1,4 @@
+-- each posted entry's hash covers its content and the hash of the entry
+-- before it in ID order, so that edits made outside the application show
+ALTER TABLE journal_entries ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE journal_entries ADD COLUMN hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+-- each account's totals within a period as of its closing, so that the
+-- integrity check can tell if the period has changed since
+CREATE TABLE IF NOT EXISTS period_balances (
+  period_id TEXT NOT NULL REFERENCES accounting_periods(id) ON DELETE CASCADE,
+  account_name TEXT NOT NULL,
+  debits REAL NOT NULL,
+  credits REAL NOT NULL,
+  PRIMARY KEY (period_id, account_name)
+);
+
+-- periods closed already are taken as they stand
+INSERT INTO period_balances (period_id, account_name, debits, credits)
+SELECT
+  p.id,
+  l.account_name,
+  COALESCE(SUM(CASE WHEN l.side = 'Debit' THEN l.amount END), 0),
+  COALESCE(SUM(CASE WHEN l.side = 'Credit' THEN l.amount END), 0)
+FROM accounting_periods p
+JOIN journal_entries e
+  ON e.timestamp >= p.start_date || 'T00:00:00Z'
+  AND e.timestamp < date(p.end_date, '+1 day') || 'T00:00:00Z'
+JOIN journal_lines l ON l.journal_entry_id = e.id
+WHERE p.status = 'Closed'
+GROUP BY p.id, l.account_name;
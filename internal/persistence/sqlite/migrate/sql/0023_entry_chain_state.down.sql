@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS entry_chain_state_no_delete;
+DROP TRIGGER IF EXISTS entry_chain_state_no_update;
+DROP TABLE IF EXISTS entry_chain_state;
@@ -0,0 +1,26 @@
+-- marks the books as chained, once the entries posted before hashing have
+-- been hashed; entries left unhashed after that are breaks in the chain,
+-- never to be rechained over
+CREATE TABLE IF NOT EXISTS entry_chain_state (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  chained_at TEXT NOT NULL
+);
+
+-- books holding no entries, or hashed ones already, have nothing to backfill
+INSERT INTO entry_chain_state (id, chained_at)
+SELECT 1, strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+WHERE NOT EXISTS (SELECT 1 FROM journal_entries)
+  OR EXISTS (SELECT 1 FROM journal_entries WHERE hash <> '');
+
+-- once chained, always chained
+CREATE TRIGGER IF NOT EXISTS entry_chain_state_no_update
+BEFORE UPDATE ON entry_chain_state
+BEGIN
+  SELECT RAISE(ABORT, 'the books are chained for good');
+END;
+
+CREATE TRIGGER IF NOT EXISTS entry_chain_state_no_delete
+BEFORE DELETE ON entry_chain_state
+BEGIN
+  SELECT RAISE(ABORT, 'the books are chained for good');
+END;
@@ -0,0 +1,269 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type periodRepo struct {
+	db *sql.DB
+}
+
+// Retrieves the fiscal calendar; there is exactly one row, seeded by migration
+func (r *periodRepo) Calendar(ctx context.Context) (accounting.FiscalCalendar, error) {
+	const query = `
+		SELECT start_month, period_pattern
+		FROM fiscal_calendar
+		WHERE id = 1;
+	`
+
+	var calendar accounting.FiscalCalendar
+	if err := r.db.QueryRowContext(ctx, query).Scan(&calendar.StartMonth, &calendar.Pattern); err != nil {
+		return accounting.FiscalCalendar{}, err
+	}
+
+	return calendar, nil
+}
+
+// SaveCalendar replaces the fiscal calendar and discards the periods
+// generated under the old one, in a single transaction. Fails once any period
+// has left Open, even if since reopened, since discarding it would cascade
+// away its close and reopen history.
+func (r *periodRepo) SaveCalendar(ctx context.Context, calendar accounting.FiscalCalendar) error {
+	const lockedQuery = `
+		SELECT COUNT(*)
+		FROM accounting_periods
+		WHERE status <> 'Open'
+			OR id IN (SELECT period_id FROM period_status_changes);
+	`
+	const calendarQuery = `
+		UPDATE fiscal_calendar SET
+			start_month = ?,
+			period_pattern = ?
+		WHERE id = 1;
+	`
+	const deleteQuery = `
+		DELETE FROM accounting_periods;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var locked int
+		if err := tx.QueryRowContext(ctx, lockedQuery).Scan(&locked); err != nil {
+			return err
+		}
+		if locked > 0 {
+			return &accounting.ErrCalendarLocked{Periods: locked}
+		}
+
+		if _, err := tx.ExecContext(ctx, calendarQuery, int(calendar.StartMonth), calendar.Pattern); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, deleteQuery)
+		return err
+	})
+}
+
+// Insert saves newly generated periods in a single transaction
+func (r *periodRepo) Insert(ctx context.Context, periods []accounting.AccountingPeriod) error {
+	const query = `
+		INSERT INTO accounting_periods
+			(id, fiscal_year, number, start_date, end_date, status)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, period := range periods {
+			if _, err := tx.ExecContext(
+				ctx,
+				query,
+				period.ID,
+				period.FiscalYear,
+				period.Number,
+				formatDate(period.Start),
+				formatDate(period.End),
+				period.Status,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Retrieves a period by ID
+//
+// Returns ErrPeriodNotFound if the period does not exist.
+func (r *periodRepo) ByID(ctx context.Context, id string) (accounting.AccountingPeriod, error) {
+	const query = `
+		SELECT id, fiscal_year, number, start_date, end_date, status
+		FROM accounting_periods
+		WHERE id = ?;
+	`
+
+	period, err := scanPeriod(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.AccountingPeriod{}, &accounting.ErrPeriodNotFound{ID: id}
+		}
+		return accounting.AccountingPeriod{}, err
+	}
+
+	return period, nil
+}
+
+// Retrieves a fiscal year's periods in order
+func (r *periodRepo) ForYear(ctx context.Context, fiscalYear int) ([]accounting.AccountingPeriod, error) {
+	const query = `
+		SELECT id, fiscal_year, number, start_date, end_date, status
+		FROM accounting_periods
+		WHERE fiscal_year = ?
+		ORDER BY number;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, fiscalYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []accounting.AccountingPeriod
+	for rows.Next() {
+		period, err := scanPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+// SetStatus saves the period's status and the record of its change in a single transaction.
+// Closing a period records its balances as they stand, for the integrity
+// check to compare against; reopening it discards them.
+func (r *periodRepo) SetStatus(ctx context.Context, period *accounting.AccountingPeriod, change accounting.PeriodStatusChange) error {
+	const periodQuery = `
+		UPDATE accounting_periods
+		SET status = ?
+		WHERE id = ?;
+	`
+	const changeQuery = `
+		INSERT INTO period_status_changes
+			(id, period_id, from_status, to_status, reason, changed_at)
+		VALUES
+			(?, ?, ?, ?, ?, ?);
+	`
+	const clearBalancesQuery = `
+		DELETE FROM period_balances
+		WHERE period_id = ?;
+	`
+	const balancesQuery = `
+		INSERT INTO period_balances (period_id, account_name, debits, credits)
+		SELECT
+			?,
+			l.account_name,
+			COALESCE(SUM(CASE WHEN l.side = 'Debit' THEN l.amount END), 0),
+			COALESCE(SUM(CASE WHEN l.side = 'Credit' THEN l.amount END), 0)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.journal_entry_id
+		WHERE e.timestamp >= ? AND e.timestamp < ?
+		GROUP BY l.account_name;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, periodQuery, period.Status, period.ID)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return &accounting.ErrPeriodNotFound{ID: period.ID}
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			changeQuery,
+			change.ID,
+			change.PeriodID,
+			change.From,
+			change.To,
+			change.Reason,
+			formatTimestamp(change.ChangedAt),
+		); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, clearBalancesQuery, period.ID); err != nil {
+			return err
+		}
+		if period.Status != accounting.PeriodClosed {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, balancesQuery, period.ID, formatTimestamp(period.Start), formatTimestamp(period.End.AddDate(0, 0, 1)))
+		return err
+	})
+}
+
+// Retrieves the changes to a period's status, in the order they were made
+func (r *periodRepo) StatusChanges(ctx context.Context, periodID string) ([]accounting.PeriodStatusChange, error) {
+	const query = `
+		SELECT id, period_id, from_status, to_status, reason, changed_at
+		FROM period_status_changes
+		WHERE period_id = ?
+		ORDER BY changed_at, rowid;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, periodID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []accounting.PeriodStatusChange
+	for rows.Next() {
+		var (
+			change    accounting.PeriodStatusChange
+			changedAt string
+		)
+		if err := rows.Scan(&change.ID, &change.PeriodID, &change.From, &change.To, &change.Reason, &changedAt); err != nil {
+			return nil, err
+		}
+		if change.ChangedAt, err = parseTimestamp(changedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func scanPeriod(s scanner) (accounting.AccountingPeriod, error) {
+	var (
+		period     accounting.AccountingPeriod
+		start, end string
+	)
+
+	if err := s.Scan(&period.ID, &period.FiscalYear, &period.Number, &start, &end, &period.Status); err != nil {
+		return accounting.AccountingPeriod{}, err
+	}
+
+	var err error
+	if period.Start, err = parseDate(start); err != nil {
+		return accounting.AccountingPeriod{}, err
+	}
+	if period.End, err = parseDate(end); err != nil {
+		return accounting.AccountingPeriod{}, err
+	}
+
+	return period, nil
+}
@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestPeriodRepo(t *testing.T) {
+	ctx := context.Background()
+
+	// admins alone may post into soft-closed periods
+	admin, err := accounting.NewUser("alice", "correct horse battery", accounting.AdminRole)
+	if err != nil {
+		t.Fatalf("failed to create user with error %v", err)
+	}
+	allowed := accounting.WithUser(ctx, admin)
+	at := time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC)
+
+	entry := func(date time.Time) accounting.JournalEntry {
+		return accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: date,
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: 100, Side: accounting.Debit},
+				{AccountName: "Sales", Amount: 100, Side: accounting.Credit},
+			},
+		}
+	}
+
+	setup := func(t *testing.T) (*Repositories, []accounting.AccountingPeriod) {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		periods := accounting.DefaultFiscalCalendar.Periods(2025)
+		if err := repos.Periods.Insert(ctx, periods); err != nil {
+			t.Fatalf("failed to insert periods with error %v", err)
+		}
+
+		return repos, periods
+	}
+
+	setStatus := func(t *testing.T, repos *Repositories, period *accounting.AccountingPeriod, status accounting.PeriodStatus) {
+		t.Helper()
+		change, err := period.SetStatus(status, "month end", at)
+		if err != nil {
+			t.Fatalf("failed to set status with error %v", err)
+		}
+		if err := repos.Periods.SetStatus(ctx, period, change); err != nil {
+			t.Fatalf("failed to save status with error %v", err)
+		}
+	}
+
+	t.Run("round trips the calendar and periods", func(t *testing.T) {
+		repos, periods := setup(t)
+
+		calendar, err := repos.Periods.Calendar(ctx)
+		if err != nil {
+			t.Fatalf("failed to get calendar with error %v", err)
+		}
+		if calendar != accounting.DefaultFiscalCalendar {
+			t.Fatalf("expected the default calendar, got %+v", calendar)
+		}
+
+		stored, err := repos.Periods.ForYear(ctx, 2025)
+		if err != nil {
+			t.Fatalf("failed to get periods with error %v", err)
+		}
+		if len(stored) != 12 || stored[1] != periods[1] {
+			t.Fatalf("expected the inserted periods, got %+v", stored)
+		}
+
+		if _, err := repos.Periods.ByID(ctx, "missing"); !accounting.IsPeriodNotFound(err) {
+			t.Fatalf("expected an ErrPeriodNotFound, received %v", err)
+		}
+	})
+
+	t.Run("rejects postings into a closed period", func(t *testing.T) {
+		repos, periods := setup(t)
+		setStatus(t, repos, &periods[1], accounting.PeriodClosed)
+
+		err := repos.JournalEntries.Save(ctx, entry(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)))
+		if !accounting.IsPeriodClosed(err) {
+			t.Fatalf("expected an ErrPeriodClosed, received %v", err)
+		}
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatalf("expected the following period to accept postings, received %v", err)
+		}
+
+		if err := repos.JournalEntries.Save(allowed, entry(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))); !accounting.IsPeriodClosed(err) {
+			t.Fatalf("expected a closed period to reject even privileged postings, received %v", err)
+		}
+	})
+
+	t.Run("admits only privileged postings into a soft-closed period", func(t *testing.T) {
+		repos, periods := setup(t)
+		setStatus(t, repos, &periods[0], accounting.PeriodSoftClosed)
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))); !accounting.IsPeriodClosed(err) {
+			t.Fatalf("expected an ErrPeriodClosed, received %v", err)
+		}
+
+		if err := repos.JournalEntries.Save(allowed, entry(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatalf("expected a privileged posting to be accepted, received %v", err)
+		}
+	})
+
+	t.Run("records status changes and reopens", func(t *testing.T) {
+		repos, periods := setup(t)
+		setStatus(t, repos, &periods[0], accounting.PeriodClosed)
+		setStatus(t, repos, &periods[0], accounting.PeriodOpen)
+
+		changes, err := repos.Periods.StatusChanges(ctx, periods[0].ID)
+		if err != nil {
+			t.Fatalf("failed to get status changes with error %v", err)
+		}
+		if len(changes) != 2 || changes[0].To != accounting.PeriodClosed || changes[1].From != accounting.PeriodClosed || changes[1].Reason != "month end" {
+			t.Fatalf("unexpected status changes %+v", changes)
+		}
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))); err != nil {
+			t.Fatalf("expected the reopened period to accept postings, received %v", err)
+		}
+	})
+
+	t.Run("replaces the calendar and its untouched periods", func(t *testing.T) {
+		repos, _ := setup(t)
+
+		calendar := accounting.FiscalCalendar{StartMonth: time.July, Pattern: accounting.FourFourFivePeriods}
+		if err := repos.Periods.SaveCalendar(ctx, calendar); err != nil {
+			t.Fatalf("failed to save calendar with error %v", err)
+		}
+		stored, _ := repos.Periods.Calendar(ctx)
+		remaining, _ := repos.Periods.ForYear(ctx, 2025)
+		if stored != calendar || len(remaining) != 0 {
+			t.Fatalf("expected the new calendar without the old periods, got %+v and %d periods", stored, len(remaining))
+		}
+	})
+
+	t.Run("refuses a new calendar once a period has been closed, even if reopened", func(t *testing.T) {
+		repos, periods := setup(t)
+		setStatus(t, repos, &periods[0], accounting.PeriodClosed)
+
+		calendar := accounting.FiscalCalendar{StartMonth: time.July, Pattern: accounting.FourFourFivePeriods}
+		if err := repos.Periods.SaveCalendar(ctx, calendar); !accounting.IsCalendarLocked(err) {
+			t.Fatalf("expected ErrCalendarLocked replacing the calendar, received %v", err)
+		}
+
+		setStatus(t, repos, &periods[0], accounting.PeriodOpen)
+		if err := repos.Periods.SaveCalendar(ctx, calendar); !accounting.IsCalendarLocked(err) {
+			t.Fatalf("expected ErrCalendarLocked after reopening, received %v", err)
+		}
+
+		stored, _ := repos.Periods.Calendar(ctx)
+		changes, _ := repos.Periods.StatusChanges(ctx, periods[0].ID)
+		if stored != accounting.DefaultFiscalCalendar || len(changes) != 2 {
+			t.Fatalf("expected the calendar and history kept, got %+v and %d changes", stored, len(changes))
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type projectRepo struct {
+	db *sql.DB
+}
+
+// Save inserts or updates a project in the database.
+func (r *projectRepo) Save(ctx context.Context, project *accounting.Project) error {
+	const query = `
+		INSERT INTO projects
+			(id, name, customer_id, start_date, status, description)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			customer_id = excluded.customer_id,
+			start_date = excluded.start_date,
+			status = excluded.status,
+			description = excluded.description;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		project.ID,
+		project.Name,
+		sql.NullString{String: project.CustomerID, Valid: project.CustomerID != ""},
+		formatDate(project.StartDate),
+		project.Status,
+		project.Description,
+	)
+
+	return err
+}
+
+// Retrieves a project by ID
+//
+// Returns ErrProjectNotFound if the project does not exist.
+func (r *projectRepo) ByID(ctx context.Context, id string) (accounting.Project, error) {
+	const query = `
+		SELECT id, name, customer_id, start_date, status, description
+		FROM projects
+		WHERE id = ?;
+	`
+
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Project{}, &accounting.ErrProjectNotFound{ID: id}
+		}
+		return accounting.Project{}, err
+	}
+
+	return project, nil
+}
+
+// Retrieves all projects, ordered by name
+func (r *projectRepo) GetAll(ctx context.Context) ([]*accounting.Project, error) {
+	const query = `
+		SELECT id, name, customer_id, start_date, status, description
+		FROM projects
+		ORDER BY name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*accounting.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, &project)
+	}
+
+	return projects, rows.Err()
+}
+
+// RecordTime saves a time entry and the journal entry which posts its cost in
+// a single transaction. Hours without cost are saved without an entry.
+func (r *projectRepo) RecordTime(ctx context.Context, entry *accounting.TimeEntry, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO time_entries
+			(id, project_id, person, work_date, hours, rate, debit_account_name, credit_account_name, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var entryID string
+		if entry.Cost() > 0 {
+			if err := insertJournalEntry(ctx, tx, je); err != nil {
+				return err
+			}
+			entryID = je.ID
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			entry.ID,
+			entry.ProjectID,
+			entry.Person,
+			formatDate(entry.Date),
+			entry.Hours,
+			entry.Rate,
+			entry.DebitAccountName,
+			entry.CreditAccountName,
+			entry.Memo,
+			sql.NullString{String: entryID, Valid: entryID != ""},
+		); err != nil {
+			return err
+		}
+
+		entry.EntryID = entryID
+		return nil
+	})
+}
+
+// Retrieves a project's time entries, ordered by date and then the order they were recorded
+func (r *projectRepo) TimeEntries(ctx context.Context, projectID string) ([]accounting.TimeEntry, error) {
+	const query = `
+		SELECT id, project_id, person, work_date, hours, rate, debit_account_name, credit_account_name, memo, journal_entry_id
+		FROM time_entries
+		WHERE project_id = ?
+		ORDER BY work_date, id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []accounting.TimeEntry
+	for rows.Next() {
+		var (
+			entry   accounting.TimeEntry
+			date    string
+			entryID sql.NullString
+		)
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ProjectID,
+			&entry.Person,
+			&date,
+			&entry.Hours,
+			&entry.Rate,
+			&entry.DebitAccountName,
+			&entry.CreditAccountName,
+			&entry.Memo,
+			&entryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if entry.Date, err = parseDate(date); err != nil {
+			return nil, err
+		}
+		entry.EntryID = entryID.String
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func scanProject(s scanner) (accounting.Project, error) {
+	var (
+		project    accounting.Project
+		customerID sql.NullString
+		startDate  string
+	)
+
+	if err := s.Scan(
+		&project.ID,
+		&project.Name,
+		&customerID,
+		&startDate,
+		&project.Status,
+		&project.Description,
+	); err != nil {
+		return accounting.Project{}, err
+	}
+
+	var err error
+	if project.StartDate, err = parseDate(startDate); err != nil {
+		return accounting.Project{}, err
+	}
+	project.CustomerID = customerID.String
+
+	return project, nil
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestProjectRepo(t *testing.T) {
+	ctx := context.Background()
+	worked := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*Repositories, *accounting.Project) {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		project, err := accounting.NewProject("Kitchen Remodel", "", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), "Cabinets and counters")
+		if err != nil {
+			t.Fatalf("failed to create project with error %v", err)
+		}
+		if err := repos.Projects.Save(ctx, project); err != nil {
+			t.Fatalf("failed to save project with error %v", err)
+		}
+		return repos, project
+	}
+
+	t.Run("saves and retrieves a project", func(t *testing.T) {
+		repos, project := setup(t)
+
+		project.Status = accounting.ProjectClosed
+		if err := repos.Projects.Save(ctx, project); err != nil {
+			t.Fatalf("failed to update project with error %v", err)
+		}
+
+		retrieved, err := repos.Projects.ByID(ctx, project.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve project with error %v", err)
+		}
+		if retrieved != *project {
+			t.Fatalf("expected %+v, received %+v", *project, retrieved)
+		}
+
+		if _, err := repos.Projects.ByID(ctx, "missing"); !accounting.IsProjectNotFound(err) {
+			t.Fatalf("expected an ErrProjectNotFound, received %v", err)
+		}
+	})
+
+	t.Run("records time with its entry, tagging the journal line", func(t *testing.T) {
+		repos, project := setup(t)
+
+		entry, err := accounting.NewTimeEntry(project, "Dana", worked, 8, 35, "Work in Process", "Accrued Payroll")
+		if err != nil {
+			t.Fatalf("failed to create time entry with error %v", err)
+		}
+		if err := repos.Projects.RecordTime(ctx, entry, entry.JournalEntry(project)); err != nil {
+			t.Fatalf("failed to record time with error %v", err)
+		}
+
+		entries, err := repos.Projects.TimeEntries(ctx, project.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve time entries with error %v", err)
+		}
+		if len(entries) != 1 || entries[0] != *entry || entry.EntryID == "" {
+			t.Fatalf("expected the recorded entry, received %+v", entries)
+		}
+
+		je, err := repos.JournalEntries.ByID(ctx, entry.EntryID)
+		if err != nil {
+			t.Fatalf("failed to retrieve journal entry with error %v", err)
+		}
+		if je.Lines[0].ProjectID != project.ID || je.Lines[1].ProjectID != "" {
+			t.Fatalf("expected only the labor line tagged with the project, received %+v", je.Lines)
+		}
+	})
+
+	t.Run("records unpaid hours without an entry", func(t *testing.T) {
+		repos, project := setup(t)
+
+		entry, _ := accounting.NewTimeEntry(project, "Owner", worked, 3, 0, "Work in Process", "Accrued Payroll")
+		if err := repos.Projects.RecordTime(ctx, entry, entry.JournalEntry(project)); err != nil {
+			t.Fatalf("failed to record time with error %v", err)
+		}
+		if entry.EntryID != "" {
+			t.Fatalf("expected no entry for zero-cost hours, received %q", entry.EntryID)
+		}
+	})
+
+	t.Run("lists tagged lines with the document which posted them", func(t *testing.T) {
+		repos, project := setup(t)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		entry, _ := accounting.NewTimeEntry(project, "Dana", worked, 8, 35, "Work in Process", "Accrued Payroll")
+		if err := repos.Projects.RecordTime(ctx, entry, entry.JournalEntry(project)); err != nil {
+			t.Fatalf("failed to record time with error %v", err)
+		}
+
+		customer, _ := accounting.NewCustomer("Acme Corp.", accounting.DefaultPaymentTerms)
+		if err := repos.Customers.Save(ctx, customer); err != nil {
+			t.Fatalf("failed to save customer with error %v", err)
+		}
+		invoice, _ := accounting.NewInvoice(customer.ID, "1001", worked.AddDate(0, 0, 5), accounting.DefaultPaymentTerms, "Accounts Receivable",
+			[]accounting.InvoiceLine{{AccountName: "Sales", Amount: 900, ProjectID: project.ID}, {AccountName: "Sales", Amount: 100}})
+		if err := repos.Invoices.Post(ctx, invoice, invoice.JournalEntry()); err != nil {
+			t.Fatalf("failed to post invoice with error %v", err)
+		}
+
+		lines, err := repos.JournalEntries.ProjectLines(ctx, worked, worked.AddDate(0, 1, 0))
+		if err != nil {
+			t.Fatalf("failed to retrieve project lines with error %v", err)
+		}
+		if len(lines) != 2 {
+			t.Fatalf("expected the labor and revenue lines only, received %+v", lines)
+		}
+		if lines[0].Source != accounting.SourceTimeEntry || lines[0].AccountType != accounting.Asset || lines[0].Amount != 280 {
+			t.Fatalf("expected the labor line from the time entry, received %+v", lines[0])
+		}
+		if lines[1].Source != accounting.SourceInvoice || lines[1].AccountType != accounting.Revenue || lines[1].Amount != 900 {
+			t.Fatalf("expected the tagged revenue line from the invoice, received %+v", lines[1])
+		}
+
+		retrieved, err := repos.Invoices.ByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve invoice with error %v", err)
+		}
+		if retrieved.Lines[0].ProjectID != project.ID || retrieved.Lines[1].ProjectID != "" {
+			t.Fatalf("expected the invoice lines to keep their projects, received %+v", retrieved.Lines)
+		}
+	})
+
+	t.Run("rejects lines tagged with an unknown project", func(t *testing.T) {
+		repos, _ := setup(t)
+
+		je := accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: worked,
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Direct Labor", Amount: 10, Side: accounting.Debit, ProjectID: "missing"},
+				{AccountName: "Accrued Payroll", Amount: 10, Side: accounting.Credit},
+			},
+		}
+		if err := repos.JournalEntries.Save(ctx, je); err == nil {
+			t.Fatalf("expected a foreign key error for an unknown project")
+		}
+	})
+}
@@ -2,7 +2,7 @@ package sqlite
 
 import (
 	// std
-
+	"context"
 	"database/sql"
 
 	// external
@@ -14,29 +14,101 @@ import (
 )
 
 type Repositories struct {
-	Accounts      accounting.AccountRepository
-	AccountGroups accounting.AccountGroupRepository
-	// JournalEntries accounting.JournalEntryRepository
+	Accounts       accounting.AccountRepository
+	AccountGroups  accounting.AccountGroupRepository
+	JournalEntries accounting.JournalEntryRepository
+	Vendors        accounting.VendorRepository
+	Bills          accounting.BillRepository
+	Customers      accounting.CustomerRepository
+	Invoices       accounting.InvoiceRepository
+	BookSettings   accounting.BookSettingsRepository
+	Attachments    accounting.AttachmentRepository
+	FixedAssets    accounting.FixedAssetRepository
+	InventoryUnits accounting.InventoryUnitRepository
+	Stock          accounting.StockRepository
+	Counts         accounting.InventoryCountRepository
+	Projects       accounting.ProjectRepository
+	YearEnds       accounting.YearEndRepository
+	Periods        accounting.PeriodRepository
+	SETax          accounting.SETaxRepository
+	TaxLines       accounting.TaxLineRepository
+	SalesTax       accounting.SalesTaxRepository
+	Users          accounting.UserRepository
+	Sessions       accounting.SessionRepository
+	Audit          accounting.AuditRepository
+	Integrity      accounting.IntegrityRepository
+
+	db *sql.DB
 }
 
 // New opens/creates the DB, runs migrations, enables FK checks, and returns repositories
 func New(path string) (*Repositories, error) {
-	db, err := sql.Open("sqlite3", path)
+	db, err := Open(path)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
+	if err := migrate.Up(db); err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	if err := migrate.Up(db); err != nil {
+	// books which predate the hash chain are chained on first opening, and
+	// never again
+	if err := chainUnhashedEntries(context.Background(), db); err != nil {
 		return nil, err
 	}
 
 	return &Repositories{
-		Accounts:      &accountRepo{db: db},
-		AccountGroups: &accountGroupRepo{db: db},
-		// JournalEntries: &journalEntryRepo{db: db},
+		Accounts:       &accountRepo{db: db},
+		AccountGroups:  &accountGroupRepo{db: db},
+		JournalEntries: &journalEntryRepo{db: db},
+		Vendors:        &vendorRepo{db: db},
+		Bills:          &billRepo{db: db},
+		Customers:      &customerRepo{db: db},
+		Invoices:       &invoiceRepo{db: db},
+		BookSettings:   &bookSettingsRepo{db: db},
+		Attachments:    &attachmentRepo{db: db},
+		FixedAssets:    &fixedAssetRepo{db: db},
+		InventoryUnits: &inventoryUnitRepo{db: db},
+		Stock:          &stockRepo{db: db},
+		Counts:         &inventoryCountRepo{db: db},
+		Projects:       &projectRepo{db: db},
+		YearEnds:       &yearEndRepo{db: db},
+		Periods:        &periodRepo{db: db},
+		SETax:          &seTaxRepo{db: db},
+		TaxLines:       &taxLineRepo{db: db},
+		SalesTax:       &salesTaxRepo{db: db},
+		Users:          &userRepo{db: db},
+		Sessions:       &sessionRepo{db: db},
+		Audit:          &auditRepo{db: db},
+		Integrity:      &integrityRepo{db: db},
+		db:             db,
 	}, nil
 }
+
+// Open opens/creates the DB with FK checks enabled, without running
+// migrations, for inspecting or migrating it by hand
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite permits a single writer, and an in-memory database exists only
+	// on the connection which created it; share one connection throughout.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Close closes the database once the queries and transactions under way have
+// finished; the repositories are unusable afterward
+func (r *Repositories) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,275 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"time"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type salesTaxRepo struct {
+	db *sql.DB
+}
+
+// Retrieves the jurisdictions, rates and codes currently configured, each by name
+func (r *salesTaxRepo) Schedule(ctx context.Context) (accounting.SalesTaxSchedule, error) {
+	const jurisdictionsQuery = `
+		SELECT id, name, liability_account_name
+		FROM sales_tax_jurisdictions
+		ORDER BY name;
+	`
+	const ratesQuery = `
+		SELECT r.id, r.jurisdiction_id, r.name, r.rate
+		FROM sales_tax_rates r
+		JOIN sales_tax_jurisdictions j ON j.id = r.jurisdiction_id
+		ORDER BY j.name, r.name;
+	`
+	const codesQuery = `
+		SELECT code, description
+		FROM sales_tax_codes
+		ORDER BY code;
+	`
+	const codeRatesQuery = `
+		SELECT code, rate_id
+		FROM sales_tax_code_rates
+		ORDER BY code, position;
+	`
+
+	var schedule accounting.SalesTaxSchedule
+
+	rows, err := r.db.QueryContext(ctx, jurisdictionsQuery)
+	if err != nil {
+		return schedule, err
+	}
+	for rows.Next() {
+		var jurisdiction accounting.SalesTaxJurisdiction
+		if err := rows.Scan(&jurisdiction.ID, &jurisdiction.Name, &jurisdiction.LiabilityAccountName); err != nil {
+			rows.Close()
+			return schedule, err
+		}
+		schedule.Jurisdictions = append(schedule.Jurisdictions, jurisdiction)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return schedule, err
+	}
+
+	if rows, err = r.db.QueryContext(ctx, ratesQuery); err != nil {
+		return schedule, err
+	}
+	for rows.Next() {
+		var rate accounting.SalesTaxRate
+		if err := rows.Scan(&rate.ID, &rate.JurisdictionID, &rate.Name, &rate.Rate); err != nil {
+			rows.Close()
+			return schedule, err
+		}
+		schedule.Rates = append(schedule.Rates, rate)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return schedule, err
+	}
+
+	if rows, err = r.db.QueryContext(ctx, codesQuery); err != nil {
+		return schedule, err
+	}
+	index := make(map[string]int)
+	for rows.Next() {
+		var code accounting.SalesTaxCode
+		if err := rows.Scan(&code.Code, &code.Description); err != nil {
+			rows.Close()
+			return schedule, err
+		}
+		index[code.Code] = len(schedule.Codes)
+		schedule.Codes = append(schedule.Codes, code)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return schedule, err
+	}
+
+	if rows, err = r.db.QueryContext(ctx, codeRatesQuery); err != nil {
+		return schedule, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var code, rateID string
+		if err := rows.Scan(&code, &rateID); err != nil {
+			return schedule, err
+		}
+		if i, ok := index[code]; ok {
+			schedule.Codes[i].RateIDs = append(schedule.Codes[i].RateIDs, rateID)
+		}
+	}
+
+	return schedule, rows.Err()
+}
+
+// SaveJurisdiction inserts a jurisdiction, or renames an existing one; the
+// liability account of a jurisdiction is fixed once saved.
+func (r *salesTaxRepo) SaveJurisdiction(ctx context.Context, jurisdiction accounting.SalesTaxJurisdiction) error {
+	const query = `
+		INSERT INTO sales_tax_jurisdictions
+			(id, name, liability_account_name)
+		VALUES
+			(?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, jurisdiction.ID, jurisdiction.Name, jurisdiction.LiabilityAccountName)
+	return err
+}
+
+// SaveRate inserts a rate, or updates the name and rate of an existing one.
+func (r *salesTaxRepo) SaveRate(ctx context.Context, rate accounting.SalesTaxRate) error {
+	const query = `
+		INSERT INTO sales_tax_rates
+			(id, jurisdiction_id, name, rate)
+		VALUES
+			(?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			rate = excluded.rate;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, rate.ID, rate.JurisdictionID, rate.Name, rate.Rate)
+	return err
+}
+
+// SaveCode inserts a code, or updates the description of an existing one,
+// replacing its rates in a single transaction.
+func (r *salesTaxRepo) SaveCode(ctx context.Context, code accounting.SalesTaxCode) error {
+	const codeQuery = `
+		INSERT INTO sales_tax_codes
+			(code, description)
+		VALUES
+			(?, ?)
+		ON CONFLICT (code) DO UPDATE SET
+			description = excluded.description;
+	`
+	const clearQuery = `
+		DELETE FROM sales_tax_code_rates
+		WHERE code = ?;
+	`
+	const rateQuery = `
+		INSERT INTO sales_tax_code_rates
+			(code, rate_id, position)
+		VALUES
+			(?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, codeQuery, code.Code, code.Description); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, clearQuery, code.Code); err != nil {
+			return err
+		}
+
+		for i, rateID := range code.RateIDs {
+			if _, err := tx.ExecContext(ctx, rateQuery, code.Code, rateID, i+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RecordRemittance saves a remittance and the journal entry which posts it in a single transaction.
+func (r *salesTaxRepo) RecordRemittance(ctx context.Context, remittance *accounting.SalesTaxRemittance, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO sales_tax_remittances
+			(id, jurisdiction_id, period_start, period_end, paid_on, amount,
+			 liability_account_name, cash_account_name, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			remittance.ID,
+			remittance.JurisdictionID,
+			formatDate(remittance.PeriodStart),
+			formatDate(remittance.PeriodEnd),
+			formatDate(remittance.PaidOn),
+			remittance.Amount,
+			remittance.LiabilityAccountName,
+			remittance.CashAccountName,
+			remittance.Memo,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		remittance.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves the remittances for periods ending within [from, to], ordered by period end
+func (r *salesTaxRepo) Remittances(ctx context.Context, from, to time.Time) ([]accounting.SalesTaxRemittance, error) {
+	const query = `
+		SELECT id, jurisdiction_id, period_start, period_end, paid_on, amount,
+			liability_account_name, cash_account_name, memo, journal_entry_id
+		FROM sales_tax_remittances
+		WHERE period_end >= ? AND period_end <= ?
+		ORDER BY period_end, paid_on, rowid;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, formatDate(from), formatDate(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var remittances []accounting.SalesTaxRemittance
+	for rows.Next() {
+		var (
+			remittance                     accounting.SalesTaxRemittance
+			periodStart, periodEnd, paidOn string
+		)
+		if err := rows.Scan(
+			&remittance.ID,
+			&remittance.JurisdictionID,
+			&periodStart,
+			&periodEnd,
+			&paidOn,
+			&remittance.Amount,
+			&remittance.LiabilityAccountName,
+			&remittance.CashAccountName,
+			&remittance.Memo,
+			&remittance.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if remittance.PeriodStart, err = parseDate(periodStart); err != nil {
+			return nil, err
+		}
+		if remittance.PeriodEnd, err = parseDate(periodEnd); err != nil {
+			return nil, err
+		}
+		if remittance.PaidOn, err = parseDate(paidOn); err != nil {
+			return nil, err
+		}
+
+		remittances = append(remittances, remittance)
+	}
+
+	return remittances, rows.Err()
+}
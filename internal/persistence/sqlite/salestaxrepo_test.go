@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestSalesTaxRepo(t *testing.T) {
+	ctx := context.Background()
+
+	// a repository with a state jurisdiction and rate, and a code charging it
+	setup := func(t *testing.T) (*Repositories, accounting.SalesTaxJurisdiction) {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "State Sales Tax Payable", "Liabilities", accounting.Liability, accounting.CreditNormal)
+
+		state, _ := accounting.NewSalesTaxJurisdiction("State", "State Sales Tax Payable")
+		if err := repos.SalesTax.SaveJurisdiction(ctx, *state); err != nil {
+			t.Fatalf("failed to save jurisdiction with error %v", err)
+		}
+		if err := repos.SalesTax.SaveRate(ctx, accounting.SalesTaxRate{ID: "state-general", JurisdictionID: state.ID, Name: "General", Rate: 0.05}); err != nil {
+			t.Fatalf("failed to save rate with error %v", err)
+		}
+		if err := repos.SalesTax.SaveCode(ctx, accounting.SalesTaxCode{Code: "TAX", Description: "Taxable", RateIDs: []string{"state-general"}}); err != nil {
+			t.Fatalf("failed to save code with error %v", err)
+		}
+
+		return repos, *state
+	}
+
+	t.Run("saves and retrieves the schedule", func(t *testing.T) {
+		repos, state := setup(t)
+
+		if err := repos.SalesTax.SaveRate(ctx, accounting.SalesTaxRate{ID: "state-general", JurisdictionID: state.ID, Name: "General", Rate: 0.055}); err != nil {
+			t.Fatalf("failed to update rate with error %v", err)
+		}
+
+		schedule, err := repos.SalesTax.Schedule(ctx)
+		if err != nil {
+			t.Fatalf("failed to get schedule with error %v", err)
+		}
+
+		if len(schedule.Jurisdictions) != 1 || schedule.Jurisdictions[0] != state {
+			t.Fatalf("expected the state jurisdiction, got %+v", schedule.Jurisdictions)
+		}
+		if len(schedule.Rates) != 1 || schedule.Rates[0].Rate != 0.055 {
+			t.Fatalf("expected the updated rate, got %+v", schedule.Rates)
+		}
+		// the seeded exempt code sorts first
+		if len(schedule.Codes) != 2 || !schedule.Codes[0].Exempt() || schedule.Codes[1].Code != "TAX" || len(schedule.Codes[1].RateIDs) != 1 {
+			t.Fatalf("expected the EXEMPT and TAX codes, got %+v", schedule.Codes)
+		}
+	})
+
+	t.Run("keeps the tax an invoice charged", func(t *testing.T) {
+		repos, _ := setup(t)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		customer, _ := accounting.NewCustomer("Acme Corp.", accounting.DefaultPaymentTerms)
+		if err := repos.Customers.Save(ctx, customer); err != nil {
+			t.Fatalf("failed to save customer with error %v", err)
+		}
+		invoice, _ := accounting.NewInvoice(customer.ID, "1001", time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), customer.DefaultTerms, "Accounts Receivable", []accounting.InvoiceLine{
+			{AccountName: "Sales", Amount: 200, TaxCode: "TAX"},
+			{AccountName: "Sales", Amount: 100, TaxCode: "EXEMPT"},
+		})
+		schedule, _ := repos.SalesTax.Schedule(ctx)
+		if err := schedule.Apply(invoice); err != nil {
+			t.Fatalf("failed to apply sales tax with error %v", err)
+		}
+		if err := repos.Invoices.Post(ctx, invoice, invoice.JournalEntry()); err != nil {
+			t.Fatalf("failed to post invoice with error %v", err)
+		}
+
+		// a later rate change leaves the invoice be
+		if err := repos.SalesTax.SaveRate(ctx, accounting.SalesTaxRate{ID: "state-general", JurisdictionID: schedule.Jurisdictions[0].ID, Name: "General", Rate: 0.07}); err != nil {
+			t.Fatalf("failed to update rate with error %v", err)
+		}
+
+		saved, err := repos.Invoices.ByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve invoice with error %v", err)
+		}
+		if saved.Lines[0].TaxCode != "TAX" || saved.Lines[1].TaxCode != "EXEMPT" {
+			t.Fatalf("expected the lines' tax codes, got %+v", saved.Lines)
+		}
+		if len(saved.Taxes) != 1 || saved.Taxes[0] != invoice.Taxes[0] || saved.Total() != 310 {
+			t.Fatalf("expected 10 of tax for a total of 310, got %+v", saved.Taxes)
+		}
+	})
+
+	t.Run("records remittances and retrieves them by period end", func(t *testing.T) {
+		repos, state := setup(t)
+
+		march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		remittance, _ := accounting.NewSalesTaxRemittance(state, march, march.AddDate(0, 1, -1), march.AddDate(0, 1, 14), "Cash", 0.01, "")
+		if err := repos.SalesTax.RecordRemittance(ctx, remittance, remittance.JournalEntry(state)); err != nil {
+			t.Fatalf("failed to record remittance with error %v", err)
+		}
+		if remittance.EntryID == "" {
+			t.Fatal("expected the remittance to record its journal entry")
+		}
+
+		remittances, err := repos.SalesTax.Remittances(ctx, march, march.AddDate(0, 1, -1))
+		if err != nil {
+			t.Fatalf("failed to get remittances with error %v", err)
+		}
+		if len(remittances) != 1 || remittances[0] != *remittance {
+			t.Fatalf("expected %+v, got %+v", *remittance, remittances)
+		}
+
+		if later, _ := repos.SalesTax.Remittances(ctx, march.AddDate(0, 1, 0), march.AddDate(0, 2, 0)); len(later) != 0 {
+			t.Fatalf("expected no remittances for April, got %+v", later)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"time"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type sessionRepo struct {
+	db *sql.DB
+}
+
+// Save inserts a session
+func (r *sessionRepo) Save(ctx context.Context, session *accounting.Session) error {
+	const query = `
+		INSERT INTO sessions
+			(id, user_id, csrf_token, created_at, expires_at)
+		VALUES
+			(?, ?, ?, ?, ?);
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.CSRFToken,
+		formatTimestamp(session.CreatedAt),
+		formatTimestamp(session.ExpiresAt),
+	)
+
+	return err
+}
+
+// Retrieves a session by ID, whether or not it has expired
+//
+// Returns ErrSessionNotFound if the session does not exist.
+func (r *sessionRepo) ByID(ctx context.Context, id string) (*accounting.Session, error) {
+	const query = `
+		SELECT id, user_id, csrf_token, created_at, expires_at
+		FROM sessions
+		WHERE id = ?;
+	`
+
+	var (
+		session              accounting.Session
+		createdAt, expiresAt string
+	)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.CSRFToken,
+		&createdAt,
+		&expiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &accounting.ErrSessionNotFound{}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return nil, err
+	}
+	if session.ExpiresAt, err = parseTimestamp(expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Delete removes a session; removing one which does not exist is not an error
+func (r *sessionRepo) Delete(ctx context.Context, id string) error {
+	const query = `
+		DELETE FROM sessions
+		WHERE id = ?;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// DeleteExpired removes the sessions which have expired by the given time
+func (r *sessionRepo) DeleteExpired(ctx context.Context, now time.Time) error {
+	const query = `
+		DELETE FROM sessions
+		WHERE expires_at <= ?;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, formatTimestamp(now))
+	return err
+}
@@ -0,0 +1,301 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type seTaxRepo struct {
+	db *sql.DB
+}
+
+const seTaxRatesColumns = `
+	tax_year, version, net_earnings_factor, social_security_rate, social_security_wage_base,
+	medicare_rate, additional_medicare_rate, additional_medicare_threshold, minimum_net_earnings, note, entered_at
+`
+
+// Retrieves the latest version of the rates for the latest tax year no later than taxYear
+//
+// Returns ErrSETaxRatesNotFound if no such rates exist.
+func (r *seTaxRepo) Rates(ctx context.Context, taxYear int) (accounting.SETaxRates, error) {
+	query := `
+		SELECT ` + seTaxRatesColumns + `
+		FROM se_tax_rates
+		WHERE tax_year <= ?
+		ORDER BY tax_year DESC, version DESC
+		LIMIT 1;
+	`
+
+	rates, err := scanSETaxRates(r.db.QueryRowContext(ctx, query, taxYear))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.SETaxRates{}, &accounting.ErrSETaxRatesNotFound{TaxYear: taxYear}
+		}
+		return accounting.SETaxRates{}, err
+	}
+
+	return rates, nil
+}
+
+// Retrieves every version of a tax year's rates, latest first
+func (r *seTaxRepo) RateVersions(ctx context.Context, taxYear int) ([]accounting.SETaxRates, error) {
+	query := `
+		SELECT ` + seTaxRatesColumns + `
+		FROM se_tax_rates
+		WHERE tax_year = ?
+		ORDER BY version DESC;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []accounting.SETaxRates
+	for rows.Next() {
+		rates, err := scanSETaxRates(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, rates)
+	}
+
+	return versions, rows.Err()
+}
+
+// SaveRates inserts the rates as the next version for their tax year, setting rates.Version.
+func (r *seTaxRepo) SaveRates(ctx context.Context, rates *accounting.SETaxRates) error {
+	const versionQuery = `
+		SELECT COALESCE(MAX(version), 0) + 1
+		FROM se_tax_rates
+		WHERE tax_year = ?;
+	`
+	query := `
+		INSERT INTO se_tax_rates
+			(` + seTaxRatesColumns + `)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var version int
+		if err := tx.QueryRowContext(ctx, versionQuery, rates.TaxYear).Scan(&version); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			rates.TaxYear,
+			version,
+			rates.NetEarningsFactor,
+			rates.SocialSecurityRate,
+			rates.SocialSecurityWageBase,
+			rates.MedicareRate,
+			rates.AdditionalMedicareRate,
+			rates.AdditionalMedicareThreshold,
+			rates.MinimumNetEarnings,
+			rates.Note,
+			formatTimestamp(rates.EnteredAt),
+		); err != nil {
+			return err
+		}
+
+		rates.Version = version
+		return nil
+	})
+}
+
+// PostReserve saves a reserve transfer and the journal entry which posts it in a single transaction.
+func (r *seTaxRepo) PostReserve(ctx context.Context, transfer *accounting.TaxReserveTransfer, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO se_tax_reserve_transfers
+			(id, tax_year, transfer_date, amount, from_account_name, reserve_account_name, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			transfer.ID,
+			transfer.TaxYear,
+			formatDate(transfer.Date),
+			transfer.Amount,
+			transfer.FromAccountName,
+			transfer.ReserveAccountName,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		transfer.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves the reserve transfers made for a tax year, ordered by date
+func (r *seTaxRepo) Reserves(ctx context.Context, taxYear int) ([]accounting.TaxReserveTransfer, error) {
+	const query = `
+		SELECT id, tax_year, transfer_date, amount, from_account_name, reserve_account_name, journal_entry_id
+		FROM se_tax_reserve_transfers
+		WHERE tax_year = ?
+		ORDER BY transfer_date, id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []accounting.TaxReserveTransfer
+	for rows.Next() {
+		var (
+			transfer accounting.TaxReserveTransfer
+			date     string
+		)
+		if err := rows.Scan(
+			&transfer.ID,
+			&transfer.TaxYear,
+			&date,
+			&transfer.Amount,
+			&transfer.FromAccountName,
+			&transfer.ReserveAccountName,
+			&transfer.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if transfer.Date, err = parseDate(date); err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+// RecordPayment saves an estimated payment and the journal entry which posts it in a single transaction.
+func (r *seTaxRepo) RecordPayment(ctx context.Context, payment *accounting.EstimatedTaxPayment, je accounting.JournalEntry) error {
+	const query = `
+		INSERT INTO estimated_tax_payments
+			(id, tax_year, quarter, paid_on, amount, from_account_name, draw_account_name, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			payment.ID,
+			payment.TaxYear,
+			payment.Quarter,
+			formatDate(payment.PaidOn),
+			payment.Amount,
+			payment.FromAccountName,
+			payment.DrawAccountName,
+			payment.Memo,
+			je.ID,
+		); err != nil {
+			return err
+		}
+
+		payment.EntryID = je.ID
+		return nil
+	})
+}
+
+// Retrieves the estimated payments made for a tax year, ordered by quarter and date
+func (r *seTaxRepo) Payments(ctx context.Context, taxYear int) ([]accounting.EstimatedTaxPayment, error) {
+	const query = `
+		SELECT id, tax_year, quarter, paid_on, amount, from_account_name, draw_account_name, memo, journal_entry_id
+		FROM estimated_tax_payments
+		WHERE tax_year = ?
+		ORDER BY quarter, paid_on, id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []accounting.EstimatedTaxPayment
+	for rows.Next() {
+		var (
+			payment accounting.EstimatedTaxPayment
+			paidOn  string
+		)
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.TaxYear,
+			&payment.Quarter,
+			&paidOn,
+			&payment.Amount,
+			&payment.FromAccountName,
+			&payment.DrawAccountName,
+			&payment.Memo,
+			&payment.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if payment.PaidOn, err = parseDate(paidOn); err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+func scanSETaxRates(s scanner) (accounting.SETaxRates, error) {
+	var (
+		rates     accounting.SETaxRates
+		enteredAt string
+	)
+
+	if err := s.Scan(
+		&rates.TaxYear,
+		&rates.Version,
+		&rates.NetEarningsFactor,
+		&rates.SocialSecurityRate,
+		&rates.SocialSecurityWageBase,
+		&rates.MedicareRate,
+		&rates.AdditionalMedicareRate,
+		&rates.AdditionalMedicareThreshold,
+		&rates.MinimumNetEarnings,
+		&rates.Note,
+		&enteredAt,
+	); err != nil {
+		return accounting.SETaxRates{}, err
+	}
+
+	var err error
+	if rates.EnteredAt, err = parseTimestamp(enteredAt); err != nil {
+		return accounting.SETaxRates{}, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestSETaxRepo(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) *Repositories {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		return repos
+	}
+
+	t.Run("saves rates as new versions and falls back to earlier years", func(t *testing.T) {
+		repos := setup(t)
+
+		seeded, err := repos.SETax.Rates(ctx, 2025)
+		if err != nil {
+			t.Fatalf("failed to get rates with error %v", err)
+		}
+		if seeded.TaxYear != 2025 || seeded.Version != 1 || seeded.SocialSecurityWageBase != 176100 {
+			t.Fatalf("expected the seeded 2025 rates, got %+v", seeded)
+		}
+
+		edited := seeded
+		edited.AdditionalMedicareThreshold = 250000
+		edited.Note = "married filing jointly"
+		edited.EnteredAt = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+		if err := repos.SETax.SaveRates(ctx, &edited); err != nil {
+			t.Fatalf("failed to save rates with error %v", err)
+		}
+		if edited.Version != 2 {
+			t.Fatalf("expected version 2, got %d", edited.Version)
+		}
+
+		current, _ := repos.SETax.Rates(ctx, 2025)
+		if current != edited {
+			t.Fatalf("expected the latest version %+v, got %+v", edited, current)
+		}
+		versions, _ := repos.SETax.RateVersions(ctx, 2025)
+		if len(versions) != 2 || versions[1] != seeded {
+			t.Fatalf("expected both versions, latest first, got %+v", versions)
+		}
+
+		later, _ := repos.SETax.Rates(ctx, 2030)
+		if later.TaxYear != 2026 {
+			t.Fatalf("expected a later year to fall back to the 2026 rates, got %d", later.TaxYear)
+		}
+		if _, err := repos.SETax.Rates(ctx, 2000); !accounting.IsSETaxRatesNotFound(err) {
+			t.Fatalf("expected an ErrSETaxRatesNotFound, received %v", err)
+		}
+	})
+
+	t.Run("posts reserve transfers and estimated payments", func(t *testing.T) {
+		repos := setup(t)
+
+		transfer, _ := accounting.NewTaxReserveTransfer(2025, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), 3000, "Cash", "Tax Reserve")
+		if err := repos.SETax.PostReserve(ctx, transfer, transfer.JournalEntry()); err != nil {
+			t.Fatalf("failed to post reserve with error %v", err)
+		}
+
+		payment, _ := accounting.NewEstimatedTaxPayment(2025, 1, time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC), 2500, "Tax Reserve", "Owner Draws", "")
+		if err := repos.SETax.RecordPayment(ctx, payment, payment.JournalEntry()); err != nil {
+			t.Fatalf("failed to record payment with error %v", err)
+		}
+
+		transfers, _ := repos.SETax.Reserves(ctx, 2025)
+		payments, _ := repos.SETax.Payments(ctx, 2025)
+		if len(transfers) != 1 || transfers[0] != *transfer {
+			t.Fatalf("expected the transfer %+v, got %+v", *transfer, transfers)
+		}
+		if len(payments) != 1 || payments[0] != *payment {
+			t.Fatalf("expected the payment %+v, got %+v", *payment, payments)
+		}
+
+		totals, err := repos.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to get account totals with error %v", err)
+		}
+		for _, total := range totals {
+			if total.AccountName == "Tax Reserve" && total.Balance(accounting.DebitNormal) != 500 {
+				t.Fatalf("expected 500 left in reserve, got %v", total.Balance(accounting.DebitNormal))
+			}
+		}
+	})
+}
@@ -0,0 +1,287 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type stockRepo struct {
+	db *sql.DB
+}
+
+// SaveItem inserts or updates a stock item in the database.
+func (r *stockRepo) SaveItem(ctx context.Context, item *accounting.StockItem) error {
+	const query = `
+		INSERT INTO stock_items
+			(id, sku, name, costing_method, inventory_account_name, cogs_account_name)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			sku = excluded.sku,
+			name = excluded.name,
+			costing_method = excluded.costing_method,
+			inventory_account_name = excluded.inventory_account_name,
+			cogs_account_name = excluded.cogs_account_name;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		item.ID,
+		item.SKU,
+		item.Name,
+		item.Method,
+		item.InventoryAccountName,
+		item.COGSAccountName,
+	)
+
+	return err
+}
+
+// Retrieves a stock item by ID
+//
+// Returns ErrStockItemNotFound if the item does not exist.
+func (r *stockRepo) ItemByID(ctx context.Context, id string) (accounting.StockItem, error) {
+	const query = `
+		SELECT id, sku, name, costing_method, inventory_account_name, cogs_account_name
+		FROM stock_items
+		WHERE id = ?;
+	`
+
+	item, err := scanStockItem(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.StockItem{}, &accounting.ErrStockItemNotFound{ID: id}
+		}
+		return accounting.StockItem{}, err
+	}
+
+	return item, nil
+}
+
+// Retrieves all stock items, ordered by SKU
+func (r *stockRepo) Items(ctx context.Context) ([]*accounting.StockItem, error) {
+	const query = `
+		SELECT id, sku, name, costing_method, inventory_account_name, cogs_account_name
+		FROM stock_items
+		ORDER BY sku;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*accounting.StockItem
+	for rows.Next() {
+		item, err := scanStockItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+// Retrieves an item's movements, ordered by date and then the order they were recorded
+func (r *stockRepo) Movements(ctx context.Context, itemID string) ([]accounting.StockMovement, error) {
+	const query = `
+		SELECT id, item_id, kind, movement_date, sequence, quantity, cost, account_name, project_id, memo, journal_entry_id
+		FROM stock_movements
+		WHERE item_id = ?
+		ORDER BY movement_date, sequence;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []accounting.StockMovement
+	for rows.Next() {
+		var (
+			movement  accounting.StockMovement
+			date      string
+			projectID sql.NullString
+		)
+		if err := rows.Scan(
+			&movement.ID,
+			&movement.ItemID,
+			&movement.Kind,
+			&date,
+			&movement.Sequence,
+			&movement.Quantity,
+			&movement.Cost,
+			&movement.AccountName,
+			&projectID,
+			&movement.Memo,
+			&movement.EntryID,
+		); err != nil {
+			return nil, err
+		}
+
+		if movement.Date, err = parseDate(date); err != nil {
+			return nil, err
+		}
+
+		movement.ProjectID = projectID.String
+
+		movements = append(movements, movement)
+	}
+
+	return movements, rows.Err()
+}
+
+// Record saves a movement and the entry which posts it, along with each
+// adjustment to a recosted issue and its entry, in a single transaction.
+// Adjusted issues take their new cost.
+func (r *stockRepo) Record(
+	ctx context.Context,
+	movement *accounting.StockMovement,
+	je accounting.JournalEntry,
+	adjustments []accounting.StockCostAdjustment,
+	adjustmentEntries []accounting.JournalEntry,
+) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if err := insertStockMovement(ctx, tx, movement, je.ID); err != nil {
+			return err
+		}
+
+		return insertStockCostAdjustments(ctx, tx, adjustments, adjustmentEntries)
+	})
+}
+
+// Retrieves the cost adjustments made to a movement, in the order they were made
+func (r *stockRepo) Adjustments(ctx context.Context, movementID string) ([]accounting.StockCostAdjustment, error) {
+	const query = `
+		SELECT a.id, a.movement_id, m.journal_entry_id, a.previous_cost, a.new_cost, a.journal_entry_id
+		FROM stock_cost_adjustments a
+		JOIN stock_movements m ON m.id = a.movement_id
+		WHERE a.movement_id = ?
+		ORDER BY a.id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, movementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []accounting.StockCostAdjustment
+	for rows.Next() {
+		var adjustment accounting.StockCostAdjustment
+		if err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.MovementID,
+			&adjustment.AffectedEntryID,
+			&adjustment.PreviousCost,
+			&adjustment.NewCost,
+			&adjustment.EntryID,
+		); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	return adjustments, rows.Err()
+}
+
+func scanStockItem(s scanner) (accounting.StockItem, error) {
+	var item accounting.StockItem
+
+	err := s.Scan(
+		&item.ID,
+		&item.SKU,
+		&item.Name,
+		&item.Method,
+		&item.InventoryAccountName,
+		&item.COGSAccountName,
+	)
+
+	return item, err
+}
+
+// insertStockMovement writes a movement within tx, recording the
+// already-inserted journal entry which posts it
+func insertStockMovement(ctx context.Context, tx *sql.Tx, movement *accounting.StockMovement, entryID string) error {
+	const query = `
+		INSERT INTO stock_movements
+			(id, item_id, kind, movement_date, sequence, quantity, cost, account_name, project_id, memo, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		movement.ID,
+		movement.ItemID,
+		movement.Kind,
+		formatDate(movement.Date),
+		movement.Sequence,
+		movement.Quantity,
+		movement.Cost,
+		movement.AccountName,
+		sql.NullString{String: movement.ProjectID, Valid: movement.ProjectID != ""},
+		movement.Memo,
+		entryID,
+	); err != nil {
+		return err
+	}
+
+	movement.EntryID = entryID
+	return nil
+}
+
+// insertStockCostAdjustments posts each adjustment's entry within tx, records
+// the adjustment, and brings the issue it corrects to its new cost
+func insertStockCostAdjustments(ctx context.Context, tx *sql.Tx, adjustments []accounting.StockCostAdjustment, entries []accounting.JournalEntry) error {
+	const adjustmentQuery = `
+		INSERT INTO stock_cost_adjustments
+			(id, movement_id, previous_cost, new_cost, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?);
+	`
+	const recostQuery = `
+		UPDATE stock_movements
+		SET cost = ?
+		WHERE id = ?;
+	`
+
+	for i, adjustment := range adjustments {
+		if err := insertJournalEntry(ctx, tx, entries[i]); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(
+			ctx,
+			adjustmentQuery,
+			adjustment.ID,
+			adjustment.MovementID,
+			adjustment.PreviousCost,
+			adjustment.NewCost,
+			entries[i].ID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, recostQuery, adjustment.NewCost, adjustment.MovementID); err != nil {
+			return err
+		}
+		adjustments[i].EntryID = entries[i].ID
+	}
+
+	return nil
+}
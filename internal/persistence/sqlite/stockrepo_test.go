@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestStockRepo(t *testing.T) {
+	t.Run("records movements and recosts issues atomically", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+
+		item, err := accounting.NewStockItem("BOLT", "Bolt", accounting.FIFO, "Inventory", "Cost of Goods Sold")
+		if err != nil {
+			t.Fatalf("failed to create stock item with error %v", err)
+		}
+		if err := repos.Stock.SaveItem(ctx, item); err != nil {
+			t.Fatalf("failed to save stock item with error %v", err)
+		}
+
+		record := func(movement *accounting.StockMovement) []accounting.StockCostAdjustment {
+			t.Helper()
+			recorded, err := repos.Stock.Movements(ctx, item.ID)
+			if err != nil {
+				t.Fatalf("failed to retrieve movements with error %v", err)
+			}
+			adjustments, err := accounting.ApplyStockMovement(item, recorded, movement)
+			if err != nil {
+				t.Fatalf("failed to apply movement with error %v", err)
+			}
+			entries := make([]accounting.JournalEntry, len(adjustments))
+			for i, adjustment := range adjustments {
+				for _, m := range recorded {
+					if m.ID == adjustment.MovementID {
+						entries[i] = adjustment.JournalEntry(item, &m)
+					}
+				}
+			}
+			if err := repos.Stock.Record(ctx, movement, movement.JournalEntry(item), adjustments, entries); err != nil {
+				t.Fatalf("failed to record movement with error %v", err)
+			}
+			return adjustments
+		}
+
+		receipt, _ := accounting.NewStockReceipt(item, time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), 10, 2, "Cash")
+		record(receipt)
+		issue, _ := accounting.NewStockIssue(item, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), 5, "")
+		record(issue)
+
+		backdated, _ := accounting.NewStockReceipt(item, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), 5, 1, "Cash")
+		adjustments := record(backdated)
+		if len(adjustments) != 1 || adjustments[0].EntryID == "" {
+			t.Fatalf("expected the issue to be recosted by a posted entry, received %+v", adjustments)
+		}
+
+		movements, err := repos.Stock.Movements(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve movements with error %v", err)
+		}
+		if len(movements) != 3 || movements[0].ID != backdated.ID || movements[2].ID != issue.ID || movements[2].Cost != 5 {
+			t.Fatalf("expected the backdated receipt first and the issue recosted to 5, received %+v", movements)
+		}
+
+		saved, err := repos.Stock.Adjustments(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve adjustments with error %v", err)
+		}
+		if len(saved) != 1 || saved[0] != adjustments[0] || saved[0].AffectedEntryID != issue.EntryID {
+			t.Fatalf("expected to retrieve %+v, received %+v", adjustments, saved)
+		}
+
+		je, err := repos.JournalEntries.ByID(ctx, adjustments[0].EntryID)
+		if err != nil {
+			t.Fatalf("failed to retrieve adjusting entry with error %v", err)
+		}
+		if je.CrossReference.String != issue.EntryID {
+			t.Fatalf("expected the adjustment to reference the issue's entry, received %+v", je)
+		}
+
+		totals, err := repos.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to retrieve account totals with error %v", err)
+		}
+		_, valuation, _ := accounting.CostMovements(item, movements)
+		for _, total := range totals {
+			if total.AccountName == "Inventory" && total.Balance(accounting.DebitNormal) != valuation.Value {
+				t.Fatalf("expected inventory of %.2f to match the layers, received %+v", valuation.Value, total)
+			}
+		}
+	})
+
+	t.Run("returns a specific error if the item is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.Stock.ItemByID(context.Background(), "missing"); !accounting.IsStockItemNotFound(err) {
+			t.Fatalf("expected a StockItemNotFound error, received %v", err)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"fmt"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type taxLineRepo struct {
+	db *sql.DB
+}
+
+// Retrieves the names of the forms with lines for a tax year
+func (r *taxLineRepo) Forms(ctx context.Context, taxYear int) ([]string, error) {
+	const query = `
+		SELECT DISTINCT form
+		FROM tax_lines
+		WHERE tax_year = ?
+		ORDER BY form;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forms []string
+	for rows.Next() {
+		var form string
+		if err := rows.Scan(&form); err != nil {
+			return nil, err
+		}
+		forms = append(forms, form)
+	}
+
+	return forms, rows.Err()
+}
+
+// Retrieves a form's lines for a tax year in display order
+func (r *taxLineRepo) Lines(ctx context.Context, taxYear int, form string) ([]accounting.TaxLine, error) {
+	const query = `
+		SELECT tax_year, form, code, description, display_order
+		FROM tax_lines
+		WHERE tax_year = ? AND form = ?
+		ORDER BY display_order, code;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear, form)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []accounting.TaxLine
+	for rows.Next() {
+		var line accounting.TaxLine
+		if err := rows.Scan(&line.TaxYear, &line.Form, &line.Code, &line.Description, &line.DisplayOrder); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// SaveLine inserts a line, or updates the description and order of an existing one.
+func (r *taxLineRepo) SaveLine(ctx context.Context, line accounting.TaxLine) error {
+	const query = `
+		INSERT INTO tax_lines
+			(tax_year, form, code, description, display_order)
+		VALUES
+			(?, ?, ?, ?, ?)
+		ON CONFLICT (tax_year, form, code) DO UPDATE SET
+			description = excluded.description,
+			display_order = excluded.display_order;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, line.TaxYear, line.Form, line.Code, line.Description, line.DisplayOrder)
+	return err
+}
+
+// DeleteLine removes a line to which no account is mapped
+//
+// Returns ErrTaxLineNotFound if the line does not exist.
+func (r *taxLineRepo) DeleteLine(ctx context.Context, taxYear int, form, code string) error {
+	const mappedQuery = `
+		SELECT COUNT(*)
+		FROM tax_line_mappings
+		WHERE tax_year = ? AND form = ? AND line_code = ?;
+	`
+	const deleteQuery = `
+		DELETE FROM tax_lines
+		WHERE tax_year = ? AND form = ? AND code = ?;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var mapped int
+		if err := tx.QueryRowContext(ctx, mappedQuery, taxYear, form, code).Scan(&mapped); err != nil {
+			return err
+		}
+		if mapped > 0 {
+			return fmt.Errorf("cannot delete line %s of %s for %d while accounts are mapped to it", code, form, taxYear)
+		}
+
+		result, err := tx.ExecContext(ctx, deleteQuery, taxYear, form, code)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return &accounting.ErrTaxLineNotFound{TaxYear: taxYear, Form: form, Code: code}
+		}
+
+		return nil
+	})
+}
+
+// Retrieves the accounts mapped to a form's lines for a tax year
+func (r *taxLineRepo) Mappings(ctx context.Context, taxYear int, form string) ([]accounting.TaxLineMapping, error) {
+	const query = `
+		SELECT tax_year, form, account_name, line_code
+		FROM tax_line_mappings
+		WHERE tax_year = ? AND form = ?
+		ORDER BY account_name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taxYear, form)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mappings []accounting.TaxLineMapping
+	for rows.Next() {
+		var mapping accounting.TaxLineMapping
+		if err := rows.Scan(&mapping.TaxYear, &mapping.Form, &mapping.AccountName, &mapping.LineCode); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+// Map maps an account to a line, replacing its mapping on the form for the year
+//
+// Returns ErrTaxLineNotFound if the line does not exist.
+func (r *taxLineRepo) Map(ctx context.Context, mapping accounting.TaxLineMapping) error {
+	const lineQuery = `
+		SELECT COUNT(*)
+		FROM tax_lines
+		WHERE tax_year = ? AND form = ? AND code = ?;
+	`
+	const query = `
+		INSERT INTO tax_line_mappings
+			(tax_year, form, account_name, line_code)
+		VALUES
+			(?, ?, ?, ?)
+		ON CONFLICT (tax_year, form, account_name) DO UPDATE SET
+			line_code = excluded.line_code;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var exists int
+		if err := tx.QueryRowContext(ctx, lineQuery, mapping.TaxYear, mapping.Form, mapping.LineCode).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return &accounting.ErrTaxLineNotFound{TaxYear: mapping.TaxYear, Form: mapping.Form, Code: mapping.LineCode}
+		}
+
+		_, err := tx.ExecContext(ctx, query, mapping.TaxYear, mapping.Form, mapping.AccountName, mapping.LineCode)
+		return err
+	})
+}
+
+// Unmap removes an account's mapping on a form for a tax year, if it has one.
+func (r *taxLineRepo) Unmap(ctx context.Context, taxYear int, form, accountName string) error {
+	const query = `
+		DELETE FROM tax_line_mappings
+		WHERE tax_year = ? AND form = ? AND account_name = ?;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, taxYear, form, accountName)
+	return err
+}
+
+// CopyYear copies one year's lines and mappings to another in a single
+// transaction, keeping those the other year already has.
+func (r *taxLineRepo) CopyYear(ctx context.Context, fromYear, toYear int) error {
+	const linesQuery = `
+		INSERT OR IGNORE INTO tax_lines
+			(tax_year, form, code, description, display_order)
+		SELECT ?, form, code, description, display_order
+		FROM tax_lines
+		WHERE tax_year = ?;
+	`
+	const mappingsQuery = `
+		INSERT OR IGNORE INTO tax_line_mappings
+			(tax_year, form, account_name, line_code)
+		SELECT ?, form, account_name, line_code
+		FROM tax_line_mappings
+		WHERE tax_year = ?;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, linesQuery, toYear, fromYear); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, mappingsQuery, toYear, fromYear)
+		return err
+	})
+}
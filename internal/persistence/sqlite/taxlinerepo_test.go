@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestTaxLineRepo(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) *Repositories {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+		return repos
+	}
+
+	t.Run("seeds Schedule C lines and mappings per year", func(t *testing.T) {
+		repos := setup(t)
+
+		forms, err := repos.TaxLines.Forms(ctx, 2025)
+		if err != nil {
+			t.Fatalf("failed to get forms with error %v", err)
+		}
+		if len(forms) != 1 || forms[0] != accounting.ScheduleC {
+			t.Fatalf("expected Schedule C, got %v", forms)
+		}
+
+		lines, _ := repos.TaxLines.Lines(ctx, 2025, accounting.ScheduleC)
+		if len(lines) == 0 || lines[0].Code != "1" || lines[len(lines)-1].Code != "27a" {
+			t.Fatalf("expected lines 1 through 27a in order, got %+v", lines)
+		}
+
+		mappings, _ := repos.TaxLines.Mappings(ctx, 2025, accounting.ScheduleC)
+		var cogs string
+		for _, mapping := range mappings {
+			if mapping.AccountName == "Cost of Goods Sold" {
+				cogs = mapping.LineCode
+			}
+		}
+		if cogs != "4" {
+			t.Fatalf("expected cost of goods sold on line 4, got %+v", mappings)
+		}
+	})
+
+	t.Run("maps, remaps and unmaps accounts", func(t *testing.T) {
+		repos := setup(t)
+		mapping := accounting.TaxLineMapping{TaxYear: 2025, Form: accounting.ScheduleC, AccountName: "Sales", LineCode: "6"}
+
+		if err := repos.TaxLines.Map(ctx, mapping); err != nil {
+			t.Fatalf("failed to map account with error %v", err)
+		}
+		mapping.LineCode = "1"
+		if err := repos.TaxLines.Map(ctx, mapping); err != nil {
+			t.Fatalf("failed to remap account with error %v", err)
+		}
+
+		missing := mapping
+		missing.LineCode = "99"
+		if err := repos.TaxLines.Map(ctx, missing); !accounting.IsTaxLineNotFound(err) {
+			t.Fatalf("expected an ErrTaxLineNotFound, received %v", err)
+		}
+
+		mappings, _ := repos.TaxLines.Mappings(ctx, 2025, accounting.ScheduleC)
+		count := 0
+		for _, m := range mappings {
+			if m.AccountName == "Sales" {
+				count++
+				if m.LineCode != "1" {
+					t.Fatalf("expected sales on line 1, got %s", m.LineCode)
+				}
+			}
+		}
+		if count != 1 {
+			t.Fatalf("expected one mapping for sales, got %d", count)
+		}
+
+		if err := repos.TaxLines.DeleteLine(ctx, 2025, accounting.ScheduleC, "1"); err == nil {
+			t.Fatal("expected an error deleting a mapped line")
+		}
+
+		if err := repos.TaxLines.Unmap(ctx, 2025, accounting.ScheduleC, "Sales"); err != nil {
+			t.Fatalf("failed to unmap account with error %v", err)
+		}
+		if err := repos.TaxLines.DeleteLine(ctx, 2025, accounting.ScheduleC, "1"); err != nil {
+			t.Fatalf("failed to delete line with error %v", err)
+		}
+		if err := repos.TaxLines.DeleteLine(ctx, 2025, accounting.ScheduleC, "1"); !accounting.IsTaxLineNotFound(err) {
+			t.Fatalf("expected an ErrTaxLineNotFound, received %v", err)
+		}
+	})
+
+	t.Run("copies a year forward keeping the other year's edits", func(t *testing.T) {
+		repos := setup(t)
+
+		if err := repos.TaxLines.Map(ctx, accounting.TaxLineMapping{TaxYear: 2026, Form: accounting.ScheduleC, AccountName: "Sales", LineCode: "1"}); err != nil {
+			t.Fatalf("failed to map account with error %v", err)
+		}
+		renumbered := accounting.TaxLine{TaxYear: 2027, Form: accounting.ScheduleC, Code: "1", Description: "Gross receipts", DisplayOrder: 1}
+		if err := repos.TaxLines.SaveLine(ctx, renumbered); err != nil {
+			t.Fatalf("failed to save line with error %v", err)
+		}
+
+		if err := repos.TaxLines.CopyYear(ctx, 2026, 2027); err != nil {
+			t.Fatalf("failed to copy year with error %v", err)
+		}
+
+		lines, _ := repos.TaxLines.Lines(ctx, 2027, accounting.ScheduleC)
+		seeded, _ := repos.TaxLines.Lines(ctx, 2026, accounting.ScheduleC)
+		if len(lines) != len(seeded) || lines[0] != renumbered {
+			t.Fatalf("expected the 2026 lines alongside the edited line 1, got %+v", lines)
+		}
+
+		mappings, _ := repos.TaxLines.Mappings(ctx, 2027, accounting.ScheduleC)
+		found := false
+		for _, mapping := range mappings {
+			found = found || (mapping.AccountName == "Sales" && mapping.LineCode == "1")
+		}
+		if !found {
+			t.Fatalf("expected the sales mapping to be copied, got %+v", mappings)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import "time"
+
+// layouts used for storing times as TEXT; both sort lexicographically
+const (
+	timestampLayout = "2006-01-02T15:04:05Z"
+	dateLayout      = "2006-01-02"
+)
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	return time.Parse(timestampLayout, s)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+func parseDate(s string) (time.Time, error) {
+	return time.Parse(dateLayout, s)
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+	"errors"
+
+	// external
+	sqlite3 "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type userRepo struct {
+	db *sql.DB
+}
+
+// Save inserts a user, or updates the username, role and password hash of an existing one.
+//
+// Returns ErrUserExists if another user has the username, regardless of case.
+func (r *userRepo) Save(ctx context.Context, user *accounting.User) error {
+	const query = `
+		INSERT INTO users
+			(id, username, role, password_hash, created_at)
+		VALUES
+			(?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			username = excluded.username,
+			role = excluded.role,
+			password_hash = excluded.password_hash;
+	`
+
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, string(user.Role), user.PasswordHash, formatTimestamp(user.CreatedAt))
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return &accounting.ErrUserExists{Username: user.Username}
+	}
+
+	return err
+}
+
+// Retrieves a user by ID
+//
+// Returns ErrUserNotFound if the user does not exist.
+func (r *userRepo) ByID(ctx context.Context, id string) (*accounting.User, error) {
+	const query = `
+		SELECT id, username, role, password_hash, created_at
+		FROM users
+		WHERE id = ?;
+	`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &accounting.ErrUserNotFound{Username: id}
+	}
+
+	return user, err
+}
+
+// Retrieves a user by username, regardless of case
+//
+// Returns ErrUserNotFound if the user does not exist.
+func (r *userRepo) ByUsername(ctx context.Context, username string) (*accounting.User, error) {
+	const query = `
+		SELECT id, username, role, password_hash, created_at
+		FROM users
+		WHERE username = ?;
+	`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, username))
+	if err == sql.ErrNoRows {
+		return nil, &accounting.ErrUserNotFound{Username: username}
+	}
+
+	return user, err
+}
+
+// Retrieves all users, ordered by username
+func (r *userRepo) GetAll(ctx context.Context) ([]*accounting.User, error) {
+	const query = `
+		SELECT id, username, role, password_hash, created_at
+		FROM users
+		ORDER BY username;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*accounting.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func scanUser(s scanner) (*accounting.User, error) {
+	var (
+		user      accounting.User
+		createdAt string
+	)
+
+	if err := s.Scan(&user.ID, &user.Username, &user.Role, &user.PasswordHash, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if user.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
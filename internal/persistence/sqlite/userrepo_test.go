@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestUserRepo(t *testing.T) {
+	t.Run("saves and retrieves a user by ID and by username regardless of case", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		user, err := accounting.NewUser("Alice", "correct horse battery", accounting.AdminRole)
+		if err != nil {
+			t.Fatalf("failed to create user with error %v", err)
+		}
+		if err := repos.Users.Save(ctx, user); err != nil {
+			t.Fatalf("failed to save user with error %v", err)
+		}
+
+		byID, err := repos.Users.ByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve user with error %v", err)
+		}
+		if byID.Username != "Alice" || byID.Role != accounting.AdminRole || byID.PasswordHash != user.PasswordHash || !byID.CreatedAt.Equal(user.CreatedAt.Truncate(time.Second)) {
+			t.Fatalf("expected to retrieve %+v, received %+v", user, byID)
+		}
+
+		byName, err := repos.Users.ByUsername(ctx, "alice")
+		if err != nil {
+			t.Fatalf("failed to retrieve user by username with error %v", err)
+		}
+		if byName.ID != user.ID || !byName.CheckPassword("correct horse battery") {
+			t.Fatalf("expected to retrieve %+v, received %+v", user, byName)
+		}
+	})
+
+	t.Run("rejects a username already taken in another case", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		first, _ := accounting.NewUser("alice", "correct horse battery", accounting.BookkeeperRole)
+		second, _ := accounting.NewUser("ALICE", "another long password", accounting.BookkeeperRole)
+		if err := repos.Users.Save(ctx, first); err != nil {
+			t.Fatalf("failed to save user with error %v", err)
+		}
+
+		if err := repos.Users.Save(ctx, second); !accounting.IsUserExists(err) {
+			t.Fatalf("expected a UserExists error, received %v", err)
+		}
+	})
+
+	t.Run("returns a specific error if the user is not found", func(t *testing.T) {
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		if _, err := repos.Users.ByUsername(context.Background(), "nobody"); !accounting.IsUserNotFound(err) {
+			t.Fatalf("expected a UserNotFound error, received %v", err)
+		}
+	})
+}
+
+func TestSessionRepo(t *testing.T) {
+	t.Run("saves, retrieves and deletes sessions, and sweeps expired ones", func(t *testing.T) {
+		ctx := context.Background()
+
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+
+		user, _ := accounting.NewUser("alice", "correct horse battery", accounting.BookkeeperRole)
+		if err := repos.Users.Save(ctx, user); err != nil {
+			t.Fatalf("failed to save user with error %v", err)
+		}
+
+		now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+		current, token, err := accounting.NewSession(user.ID, now)
+		if err != nil {
+			t.Fatalf("failed to create session with error %v", err)
+		}
+		stale, _, _ := accounting.NewSession(user.ID, now.Add(-2*accounting.SessionLifetime))
+		for _, session := range []*accounting.Session{current, stale} {
+			if err := repos.Sessions.Save(ctx, session); err != nil {
+				t.Fatalf("failed to save session with error %v", err)
+			}
+		}
+
+		saved, err := repos.Sessions.ByID(ctx, accounting.SessionID(token))
+		if err != nil {
+			t.Fatalf("failed to retrieve session with error %v", err)
+		}
+		if saved.UserID != user.ID || saved.CSRFToken != current.CSRFToken || !saved.ExpiresAt.Equal(current.ExpiresAt) {
+			t.Fatalf("expected to retrieve %+v, received %+v", current, saved)
+		}
+
+		if err := repos.Sessions.DeleteExpired(ctx, now); err != nil {
+			t.Fatalf("failed to delete expired sessions with error %v", err)
+		}
+		if _, err := repos.Sessions.ByID(ctx, stale.ID); !accounting.IsSessionNotFound(err) {
+			t.Fatalf("expected the expired session to be swept, received %v", err)
+		}
+
+		if err := repos.Sessions.Delete(ctx, current.ID); err != nil {
+			t.Fatalf("failed to delete session with error %v", err)
+		}
+		if _, err := repos.Sessions.ByID(ctx, current.ID); !accounting.IsSessionNotFound(err) {
+			t.Fatalf("expected a SessionNotFound error after deleting, received %v", err)
+		}
+	})
+}
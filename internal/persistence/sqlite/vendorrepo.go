@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type vendorRepo struct {
+	db *sql.DB
+}
+
+// Save inserts or updates a vendor in the database.
+func (r *vendorRepo) Save(ctx context.Context, vendor *accounting.Vendor) error {
+	const query = `
+		INSERT INTO vendors
+			(id, name, email, phone, address, default_terms)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			email = excluded.email,
+			phone = excluded.phone,
+			address = excluded.address,
+			default_terms = excluded.default_terms;
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		vendor.ID,
+		vendor.Name,
+		vendor.Email,
+		vendor.Phone,
+		vendor.Address,
+		vendor.DefaultTerms.String(),
+	)
+
+	return err
+}
+
+// Retrieves a vendor by ID
+//
+// Returns ErrVendorNotFound if the vendor does not exist.
+func (r *vendorRepo) ByID(ctx context.Context, id string) (accounting.Vendor, error) {
+	const query = `
+		SELECT id, name, email, phone, address, default_terms
+		FROM vendors
+		WHERE id = ?;
+	`
+
+	vendor, err := scanVendor(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.Vendor{}, &accounting.ErrVendorNotFound{ID: id}
+		}
+		return accounting.Vendor{}, err
+	}
+
+	return vendor, nil
+}
+
+// Retrieves all vendors, ordered by name
+func (r *vendorRepo) GetAll(ctx context.Context) ([]*accounting.Vendor, error) {
+	const query = `
+		SELECT id, name, email, phone, address, default_terms
+		FROM vendors
+		ORDER BY name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vendors []*accounting.Vendor
+	for rows.Next() {
+		vendor, err := scanVendor(rows)
+		if err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, &vendor)
+	}
+
+	return vendors, rows.Err()
+}
+
+// utility type satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVendor(s scanner) (accounting.Vendor, error) {
+	var (
+		vendor accounting.Vendor
+		terms  string
+	)
+
+	if err := s.Scan(&vendor.ID, &vendor.Name, &vendor.Email, &vendor.Phone, &vendor.Address, &terms); err != nil {
+		return accounting.Vendor{}, err
+	}
+
+	parsed, err := accounting.ParsePaymentTerms(terms)
+	if err != nil {
+		return accounting.Vendor{}, err
+	}
+	vendor.DefaultTerms = parsed
+
+	return vendor, nil
+}
@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"database/sql"
+
+	// external
+	_ "github.com/mattn/go-sqlite3" // sqlite driver
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type yearEndRepo struct {
+	db *sql.DB
+}
+
+// Close posts the closing entry, then saves the close and its audit record,
+// in a single transaction; the entry is posted before the close takes effect
+// so that the year's lock does not refuse it.
+//
+// Returns ErrYearAlreadyClosed if a close of the year is already in effect.
+func (r *yearEndRepo) Close(ctx context.Context, closing *accounting.YearEndClose, je accounting.JournalEntry, record accounting.YearEndAuditRecord) error {
+	const existingQuery = `
+		SELECT COUNT(*)
+		FROM year_end_closes
+		WHERE reopened_at IS NULL AND year_start <= ? AND year_end >= ?;
+	`
+	const closeQuery = `
+		INSERT INTO year_end_closes
+			(id, year_start, year_end, retained_earnings_account_name, income_summary_account_name, net_income, closed_at, journal_entry_id)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var overlapping int
+		if err := tx.QueryRowContext(ctx, existingQuery, formatDate(closing.YearEnd), formatDate(closing.YearStart)).Scan(&overlapping); err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return &accounting.ErrYearAlreadyClosed{YearEnd: closing.YearEnd}
+		}
+
+		if err := insertJournalEntry(ctx, tx, je); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			closeQuery,
+			closing.ID,
+			formatDate(closing.YearStart),
+			formatDate(closing.YearEnd),
+			closing.RetainedEarningsAccountName,
+			sql.NullString{String: closing.IncomeSummaryAccountName, Valid: closing.IncomeSummaryAccountName != ""},
+			closing.NetIncome,
+			formatTimestamp(closing.ClosedAt),
+			je.ID,
+		); err != nil {
+			return err
+		}
+		closing.EntryID = je.ID
+
+		return insertYearEndAuditRecord(ctx, tx, record)
+	})
+}
+
+// Retrieves a close by ID
+//
+// Returns ErrYearCloseNotFound if the close does not exist.
+func (r *yearEndRepo) ByID(ctx context.Context, id string) (accounting.YearEndClose, error) {
+	const query = `
+		SELECT id, year_start, year_end, retained_earnings_account_name, income_summary_account_name,
+			net_income, closed_at, journal_entry_id, reopened_at, reopen_reason, reversal_entry_id
+		FROM year_end_closes
+		WHERE id = ?;
+	`
+
+	closing, err := scanYearEndClose(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accounting.YearEndClose{}, &accounting.ErrYearCloseNotFound{ID: id}
+		}
+		return accounting.YearEndClose{}, err
+	}
+
+	return closing, nil
+}
+
+// Retrieves every closing, including those since reopened, latest year first
+func (r *yearEndRepo) GetAll(ctx context.Context) ([]*accounting.YearEndClose, error) {
+	const query = `
+		SELECT id, year_start, year_end, retained_earnings_account_name, income_summary_account_name,
+			net_income, closed_at, journal_entry_id, reopened_at, reopen_reason, reversal_entry_id
+		FROM year_end_closes
+		ORDER BY year_end DESC, closed_at DESC;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var closes []*accounting.YearEndClose
+	for rows.Next() {
+		closing, err := scanYearEndClose(rows)
+		if err != nil {
+			return nil, err
+		}
+		closes = append(closes, &closing)
+	}
+
+	return closes, rows.Err()
+}
+
+// Reopen lifts the closing, then posts the reversing entry and saves the audit
+// record, in a single transaction.
+func (r *yearEndRepo) Reopen(ctx context.Context, closing *accounting.YearEndClose, reversal accounting.JournalEntry, record accounting.YearEndAuditRecord) error {
+	const reopenQuery = `
+		UPDATE year_end_closes
+		SET reopened_at = ?, reopen_reason = ?
+		WHERE id = ? AND reopened_at IS NULL;
+	`
+	const reversalQuery = `
+		UPDATE year_end_closes
+		SET reversal_entry_id = ?
+		WHERE id = ?;
+	`
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// the year is unlocked first so that the reversal, dated within it, may post
+		result, err := tx.ExecContext(ctx, reopenQuery, formatTimestamp(closing.ReopenedAt), closing.ReopenReason, closing.ID)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return &accounting.ErrYearCloseNotFound{ID: closing.ID}
+		}
+
+		if err := insertJournalEntry(ctx, tx, reversal); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, reversalQuery, reversal.ID, closing.ID); err != nil {
+			return err
+		}
+		closing.ReversalEntryID = reversal.ID
+
+		return insertYearEndAuditRecord(ctx, tx, record)
+	})
+}
+
+// Retrieves the audit records of a closing, in the order they occurred
+func (r *yearEndRepo) AuditRecords(ctx context.Context, closeID string) ([]accounting.YearEndAuditRecord, error) {
+	const query = `
+		SELECT id, close_id, action, occurred_at, reason
+		FROM year_end_audit
+		WHERE close_id = ?
+		ORDER BY occurred_at, rowid;
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, closeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []accounting.YearEndAuditRecord
+	for rows.Next() {
+		var (
+			record     accounting.YearEndAuditRecord
+			occurredAt string
+		)
+		if err := rows.Scan(&record.ID, &record.CloseID, &record.Action, &occurredAt, &record.Reason); err != nil {
+			return nil, err
+		}
+		if record.OccurredAt, err = parseTimestamp(occurredAt); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+func insertYearEndAuditRecord(ctx context.Context, tx *sql.Tx, record accounting.YearEndAuditRecord) error {
+	const query = `
+		INSERT INTO year_end_audit
+			(id, close_id, action, occurred_at, reason)
+		VALUES
+			(?, ?, ?, ?, ?);
+	`
+
+	_, err := tx.ExecContext(ctx, query, record.ID, record.CloseID, record.Action, formatTimestamp(record.OccurredAt), record.Reason)
+	return err
+}
+
+func scanYearEndClose(s scanner) (accounting.YearEndClose, error) {
+	var (
+		closing                   accounting.YearEndClose
+		yearStart, yearEnd        string
+		closedAt                  string
+		incomeSummary, reopenedAt sql.NullString
+		reversalEntryID           sql.NullString
+	)
+
+	if err := s.Scan(
+		&closing.ID,
+		&yearStart,
+		&yearEnd,
+		&closing.RetainedEarningsAccountName,
+		&incomeSummary,
+		&closing.NetIncome,
+		&closedAt,
+		&closing.EntryID,
+		&reopenedAt,
+		&closing.ReopenReason,
+		&reversalEntryID,
+	); err != nil {
+		return accounting.YearEndClose{}, err
+	}
+
+	var err error
+	if closing.YearStart, err = parseDate(yearStart); err != nil {
+		return accounting.YearEndClose{}, err
+	}
+	if closing.YearEnd, err = parseDate(yearEnd); err != nil {
+		return accounting.YearEndClose{}, err
+	}
+	if closing.ClosedAt, err = parseTimestamp(closedAt); err != nil {
+		return accounting.YearEndClose{}, err
+	}
+	if reopenedAt.Valid {
+		if closing.ReopenedAt, err = parseTimestamp(reopenedAt.String); err != nil {
+			return accounting.YearEndClose{}, err
+		}
+	}
+	closing.IncomeSummaryAccountName = incomeSummary.String
+	closing.ReversalEntryID = reversalEntryID.String
+
+	return closing, nil
+}
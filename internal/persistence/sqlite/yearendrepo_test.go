@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	// std
+	"context"
+	"testing"
+	"time"
+
+	// internal
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestYearEndRepo(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	entry := func(date time.Time, amount float64) accounting.JournalEntry {
+		return accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: date,
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Cash", Amount: amount, Side: accounting.Debit},
+				{AccountName: "Sales", Amount: amount, Side: accounting.Credit},
+			},
+		}
+	}
+
+	setup := func(t *testing.T) (*Repositories, *accounting.YearEndClose, accounting.JournalEntry) {
+		t.Helper()
+		repos, err := New(":memory:")
+		if err != nil {
+			t.Fatalf("failed to create in-memory SQLite DB with error %v", err)
+		}
+		saveTestAccount(t, repos, "Cash", "Assets", accounting.Asset, accounting.DebitNormal)
+		saveTestAccount(t, repos, "Sales", "Revenues", accounting.Revenue, accounting.CreditNormal)
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 500)); err != nil {
+			t.Fatalf("failed to save journal entry with error %v", err)
+		}
+
+		closing, _ := accounting.NewYearEndClose(start, end, "Retained Earnings", "")
+		je := accounting.JournalEntry{
+			ID:        accounting.NewID(),
+			Timestamp: end,
+			Lines: []accounting.JournalEntryLine{
+				{AccountName: "Sales", Amount: 500, Side: accounting.Debit},
+				{AccountName: "Retained Earnings", Amount: 500, Side: accounting.Credit},
+			},
+		}
+		closing.NetIncome = 500
+		closing.ClosedAt = time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+		record := accounting.YearEndAuditRecord{ID: accounting.NewID(), CloseID: closing.ID, Action: accounting.YearClosed, OccurredAt: closing.ClosedAt}
+		if err := repos.YearEnds.Close(ctx, closing, je, record); err != nil {
+			t.Fatalf("failed to close year with error %v", err)
+		}
+
+		return repos, closing, je
+	}
+
+	t.Run("locks the closed year against new postings", func(t *testing.T) {
+		repos, closing, _ := setup(t)
+
+		err := repos.JournalEntries.Save(ctx, entry(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 10))
+		if !accounting.IsYearClosed(err) {
+			t.Fatalf("expected an ErrYearClosed, received %v", err)
+		}
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10)); err != nil {
+			t.Fatalf("expected the following year to accept postings, received %v", err)
+		}
+
+		again, _ := accounting.NewYearEndClose(start, end, "Retained Earnings", "")
+		if err := repos.YearEnds.Close(ctx, again, entry(end, 1), accounting.YearEndAuditRecord{ID: accounting.NewID(), CloseID: again.ID, Action: accounting.YearClosed}); !accounting.IsYearAlreadyClosed(err) {
+			t.Fatalf("expected an ErrYearAlreadyClosed, received %v", err)
+		}
+
+		saved, err := repos.YearEnds.ByID(ctx, closing.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve close with error %v", err)
+		}
+		if saved != *closing {
+			t.Fatalf("expected %+v, received %+v", *closing, saved)
+		}
+	})
+
+	t.Run("reopening posts the reversal and unlocks the year", func(t *testing.T) {
+		repos, closing, je := setup(t)
+
+		reversal, err := closing.Reopen(je, time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC), "Late vendor bill")
+		if err != nil {
+			t.Fatalf("failed to reopen with error %v", err)
+		}
+		record := accounting.YearEndAuditRecord{ID: accounting.NewID(), CloseID: closing.ID, Action: accounting.YearReopened, OccurredAt: closing.ReopenedAt, Reason: closing.ReopenReason}
+		if err := repos.YearEnds.Reopen(ctx, closing, reversal, record); err != nil {
+			t.Fatalf("failed to save reopening with error %v", err)
+		}
+
+		if err := repos.JournalEntries.Save(ctx, entry(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 10)); err != nil {
+			t.Fatalf("expected the reopened year to accept postings, received %v", err)
+		}
+
+		closes, err := repos.YearEnds.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("failed to retrieve closes with error %v", err)
+		}
+		if len(closes) != 1 || closes[0].Closed() || closes[0].ReversalEntryID != reversal.ID || closes[0].ReopenReason != "Late vendor bill" {
+			t.Fatalf("expected the close to record its reopening, received %+v", closes)
+		}
+
+		records, err := repos.YearEnds.AuditRecords(ctx, closing.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve audit records with error %v", err)
+		}
+		if len(records) != 2 || records[0].Action != accounting.YearClosed || records[1].Action != accounting.YearReopened {
+			t.Fatalf("expected close and reopen records, received %+v", records)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type AgingService struct {
+	CustomerRepo     accounting.CustomerRepository
+	InvoiceRepo      accounting.InvoiceRepository
+	VendorRepo       accounting.VendorRepository
+	BillRepo         accounting.BillRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+
+	// control accounts always tied out, even when no open document uses them
+	ReceivableAccountName string
+	PayableAccountName    string
+}
+
+// Produces the A/R aging report as of the close of business on asOf
+func (s *AgingService) GetReceivablesAging(ctx context.Context, asOf time.Time) (*accounting.AgingReport, error) {
+	customers, err := s.CustomerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(customers))
+	for _, c := range customers {
+		names[c.ID] = c.Name
+	}
+
+	invoices, err := s.InvoiceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []accounting.AgingDocument
+	controlAccounts := []string{s.ReceivableAccountName}
+	for _, invoice := range invoices {
+		if invoice.InvoiceDate.After(asOf) {
+			continue
+		}
+
+		payments, err := s.InvoiceRepo.Payments(ctx, invoice.ID)
+		if err != nil {
+			return nil, err
+		}
+		payments = slices.DeleteFunc(payments, func(p accounting.InvoicePayment) bool {
+			return p.PaymentDate.After(asOf)
+		})
+
+		documents = append(documents, accounting.AgingDocument{
+			ID:          invoice.ID,
+			PartyID:     invoice.CustomerID,
+			PartyName:   names[invoice.CustomerID],
+			Number:      invoice.Number,
+			Date:        invoice.InvoiceDate,
+			DueDate:     invoice.DueDate,
+			Total:       invoice.Total(),
+			OpenBalance: invoice.OpenBalance(payments),
+		})
+
+		if !slices.Contains(controlAccounts, invoice.ReceivableAccountName) {
+			controlAccounts = append(controlAccounts, invoice.ReceivableAccountName)
+		}
+	}
+
+	controlBalance, err := controlBalance(ctx, s.JournalEntryRepo, s.AccountRepo, controlAccounts, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := accounting.BuildAgingReport(accounting.ReceivablesAging, asOf, documents, controlAccounts, controlBalance)
+	return &report, nil
+}
+
+// Produces the A/P aging report as of the close of business on asOf
+func (s *AgingService) GetPayablesAging(ctx context.Context, asOf time.Time) (*accounting.AgingReport, error) {
+	vendors, err := s.VendorRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(vendors))
+	for _, v := range vendors {
+		names[v.ID] = v.Name
+	}
+
+	bills, err := s.BillRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []accounting.AgingDocument
+	controlAccounts := []string{s.PayableAccountName}
+	for _, bill := range bills {
+		if bill.BillDate.After(asOf) {
+			continue
+		}
+
+		payments, err := s.BillRepo.Payments(ctx, bill.ID)
+		if err != nil {
+			return nil, err
+		}
+		payments = slices.DeleteFunc(payments, func(p accounting.BillPayment) bool {
+			return p.PaymentDate.After(asOf)
+		})
+
+		documents = append(documents, accounting.AgingDocument{
+			ID:          bill.ID,
+			PartyID:     bill.VendorID,
+			PartyName:   names[bill.VendorID],
+			Number:      bill.Number,
+			Date:        bill.BillDate,
+			DueDate:     bill.DueDate,
+			Total:       bill.Total(),
+			OpenBalance: bill.OpenBalance(payments),
+		})
+
+		if !slices.Contains(controlAccounts, bill.PayableAccountName) {
+			controlAccounts = append(controlAccounts, bill.PayableAccountName)
+		}
+	}
+
+	controlBalance, err := controlBalance(ctx, s.JournalEntryRepo, s.AccountRepo, controlAccounts, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := accounting.BuildAgingReport(accounting.PayablesAging, asOf, documents, controlAccounts, controlBalance)
+	return &report, nil
+}
+
+// the combined normal balance of the control accounts through the end of asOf
+func controlBalance(
+	ctx context.Context,
+	entries accounting.JournalEntryRepository,
+	accounts accounting.AccountRepository,
+	accountNames []string,
+	asOf time.Time,
+) (float64, error) {
+	totals, err := entries.AccountTotals(ctx, time.Time{}, asOf.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+
+	var balance float64
+	for _, name := range accountNames {
+		account, err := accounts.ByName(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, total := range totals {
+			if total.AccountName == name {
+				balance += total.Balance(account.NormalBalance)
+			}
+		}
+	}
+
+	return accounting.RoundCents(balance), nil
+}
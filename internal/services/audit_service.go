@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+// the most records the audit log shows at once
+const auditLogLimit = 500
+
+type AuditService struct {
+	AuditRepo accounting.AuditRepository
+}
+
+// Retrieves the latest audit records matching the filter, newest first
+func (s *AuditService) GetRecords(ctx context.Context, filter accounting.AuditFilter) ([]accounting.AuditRecord, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "review the audit log"); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit == 0 || filter.Limit > auditLogLimit {
+		filter.Limit = auditLogLimit
+	}
+
+	return s.AuditRepo.Records(ctx, filter)
+}
+
+// Retrieves the changes made to a single record, in the order they occurred
+func (s *AuditService) GetHistory(ctx context.Context, entityType accounting.EntityType, entityID string) ([]accounting.AuditRecord, error) {
+	return s.AuditRepo.History(ctx, entityType, entityID)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type AuthService struct {
+	UserRepo    accounting.UserRepository
+	SessionRepo accounting.SessionRepository
+}
+
+// CreateUser validates and saves a new user with the password hashed
+func (s *AuthService) CreateUser(ctx context.Context, username, password string, role accounting.Role) (*accounting.User, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "manage users"); err != nil {
+		return nil, err
+	}
+
+	user, err := accounting.NewUser(username, password, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.UserRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Retrieves every user by username
+func (s *AuthService) GetUsers(ctx context.Context) ([]*accounting.User, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "manage users"); err != nil {
+		return nil, err
+	}
+
+	return s.UserRepo.GetAll(ctx)
+}
+
+// SetRole changes the role of the user with the given ID, refusing to demote
+// the last admin so that someone can always manage users
+func (s *AuthService) SetRole(ctx context.Context, userID string, role accounting.Role) (*accounting.User, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "manage users"); err != nil {
+		return nil, err
+	}
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.ByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == accounting.AdminRole && role != accounting.AdminRole {
+		users, err := s.UserRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		admins := 0
+		for _, u := range users {
+			if u.Role == accounting.AdminRole {
+				admins++
+			}
+		}
+		if admins <= 1 {
+			return nil, fmt.Errorf("cannot demote %s: they are the only admin", user.Username)
+		}
+	}
+
+	user.Role = role
+	if err := s.UserRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Login checks the username and password and starts a session, returning it
+// along with the token for the user's cookie. Either mistake fails with
+// ErrInvalidCredentials, taking as long as the other.
+func (s *AuthService) Login(ctx context.Context, username, password string) (*accounting.Session, string, error) {
+	user, err := s.UserRepo.ByUsername(ctx, username)
+	if accounting.IsUserNotFound(err) {
+		// hash anyway, so that unknown usernames cannot be told by timing
+		dummyUser().CheckPassword(password)
+		return nil, "", &accounting.ErrInvalidCredentials{}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !user.CheckPassword(password) {
+		return nil, "", &accounting.ErrInvalidCredentials{}
+	}
+
+	now := time.Now().UTC()
+	if err := s.SessionRepo.DeleteExpired(ctx, now); err != nil {
+		return nil, "", err
+	}
+
+	session, token, err := accounting.NewSession(user.ID, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.SessionRepo.Save(ctx, session); err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+// Authenticate resolves a cookie's token to its session and user, failing
+// with ErrSessionNotFound if the session is unknown, signed out or expired
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*accounting.User, *accounting.Session, error) {
+	if token == "" {
+		return nil, nil, &accounting.ErrSessionNotFound{}
+	}
+
+	session, err := s.SessionRepo.ByID(ctx, accounting.SessionID(token))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if session.Expired(time.Now().UTC()) {
+		if err := s.SessionRepo.Delete(ctx, session.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, &accounting.ErrSessionNotFound{}
+	}
+
+	user, err := s.UserRepo.ByID(ctx, session.UserID)
+	if accounting.IsUserNotFound(err) {
+		return nil, nil, &accounting.ErrSessionNotFound{}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, session, nil
+}
+
+// Logout ends the session of the cookie's token
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	return s.SessionRepo.Delete(ctx, accounting.SessionID(token))
+}
+
+// a user whose password no one knows, checked against when the username is
+// unknown
+var dummyUser = sync.OnceValue(func() *accounting.User {
+	token, err := accounting.NewToken()
+	if err != nil {
+		panic(err)
+	}
+	user, err := accounting.NewUser("nobody", token, accounting.ViewerRole)
+	if err != nil {
+		panic(err)
+	}
+	return user
+})
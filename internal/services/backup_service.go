@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/backup"
+)
+
+type BackupService struct {
+	Snapshotter   backup.Snapshotter
+	IntegrityRepo accounting.IntegrityRepository
+	Dir           string
+	Retention     backup.Retention
+}
+
+// the outcome of taking a backup
+type BackupResult struct {
+	Backup backup.Backup
+	Pruned []backup.Backup
+	// the integrity check run just before; a backup is taken whatever it
+	// finds, since books with problems need backing up all the same
+	Check accounting.IntegrityReport
+}
+
+// Checks the books, takes and verifies a backup of them, then prunes those
+// the retention no longer keeps
+func (s *BackupService) CreateBackup(ctx context.Context) (BackupResult, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "back up the books"); err != nil {
+		return BackupResult{}, err
+	}
+
+	data, err := s.IntegrityRepo.Load(ctx)
+	if err != nil {
+		return BackupResult{}, err
+	}
+	result := BackupResult{Check: accounting.CheckIntegrity(data)}
+
+	now := time.Now()
+	if result.Backup, err = backup.Create(ctx, s.Snapshotter, s.Dir, now); err != nil {
+		return result, err
+	}
+
+	result.Pruned, err = backup.Prune(s.Dir, s.Retention, now)
+	return result, err
+}
+
+// Retrieves the backups taken, newest first
+func (s *BackupService) GetBackups(ctx context.Context) ([]backup.Backup, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "review the backups"); err != nil {
+		return nil, err
+	}
+
+	return backup.List(s.Dir)
+}
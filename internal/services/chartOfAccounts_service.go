@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/hoodnoah/ghoam/internal/accounting"
 )
@@ -15,3 +19,125 @@ type ChartOfAccountsService struct {
 func (s *ChartOfAccountsService) GetChartOfAccounts(ctx context.Context) (*accounting.ChartOfAccountsNode, error) {
 	return accounting.BuildChartOfAccountsTree(ctx, s.AccountGroupRepo, s.AccountRepo)
 }
+
+// Retrieves an account by name
+func (s *ChartOfAccountsService) GetAccount(ctx context.Context, name string) (accounting.Account, error) {
+	return s.AccountRepo.ByName(ctx, name)
+}
+
+// Retrieves every account group
+func (s *ChartOfAccountsService) GetGroups(ctx context.Context) ([]*accounting.AccountGroup, error) {
+	return s.AccountGroupRepo.GetAll(ctx)
+}
+
+// SaveAccount adds an account to the chart, or changes an existing account's
+// group, type, normal balance or position. The normal balance must suit the
+// type, and the group and the account it is displayed after must exist.
+func (s *ChartOfAccountsService) SaveAccount(ctx context.Context, account accounting.Account) (*accounting.Account, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "edit the chart"); err != nil {
+		return nil, err
+	}
+
+	account.Name = strings.TrimSpace(account.Name)
+	if account.Name == "" {
+		return nil, errors.New("an account requires a name")
+	}
+
+	switch account.AccountType {
+	case accounting.Asset, accounting.ContraAsset, accounting.Liability, accounting.Equity, accounting.Revenue, accounting.Expense:
+	default:
+		return nil, fmt.Errorf("invalid account type %q", account.AccountType)
+	}
+	if account.NormalBalance != accounting.DebitNormal && account.NormalBalance != accounting.CreditNormal {
+		return nil, fmt.Errorf("invalid normal balance %q: use Debit or Credit", account.NormalBalance)
+	}
+	if expected, ok := accounting.ExpectedNormalBalance(account.AccountType); ok && account.NormalBalance != expected {
+		return nil, fmt.Errorf("a %s account is %s-normal, not %s-normal", account.AccountType, expected, account.NormalBalance)
+	}
+
+	if _, err := s.AccountGroupRepo.GetByName(ctx, account.ParentGroupName); err != nil {
+		return nil, err
+	}
+	if account.DisplayAfter.Valid {
+		if account.DisplayAfter.String == account.Name {
+			return nil, fmt.Errorf("account %s cannot be displayed after itself", account.Name)
+		}
+		if _, err := s.AccountRepo.ByName(ctx, account.DisplayAfter.String); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.AccountRepo.Save(ctx, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// CreateGroup adds a group to the chart under an existing parent group,
+// optionally displayed after one of its siblings
+func (s *ChartOfAccountsService) CreateGroup(ctx context.Context, name, parentName string, displayAfter sql.NullString) (*accounting.AccountGroup, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "edit the chart"); err != nil {
+		return nil, err
+	}
+
+	group, err := accounting.NewAccountGroup(strings.TrimSpace(name), parentName, displayAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.AccountGroupRepo.Insert(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// MoveGroup places an existing group under another parent group, or after
+// another sibling; the base groups cannot be moved
+func (s *ChartOfAccountsService) MoveGroup(ctx context.Context, name, parentName string, displayAfter sql.NullString) (*accounting.AccountGroup, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "edit the chart"); err != nil {
+		return nil, err
+	}
+
+	extant, err := s.AccountGroupRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if extant.IsImmutable {
+		return nil, &accounting.ErrGroupImmutable{Name: name}
+	}
+
+	group, err := accounting.NewAccountGroup(name, parentName, displayAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.AccountGroupRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[string]string, len(groups))
+	for _, g := range groups {
+		parents[g.Name] = g.ParentName.String
+	}
+	if _, ok := parents[parentName]; !ok {
+		return nil, &accounting.ErrParentNameNotExists{Name: parentName}
+	}
+	if _, ok := parents[displayAfter.String]; displayAfter.Valid && !ok {
+		return nil, &accounting.ErrDisplayAfterNameNotExists{Name: displayAfter.String}
+	}
+	// walk up from the new parent, which must not be the group or beneath it;
+	// no chain is longer than the groups, should the stored tree have a cycle
+	for i, ancestor := 0, parentName; ancestor != "" && i < len(groups); i, ancestor = i+1, parents[ancestor] {
+		if ancestor == name {
+			return nil, fmt.Errorf("account group %s cannot be moved beneath itself", name)
+		}
+	}
+
+	if err := s.AccountGroupRepo.Upsert(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+)
+
+func TestChartOfAccountsService(t *testing.T) {
+	setup := func(t *testing.T) (context.Context, *sqlite.Repositories, *ChartOfAccountsService) {
+		t.Helper()
+		ctx, repos := newTestBooks(t)
+		service := &ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}
+
+		for _, name := range []string{"Current Assets", "Other Assets"} {
+			if _, err := service.CreateGroup(ctx, name, "Assets", sql.NullString{}); err != nil {
+				t.Fatalf("failed to create group with error %v", err)
+			}
+		}
+
+		return ctx, repos, service
+	}
+
+	t.Run("adds and moves accounts and groups", func(t *testing.T) {
+		ctx, repos, service := setup(t)
+
+		if _, err := service.SaveAccount(ctx, accounting.Account{Name: " Petty Cash ", ParentGroupName: "Current Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal}); err != nil {
+			t.Fatalf("failed to add account with error %v", err)
+		}
+		if _, err := service.SaveAccount(ctx, accounting.Account{Name: "Petty Cash", ParentGroupName: "Other Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal}); err != nil {
+			t.Fatalf("failed to change account with error %v", err)
+		}
+		if account, err := service.GetAccount(ctx, "Petty Cash"); err != nil || account.ParentGroupName != "Other Assets" {
+			t.Fatalf("expected the account moved to Other Assets, got %+v and %v", account, err)
+		}
+
+		if _, err := service.MoveGroup(ctx, "Other Assets", "Assets", sql.NullString{String: "Current Assets", Valid: true}); err != nil {
+			t.Fatalf("failed to move group with error %v", err)
+		}
+		group, err := repos.AccountGroups.GetByName(ctx, "Other Assets")
+		if err != nil || group.DisplayAfter.String != "Current Assets" {
+			t.Fatalf("expected the group displayed after Current Assets, got %+v and %v", group, err)
+		}
+	})
+
+	t.Run("rejects a normal balance at odds with the account type", func(t *testing.T) {
+		ctx, _, service := setup(t)
+
+		if _, err := service.SaveAccount(ctx, accounting.Account{Name: "Petty Cash", ParentGroupName: "Current Assets", AccountType: accounting.Asset, NormalBalance: accounting.CreditNormal}); err == nil {
+			t.Fatal("expected an error saving a credit-normal asset")
+		}
+	})
+
+	t.Run("rejects accounts in missing groups", func(t *testing.T) {
+		ctx, _, service := setup(t)
+
+		_, err := service.SaveAccount(ctx, accounting.Account{Name: "Petty Cash", ParentGroupName: "Nowhere", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal})
+		if !accounting.IsGroupNotFound(err) {
+			t.Fatalf("expected ErrGroupNotFound, got %v", err)
+		}
+	})
+
+	t.Run("refuses to move a group beneath itself or a base group at all", func(t *testing.T) {
+		ctx, _, service := setup(t)
+		if _, err := service.CreateGroup(ctx, "Cash", "Current Assets", sql.NullString{}); err != nil {
+			t.Fatalf("failed to create group with error %v", err)
+		}
+
+		if _, err := service.MoveGroup(ctx, "Current Assets", "Cash", sql.NullString{}); err == nil {
+			t.Fatal("expected an error moving a group beneath its own child")
+		}
+		if _, err := service.MoveGroup(ctx, "Assets", "Equity", sql.NullString{}); !accounting.IsGroupImmutable(err) {
+			t.Fatalf("expected ErrGroupImmutable moving a base group, got %v", err)
+		}
+	})
+}
+
+// Each action is permitted to the role it requires and those above it, and
+// denied with ErrPermissionDenied to those below
+func TestServiceRoles(t *testing.T) {
+	actions := []struct {
+		name     string
+		required accounting.Role
+		do       func(ctx context.Context, repos *sqlite.Repositories) error
+	}{
+		{"view the chart", accounting.ViewerRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}).GetChartOfAccounts(ctx)
+			return err
+		}},
+		{"create customers", accounting.BookkeeperRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&ReceivablesService{CustomerRepo: repos.Customers}).CreateCustomer(ctx, "Acme Corp.", accounting.DefaultPaymentTerms)
+			return err
+		}},
+		{"create vendors", accounting.BookkeeperRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&PayablesService{VendorRepo: repos.Vendors}).CreateVendor(ctx, "Paper Co.", accounting.DefaultPaymentTerms)
+			return err
+		}},
+		{"add accounts", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}).SaveAccount(ctx, accounting.Account{
+				Name:            "Petty Cash",
+				ParentGroupName: "Assets",
+				AccountType:     accounting.Asset,
+				NormalBalance:   accounting.DebitNormal,
+			})
+			return err
+		}},
+		{"add groups", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}).CreateGroup(ctx, "Current Assets", "Assets", sql.NullString{})
+			return err
+		}},
+		{"move groups", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			service := &ChartOfAccountsService{AccountRepo: repos.Accounts, AccountGroupRepo: repos.AccountGroups}
+			if _, err := service.CreateGroup(accounting.AsSystem(ctx), "Current Assets", "Assets", sql.NullString{}); err != nil {
+				return err
+			}
+			_, err := service.MoveGroup(ctx, "Current Assets", "Liabilities", sql.NullString{})
+			return err
+		}},
+		{"open fiscal years", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&PeriodService{PeriodRepo: repos.Periods}).OpenYear(ctx, 2025)
+			return err
+		}},
+		{"close periods", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			service := &PeriodService{PeriodRepo: repos.Periods}
+			periods, err := service.OpenYear(accounting.AsSystem(ctx), 2025)
+			if err != nil {
+				return err
+			}
+			_, err = service.SetPeriodStatus(ctx, periods[0].ID, accounting.PeriodClosed, "month end")
+			return err
+		}},
+		{"manage users", accounting.AdminRole, func(ctx context.Context, repos *sqlite.Repositories) error {
+			_, err := (&AuthService{UserRepo: repos.Users, SessionRepo: repos.Sessions}).CreateUser(ctx, "bob", "correct horse battery", accounting.BookkeeperRole)
+			return err
+		}},
+	}
+
+	for _, action := range actions {
+		for _, role := range accounting.Roles {
+			t.Run(action.name+" as "+string(role), func(t *testing.T) {
+				_, repos := newTestBooks(t)
+				user, err := accounting.NewUser("alice", "correct horse battery", role)
+				if err != nil {
+					t.Fatalf("failed to create user with error %v", err)
+				}
+
+				err = action.do(accounting.WithUser(context.Background(), user), repos)
+				if role.Includes(action.required) && err != nil {
+					t.Fatalf("expected a %s to be permitted, got %v", role, err)
+				}
+				if !role.Includes(action.required) && !accounting.IsPermissionDenied(err) {
+					t.Fatalf("expected a %s to be denied, got %v", role, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/pdf"
+)
+
+// functions available to document templates
+var DocumentTemplateFuncs = template.FuncMap{
+	// formats an amount with two decimal places
+	"money": func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	// formats a date as YYYY-MM-DD
+	"date": func(t time.Time) string { return t.Format(time.DateOnly) },
+	// splits multi-line text, such as an address, into its non-blank lines
+	"lines": func(s string) []string {
+		var lines []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	},
+	// pads (or truncates) s on the right to width characters
+	"rpad": func(s string, width int) string { return fmt.Sprintf("%-*s", width, truncate(s, width-1)) },
+	// pads (or truncates) s on the left to width characters
+	"lpad": func(s string, width int) string { return fmt.Sprintf("%*s", width, truncate(s, width)) },
+}
+
+type DocumentService struct {
+	BookSettingsRepo accounting.BookSettingsRepository
+	CustomerRepo     accounting.CustomerRepository
+	InvoiceRepo      accounting.InvoiceRepository
+	AttachmentRepo   accounting.AttachmentRepository
+
+	// must define "invoicePDF" and "statementPDF"; see pdf.Layout for their markup
+	Templates *template.Template
+}
+
+// the data made available to the "invoicePDF" template
+type InvoiceDocument struct {
+	Settings    accounting.BookSettings
+	Customer    accounting.Customer
+	Invoice     *accounting.Invoice
+	Payments    []accounting.InvoicePayment
+	Paid        float64
+	OpenBalance float64
+}
+
+// the data made available to the "statementPDF" template
+type StatementDocument struct {
+	Settings  accounting.BookSettings
+	Statement accounting.CustomerStatement
+}
+
+// Retrieves the book settings
+func (s *DocumentService) GetBookSettings(ctx context.Context) (accounting.BookSettings, error) {
+	return s.BookSettingsRepo.Get(ctx)
+}
+
+// Saves the book settings, validating the logo if one is given
+func (s *DocumentService) SaveBookSettings(ctx context.Context, settings *accounting.BookSettings) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change the book settings"); err != nil {
+		return err
+	}
+
+	if len(settings.Logo) > 0 {
+		if _, err := pdf.LoadJPEG(settings.Logo); err != nil {
+			return err
+		}
+	}
+	return s.BookSettingsRepo.Save(ctx, settings)
+}
+
+// GenerateInvoicePDF renders an invoice and stores the PDF as an attachment on it
+func (s *DocumentService) GenerateInvoicePDF(ctx context.Context, invoiceID string) (*accounting.Attachment, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "generate invoices"); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.BookSettingsRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.InvoiceRepo.ByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.CustomerRepo.ByID(ctx, invoice.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.InvoiceRepo.Payments(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	open := invoice.OpenBalance(payments)
+	data := InvoiceDocument{
+		Settings:    settings,
+		Customer:    customer,
+		Invoice:     &invoice,
+		Payments:    payments,
+		Paid:        accounting.RoundCents(invoice.Total() - open),
+		OpenBalance: open,
+	}
+
+	content, err := s.render("invoicePDF", settings, data)
+	if err != nil {
+		return nil, err
+	}
+
+	name := invoice.Number
+	if name == "" {
+		name = invoice.ID
+	}
+
+	return s.attach(ctx, accounting.InvoiceEntity, invoice.ID, fmt.Sprintf("invoice-%s.pdf", name), content)
+}
+
+// GenerateStatementPDF renders a customer's statement for the month containing `month`
+// and stores the PDF as an attachment on the customer
+func (s *DocumentService) GenerateStatementPDF(ctx context.Context, customerID string, month time.Time) (*accounting.Attachment, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "generate statements"); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.BookSettingsRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.CustomerRepo.ByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.InvoiceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoices []accounting.Invoice
+	payments := make(map[string][]accounting.InvoicePayment)
+	for _, invoice := range all {
+		if invoice.CustomerID != customer.ID {
+			continue
+		}
+		invoices = append(invoices, *invoice)
+
+		if payments[invoice.ID], err = s.InvoiceRepo.Payments(ctx, invoice.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, -1)
+	data := StatementDocument{
+		Settings:  settings,
+		Statement: accounting.BuildCustomerStatement(customer, invoices, payments, start, end),
+	}
+
+	content, err := s.render("statementPDF", settings, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attach(ctx, accounting.CustomerEntity, customer.ID, fmt.Sprintf("statement-%s.pdf", start.Format("2006-01")), content)
+}
+
+// Retrieves a stored attachment
+func (s *DocumentService) GetAttachment(ctx context.Context, id string) (accounting.Attachment, error) {
+	return s.AttachmentRepo.ByID(ctx, id)
+}
+
+// executes a document template and lays its output out as a PDF
+func (s *DocumentService) render(name string, settings accounting.BookSettings, data any) ([]byte, error) {
+	var markup bytes.Buffer
+	if err := s.Templates.ExecuteTemplate(&markup, name, data); err != nil {
+		return nil, err
+	}
+
+	var logo *pdf.JPEG
+	if len(settings.Logo) > 0 {
+		var err error
+		if logo, err = pdf.LoadJPEG(settings.Logo); err != nil {
+			return nil, err
+		}
+	}
+
+	return pdf.Layout(markup.String(), logo).Bytes(), nil
+}
+
+func (s *DocumentService) attach(ctx context.Context, entityType accounting.EntityType, entityID, fileName string, content []byte) (*accounting.Attachment, error) {
+	attachment, err := accounting.NewAttachment(entityType, entityID, fileName, "application/pdf", content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.AttachmentRepo.Save(ctx, attachment); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// shortens s to at most width characters
+func truncate(s string, width int) string {
+	if width < 0 {
+		width = 0
+	}
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
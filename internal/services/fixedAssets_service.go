@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type FixedAssetService struct {
+	FixedAssetRepo accounting.FixedAssetRepository
+	AccountRepo    accounting.AccountRepository
+	// the account disposals post to unless a request names another
+	GainLossAccountName string
+}
+
+// the details of an asset to add to the register
+type RegisterAssetRequest struct {
+	Name                               string
+	Cost                               float64
+	SalvageValue                       float64
+	InServiceDate                      time.Time
+	UsefulLife                         int
+	Method                             accounting.DepreciationMethod
+	DecliningBalanceFactor             float64 // zero -> double-declining
+	AssetAccountName                   string
+	AccumulatedDepreciationAccountName string
+	DepreciationExpenseAccountName     string
+}
+
+// RegisterAsset validates and saves a new fixed asset
+func (s *FixedAssetService) RegisterAsset(ctx context.Context, req RegisterAssetRequest) (*accounting.FixedAsset, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "register fixed assets"); err != nil {
+		return nil, err
+	}
+
+	asset, err := accounting.NewFixedAsset(
+		req.Name,
+		req.Cost,
+		req.SalvageValue,
+		req.InServiceDate,
+		req.UsefulLife,
+		req.Method,
+		req.AssetAccountName,
+		req.AccumulatedDepreciationAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	asset.DepreciationExpenseAccountName = req.DepreciationExpenseAccountName
+
+	if req.DecliningBalanceFactor != 0 {
+		asset.DecliningBalanceFactor = req.DecliningBalanceFactor
+		if err := asset.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, asset.AssetAccountName, "a fixed asset account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, asset.AccumulatedDepreciationAccountName, "an accumulated depreciation account", accounting.ContraAsset); err != nil {
+		return nil, err
+	}
+
+	if asset.DepreciationExpenseAccountName != "" {
+		if err := requireAccountType(ctx, s.AccountRepo, asset.DepreciationExpenseAccountName, "a depreciation expense account", accounting.Expense); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.FixedAssetRepo.Save(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// Retrieves an asset and its full depreciation schedule
+func (s *FixedAssetService) GetSchedule(ctx context.Context, id string) (*accounting.FixedAsset, []accounting.DepreciationPeriod, error) {
+	asset, err := s.FixedAssetRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schedule, err := asset.Schedule()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &asset, schedule, nil
+}
+
+// Produces the fixed asset register as of the end of asOf
+func (s *FixedAssetService) GetRegister(ctx context.Context, asOf time.Time) (*accounting.FixedAssetRegister, error) {
+	assets, err := s.FixedAssetRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	register, err := accounting.BuildFixedAssetRegister(assets, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &register, nil
+}
+
+// RunDepreciation posts every scheduled period ending on or before `through`
+// which has not been posted yet, as one journal entry per period. Running it
+// again for the same date posts nothing.
+func (s *FixedAssetService) RunDepreciation(ctx context.Context, through time.Time) ([]accounting.DepreciationPosting, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "post depreciation"); err != nil {
+		return nil, err
+	}
+
+	assets, err := s.FixedAssetRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postDepreciation(ctx, assets, through)
+}
+
+// the details of an asset's sale or retirement
+type DisposeAssetRequest struct {
+	AssetID             string
+	DisposalDate        time.Time
+	Proceeds            float64
+	ProceedsAccountName string // required when there are proceeds
+	GainLossAccountName string // optional; defaults to the service's account
+}
+
+// DisposeAsset brings the asset's depreciation up to date through the
+// disposal date, then removes its cost and accumulated depreciation from
+// the books, posting the difference from the proceeds as a gain or loss.
+// An asset already depreciated for a period ending after the disposal date
+// is refused with ErrDisposalBeforeDepreciation.
+func (s *FixedAssetService) DisposeAsset(ctx context.Context, req DisposeAssetRequest) (*accounting.FixedAssetDisposal, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "dispose of fixed assets"); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.FixedAssetRepo.ByID(ctx, req.AssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	gainLossAccount := req.GainLossAccountName
+	if gainLossAccount == "" {
+		gainLossAccount = s.GainLossAccountName
+	}
+
+	if req.Proceeds > 0 {
+		if err := requireAccountType(ctx, s.AccountRepo, req.ProceedsAccountName, "a proceeds account", accounting.Asset); err != nil {
+			return nil, err
+		}
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, gainLossAccount, "a gain or loss account", accounting.Revenue, accounting.Expense); err != nil {
+		return nil, err
+	}
+
+	// validate before catching up, so a bad request posts nothing
+	if _, err := accounting.NewFixedAssetDisposal(&asset, req.DisposalDate, req.Proceeds, req.ProceedsAccountName, gainLossAccount, 0); err != nil {
+		return nil, err
+	}
+
+	// depreciation already posted for periods ending after the disposal would
+	// stay on the books of an asset gone
+	existing, err := s.FixedAssetRepo.DepreciationPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var depreciatedThrough time.Time
+	for _, posting := range existing {
+		if posting.AssetID == asset.ID && posting.PeriodEnd.After(depreciatedThrough) {
+			depreciatedThrough = posting.PeriodEnd
+		}
+	}
+	if depreciatedThrough.After(req.DisposalDate) {
+		return nil, &accounting.ErrDisposalBeforeDepreciation{Name: asset.Name, DisposalDate: req.DisposalDate, DepreciatedThrough: depreciatedThrough}
+	}
+
+	if _, err := s.postDepreciation(ctx, []*accounting.FixedAsset{&asset}, req.DisposalDate); err != nil {
+		return nil, err
+	}
+
+	postings, err := s.FixedAssetRepo.DepreciationPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var accumulated float64
+	for _, posting := range postings {
+		if posting.AssetID == asset.ID && !posting.PeriodEnd.After(req.DisposalDate) {
+			accumulated += posting.Amount
+		}
+	}
+
+	disposal, err := accounting.NewFixedAssetDisposal(&asset, req.DisposalDate, req.Proceeds, req.ProceedsAccountName, gainLossAccount, accumulated)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.FixedAssetRepo.PostDisposal(ctx, disposal, disposal.JournalEntry(&asset)); err != nil {
+		return nil, err
+	}
+
+	return disposal, nil
+}
+
+// posts the given assets' unposted periods through the date, grouped into one entry per period
+func (s *FixedAssetService) postDepreciation(ctx context.Context, assets []*accounting.FixedAsset, through time.Time) ([]accounting.DepreciationPosting, error) {
+	existing, err := s.FixedAssetRepo.DepreciationPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		assetID   string
+		periodEnd time.Time
+	}
+	posted := make(map[key]bool, len(existing))
+	for _, posting := range existing {
+		posted[key{posting.AssetID, posting.PeriodEnd}] = true
+	}
+
+	type pending struct {
+		assets   []*accounting.FixedAsset
+		amounts  []float64
+		postings []accounting.DepreciationPosting
+	}
+	byPeriod := make(map[time.Time]*pending)
+
+	for _, asset := range assets {
+		if asset.Disposal != nil {
+			continue
+		}
+
+		schedule, err := asset.Schedule()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, period := range schedule {
+			if period.PeriodEnd.After(through) {
+				break
+			}
+			if period.Depreciation <= 0 || posted[key{asset.ID, period.PeriodEnd}] {
+				continue
+			}
+			if asset.DepreciationExpenseAccountName == "" {
+				return nil, fmt.Errorf("fixed asset \"%s\" has no depreciation expense account", asset.Name)
+			}
+
+			p, ok := byPeriod[period.PeriodEnd]
+			if !ok {
+				p = &pending{}
+				byPeriod[period.PeriodEnd] = p
+			}
+			p.assets = append(p.assets, asset)
+			p.amounts = append(p.amounts, period.Depreciation)
+			p.postings = append(p.postings, accounting.DepreciationPosting{
+				AssetID:   asset.ID,
+				PeriodEnd: period.PeriodEnd,
+				Amount:    period.Depreciation,
+			})
+		}
+	}
+
+	periodEnds := make([]time.Time, 0, len(byPeriod))
+	for periodEnd := range byPeriod {
+		periodEnds = append(periodEnds, periodEnd)
+	}
+	sort.Slice(periodEnds, func(i, j int) bool { return periodEnds[i].Before(periodEnds[j]) })
+
+	var postings []accounting.DepreciationPosting
+	for _, periodEnd := range periodEnds {
+		p := byPeriod[periodEnd]
+		je := accounting.DepreciationEntry(periodEnd, p.assets, p.amounts)
+		if err := s.FixedAssetRepo.PostDepreciation(ctx, p.postings, je); err != nil {
+			return postings, err
+		}
+		postings = append(postings, p.postings...)
+	}
+
+	return postings, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestFixedAssetService(t *testing.T) {
+	t.Run("refuses a disposal dated before depreciation already posted", func(t *testing.T) {
+		ctx, repos := newTestBooks(t,
+			accounting.Account{Name: "Checking", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal},
+			accounting.Account{Name: "Equipment", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal},
+		)
+		service := &FixedAssetService{
+			FixedAssetRepo:      repos.FixedAssets,
+			AccountRepo:         repos.Accounts,
+			GainLossAccountName: "Gain or Loss on Disposal of Assets",
+		}
+
+		asset, err := service.RegisterAsset(ctx, RegisterAssetRequest{
+			Name:                               "Lathe",
+			Cost:                               12000,
+			InServiceDate:                      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			UsefulLife:                         1,
+			Method:                             accounting.StraightLine,
+			AssetAccountName:                   "Equipment",
+			AccumulatedDepreciationAccountName: "Accumulated Depreciation",
+			DepreciationExpenseAccountName:     "Depreciation Expense",
+		})
+		if err != nil {
+			t.Fatalf("failed to register asset with error %v", err)
+		}
+
+		// depreciation is run through June, past the sale in March
+		if _, err := service.RunDepreciation(ctx, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("failed to run depreciation with error %v", err)
+		}
+
+		_, err = service.DisposeAsset(ctx, DisposeAssetRequest{
+			AssetID:             asset.ID,
+			DisposalDate:        time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+			Proceeds:            10000,
+			ProceedsAccountName: "Checking",
+		})
+		if !accounting.IsDisposalBeforeDepreciation(err) {
+			t.Fatalf("expected ErrDisposalBeforeDepreciation, got %v", err)
+		}
+		if stored, err := repos.FixedAssets.ByID(ctx, asset.ID); err != nil || stored.Disposal != nil {
+			t.Fatalf("expected the asset still in service, got %+v and %v", stored.Disposal, err)
+		}
+		if balance := testBalance(t, ctx, repos, "Accumulated Depreciation"); balance != -6000 {
+			t.Fatalf("expected accumulated depreciation of 6000 left on the books, got %v", balance)
+		}
+
+		// disposed of as of the last period posted, the gain is on six months' depreciation
+		disposal, err := service.DisposeAsset(ctx, DisposeAssetRequest{
+			AssetID:             asset.ID,
+			DisposalDate:        time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+			Proceeds:            7000,
+			ProceedsAccountName: "Checking",
+		})
+		if err != nil {
+			t.Fatalf("failed to dispose of asset with error %v", err)
+		}
+		if disposal.AccumulatedDepreciation != 6000 || disposal.GainOrLoss != 1000 {
+			t.Fatalf("expected accumulated depreciation of 6000 and a gain of 1000, got %+v", disposal)
+		}
+		for name, want := range map[string]float64{
+			"Checking":                           7000,
+			"Equipment":                          -12000, // its purchase is not posted here
+			"Accumulated Depreciation":           0,
+			"Depreciation Expense":               6000,
+			"Gain or Loss on Disposal of Assets": -1000,
+		} {
+			if balance := testBalance(t, ctx, repos, name); balance != want {
+				t.Fatalf("expected %s to balance at %v, got %v", name, want, balance)
+			}
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type IntegrityService struct {
+	IntegrityRepo accounting.IntegrityRepository
+}
+
+// Checks the books whole, reporting each problem found with a suggested fix
+func (s *IntegrityService) Check(ctx context.Context) (accounting.IntegrityReport, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "check the books"); err != nil {
+		return accounting.IntegrityReport{}, err
+	}
+
+	data, err := s.IntegrityRepo.Load(ctx)
+	if err != nil {
+		return accounting.IntegrityReport{}, err
+	}
+
+	return accounting.CheckIntegrity(data), nil
+}
+
+// WriteIntegrityReport writes what the integrity check of the database found,
+// each problem with the record it concerns and a suggested fix
+func WriteIntegrityReport(w io.Writer, dbPath string, report accounting.IntegrityReport) {
+	fmt.Fprintf(w, "%s: checked %d entries, %d accounts and %d closed periods; ", dbPath, report.Entries, report.Accounts, report.ClosedPeriods)
+	if report.OK() {
+		fmt.Fprintln(w, "no problems found")
+		return
+	}
+	fmt.Fprintf(w, "%d problems found\n", len(report.Problems))
+	for _, p := range report.Problems {
+		fmt.Fprintf(w, "  %s %q %s\n    fix: %s\n", p.EntityType, p.EntityID, p.Problem, p.Fix)
+	}
+}
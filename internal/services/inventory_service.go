@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type InventoryService struct {
+	UnitRepo         accounting.InventoryUnitRepository
+	StockRepo        accounting.StockRepository
+	CountRepo        accounting.InventoryCountRepository
+	ProjectRepo      accounting.ProjectRepository
+	CustomerRepo     accounting.CustomerRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+	COGSAccountName  string
+	// the account count variances post to unless a count names another
+	ShrinkageAccountName string
+}
+
+// the details of an individually tracked unit brought into inventory
+type AcquireUnitRequest struct {
+	SerialNumber         string
+	Description          string
+	Cost                 float64
+	AcquisitionDate      time.Time
+	Location             string
+	InventoryAccountName string
+	PaymentAccountName   string // credited with the cost, e.g. cash or a payable
+}
+
+// the details of the sale of a unit, invoiced to a customer
+type SellUnitRequest struct {
+	UnitID                string
+	CustomerID            string
+	InvoiceNumber         string
+	SaleDate              time.Time
+	Price                 float64
+	Terms                 *accounting.PaymentTerms // nil -> the customer's default terms
+	ReceivableAccountName string
+	RevenueAccountName    string
+	ProjectID             string // empty if the sale is not for a project
+	Memo                  string
+}
+
+// the details of a new fungible stock item
+type CreateStockItemRequest struct {
+	SKU                  string
+	Name                 string
+	Method               accounting.CostingMethod
+	InventoryAccountName string
+	COGSAccountName      string // empty -> the service's account
+}
+
+// the details of a quantity of stock received
+type ReceiveStockRequest struct {
+	ItemID            string
+	Date              time.Time
+	Quantity          float64
+	UnitCost          float64
+	CreditAccountName string // e.g. cash or a payable
+	Memo              string
+}
+
+// the details of a quantity of stock issued or sold
+type IssueStockRequest struct {
+	ItemID           string
+	Date             time.Time
+	Quantity         float64
+	DebitAccountName string // empty -> the item's cost of goods sold account
+	ProjectID        string // empty if the stock is not consumed by a project
+	Memo             string
+}
+
+// a recorded stock movement, and the adjustments it made to issues
+// dated after it, each identifying the entry it corrects
+type StockMovementResult struct {
+	Movement    *accounting.StockMovement        `json:"movement"`
+	Adjustments []accounting.StockCostAdjustment `json:"adjustments"`
+}
+
+// AcquireUnit records a new unit and posts its cost to the inventory account
+func (s *InventoryService) AcquireUnit(ctx context.Context, req AcquireUnitRequest) (*accounting.InventoryUnit, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "acquire inventory"); err != nil {
+		return nil, err
+	}
+
+	unit, err := accounting.NewInventoryUnit(
+		req.SerialNumber,
+		req.Description,
+		req.Cost,
+		req.AcquisitionDate,
+		req.Location,
+		req.InventoryAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, unit.InventoryAccountName, "an inventory account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, req.PaymentAccountName, "a payment account", accounting.Asset, accounting.Liability, accounting.Equity); err != nil {
+		return nil, err
+	}
+
+	if err := s.UnitRepo.Acquire(ctx, unit, unit.AcquisitionEntry(req.PaymentAccountName)); err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+// Records a unit's new location
+func (s *InventoryService) MoveUnit(ctx context.Context, id string, location string) error {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "move inventory"); err != nil {
+		return err
+	}
+
+	return s.UnitRepo.Move(ctx, id, location)
+}
+
+// SellUnit invoices the customer for the unit, and relieves inventory of that
+// unit's cost, in a single journal entry.
+func (s *InventoryService) SellUnit(ctx context.Context, req SellUnitRequest) (*accounting.UnitSale, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "sell inventory"); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.UnitRepo.ByID(ctx, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.CustomerRepo.ByID(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := customer.DefaultTerms
+	if req.Terms != nil {
+		terms = *req.Terms
+	}
+
+	line := accounting.InvoiceLine{
+		AccountName: req.RevenueAccountName,
+		Description: unit.Description,
+		Amount:      accounting.RoundCents(req.Price),
+		ProjectID:   req.ProjectID,
+	}
+	if line.Description == "" {
+		line.Description = unit.SerialNumber
+	}
+
+	invoice, err := accounting.NewInvoice(customer.ID, req.InvoiceNumber, req.SaleDate, terms, req.ReceivableAccountName, []accounting.InvoiceLine{line})
+	if err != nil {
+		return nil, err
+	}
+	invoice.Memo = req.Memo
+
+	if err := requireAccountType(ctx, s.AccountRepo, invoice.ReceivableAccountName, "a receivable account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, line.AccountName, "a revenue account", accounting.Revenue); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, s.COGSAccountName, "a cost of goods sold account", accounting.Expense); err != nil {
+		return nil, err
+	}
+	if err := requireActiveProject(ctx, s.ProjectRepo, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	sale, err := accounting.NewUnitSale(&unit, invoice, s.COGSAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.UnitRepo.Sell(ctx, sale, invoice, sale.JournalEntry(&unit, invoice)); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// Creates and saves a new stock item
+func (s *InventoryService) CreateStockItem(ctx context.Context, req CreateStockItemRequest) (*accounting.StockItem, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "create stock items"); err != nil {
+		return nil, err
+	}
+
+	cogsAccount := req.COGSAccountName
+	if cogsAccount == "" {
+		cogsAccount = s.COGSAccountName
+	}
+
+	item, err := accounting.NewStockItem(req.SKU, req.Name, req.Method, req.InventoryAccountName, cogsAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, item.InventoryAccountName, "an inventory account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, item.COGSAccountName, "a cost of goods sold account", accounting.Expense); err != nil {
+		return nil, err
+	}
+
+	if err := s.StockRepo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Lists every stock item
+func (s *InventoryService) GetStockItems(ctx context.Context) ([]*accounting.StockItem, error) {
+	return s.StockRepo.Items(ctx)
+}
+
+// ReceiveStock adds a cost layer to the item and posts it to inventory. A
+// backdated receipt recosts the issues after it, each by an adjusting entry.
+func (s *InventoryService) ReceiveStock(ctx context.Context, req ReceiveStockRequest) (*StockMovementResult, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "receive stock"); err != nil {
+		return nil, err
+	}
+
+	item, err := s.StockRepo.ItemByID(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, req.CreditAccountName, "a payment account", accounting.Asset, accounting.Liability, accounting.Equity); err != nil {
+		return nil, err
+	}
+
+	movement, err := accounting.NewStockReceipt(&item, req.Date, req.Quantity, req.UnitCost, req.CreditAccountName)
+	if err != nil {
+		return nil, err
+	}
+	movement.Memo = req.Memo
+
+	return s.recordMovement(ctx, &item, movement)
+}
+
+// IssueStock consumes the item's layers by its costing method and posts the
+// cost, to cost of goods sold unless another account is named. A backdated
+// issue recosts the issues after it, each by an adjusting entry.
+func (s *InventoryService) IssueStock(ctx context.Context, req IssueStockRequest) (*StockMovementResult, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "issue stock"); err != nil {
+		return nil, err
+	}
+
+	item, err := s.StockRepo.ItemByID(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	movement, err := accounting.NewStockIssue(&item, req.Date, req.Quantity, req.DebitAccountName)
+	if err != nil {
+		return nil, err
+	}
+	movement.Memo = req.Memo
+	movement.ProjectID = req.ProjectID
+
+	if err := requireAccountType(ctx, s.AccountRepo, movement.AccountName, "an issue account", accounting.Expense, accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireActiveProject(ctx, s.ProjectRepo, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.recordMovement(ctx, &item, movement)
+}
+
+// Retrieves an item's movements, and its valuation as of the end of asOf
+func (s *InventoryService) GetStockValuation(ctx context.Context, itemID string, asOf time.Time) (*accounting.StockItem, []accounting.StockMovement, *accounting.StockValuation, error) {
+	item, err := s.StockRepo.ItemByID(ctx, itemID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	movements, err := s.StockRepo.Movements(ctx, item.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	valuation, err := accounting.ValuationAsOf(&item, movements, asOf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &item, movements, &valuation, nil
+}
+
+// Produces the units and stock on hand as of the end of asOf, reconciled to the inventory accounts
+func (s *InventoryService) GetOnHand(ctx context.Context, asOf time.Time) (*accounting.OnHandReport, error) {
+	units, err := s.UnitRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.StockRepo.Items(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var controlAccounts []string
+	addControl := func(name string) {
+		if !slices.Contains(controlAccounts, name) {
+			controlAccounts = append(controlAccounts, name)
+		}
+	}
+	for _, unit := range units {
+		addControl(unit.InventoryAccountName)
+	}
+
+	stock := make([]accounting.StockOnHand, 0, len(items))
+	for _, item := range items {
+		addControl(item.InventoryAccountName)
+
+		movements, err := s.StockRepo.Movements(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		valuation, err := accounting.ValuationAsOf(item, movements, asOf)
+		if err != nil {
+			return nil, err
+		}
+		stock = append(stock, accounting.StockOnHand{Item: item, Valuation: valuation})
+	}
+
+	balance, err := controlBalance(ctx, s.JournalEntryRepo, s.AccountRepo, controlAccounts, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := accounting.BuildOnHandReport(units, stock, asOf, controlAccounts, balance)
+	return &report, nil
+}
+
+// fits the movement among the item's recorded movements, and posts it along
+// with an adjusting entry for every issue it recosts
+func (s *InventoryService) recordMovement(ctx context.Context, item *accounting.StockItem, movement *accounting.StockMovement) (*StockMovementResult, error) {
+	recorded, err := s.StockRepo.Movements(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments, err := accounting.ApplyStockMovement(item, recorded, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]accounting.JournalEntry, len(adjustments))
+	for i, adjustment := range adjustments {
+		issue := recorded[slices.IndexFunc(recorded, func(m accounting.StockMovement) bool { return m.ID == adjustment.MovementID })]
+		entries[i] = adjustment.JournalEntry(item, &issue)
+	}
+
+	if err := s.StockRepo.Record(ctx, movement, movement.JournalEntry(item), adjustments, entries); err != nil {
+		return nil, err
+	}
+
+	return &StockMovementResult{Movement: movement, Adjustments: adjustments}, nil
+}
+
+// StartCount generates a count sheet for every stock item, expecting the
+// quantities on the books as of the end of countDate
+func (s *InventoryService) StartCount(ctx context.Context, countDate time.Time, shrinkageAccountName string) (*accounting.InventoryCount, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "count inventory"); err != nil {
+		return nil, err
+	}
+
+	if shrinkageAccountName == "" {
+		shrinkageAccountName = s.ShrinkageAccountName
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, shrinkageAccountName, "a shrinkage account", accounting.Expense); err != nil {
+		return nil, err
+	}
+
+	items, err := s.StockRepo.Items(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make([]float64, len(items))
+	for i, item := range items {
+		movements, err := s.StockRepo.Movements(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		valuation, err := accounting.ValuationAsOf(item, movements, countDate)
+		if err != nil {
+			return nil, err
+		}
+		expected[i] = valuation.Quantity
+	}
+
+	count, err := accounting.NewInventoryCount(countDate, shrinkageAccountName, items, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CountRepo.Save(ctx, count); err != nil {
+		return nil, err
+	}
+
+	return count, nil
+}
+
+// Lists every count, most recent first
+func (s *InventoryService) GetCounts(ctx context.Context) ([]*accounting.InventoryCount, error) {
+	return s.CountRepo.GetAll(ctx)
+}
+
+// Retrieves a count; while it is open, its variances are costed as they would post
+func (s *InventoryService) GetCount(ctx context.Context, id string) (*accounting.InventoryCount, error) {
+	count, err := s.CountRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if count.Status == accounting.CountOpen {
+		if _, err := s.planCount(ctx, &count); err != nil {
+			return nil, err
+		}
+	}
+
+	return &count, nil
+}
+
+// RecordCounts enters counted quantities, by item ID, on an open count
+func (s *InventoryService) RecordCounts(ctx context.Context, id string, counted map[string]float64) (*accounting.InventoryCount, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "count inventory"); err != nil {
+		return nil, err
+	}
+
+	count, err := s.CountRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for itemID, quantity := range counted {
+		if err := count.Record(itemID, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.CountRepo.Save(ctx, &count); err != nil {
+		return nil, err
+	}
+
+	return s.GetCount(ctx, id)
+}
+
+// PostCount posts a fully counted count: each item's variance becomes a
+// movement costed by its method, and together they post as a single entry
+// to the count's shrinkage account. The count and its variances are kept.
+func (s *InventoryService) PostCount(ctx context.Context, id string) (*accounting.InventoryCount, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "post inventory counts"); err != nil {
+		return nil, err
+	}
+
+	count, err := s.CountRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if count.Status != accounting.CountOpen {
+		return nil, fmt.Errorf("count of %s was already posted", count.CountDate.Format(time.DateOnly))
+	}
+	if !count.Complete() {
+		return nil, fmt.Errorf("count of %s has items not yet counted", count.CountDate.Format(time.DateOnly))
+	}
+
+	plan, err := s.planCount(ctx, &count)
+	if err != nil {
+		return nil, err
+	}
+
+	count.Status = accounting.CountPosted
+	je := count.JournalEntry(plan.items, plan.movements)
+	if err := s.CountRepo.Post(ctx, &count, plan.movements, je, plan.adjustments, plan.adjustmentEntries); err != nil {
+		return nil, err
+	}
+
+	return &count, nil
+}
+
+// the movements, and adjustments to later issues, which would post a count
+type countPlan struct {
+	items             []*accounting.StockItem
+	movements         []*accounting.StockMovement
+	adjustments       []accounting.StockCostAdjustment
+	adjustmentEntries []accounting.JournalEntry
+}
+
+// costs each line's variance against the books as they stand, refreshing the
+// expected quantities so movements entered since the sheet was generated are
+// not mistaken for variances. Overages are valued at the item's average cost.
+func (s *InventoryService) planCount(ctx context.Context, count *accounting.InventoryCount) (*countPlan, error) {
+	plan := &countPlan{}
+
+	for i := range count.Lines {
+		line := &count.Lines[i]
+
+		item, err := s.StockRepo.ItemByID(ctx, line.ItemID)
+		if err != nil {
+			return nil, err
+		}
+		recorded, err := s.StockRepo.Movements(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		valuation, err := accounting.ValuationAsOf(&item, recorded, count.CountDate)
+		if err != nil {
+			return nil, err
+		}
+		line.ExpectedQuantity = valuation.Quantity
+		line.VarianceCost, line.MovementID = 0, ""
+
+		movement, err := count.VarianceMovement(&item, averageCost(valuation, recorded))
+		if err != nil {
+			return nil, err
+		}
+		if movement == nil {
+			continue
+		}
+
+		adjustments, err := accounting.ApplyStockMovement(&item, recorded, movement)
+		if err != nil {
+			return nil, err
+		}
+		for _, adjustment := range adjustments {
+			issue := recorded[slices.IndexFunc(recorded, func(m accounting.StockMovement) bool { return m.ID == adjustment.MovementID })]
+			plan.adjustmentEntries = append(plan.adjustmentEntries, adjustment.JournalEntry(&item, &issue))
+		}
+		plan.adjustments = append(plan.adjustments, adjustments...)
+
+		line.MovementID = movement.ID
+		line.VarianceCost = movement.Cost
+		if movement.Kind == accounting.StockIssue {
+			line.VarianceCost = -movement.Cost
+		}
+
+		plan.items = append(plan.items, &item)
+		plan.movements = append(plan.movements, movement)
+	}
+
+	return plan, nil
+}
+
+// the average cost of the units on hand or, with none on hand, of the latest receipt
+func averageCost(valuation accounting.StockValuation, movements []accounting.StockMovement) float64 {
+	if valuation.Quantity > 0 {
+		return valuation.Value / valuation.Quantity
+	}
+
+	for i := len(movements) - 1; i >= 0; i-- {
+		if movements[i].Kind == accounting.StockReceipt {
+			return movements[i].UnitCost()
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type JournalEntryService struct {
+	JournalEntryRepo accounting.JournalEntryRepository
+}
+
+// Retrieves a journal entry, with its lines, by ID
+func (s *JournalEntryService) GetEntry(ctx context.Context, id string) (accounting.JournalEntry, error) {
+	return s.JournalEntryRepo.ByID(ctx, id)
+}
+
+// VerifyChain walks the hash chain over every posted entry, reporting the
+// first entry whose content or link no longer matches
+func (s *JournalEntryService) VerifyChain(ctx context.Context) (accounting.ChainReport, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "verify the ledger"); err != nil {
+		return accounting.ChainReport{}, err
+	}
+
+	links, err := s.JournalEntryRepo.Chain(ctx)
+	if err != nil {
+		return accounting.ChainReport{}, err
+	}
+
+	return accounting.VerifyChain(links), nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type PayablesService struct {
+	VendorRepo  accounting.VendorRepository
+	BillRepo    accounting.BillRepository
+	AccountRepo accounting.AccountRepository
+
+	// account credited with early-payment discounts taken
+	DiscountAccountName string
+}
+
+// the details of a bill received from a vendor
+type EnterBillRequest struct {
+	VendorID           string
+	Number             string
+	BillDate           time.Time
+	Terms              *accounting.PaymentTerms // nil -> the vendor's default terms
+	PayableAccountName string
+	Memo               string
+	Lines              []accounting.BillLine
+}
+
+// the details of a payment against a bill
+type PayBillRequest struct {
+	BillID          string
+	PaymentDate     time.Time
+	CashAccountName string
+	Amount          float64 // zero -> settle the bill in full, net of any discount
+}
+
+// a bill with its vendor and what remains to pay on it
+type BillBalance struct {
+	Bill        *accounting.Bill
+	VendorName  string
+	OpenBalance float64
+}
+
+// Creates and saves a new vendor
+func (s *PayablesService) CreateVendor(ctx context.Context, name string, defaultTerms accounting.PaymentTerms) (*accounting.Vendor, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "create vendors"); err != nil {
+		return nil, err
+	}
+
+	vendor, err := accounting.NewVendor(name, defaultTerms)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.VendorRepo.Save(ctx, vendor); err != nil {
+		return nil, err
+	}
+
+	return vendor, nil
+}
+
+// Lists every vendor
+func (s *PayablesService) GetVendors(ctx context.Context) ([]*accounting.Vendor, error) {
+	return s.VendorRepo.GetAll(ctx)
+}
+
+// Lists every bill, ordered by bill date, with its vendor and open balance
+func (s *PayablesService) GetBills(ctx context.Context) ([]BillBalance, error) {
+	vendors, err := s.VendorRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(vendors))
+	for _, vendor := range vendors {
+		names[vendor.ID] = vendor.Name
+	}
+
+	bills, err := s.BillRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]BillBalance, 0, len(bills))
+	for _, bill := range bills {
+		payments, err := s.BillRepo.Payments(ctx, bill.ID)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, BillBalance{
+			Bill:        bill,
+			VendorName:  names[bill.VendorID],
+			OpenBalance: bill.OpenBalance(payments),
+		})
+	}
+
+	return balances, nil
+}
+
+// Retrieves a bill with its vendor and the payments made against it
+func (s *PayablesService) GetBill(ctx context.Context, id string) (accounting.Bill, accounting.Vendor, []accounting.BillPayment, error) {
+	bill, err := s.BillRepo.ByID(ctx, id)
+	if err != nil {
+		return accounting.Bill{}, accounting.Vendor{}, nil, err
+	}
+
+	vendor, err := s.VendorRepo.ByID(ctx, bill.VendorID)
+	if err != nil {
+		return accounting.Bill{}, accounting.Vendor{}, nil, err
+	}
+
+	payments, err := s.BillRepo.Payments(ctx, bill.ID)
+	if err != nil {
+		return accounting.Bill{}, accounting.Vendor{}, nil, err
+	}
+
+	return bill, vendor, payments, nil
+}
+
+// EnterBill records a vendor bill and posts it to the journal.
+//
+// Lines must be charged to expense or asset (including inventory) accounts,
+// and the payable account must be a liability.
+func (s *PayablesService) EnterBill(ctx context.Context, req EnterBillRequest) (*accounting.Bill, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "enter bills"); err != nil {
+		return nil, err
+	}
+
+	vendor, err := s.VendorRepo.ByID(ctx, req.VendorID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := vendor.DefaultTerms
+	if req.Terms != nil {
+		terms = *req.Terms
+	}
+
+	bill, err := accounting.NewBill(vendor.ID, req.Number, req.BillDate, terms, req.PayableAccountName, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	bill.Memo = req.Memo
+
+	if err := requireAccountType(ctx, s.AccountRepo, bill.PayableAccountName, "a payable account", accounting.Liability); err != nil {
+		return nil, err
+	}
+	for _, line := range bill.Lines {
+		if err := requireAccountType(ctx, s.AccountRepo, line.AccountName, "a bill line account", accounting.Expense, accounting.Asset); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.BillRepo.Post(ctx, bill, bill.JournalEntry()); err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+// PayBill pays a bill from a cash account, taking any early-payment discount earned.
+func (s *PayablesService) PayBill(ctx context.Context, req PayBillRequest) (*accounting.BillPayment, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "pay bills"); err != nil {
+		return nil, err
+	}
+
+	bill, err := s.BillRepo.ByID(ctx, req.BillID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.BillRepo.Payments(ctx, bill.ID)
+	if err != nil {
+		return nil, err
+	}
+	open := bill.OpenBalance(payments)
+
+	if err := requireAccountType(ctx, s.AccountRepo, req.CashAccountName, "a cash account", accounting.Asset); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = open - bill.Terms.DiscountFor(bill.BillDate, req.PaymentDate, open)
+	}
+
+	payment, err := accounting.NewBillPayment(&bill, open, req.PaymentDate, req.CashAccountName, amount, s.DiscountAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.BillRepo.RecordPayment(ctx, payment, payment.JournalEntry(&bill)); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ensures the named account exists and is one of the allowed types
+func requireAccountType(ctx context.Context, repo accounting.AccountRepository, name string, purpose string, allowed ...accounting.AccountType) error {
+	account, err := repo.ByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range allowed {
+		if account.AccountType == t {
+			return nil
+		}
+	}
+
+	return &accounting.ErrAccountTypeNotAllowed{Name: name, AccountType: account.AccountType, Purpose: purpose}
+}
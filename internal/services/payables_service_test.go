@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+)
+
+// opens an in-memory book holding the named accounts, with a context which
+// may do anything
+func newTestBooks(t *testing.T, accounts ...accounting.Account) (context.Context, *sqlite.Repositories) {
+	t.Helper()
+	ctx := accounting.AsSystem(context.Background())
+
+	repos, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database with error %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	for _, account := range accounts {
+		account.DisplayAfter = sql.NullString{}
+		if err := repos.Accounts.Save(ctx, &account); err != nil {
+			t.Fatalf("failed to save account %s with error %v", account.Name, err)
+		}
+	}
+
+	return ctx, repos
+}
+
+// the balance of the named account on the debit side, or negative on the credit side
+func testBalance(t *testing.T, ctx context.Context, repos *sqlite.Repositories, name string) float64 {
+	t.Helper()
+	totals, err := repos.JournalEntries.AccountTotals(ctx, time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("failed to total accounts with error %v", err)
+	}
+	for _, total := range totals {
+		if total.AccountName == name {
+			return accounting.RoundCents(total.Debits - total.Credits)
+		}
+	}
+	return 0
+}
+
+func TestPayablesService(t *testing.T) {
+	setup := func(t *testing.T) (context.Context, *sqlite.Repositories, *PayablesService, *accounting.Bill) {
+		t.Helper()
+		ctx, repos := newTestBooks(t,
+			accounting.Account{Name: "Checking", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal},
+			accounting.Account{Name: "Office Supplies", ParentGroupName: "Expenses", AccountType: accounting.Expense, NormalBalance: accounting.DebitNormal},
+		)
+		service := &PayablesService{
+			VendorRepo:          repos.Vendors,
+			BillRepo:            repos.Bills,
+			AccountRepo:         repos.Accounts,
+			DiscountAccountName: "Purchase Discounts",
+		}
+
+		vendor, err := service.CreateVendor(ctx, "Paper Co.", accounting.PaymentTerms{DiscountPercent: 2, DiscountDays: 10, NetDays: 30})
+		if err != nil {
+			t.Fatalf("failed to create vendor with error %v", err)
+		}
+		bill, err := service.EnterBill(ctx, EnterBillRequest{
+			VendorID:           vendor.ID,
+			Number:             "PC-100",
+			BillDate:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			PayableAccountName: "Accounts Payable",
+			Lines:              []accounting.BillLine{{AccountName: "Office Supplies", Amount: 1000}},
+		})
+		if err != nil {
+			t.Fatalf("failed to enter bill with error %v", err)
+		}
+
+		return ctx, repos, service, bill
+	}
+
+	t.Run("takes the early-payment discount within the discount period", func(t *testing.T) {
+		ctx, repos, service, bill := setup(t)
+
+		payment, err := service.PayBill(ctx, PayBillRequest{
+			BillID:          bill.ID,
+			PaymentDate:     time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC),
+			CashAccountName: "Checking",
+		})
+		if err != nil {
+			t.Fatalf("failed to pay bill with error %v", err)
+		}
+		if payment.Amount != 980 || payment.DiscountTaken != 20 || payment.DiscountAccountName != "Purchase Discounts" {
+			t.Fatalf("expected 980 paid with a 20 discount, got %+v", payment)
+		}
+
+		bills, err := service.GetBills(ctx)
+		if err != nil || len(bills) != 1 || bills[0].OpenBalance != 0 || bills[0].VendorName != "Paper Co." {
+			t.Fatalf("expected the bill settled, got %+v and %v", bills, err)
+		}
+		for name, want := range map[string]float64{"Accounts Payable": 0, "Checking": -980, "Purchase Discounts": -20} {
+			if got := testBalance(t, ctx, repos, name); got != want {
+				t.Fatalf("expected %s to balance at %.2f, got %.2f", name, want, got)
+			}
+		}
+	})
+
+	t.Run("pays in full after the discount period", func(t *testing.T) {
+		ctx, _, service, bill := setup(t)
+
+		payment, err := service.PayBill(ctx, PayBillRequest{
+			BillID:          bill.ID,
+			PaymentDate:     time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+			CashAccountName: "Checking",
+		})
+		if err != nil {
+			t.Fatalf("failed to pay bill with error %v", err)
+		}
+		if payment.Amount != 1000 || payment.DiscountTaken != 0 {
+			t.Fatalf("expected 1000 paid without discount, got %+v", payment)
+		}
+	})
+
+	t.Run("rejects overpayment", func(t *testing.T) {
+		ctx, _, service, bill := setup(t)
+		paidAt := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+		if _, err := service.PayBill(ctx, PayBillRequest{BillID: bill.ID, PaymentDate: paidAt, CashAccountName: "Checking", Amount: 1000.01}); !accounting.IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error, got %v", err)
+		}
+
+		if _, err := service.PayBill(ctx, PayBillRequest{BillID: bill.ID, PaymentDate: paidAt, CashAccountName: "Checking", Amount: 600}); err != nil {
+			t.Fatalf("failed to pay part of the bill with error %v", err)
+		}
+		if _, err := service.PayBill(ctx, PayBillRequest{BillID: bill.ID, PaymentDate: paidAt, CashAccountName: "Checking", Amount: 500}); !accounting.IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error paying past the open balance, got %v", err)
+		}
+	})
+
+	t.Run("rejects accounts of the wrong type", func(t *testing.T) {
+		ctx, _, service, bill := setup(t)
+
+		_, err := service.EnterBill(ctx, EnterBillRequest{
+			VendorID:           bill.VendorID,
+			Number:             "PC-101",
+			BillDate:           bill.BillDate,
+			PayableAccountName: "Office Supplies",
+			Lines:              []accounting.BillLine{{AccountName: "Office Supplies", Amount: 10}},
+		})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected an expense payable account rejected, got %v", err)
+		}
+
+		_, err = service.EnterBill(ctx, EnterBillRequest{
+			VendorID:           bill.VendorID,
+			Number:             "PC-102",
+			BillDate:           bill.BillDate,
+			PayableAccountName: "Accounts Payable",
+			Lines:              []accounting.BillLine{{AccountName: "Purchase Discounts", Amount: 10}},
+		})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected a revenue bill line rejected, got %v", err)
+		}
+
+		_, err = service.PayBill(ctx, PayBillRequest{BillID: bill.ID, PaymentDate: bill.BillDate, CashAccountName: "Accounts Payable"})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected a liability cash account rejected, got %v", err)
+		}
+	})
+}
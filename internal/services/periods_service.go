@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type PeriodService struct {
+	PeriodRepo accounting.PeriodRepository
+}
+
+// Retrieves the book's fiscal calendar
+func (s *PeriodService) GetCalendar(ctx context.Context) (accounting.FiscalCalendar, error) {
+	return s.PeriodRepo.Calendar(ctx)
+}
+
+// SaveCalendar validates and replaces the fiscal calendar. Only allowed while
+// no period has ever been closed; the untouched open periods are discarded,
+// their years to be opened afresh under the new calendar
+func (s *PeriodService) SaveCalendar(ctx context.Context, calendar accounting.FiscalCalendar) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change the fiscal calendar"); err != nil {
+		return err
+	}
+
+	if err := calendar.Validate(); err != nil {
+		return err
+	}
+
+	return s.PeriodRepo.SaveCalendar(ctx, calendar)
+}
+
+// Retrieves a fiscal year's periods, none if the year has not been opened
+func (s *PeriodService) GetPeriods(ctx context.Context, fiscalYear int) ([]accounting.AccountingPeriod, error) {
+	return s.PeriodRepo.ForYear(ctx, fiscalYear)
+}
+
+// OpenYear generates a fiscal year's periods, open, under the fiscal
+// calendar. A year opened already is left as it stands.
+func (s *PeriodService) OpenYear(ctx context.Context, fiscalYear int) ([]accounting.AccountingPeriod, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "open fiscal years"); err != nil {
+		return nil, err
+	}
+
+	periods, err := s.PeriodRepo.ForYear(ctx, fiscalYear)
+	if err != nil || len(periods) > 0 {
+		return periods, err
+	}
+
+	calendar, err := s.PeriodRepo.Calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	periods = calendar.Periods(fiscalYear)
+	if err := s.PeriodRepo.Insert(ctx, periods); err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// FiscalYearOf reports the fiscal year in which the date falls under the book's calendar
+func (s *PeriodService) FiscalYearOf(ctx context.Context, date time.Time) (int, error) {
+	calendar, err := s.PeriodRepo.Calendar(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return calendar.FiscalYearOf(date), nil
+}
+
+// SetPeriodStatus opens, soft-closes or closes a period, recording the reason
+func (s *PeriodService) SetPeriodStatus(ctx context.Context, id string, status accounting.PeriodStatus, reason string) (*accounting.AccountingPeriod, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "close periods"); err != nil {
+		return nil, err
+	}
+
+	period, err := s.PeriodRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := period.SetStatus(status, reason, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.PeriodRepo.SetStatus(ctx, &period, change); err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// Retrieves the history of a period's status
+func (s *PeriodService) GetStatusChanges(ctx context.Context, id string) ([]accounting.PeriodStatusChange, error) {
+	return s.PeriodRepo.StatusChanges(ctx, id)
+}
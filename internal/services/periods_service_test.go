@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestPeriodService(t *testing.T) {
+	t.Run("leaves a year unopened until an admin opens it", func(t *testing.T) {
+		ctx, repos := newTestBooks(t)
+		service := &PeriodService{PeriodRepo: repos.Periods}
+
+		viewer, err := accounting.NewUser("vic", "correct horse battery", accounting.ViewerRole)
+		if err != nil {
+			t.Fatalf("failed to create user with error %v", err)
+		}
+		periods, err := service.GetPeriods(accounting.WithUser(context.Background(), viewer), 2025)
+		if err != nil || len(periods) != 0 {
+			t.Fatalf("expected no periods before the year is opened, got %v and %v", periods, err)
+		}
+
+		if _, err := service.OpenYear(ctx, 2025); err != nil {
+			t.Fatalf("failed to open year with error %v", err)
+		}
+		// opening the year again leaves it be
+		opened, err := service.OpenYear(ctx, 2025)
+		if err != nil {
+			t.Fatalf("failed to reopen year with error %v", err)
+		}
+
+		periods, err = service.GetPeriods(ctx, 2025)
+		if err != nil || len(periods) != 12 || periods[0].ID != opened[0].ID {
+			t.Fatalf("expected the 12 periods opened once, got %d and %v", len(periods), err)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type ProjectService struct {
+	ProjectRepo      accounting.ProjectRepository
+	CustomerRepo     accounting.CustomerRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+	// the accounts labor posts to unless a time entry names others
+	LaborAccountName   string
+	PayrollAccountName string
+}
+
+// the details of a new project
+type CreateProjectRequest struct {
+	Name        string
+	CustomerID  string // empty for an internal project
+	StartDate   time.Time
+	Description string
+}
+
+// the details of direct labor hours worked on a project
+type RecordTimeRequest struct {
+	ProjectID         string
+	Person            string
+	Date              time.Time
+	Hours             float64
+	Rate              float64
+	DebitAccountName  string // empty -> the service's labor account, e.g. work in process or direct labor
+	CreditAccountName string // empty -> the service's payroll account
+	Memo              string
+}
+
+// Creates and saves a new project
+func (s *ProjectService) CreateProject(ctx context.Context, req CreateProjectRequest) (*accounting.Project, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "create projects"); err != nil {
+		return nil, err
+	}
+
+	if req.CustomerID != "" {
+		if _, err := s.CustomerRepo.ByID(ctx, req.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+
+	project, err := accounting.NewProject(req.Name, req.CustomerID, req.StartDate, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ProjectRepo.Save(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// Retrieves all projects
+func (s *ProjectService) GetProjects(ctx context.Context) ([]*accounting.Project, error) {
+	return s.ProjectRepo.GetAll(ctx)
+}
+
+// Retrieves a project and the hours recorded against it
+func (s *ProjectService) GetProject(ctx context.Context, id string) (*accounting.Project, []accounting.TimeEntry, error) {
+	project, err := s.ProjectRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := s.ProjectRepo.TimeEntries(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &project, entries, nil
+}
+
+// CloseProject closes a project to further time; lines already tagged with it are unaffected
+func (s *ProjectService) CloseProject(ctx context.Context, id string) (*accounting.Project, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "close projects"); err != nil {
+		return nil, err
+	}
+
+	project, err := s.ProjectRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Status = accounting.ProjectClosed
+	if err := s.ProjectRepo.Save(ctx, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// RecordTime records hours against an active project, posting their cost
+// to the labor account tagged with the project.
+func (s *ProjectService) RecordTime(ctx context.Context, req RecordTimeRequest) (*accounting.TimeEntry, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "record time"); err != nil {
+		return nil, err
+	}
+
+	project, err := s.ProjectRepo.ByID(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	debitAccount := req.DebitAccountName
+	if debitAccount == "" {
+		debitAccount = s.LaborAccountName
+	}
+	creditAccount := req.CreditAccountName
+	if creditAccount == "" {
+		creditAccount = s.PayrollAccountName
+	}
+
+	entry, err := accounting.NewTimeEntry(&project, req.Person, req.Date, req.Hours, req.Rate, debitAccount, creditAccount)
+	if err != nil {
+		return nil, err
+	}
+	entry.Memo = req.Memo
+
+	if err := requireAccountType(ctx, s.AccountRepo, entry.DebitAccountName, "a labor account", accounting.Expense, accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, entry.CreditAccountName, "a payroll account", accounting.Liability, accounting.Asset); err != nil {
+		return nil, err
+	}
+
+	if err := s.ProjectRepo.RecordTime(ctx, entry, entry.JournalEntry(&project)); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetMarginReport compares every project's margin over [from, to], inclusive
+// of both days, sorted by profit per direct labor hour
+func (s *ProjectService) GetMarginReport(ctx context.Context, from, to time.Time) (*accounting.ProjectMarginReport, error) {
+	projects, err := s.ProjectRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.JournalEntryRepo.ProjectLines(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []accounting.TimeEntry
+	for _, project := range projects {
+		projectEntries, err := s.ProjectRepo.TimeEntries(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, projectEntries...)
+	}
+
+	report := accounting.BuildProjectMarginReport(projects, lines, entries, from, to)
+	return &report, nil
+}
+
+// GetProjectMargin totals a single project's margin over [from, to], with the
+// journal lines and time entries behind it
+func (s *ProjectService) GetProjectMargin(ctx context.Context, id string, from, to time.Time) (*accounting.ProjectMargin, error) {
+	project, entries, err := s.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.JournalEntryRepo.ProjectLines(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	margin := accounting.BuildProjectMargin(project, lines, entries, from, to)
+	return &margin, nil
+}
+
+// requireActiveProject checks, when id is non-empty, that it names a project
+// still open to new costs and revenue
+func requireActiveProject(ctx context.Context, repo accounting.ProjectRepository, id string) error {
+	if id == "" {
+		return nil
+	}
+
+	project, err := repo.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if project.Status != accounting.ProjectActive {
+		return fmt.Errorf("project \"%s\" is closed", project.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type ReceivablesService struct {
+	CustomerRepo accounting.CustomerRepository
+	InvoiceRepo  accounting.InvoiceRepository
+	AccountRepo  accounting.AccountRepository
+	SalesTaxRepo accounting.SalesTaxRepository
+}
+
+// the details of an invoice issued to a customer
+type IssueInvoiceRequest struct {
+	CustomerID            string
+	Number                string
+	InvoiceDate           time.Time
+	Terms                 *accounting.PaymentTerms // nil -> the customer's default terms
+	ReceivableAccountName string
+	Memo                  string
+	Lines                 []accounting.InvoiceLine
+}
+
+// the details of a payment received against an invoice
+type ReceivePaymentRequest struct {
+	InvoiceID       string
+	PaymentDate     time.Time
+	CashAccountName string
+	Amount          float64 // zero -> the open balance
+}
+
+// an invoice with its customer and what remains to collect on it
+type InvoiceBalance struct {
+	Invoice      *accounting.Invoice
+	CustomerName string
+	OpenBalance  float64
+}
+
+// Creates and saves a new customer
+func (s *ReceivablesService) CreateCustomer(ctx context.Context, name string, defaultTerms accounting.PaymentTerms) (*accounting.Customer, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "create customers"); err != nil {
+		return nil, err
+	}
+
+	customer, err := accounting.NewCustomer(name, defaultTerms)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CustomerRepo.Save(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// Lists every customer
+func (s *ReceivablesService) GetCustomers(ctx context.Context) ([]*accounting.Customer, error) {
+	return s.CustomerRepo.GetAll(ctx)
+}
+
+// Lists the sales tax codes invoice lines may carry
+func (s *ReceivablesService) GetTaxCodes(ctx context.Context) ([]accounting.SalesTaxCode, error) {
+	if s.SalesTaxRepo == nil {
+		return nil, nil
+	}
+
+	schedule, err := s.SalesTaxRepo.Schedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule.Codes, nil
+}
+
+// Lists every invoice, ordered by invoice date, with its customer and open balance
+func (s *ReceivablesService) GetInvoices(ctx context.Context) ([]InvoiceBalance, error) {
+	customers, err := s.CustomerRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(customers))
+	for _, customer := range customers {
+		names[customer.ID] = customer.Name
+	}
+
+	invoices, err := s.InvoiceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]InvoiceBalance, 0, len(invoices))
+	for _, invoice := range invoices {
+		payments, err := s.InvoiceRepo.Payments(ctx, invoice.ID)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, InvoiceBalance{
+			Invoice:      invoice,
+			CustomerName: names[invoice.CustomerID],
+			OpenBalance:  invoice.OpenBalance(payments),
+		})
+	}
+
+	return balances, nil
+}
+
+// Retrieves an invoice with its customer and the payments received against it
+func (s *ReceivablesService) GetInvoice(ctx context.Context, id string) (accounting.Invoice, accounting.Customer, []accounting.InvoicePayment, error) {
+	invoice, err := s.InvoiceRepo.ByID(ctx, id)
+	if err != nil {
+		return accounting.Invoice{}, accounting.Customer{}, nil, err
+	}
+
+	customer, err := s.CustomerRepo.ByID(ctx, invoice.CustomerID)
+	if err != nil {
+		return accounting.Invoice{}, accounting.Customer{}, nil, err
+	}
+
+	payments, err := s.InvoiceRepo.Payments(ctx, invoice.ID)
+	if err != nil {
+		return accounting.Invoice{}, accounting.Customer{}, nil, err
+	}
+
+	return invoice, customer, payments, nil
+}
+
+// IssueInvoice records a customer invoice and posts it to the journal.
+//
+// Lines must be credited to revenue accounts, and the receivable account must
+// be an asset. Lines carrying a tax code are charged sales tax per the
+// schedule in effect.
+func (s *ReceivablesService) IssueInvoice(ctx context.Context, req IssueInvoiceRequest) (*accounting.Invoice, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "issue invoices"); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.CustomerRepo.ByID(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := customer.DefaultTerms
+	if req.Terms != nil {
+		terms = *req.Terms
+	}
+
+	invoice, err := accounting.NewInvoice(customer.ID, req.Number, req.InvoiceDate, terms, req.ReceivableAccountName, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Memo = req.Memo
+
+	if err := requireAccountType(ctx, s.AccountRepo, invoice.ReceivableAccountName, "a receivable account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	for _, line := range invoice.Lines {
+		if err := requireAccountType(ctx, s.AccountRepo, line.AccountName, "an invoice line account", accounting.Revenue); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.applySalesTax(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	if err := s.InvoiceRepo.Post(ctx, invoice, invoice.JournalEntry()); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// ReceivePayment applies a customer payment, deposited to a cash account, against an invoice.
+func (s *ReceivablesService) ReceivePayment(ctx context.Context, req ReceivePaymentRequest) (*accounting.InvoicePayment, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "receive payments"); err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.InvoiceRepo.ByID(ctx, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.InvoiceRepo.Payments(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	open := invoice.OpenBalance(payments)
+
+	if err := requireAccountType(ctx, s.AccountRepo, req.CashAccountName, "a cash account", accounting.Asset); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = open
+	}
+
+	payment, err := accounting.NewInvoicePayment(&invoice, open, req.PaymentDate, req.CashAccountName, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.InvoiceRepo.RecordPayment(ctx, payment, payment.JournalEntry(&invoice)); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// charges sales tax on the invoice's lines, if any carry a tax code
+func (s *ReceivablesService) applySalesTax(ctx context.Context, invoice *accounting.Invoice) error {
+	for _, line := range invoice.Lines {
+		if line.TaxCode == "" {
+			continue
+		}
+
+		if s.SalesTaxRepo == nil {
+			return &accounting.ErrSalesTaxCodeNotFound{Code: line.TaxCode}
+		}
+
+		schedule, err := s.SalesTaxRepo.Schedule(ctx)
+		if err != nil {
+			return err
+		}
+		return schedule.Apply(invoice)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+	"github.com/hoodnoah/ghoam/internal/persistence/sqlite"
+)
+
+func TestReceivablesService(t *testing.T) {
+	setup := func(t *testing.T) (context.Context, *sqlite.Repositories, *ReceivablesService, *accounting.Invoice) {
+		t.Helper()
+		ctx, repos := newTestBooks(t,
+			accounting.Account{Name: "Checking", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal},
+			accounting.Account{Name: "Consulting Revenue", ParentGroupName: "Revenues", AccountType: accounting.Revenue, NormalBalance: accounting.CreditNormal},
+			accounting.Account{Name: "Travel", ParentGroupName: "Expenses", AccountType: accounting.Expense, NormalBalance: accounting.DebitNormal},
+			accounting.Account{Name: "State Sales Tax Payable", ParentGroupName: "Liabilities", AccountType: accounting.Liability, NormalBalance: accounting.CreditNormal},
+			accounting.Account{Name: "County Sales Tax Payable", ParentGroupName: "Liabilities", AccountType: accounting.Liability, NormalBalance: accounting.CreditNormal},
+		)
+		service := &ReceivablesService{
+			CustomerRepo: repos.Customers,
+			InvoiceRepo:  repos.Invoices,
+			AccountRepo:  repos.Accounts,
+			SalesTaxRepo: repos.SalesTax,
+		}
+
+		customer, err := service.CreateCustomer(ctx, "Acme Corp.", accounting.PaymentTerms{NetDays: 15})
+		if err != nil {
+			t.Fatalf("failed to create customer with error %v", err)
+		}
+		invoice, err := service.IssueInvoice(ctx, IssueInvoiceRequest{
+			CustomerID:            customer.ID,
+			Number:                "1001",
+			InvoiceDate:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			ReceivableAccountName: "Accounts Receivable",
+			Lines:                 []accounting.InvoiceLine{{AccountName: "Consulting Revenue", Amount: 1200}},
+		})
+		if err != nil {
+			t.Fatalf("failed to issue invoice with error %v", err)
+		}
+
+		return ctx, repos, service, invoice
+	}
+
+	t.Run("issues an invoice on the customer's terms and collects it", func(t *testing.T) {
+		ctx, repos, service, invoice := setup(t)
+		if !invoice.DueDate.Equal(time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected the customer's Net 15 terms, got a due date of %v", invoice.DueDate)
+		}
+
+		if _, err := service.ReceivePayment(ctx, ReceivePaymentRequest{InvoiceID: invoice.ID, PaymentDate: invoice.DueDate, CashAccountName: "Checking", Amount: 700}); err != nil {
+			t.Fatalf("failed to receive part payment with error %v", err)
+		}
+		payment, err := service.ReceivePayment(ctx, ReceivePaymentRequest{InvoiceID: invoice.ID, PaymentDate: invoice.DueDate, CashAccountName: "Checking"})
+		if err != nil {
+			t.Fatalf("failed to receive the balance with error %v", err)
+		}
+		if payment.Amount != 500 {
+			t.Fatalf("expected the open balance of 500 received, got %+v", payment)
+		}
+
+		invoices, err := service.GetInvoices(ctx)
+		if err != nil || len(invoices) != 1 || invoices[0].OpenBalance != 0 || invoices[0].CustomerName != "Acme Corp." {
+			t.Fatalf("expected the invoice collected, got %+v and %v", invoices, err)
+		}
+		for name, want := range map[string]float64{"Accounts Receivable": 0, "Checking": 1200, "Consulting Revenue": -1200} {
+			if got := testBalance(t, ctx, repos, name); got != want {
+				t.Fatalf("expected %s to balance at %.2f, got %.2f", name, want, got)
+			}
+		}
+	})
+
+	t.Run("credits each jurisdiction's liability account with the tax on a taxed invoice", func(t *testing.T) {
+		ctx, repos, service, _ := setup(t)
+		salesTax := &SalesTaxService{SalesTaxRepo: repos.SalesTax, AccountRepo: repos.Accounts}
+
+		var rateIDs []string
+		for _, jurisdiction := range []struct {
+			name, account string
+			rate          float64
+		}{
+			{"State", "State Sales Tax Payable", 0.06},
+			{"County", "County Sales Tax Payable", 0.015},
+		} {
+			created, err := salesTax.CreateJurisdiction(ctx, jurisdiction.name, jurisdiction.account)
+			if err != nil {
+				t.Fatalf("failed to create jurisdiction with error %v", err)
+			}
+			rate, err := salesTax.SaveRate(ctx, accounting.SalesTaxRate{JurisdictionID: created.ID, Name: jurisdiction.name + " general", Rate: jurisdiction.rate})
+			if err != nil {
+				t.Fatalf("failed to save rate with error %v", err)
+			}
+			rateIDs = append(rateIDs, rate.ID)
+		}
+		if err := salesTax.SaveCode(ctx, accounting.SalesTaxCode{Code: "STD", Description: "Standard", RateIDs: rateIDs}); err != nil {
+			t.Fatalf("failed to save tax code with error %v", err)
+		}
+
+		customers, _ := service.GetCustomers(ctx)
+		invoice, err := service.IssueInvoice(ctx, IssueInvoiceRequest{
+			CustomerID:            customers[0].ID,
+			Number:                "1002",
+			InvoiceDate:           time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+			ReceivableAccountName: "Accounts Receivable",
+			Lines: []accounting.InvoiceLine{
+				{AccountName: "Consulting Revenue", Amount: 1000, TaxCode: "STD"},
+				{AccountName: "Consulting Revenue", Amount: 200},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to issue taxed invoice with error %v", err)
+		}
+		if invoice.Tax() != 75 || invoice.Total() != 1275 {
+			t.Fatalf("expected 75.00 tax on a 1275.00 invoice, got %.2f on %.2f", invoice.Tax(), invoice.Total())
+		}
+
+		// the setup's untaxed 1200 invoice is receivable as well
+		for name, want := range map[string]float64{
+			"Accounts Receivable":      2475,
+			"Consulting Revenue":       -2400,
+			"State Sales Tax Payable":  -60,
+			"County Sales Tax Payable": -15,
+		} {
+			if got := testBalance(t, ctx, repos, name); got != want {
+				t.Fatalf("expected %s to balance at %.2f, got %.2f", name, want, got)
+			}
+		}
+	})
+
+	t.Run("rejects overpayment", func(t *testing.T) {
+		ctx, _, service, invoice := setup(t)
+
+		_, err := service.ReceivePayment(ctx, ReceivePaymentRequest{InvoiceID: invoice.ID, PaymentDate: invoice.DueDate, CashAccountName: "Checking", Amount: 1200.01})
+		if !accounting.IsOverpayment(err) {
+			t.Fatalf("expected an Overpayment error, got %v", err)
+		}
+	})
+
+	t.Run("rejects accounts of the wrong type", func(t *testing.T) {
+		ctx, _, service, invoice := setup(t)
+
+		_, err := service.IssueInvoice(ctx, IssueInvoiceRequest{
+			CustomerID:            invoice.CustomerID,
+			Number:                "1002",
+			InvoiceDate:           invoice.InvoiceDate,
+			ReceivableAccountName: "Accounts Receivable",
+			Lines:                 []accounting.InvoiceLine{{AccountName: "Travel", Amount: 10}},
+		})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected an expense invoice line rejected, got %v", err)
+		}
+
+		_, err = service.IssueInvoice(ctx, IssueInvoiceRequest{
+			CustomerID:            invoice.CustomerID,
+			Number:                "1003",
+			InvoiceDate:           invoice.InvoiceDate,
+			ReceivableAccountName: "Consulting Revenue",
+			Lines:                 []accounting.InvoiceLine{{AccountName: "Consulting Revenue", Amount: 10}},
+		})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected a revenue receivable account rejected, got %v", err)
+		}
+
+		_, err = service.ReceivePayment(ctx, ReceivePaymentRequest{InvoiceID: invoice.ID, PaymentDate: invoice.DueDate, CashAccountName: "Travel"})
+		if !accounting.IsAccountTypeNotAllowed(err) {
+			t.Fatalf("expected an expense cash account rejected, got %v", err)
+		}
+	})
+
+	t.Run("forbids viewers from receiving payments", func(t *testing.T) {
+		_, _, service, invoice := setup(t)
+		viewer, err := accounting.NewUser("vera", "correct horse battery", accounting.ViewerRole)
+		if err != nil {
+			t.Fatalf("failed to create user with error %v", err)
+		}
+		ctx := accounting.WithUser(context.Background(), viewer)
+
+		_, err = service.ReceivePayment(ctx, ReceivePaymentRequest{InvoiceID: invoice.ID, PaymentDate: invoice.DueDate, CashAccountName: "Checking"})
+		if !accounting.IsPermissionDenied(err) {
+			t.Fatalf("expected a viewer refused, got %v", err)
+		}
+	})
+}
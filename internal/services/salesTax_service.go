@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type SalesTaxService struct {
+	SalesTaxRepo     accounting.SalesTaxRepository
+	InvoiceRepo      accounting.InvoiceRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+}
+
+// the details of collected tax paid over to a jurisdiction
+type RecordRemittanceRequest struct {
+	JurisdictionID  string
+	PeriodStart     time.Time
+	PeriodEnd       time.Time // inclusive
+	PaidOn          time.Time
+	CashAccountName string
+	Amount          float64 // zero -> the liability outstanding for the period
+	Memo            string
+}
+
+// Retrieves the jurisdictions, rates and codes currently configured
+func (s *SalesTaxService) GetSchedule(ctx context.Context) (accounting.SalesTaxSchedule, error) {
+	return s.SalesTaxRepo.Schedule(ctx)
+}
+
+// CreateJurisdiction creates and saves a jurisdiction whose collected tax is
+// owed on the given liability account.
+func (s *SalesTaxService) CreateJurisdiction(ctx context.Context, name, liabilityAccountName string) (*accounting.SalesTaxJurisdiction, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change sales tax settings"); err != nil {
+		return nil, err
+	}
+
+	jurisdiction, err := accounting.NewSalesTaxJurisdiction(name, liabilityAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, jurisdiction.LiabilityAccountName, "a sales tax liability account", accounting.Liability); err != nil {
+		return nil, err
+	}
+
+	if err := s.SalesTaxRepo.SaveJurisdiction(ctx, *jurisdiction); err != nil {
+		return nil, err
+	}
+
+	return jurisdiction, nil
+}
+
+// SaveRate validates and saves a jurisdiction's rate, adding it if it has no ID.
+func (s *SalesTaxService) SaveRate(ctx context.Context, rate accounting.SalesTaxRate) (*accounting.SalesTaxRate, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change sales tax settings"); err != nil {
+		return nil, err
+	}
+
+	rate.Name = strings.TrimSpace(rate.Name)
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.SalesTaxRepo.Schedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findJurisdiction(schedule, rate.JurisdictionID); err != nil {
+		return nil, err
+	}
+
+	if rate.ID == "" {
+		rate.ID = accounting.NewID()
+	}
+
+	if err := s.SalesTaxRepo.SaveRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// SaveCode validates and saves a tax code along with the rates it applies;
+// a code without rates marks lines exempt.
+func (s *SalesTaxService) SaveCode(ctx context.Context, code accounting.SalesTaxCode) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change sales tax settings"); err != nil {
+		return err
+	}
+
+	code.Code = strings.TrimSpace(code.Code)
+	code.Description = strings.TrimSpace(code.Description)
+	if err := code.Validate(); err != nil {
+		return err
+	}
+
+	schedule, err := s.SalesTaxRepo.Schedule(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(schedule.Rates))
+	for _, rate := range schedule.Rates {
+		known[rate.ID] = true
+	}
+	for _, rateID := range code.RateIDs {
+		if !known[rateID] {
+			return &accounting.ErrSalesTaxRateNotFound{ID: rateID}
+		}
+	}
+
+	return s.SalesTaxRepo.SaveCode(ctx, code)
+}
+
+// Return summarizes the sales and tax of invoices dated within [from, to],
+// and the tax remitted for periods ending within it, per jurisdiction.
+func (s *SalesTaxService) Return(ctx context.Context, from, to time.Time) (*accounting.SalesTaxReturn, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("sales tax return period cannot end (%s) before it starts (%s)", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	schedule, err := s.SalesTaxRepo.Schedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.InvoiceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	remittances, err := s.SalesTaxRepo.Remittances(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := accounting.BuildSalesTaxReturn(from, to, schedule.Jurisdictions, invoices, remittances)
+	return &report, nil
+}
+
+// Retrieves the remittances for periods ending within [from, to]
+func (s *SalesTaxService) GetRemittances(ctx context.Context, from, to time.Time) ([]accounting.SalesTaxRemittance, error) {
+	return s.SalesTaxRepo.Remittances(ctx, from, to)
+}
+
+// RecordRemittance pays tax collected for a jurisdiction over a period from a
+// cash account, clearing the jurisdiction's liability. Without an amount, the
+// liability outstanding at the end of the period, less what has been paid
+// against it since, is remitted.
+func (s *SalesTaxService) RecordRemittance(ctx context.Context, req RecordRemittanceRequest) (*accounting.SalesTaxRemittance, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "remit sales tax"); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.SalesTaxRepo.Schedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jurisdiction, err := findJurisdiction(schedule, req.JurisdictionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, req.CashAccountName, "a cash account", accounting.Asset); err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		if amount, err = s.outstanding(ctx, jurisdiction, req.PeriodEnd); err != nil {
+			return nil, err
+		}
+		if amount <= 0 {
+			return nil, fmt.Errorf("no sales tax is owed to %s for the period ending %s", jurisdiction.Name, req.PeriodEnd.Format(time.DateOnly))
+		}
+	}
+
+	remittance, err := accounting.NewSalesTaxRemittance(jurisdiction, req.PeriodStart, req.PeriodEnd, req.PaidOn, req.CashAccountName, amount, req.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.SalesTaxRepo.RecordRemittance(ctx, remittance, remittance.JournalEntry(jurisdiction)); err != nil {
+		return nil, err
+	}
+
+	return remittance, nil
+}
+
+// the jurisdiction's liability balance at the end of the period, less the
+// debits (remittances) posted to it since
+func (s *SalesTaxService) outstanding(ctx context.Context, jurisdiction accounting.SalesTaxJurisdiction, periodEnd time.Time) (float64, error) {
+	after := periodEnd.AddDate(0, 0, 1)
+
+	through, err := s.JournalEntryRepo.AccountTotals(ctx, time.Time{}, after)
+	if err != nil {
+		return 0, err
+	}
+
+	since, err := s.JournalEntryRepo.AccountTotals(ctx, after, time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return 0, err
+	}
+
+	var owed float64
+	for _, total := range through {
+		if total.AccountName == jurisdiction.LiabilityAccountName {
+			owed += total.Balance(accounting.CreditNormal)
+		}
+	}
+	for _, total := range since {
+		if total.AccountName == jurisdiction.LiabilityAccountName {
+			owed -= total.Debits
+		}
+	}
+
+	return accounting.RoundCents(owed), nil
+}
+
+func findJurisdiction(schedule accounting.SalesTaxSchedule, id string) (accounting.SalesTaxJurisdiction, error) {
+	for _, jurisdiction := range schedule.Jurisdictions {
+		if jurisdiction.ID == id {
+			return jurisdiction, nil
+		}
+	}
+
+	return accounting.SalesTaxJurisdiction{}, &accounting.ErrSalesTaxJurisdictionNotFound{ID: id}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type SETaxService struct {
+	SETaxRepo        accounting.SETaxRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+	YearEndRepo      accounting.YearEndRepository
+	// the asset account cash is set aside in, and the equity account the
+	// owner's tax payments are charged to
+	ReserveAccountName string
+	DrawAccountName    string
+}
+
+// the details of a transfer into the tax reserve
+type PostReserveRequest struct {
+	TaxYear         int
+	Date            time.Time
+	Amount          float64
+	FromAccountName string
+}
+
+// the details of an estimated tax payment
+type RecordEstimatedPaymentRequest struct {
+	TaxYear int
+	Quarter int
+	PaidOn  time.Time
+	Amount  float64
+	// defaults to the reserve account
+	FromAccountName string
+	Memo            string
+}
+
+// Estimate annualizes the net income of asOf's calendar year to date and
+// estimates the year's self-employment tax, with its installments, the
+// payments made, and the reserve transfer suggested to keep up with them.
+// Closing entries within the span are set aside, since they zero the very
+// income being estimated.
+func (s *SETaxService) Estimate(ctx context.Context, asOf time.Time) (*accounting.SETaxEstimate, error) {
+	taxYear := asOf.Year()
+	rates, err := s.SETaxRepo.Rates(ctx, taxYear)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.AccountRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	yearStart := time.Date(taxYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := asOf.AddDate(0, 0, 1)
+	totals, err := s.JournalEntryRepo.AccountTotals(ctx, yearStart, end)
+	if err != nil {
+		return nil, err
+	}
+	totals, err = withoutClosingEntries(ctx, s.YearEndRepo, s.JournalEntryRepo, totals, yearStart, end)
+	if err != nil {
+		return nil, err
+	}
+
+	reserveBalance, err := s.reserveBalance(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.SETaxRepo.Payments(ctx, taxYear)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := accounting.BuildSETaxEstimate(rates, asOf, accounting.NetIncome(accounts, totals), payments, reserveBalance)
+	return &estimate, nil
+}
+
+// Retrieves the rates in effect for a tax year
+func (s *SETaxService) GetRates(ctx context.Context, taxYear int) (accounting.SETaxRates, error) {
+	return s.SETaxRepo.Rates(ctx, taxYear)
+}
+
+// Retrieves every version of a tax year's rates, latest first
+func (s *SETaxService) GetRateVersions(ctx context.Context, taxYear int) ([]accounting.SETaxRates, error) {
+	return s.SETaxRepo.RateVersions(ctx, taxYear)
+}
+
+// SaveRates validates the rates and saves them as the next version for their tax year
+func (s *SETaxService) SaveRates(ctx context.Context, rates accounting.SETaxRates) (*accounting.SETaxRates, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change tax rates"); err != nil {
+		return nil, err
+	}
+
+	if err := rates.Validate(); err != nil {
+		return nil, err
+	}
+
+	rates.EnteredAt = time.Now().UTC()
+	if err := s.SETaxRepo.SaveRates(ctx, &rates); err != nil {
+		return nil, err
+	}
+
+	return &rates, nil
+}
+
+// PostReserve moves cash from an asset account into the tax reserve
+func (s *SETaxService) PostReserve(ctx context.Context, req PostReserveRequest) (*accounting.TaxReserveTransfer, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "reserve for taxes"); err != nil {
+		return nil, err
+	}
+
+	if err := requireAccountType(ctx, s.AccountRepo, req.FromAccountName, "a cash account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, s.ReserveAccountName, "a tax reserve account", accounting.Asset); err != nil {
+		return nil, err
+	}
+
+	transfer, err := accounting.NewTaxReserveTransfer(req.TaxYear, req.Date, req.Amount, req.FromAccountName, s.ReserveAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.SETaxRepo.PostReserve(ctx, transfer, transfer.JournalEntry()); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// RecordPayment posts an estimated payment against one of a year's
+// installments, paid from the reserve unless the request names another account
+func (s *SETaxService) RecordPayment(ctx context.Context, req RecordEstimatedPaymentRequest) (*accounting.EstimatedTaxPayment, error) {
+	if err := accounting.Authorize(ctx, accounting.BookkeeperRole, "record tax payments"); err != nil {
+		return nil, err
+	}
+
+	from := req.FromAccountName
+	if from == "" {
+		from = s.ReserveAccountName
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, from, "a cash account", accounting.Asset); err != nil {
+		return nil, err
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, s.DrawAccountName, "an owner draw account", accounting.Equity); err != nil {
+		return nil, err
+	}
+
+	payment, err := accounting.NewEstimatedTaxPayment(req.TaxYear, req.Quarter, req.PaidOn, req.Amount, from, s.DrawAccountName, req.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.SETaxRepo.RecordPayment(ctx, payment, payment.JournalEntry()); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Retrieves a tax year's reserve transfers and estimated payments
+func (s *SETaxService) GetActivity(ctx context.Context, taxYear int) ([]accounting.TaxReserveTransfer, []accounting.EstimatedTaxPayment, error) {
+	transfers, err := s.SETaxRepo.Reserves(ctx, taxYear)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payments, err := s.SETaxRepo.Payments(ctx, taxYear)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return transfers, payments, nil
+}
+
+// the balance of the reserve account at the end of asOf
+func (s *SETaxService) reserveBalance(ctx context.Context, asOf time.Time) (float64, error) {
+	totals, err := s.JournalEntryRepo.AccountTotals(ctx, time.Time{}, asOf.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, total := range totals {
+		if total.AccountName == s.ReserveAccountName {
+			return total.Balance(accounting.DebitNormal), nil
+		}
+	}
+
+	return 0, nil
+}
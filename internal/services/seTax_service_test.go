@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+func TestSETaxService(t *testing.T) {
+	t.Run("sets aside a year-end close within the tax year", func(t *testing.T) {
+		ctx, repos := newTestBooks(t,
+			accounting.Account{Name: "Checking", ParentGroupName: "Assets", AccountType: accounting.Asset, NormalBalance: accounting.DebitNormal},
+			accounting.Account{Name: "Consulting Revenue", ParentGroupName: "Revenues", AccountType: accounting.Revenue, NormalBalance: accounting.CreditNormal},
+		)
+		service := &SETaxService{
+			SETaxRepo:          repos.SETax,
+			AccountRepo:        repos.Accounts,
+			JournalEntryRepo:   repos.JournalEntries,
+			YearEndRepo:        repos.YearEnds,
+			ReserveAccountName: "Tax Reserve",
+			DrawAccountName:    "Owner Draws",
+		}
+		yearEnds := &YearEndService{
+			YearEndRepo:                 repos.YearEnds,
+			AccountRepo:                 repos.Accounts,
+			JournalEntryRepo:            repos.JournalEntries,
+			RetainedEarningsAccountName: "Retained Earnings",
+		}
+
+		for _, sale := range []struct {
+			date   time.Time
+			amount float64
+		}{
+			{time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), 10000},
+			{time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC), 5000},
+		} {
+			je := accounting.JournalEntry{
+				ID:        accounting.NewID(),
+				Timestamp: sale.date,
+				Lines: []accounting.JournalEntryLine{
+					{AccountName: "Checking", Amount: sale.amount, Side: accounting.Debit},
+					{AccountName: "Consulting Revenue", Amount: sale.amount, Side: accounting.Credit},
+				},
+			}
+			if err := repos.JournalEntries.Save(ctx, je); err != nil {
+				t.Fatalf("failed to save sale with error %v", err)
+			}
+		}
+
+		// a fiscal year ending mid calendar year closes the spring's revenue
+		if _, err := yearEnds.CloseYear(ctx, CloseYearRequest{
+			YearStart: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			YearEnd:   time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		}); err != nil {
+			t.Fatalf("failed to close year with error %v", err)
+		}
+
+		estimate, err := service.Estimate(ctx, time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("failed to estimate with error %v", err)
+		}
+		if estimate.YearToDateNetIncome != 15000 {
+			t.Fatalf("expected year to date net income of 15000 despite the close, got %v", estimate.YearToDateNetIncome)
+		}
+	})
+}
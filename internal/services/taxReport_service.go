@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type TaxReportService struct {
+	TaxLineRepo      accounting.TaxLineRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+	YearEndRepo      accounting.YearEndRepository
+}
+
+// Report aggregates a calendar tax year's activity onto a form's lines per
+// the year's mappings. Closing entries within the year are set aside, since
+// they zero the very balances being reported.
+func (s *TaxReportService) Report(ctx context.Context, taxYear int, form string) (*accounting.TaxReport, error) {
+	lines, err := s.TaxLineRepo.Lines(ctx, taxYear, form)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := s.TaxLineRepo.Mappings(ctx, taxYear, form)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.AccountRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := accounting.TaxYearBounds(taxYear)
+	totals, err := s.JournalEntryRepo.AccountTotals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err = withoutClosingEntries(ctx, s.YearEndRepo, s.JournalEntryRepo, totals, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := accounting.BuildTaxReport(taxYear, form, lines, mappings, accounts, totals)
+	return &report, nil
+}
+
+// Retrieves the forms with lines for a tax year
+func (s *TaxReportService) GetForms(ctx context.Context, taxYear int) ([]string, error) {
+	return s.TaxLineRepo.Forms(ctx, taxYear)
+}
+
+// Retrieves a form's lines for a tax year in display order
+func (s *TaxReportService) GetLines(ctx context.Context, taxYear int, form string) ([]accounting.TaxLine, error) {
+	return s.TaxLineRepo.Lines(ctx, taxYear, form)
+}
+
+// Retrieves every account, for mapping to lines
+func (s *TaxReportService) GetAccounts(ctx context.Context) ([]*accounting.Account, error) {
+	return s.AccountRepo.GetAll(ctx)
+}
+
+// Retrieves the accounts mapped to a form's lines for a tax year
+func (s *TaxReportService) GetMappings(ctx context.Context, taxYear int, form string) ([]accounting.TaxLineMapping, error) {
+	return s.TaxLineRepo.Mappings(ctx, taxYear, form)
+}
+
+// SaveLine validates and saves a form line, adding forms as their first line is saved
+func (s *TaxReportService) SaveLine(ctx context.Context, line accounting.TaxLine) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change tax lines"); err != nil {
+		return err
+	}
+
+	line.Form = strings.TrimSpace(line.Form)
+	line.Code = strings.TrimSpace(line.Code)
+	line.Description = strings.TrimSpace(line.Description)
+	if err := line.Validate(); err != nil {
+		return err
+	}
+
+	return s.TaxLineRepo.SaveLine(ctx, line)
+}
+
+// Removes a form line to which no account is mapped
+func (s *TaxReportService) DeleteLine(ctx context.Context, taxYear int, form, code string) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change tax lines"); err != nil {
+		return err
+	}
+
+	return s.TaxLineRepo.DeleteLine(ctx, taxYear, form, code)
+}
+
+// MapAccount maps an account to a line of a form for a tax year; an empty
+// line code removes the account's mapping on the form instead
+func (s *TaxReportService) MapAccount(ctx context.Context, mapping accounting.TaxLineMapping) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "map accounts to tax lines"); err != nil {
+		return err
+	}
+
+	if _, err := s.AccountRepo.ByName(ctx, mapping.AccountName); err != nil {
+		return err
+	}
+
+	if mapping.LineCode == "" {
+		return s.TaxLineRepo.Unmap(ctx, mapping.TaxYear, mapping.Form, mapping.AccountName)
+	}
+
+	return s.TaxLineRepo.Map(ctx, mapping)
+}
+
+// CopyYear carries a year's lines and mappings forward to another, as the
+// starting point for that year's renumbering; lines and mappings the other
+// year already has are kept
+func (s *TaxReportService) CopyYear(ctx context.Context, fromYear, toYear int) error {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "change tax lines"); err != nil {
+		return err
+	}
+
+	if fromYear == toYear {
+		return fmt.Errorf("cannot copy %d's tax lines onto itself", fromYear)
+	}
+
+	return s.TaxLineRepo.CopyYear(ctx, fromYear, toYear)
+}
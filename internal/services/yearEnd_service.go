@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hoodnoah/ghoam/internal/accounting"
+)
+
+type YearEndService struct {
+	YearEndRepo      accounting.YearEndRepository
+	AccountRepo      accounting.AccountRepository
+	JournalEntryRepo accounting.JournalEntryRepository
+	// the accounts years close to unless a request names others
+	RetainedEarningsAccountName string
+	IncomeSummaryAccountName    string
+}
+
+// the details of a fiscal year to close
+type CloseYearRequest struct {
+	YearStart time.Time
+	YearEnd   time.Time // inclusive
+	// close through the income summary account rather than directly to retained earnings
+	UseIncomeSummary bool
+	Reason           string
+}
+
+// CloseYear zeroes the year's revenue and expense accounts into retained
+// earnings with a single closing entry, and locks the year against new postings.
+func (s *YearEndService) CloseYear(ctx context.Context, req CloseYearRequest) (*accounting.YearEndClose, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "close years"); err != nil {
+		return nil, err
+	}
+
+	incomeSummary := ""
+	if req.UseIncomeSummary {
+		incomeSummary = s.IncomeSummaryAccountName
+		if err := requireAccountType(ctx, s.AccountRepo, incomeSummary, "an income summary account", accounting.Equity); err != nil {
+			return nil, err
+		}
+	}
+	if err := requireAccountType(ctx, s.AccountRepo, s.RetainedEarningsAccountName, "a retained earnings account", accounting.Equity); err != nil {
+		return nil, err
+	}
+
+	closing, err := accounting.NewYearEndClose(req.YearStart, req.YearEnd, s.RetainedEarningsAccountName, incomeSummary)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.AccountRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.JournalEntryRepo.AccountTotals(ctx, closing.YearStart, closing.YearEnd.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	je, err := closing.ClosingEntry(accounts, totals)
+	if err != nil {
+		return nil, err
+	}
+	closing.ClosedAt = time.Now().UTC()
+
+	record := accounting.YearEndAuditRecord{
+		ID:         accounting.NewID(),
+		CloseID:    closing.ID,
+		Action:     accounting.YearClosed,
+		OccurredAt: closing.ClosedAt,
+		Reason:     req.Reason,
+	}
+	if err := s.YearEndRepo.Close(ctx, closing, je, record); err != nil {
+		return nil, err
+	}
+
+	return closing, nil
+}
+
+// Retrieves every close, latest year first
+func (s *YearEndService) GetCloses(ctx context.Context) ([]*accounting.YearEndClose, error) {
+	return s.YearEndRepo.GetAll(ctx)
+}
+
+// ReopenYear reverses a year's closing entry and lifts its lock, recording
+// the reason. Only the latest closed year may be reopened, since the closes
+// of later years depend on it.
+func (s *YearEndService) ReopenYear(ctx context.Context, closeID string, reason string) (*accounting.YearEndClose, error) {
+	if err := accounting.Authorize(ctx, accounting.AdminRole, "reopen years"); err != nil {
+		return nil, err
+	}
+
+	closing, err := s.YearEndRepo.ByID(ctx, closeID)
+	if err != nil {
+		return nil, err
+	}
+
+	closes, err := s.YearEndRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range closes {
+		if other.Closed() && other.YearEnd.After(closing.YearEnd) {
+			return nil, fmt.Errorf(
+				"the year ended %s must be reopened before the year ended %s",
+				other.YearEnd.Format(time.DateOnly),
+				closing.YearEnd.Format(time.DateOnly),
+			)
+		}
+	}
+
+	je, err := s.JournalEntryRepo.ByID(ctx, closing.EntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal, err := closing.Reopen(je, time.Now().UTC(), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	record := accounting.YearEndAuditRecord{
+		ID:         accounting.NewID(),
+		CloseID:    closing.ID,
+		Action:     accounting.YearReopened,
+		OccurredAt: closing.ReopenedAt,
+		Reason:     closing.ReopenReason,
+	}
+	if err := s.YearEndRepo.Reopen(ctx, &closing, reversal, record); err != nil {
+		return nil, err
+	}
+
+	return &closing, nil
+}
+
+// withoutClosingEntries sets aside the entries of the year-end closes within
+// [start, end) from totals over the same span
+func withoutClosingEntries(
+	ctx context.Context,
+	yearEnds accounting.YearEndRepository,
+	entries accounting.JournalEntryRepository,
+	totals []accounting.AccountTotal,
+	start, end time.Time,
+) ([]accounting.AccountTotal, error) {
+	closes, err := yearEnds.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, closing := range closes {
+		// a reopened close's reversal already offsets its entry
+		if !closing.Closed() || closing.YearEnd.Before(start) || !closing.YearEnd.Before(end) {
+			continue
+		}
+
+		je, err := entries.ByID(ctx, closing.EntryID)
+		if err != nil {
+			return nil, err
+		}
+		totals = accounting.WithoutEntry(totals, je)
+	}
+
+	return totals, nil
+}
@@ -1,21 +1,164 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/hoodnoah/ghoam/cmd/admin"
 	"github.com/hoodnoah/ghoam/cmd/migrate"
 	"github.com/hoodnoah/ghoam/cmd/server"
+	"github.com/hoodnoah/ghoam/internal/config"
+	"github.com/hoodnoah/ghoam/internal/logging"
 )
 
 func main() {
-	// set up DB; run migrations and return a repository abstracting db interactions
-	repos, err := migrate.Execute("data/ghoam.db")
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		createAdmin(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		verify(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		check(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrateSchema(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		backup(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		restore(os.Args[2:])
+		return
+	}
+
+	// resolve settings from the config file, environment and flags
+	cfg, err := config.Load(os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	level, _ := cfg.Level() // validated by Load
+	logger := logging.New(os.Stderr, level)
+	slog.SetDefault(logger)
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("exiting", "error", err)
+		os.Exit(1)
+	}
+}
+
+// adds a user from the command line, e.g. the first one
+func createAdmin(args []string) {
+	err := admin.CreateAdmin(context.Background(), args, os.Getenv, os.Stdin, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create-admin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checks the hash chain over the posted entries, exiting 1 if it is broken
+func verify(args []string) {
+	err := admin.Verify(context.Background(), args, os.Getenv, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checks the books whole, exiting 1 if any problem is found
+func check(args []string) {
+	err := admin.Check(context.Background(), args, os.Getenv, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 	if err != nil {
-		log.Fatalf("failed to initialize the database with error %v", err)
+		fmt.Fprintf(os.Stderr, "check: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	log.Println("successfully initialized the database")
+// takes, verifies and prunes backups, e.g. from cron while the server runs
+func backup(args []string) {
+	err := admin.Backup(context.Background(), args, os.Getenv, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backup: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// replaces the database with a backup, with the server stopped
+func restore(args []string) {
+	err := admin.Restore(context.Background(), args, os.Getenv, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "restore: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// reports or changes the schema version, with the server stopped
+func migrateSchema(args []string) {
+	err := migrate.Command(context.Background(), args, os.Getenv, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serves until SIGINT or SIGTERM, then drains requests and closes the database
+func run(cfg config.Config, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// a second signal while draining exits immediately
+		<-ctx.Done()
+		stop()
+	}()
+
+	// set up DB; run migrations and return a repository abstracting db interactions
+	repos, err := migrate.Execute(cfg.DBPath, cfg.BackupDirectory())
+	if err != nil {
+		return fmt.Errorf("failed to initialize the database: %w", err)
+	}
+	logger.Info("database initialized", "path", cfg.DBPath)
 
 	// set up webserver
-	server.Execute(repos)
+	serveErr := server.Execute(ctx, cfg, repos, logger)
+
+	if err := repos.Close(); err != nil {
+		return errors.Join(serveErr, fmt.Errorf("failed to close the database: %w", err))
+	}
+	logger.Info("database closed")
+
+	return serveErr
 }
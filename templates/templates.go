@@ -0,0 +1,8 @@
+// Package templates embeds the page (.gohtml) and document (.gotmpl)
+// templates, so the server needs no templates directory at runtime.
+package templates
+
+import "embed"
+
+//go:embed *.gohtml *.gotmpl
+var FS embed.FS